@@ -10,6 +10,7 @@ import (
 	"crypto"
 	_ "crypto/sha512"
 	"encoding/binary"
+	"hash"
 	"math/big"
 )
 
@@ -56,8 +57,13 @@ func SHA512_256(in ...[]byte) []byte {
 }
 
 func SHA512_256i(in ...*big.Int) *big.Int {
+	return HashInts(crypto.SHA512_256.New, in...)
+}
+
+// HashInts hashes the integers as SHA512_256i does, with the hash made by `newHash`
+func HashInts(newHash func() hash.Hash, in ...*big.Int) *big.Int {
 	var data []byte
-	state := crypto.SHA512_256.New()
+	state := newHash()
 	inLen := len(in)
 	if inLen == 0 {
 		return nil
@@ -87,7 +93,7 @@ func SHA512_256i(in ...*big.Int) *big.Int {
 	// n < len(data) or an error will never happen.
 	// see: https://golang.org/pkg/hash/#Hash and https://github.com/golang/go/wiki/Hashing#the-hashhash-interface
 	if _, err := state.Write(data); err != nil {
-		Logger.Errorf("SHA512_256i Write() failed: %v", err)
+		Logger.Errorf("HashInts Write() failed: %v", err)
 		return nil
 	}
 	return new(big.Int).SetBytes(state.Sum(nil))
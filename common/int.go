@@ -8,11 +8,15 @@ package common
 
 import (
 	"math/big"
+	"reflect"
 )
 
 // modInt is a *big.Int that performs all of its arithmetic with modular reduction.
 type modInt big.Int
 
+// bigIntHeaderSize is the size of a big.Int without its words: a sign and a slice header
+const bigIntHeaderSize = 32
+
 var (
 	zero = big.NewInt(0)
 	one  = big.NewInt(1)
@@ -72,3 +76,90 @@ func (mi *modInt) i() *big.Int {
 func IsInInterval(b *big.Int, bound *big.Int) bool {
 	return b.Cmp(bound) == -1 && b.Cmp(zero) >= 0
 }
+
+// BigIntMemSize estimates the heap memory in bytes held by a big.Int of `bitLen` bits: its header and its words
+func BigIntMemSize(bitLen int) int {
+	return bigIntHeaderSize + (bitLen+63)/64*8
+}
+
+// MemSizer is implemented by values that keep their big.Ints in unexported fields, such as curve points, so that
+// MemSize can count them
+type MemSizer interface {
+	MemSize() int
+}
+
+// IntOfBitLen returns 2^(bitLen-1), the smallest int of `bitLen` bits. It stands in for a value of that size in the
+// structures that a memory estimate passes to MemSize.
+func IntOfBitLen(bitLen int) *big.Int {
+	if bitLen < 1 {
+		return new(big.Int)
+	}
+	return new(big.Int).Lsh(one, uint(bitLen-1))
+}
+
+// MemSize estimates the heap memory in bytes held by the big.Ints and byte slices that are reachable from `vs`
+// through exported fields, pointers, interfaces, slices and arrays, with BigIntMemSize for each big.Int. A value that
+// implements MemSizer counts itself. Maps and unexported fields are not followed, and a value that is reached twice is
+// counted twice.
+func MemSize(vs ...interface{}) int {
+	size := 0
+	for _, v := range vs {
+		size += memSize(reflect.ValueOf(v))
+	}
+	return size
+}
+
+func memSize(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+	}
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case *big.Int:
+			return BigIntMemSize(x.BitLen())
+		case MemSizer:
+			return x.MemSize()
+		}
+	}
+	size := 0
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		size = memSize(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				size += memSize(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len()
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			size += memSize(v.Index(i))
+		}
+	}
+	return size
+}
+
+// ZeroInt overwrites the words backing x with zeros and sets x to 0. x.SetInt64(0) alone would leave the old value in
+// the backing array.
+func ZeroInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	words = words[:cap(words)]
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
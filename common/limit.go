@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"errors"
+	"sync"
+)
+
+// GoroutineLimiter is a budget of goroutines for internal parallelism, so that work beyond it is queued rather than
+// spawned. It may be shared by several ceremonies for a global ceiling. A nil *GoroutineLimiter has no budget.
+type GoroutineLimiter struct {
+	slots chan struct{}
+
+	mtx           sync.Mutex
+	running, peak int
+}
+
+// NewGoroutineLimiter creates a budget of at most `max` goroutines running at once
+func NewGoroutineLimiter(max int) (*GoroutineLimiter, error) {
+	if max < 1 {
+		return nil, errors.New("a goroutine budget must allow at least one goroutine")
+	}
+	return &GoroutineLimiter{slots: make(chan struct{}, max)}, nil
+}
+
+// Max returns the budget, or 0 if there is none
+func (l *GoroutineLimiter) Max() int {
+	if l == nil {
+		return 0
+	}
+	return cap(l.slots)
+}
+
+// Peak returns the most goroutines that have run under the budget at once
+func (l *GoroutineLimiter) Peak() int {
+	if l == nil {
+		return 0
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.peak
+}
+
+// Go waits until the budget has room and then runs fn in a new goroutine. It must not be called from a goroutine that
+// holds a place in the budget, which could wait for itself; such a goroutine should use TryGo.
+func (l *GoroutineLimiter) Go(fn func()) {
+	if l == nil {
+		go fn()
+		return
+	}
+	l.slots <- struct{}{}
+	l.spawn(fn)
+}
+
+// TryGo runs fn in a new goroutine if the budget has room and reports whether it did. Otherwise the caller should run
+// fn itself, so that the work is done without exceeding the budget.
+func (l *GoroutineLimiter) TryGo(fn func()) bool {
+	if l == nil {
+		go fn()
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		l.spawn(fn)
+		return true
+	default:
+		return false
+	}
+}
+
+// GoOrRun runs fn in a new goroutine if the budget has room and in the calling goroutine otherwise
+func (l *GoroutineLimiter) GoOrRun(fn func()) {
+	if !l.TryGo(fn) {
+		fn()
+	}
+}
+
+// spawn runs fn in a new goroutine in a place of the budget taken by the caller
+func (l *GoroutineLimiter) spawn(fn func()) {
+	l.mtx.Lock()
+	if l.running++; l.peak < l.running {
+		l.peak = l.running
+	}
+	l.mtx.Unlock()
+	go func() {
+		defer func() {
+			l.mtx.Lock()
+			l.running--
+			l.mtx.Unlock()
+			<-l.slots
+		}()
+		fn()
+	}()
+}
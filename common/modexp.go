@@ -0,0 +1,61 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"errors"
+	"math/big"
+)
+
+type (
+	// ModExpBackend computes the modular exponentiations of proof verification in batches, so that they can be
+	// offloaded to an accelerated implementation. The default, BigIntModExp, uses math/big.
+	ModExpBackend interface {
+		// BatchModExp returns bases[i][k]^exps[i][k] mod `mod` for each i and k. Each row holds the exponentiations of
+		// one check of a proof. The exponents are never negative and the inputs must not be modified.
+		BatchModExp(mod *big.Int, bases, exps [][]*big.Int) [][]*big.Int
+	}
+
+	bigIntModExp struct{}
+)
+
+// BigIntModExp is the ModExpBackend that computes each exponentiation with big.Int.Exp
+var BigIntModExp ModExpBackend = bigIntModExp{}
+
+func (bigIntModExp) BatchModExp(mod *big.Int, bases, exps [][]*big.Int) [][]*big.Int {
+	results := make([][]*big.Int, len(bases))
+	for i := range bases {
+		results[i] = make([]*big.Int, len(bases[i]))
+		for k := range bases[i] {
+			results[i][k] = new(big.Int).Exp(bases[i][k], exps[i][k], mod)
+		}
+	}
+	return results
+}
+
+// BatchModExp runs a batch on the backend, or on BigIntModExp if it is nil, and checks that it returned a result of
+// the shape of the batch.
+func BatchModExp(backend ModExpBackend, mod *big.Int, bases, exps [][]*big.Int) ([][]*big.Int, error) {
+	if backend == nil {
+		backend = BigIntModExp
+	}
+	results := backend.BatchModExp(mod, bases, exps)
+	if len(results) != len(bases) {
+		return nil, errors.New("the ModExp backend returned a batch of the wrong size")
+	}
+	for i := range results {
+		if len(results[i]) != len(bases[i]) {
+			return nil, errors.New("the ModExp backend returned a batch of the wrong size")
+		}
+		for _, r := range results[i] {
+			if r == nil {
+				return nil, errors.New("the ModExp backend returned a nil result")
+			}
+		}
+	}
+	return results, nil
+}
@@ -9,6 +9,7 @@ package common
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/pkg/errors"
@@ -20,6 +21,11 @@ const (
 
 // MustGetRandomInt panics if it is unable to gather entropy from `rand.Reader` or when `bits` is <= 0
 func MustGetRandomInt(bits int) *big.Int {
+	return MustGetRandomIntFrom(rand.Reader, bits)
+}
+
+// MustGetRandomIntFrom is MustGetRandomInt with the entropy read from `reader`
+func MustGetRandomIntFrom(reader io.Reader, bits int) *big.Int {
 	if bits <= 0 || mustGetRandomIntMaxBits < bits {
 		panic(fmt.Errorf("MustGetRandomInt: bits should be positive, non-zero and less than %d", mustGetRandomIntMaxBits))
 	}
@@ -27,7 +33,7 @@ func MustGetRandomInt(bits int) *big.Int {
 	max := new(big.Int)
 	max = max.Lsh(one, uint(bits))
 	// Generate cryptographically strong pseudo-random int between 0 - (max - 1)
-	n, err := rand.Int(rand.Reader, max)
+	n, err := rand.Int(reader, max)
 	if err != nil {
 		panic(errors.Wrap(err, "rand.Int failure in MustGetRandomInt!"))
 	}
@@ -35,12 +41,17 @@ func MustGetRandomInt(bits int) *big.Int {
 }
 
 func GetRandomPositiveInt(upper *big.Int) *big.Int {
+	return GetRandomPositiveIntFrom(rand.Reader, upper)
+}
+
+// GetRandomPositiveIntFrom is GetRandomPositiveInt with the entropy read from `reader`
+func GetRandomPositiveIntFrom(reader io.Reader, upper *big.Int) *big.Int {
 	if upper == nil || zero.Cmp(upper) != -1 {
 		return nil
 	}
 	var try *big.Int
 	for {
-		try = MustGetRandomInt(upper.BitLen())
+		try = MustGetRandomIntFrom(reader, upper.BitLen())
 		if try.Cmp(upper) < 0 {
 			break
 		}
@@ -69,12 +80,17 @@ func GetRandomPrimeInt(bits int) *big.Int {
 // Generate a random element in the group of all the elements in Z/nZ that
 // has a multiplicative inverse.
 func GetRandomPositiveRelativelyPrimeInt(n *big.Int) *big.Int {
+	return GetRandomPositiveRelativelyPrimeIntFrom(rand.Reader, n)
+}
+
+// GetRandomPositiveRelativelyPrimeIntFrom is GetRandomPositiveRelativelyPrimeInt with the entropy read from `reader`
+func GetRandomPositiveRelativelyPrimeIntFrom(reader io.Reader, n *big.Int) *big.Int {
 	if n == nil || zero.Cmp(n) != -1 {
 		return nil
 	}
 	var try *big.Int
 	for {
-		try = MustGetRandomInt(n.BitLen())
+		try = MustGetRandomIntFrom(reader, n.BitLen())
 		if IsNumberInMultiplicativeGroup(n, try) {
 			break
 		}
@@ -121,7 +121,17 @@ var smallPrimesProduct = new(big.Int).SetUint64(16294579238595022365)
 // This function generates safe primes of at least 6 `bitLen`. For every
 // generated safe prime, the two most significant bits are always set to `1`
 // - we don't want the generated number to be too small.
-func GetRandomSafePrimesConcurrent(bitLen, numPrimes int, timeout time.Duration, concurrency int) ([]*GermainSafePrime, error) {
+//
+// With a goroutine limiter, the search runs in as many goroutines, up to
+// `concurrency`, as its budget has room for, and in at least one.
+func GetRandomSafePrimesConcurrent(bitLen, numPrimes int, timeout time.Duration, concurrency int, optionalLimiter ...*GoroutineLimiter) ([]*GermainSafePrime, error) {
+	return GetRandomSafePrimesConcurrentWithContext(context.Background(), bitLen, numPrimes, timeout, concurrency, optionalLimiter...)
+}
+
+// GetRandomSafePrimesConcurrentWithContext is GetRandomSafePrimesConcurrent
+// that also stops the search when `parent` is done, returning its error once
+// the search goroutines have returned.
+func GetRandomSafePrimesConcurrentWithContext(parent context.Context, bitLen, numPrimes int, timeout time.Duration, concurrency int, optionalLimiter ...*GoroutineLimiter) ([]*GermainSafePrime, error) {
 	if bitLen < 6 {
 		return nil, errors.New("safe prime size must be at least 6 bits")
 	}
@@ -139,21 +149,31 @@ func GetRandomSafePrimesConcurrent(bitLen, numPrimes int, timeout time.Duration,
 	defer close(errCh)
 	defer waitGroup.Wait()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Cancel after the specified timeout.
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
 
+	var limiter *GoroutineLimiter
+	if 0 < len(optionalLimiter) {
+		if 1 < len(optionalLimiter) {
+			panic(errors.New("GetRandomSafePrimesConcurrent: expected 0 or 1 item in `optionalLimiter`"))
+		}
+		limiter = optionalLimiter[0]
+	}
 	for i := 0; i < concurrency; i++ {
 		waitGroup.Add(1)
-		runGenPrimeRoutine(
+		routine := genPrimeRoutine(
 			ctx, primeCh, errCh, waitGroup, rand.Reader, bitLen,
 		)
+		// only the first routine waits for room in the budget, so that the search never waits holding a place in it
+		if i == 0 {
+			limiter.Go(routine)
+		} else if !limiter.TryGo(routine) {
+			waitGroup.Done()
+			break
+		}
 	}
 
-	// Cancel after the specified timeout.
-	go func() {
-		time.Sleep(timeout)
-		cancel()
-	}()
-
 	needed := int32(numPrimes)
 	for {
 		select {
@@ -167,12 +187,15 @@ func GetRandomSafePrimesConcurrent(bitLen, numPrimes int, timeout time.Duration,
 			cancel()
 			return nil, err
 		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return nil, err
+			}
 			return nil, fmt.Errorf("generator timed out after %v", timeout)
 		}
 	}
 }
 
-// Starts a Goroutine searching for a safe prime of the specified `pBitLen`.
+// Returns a routine, to be run in a new Goroutine, searching for a safe prime of the specified `pBitLen`.
 // If succeeds, writes prime `p` and prime `q` such that `p = 2q+1` to the
 // `primeCh`. Prime `p` has a bit length equal to `pBitLen` and prime `q` has
 // a bit length equal to `pBitLen-1`.
@@ -207,14 +230,14 @@ func GetRandomSafePrimesConcurrent(bitLen, numPrimes int, timeout time.Duration,
 //    Miller-Rabin and Baillie-PSW for `p`.
 //    If `q` and `p` are found to be prime, return them as a result. If not, go
 //    back to the point 1.
-func runGenPrimeRoutine(
+func genPrimeRoutine(
 	ctx context.Context,
 	primeCh chan<- *GermainSafePrime,
 	errCh chan<- error,
 	waitGroup *sync.WaitGroup,
 	rand io.Reader,
 	pBitLen int,
-) {
+) func() {
 	qBitLen := pBitLen - 1
 	b := uint(qBitLen % 8)
 	if b == 0 {
@@ -227,7 +250,7 @@ func runGenPrimeRoutine(
 
 	bigMod := new(big.Int)
 
-	go func() {
+	return func() {
 		defer waitGroup.Done()
 
 		for {
@@ -327,7 +350,7 @@ func runGenPrimeRoutine(
 				}
 			}
 		}
-	}()
+	}
 }
 
 // Pocklington's criterion can be used to prove the primality of `p = 2q + 1`
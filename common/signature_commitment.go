@@ -0,0 +1,47 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+)
+
+const (
+	signatureCommitmentDomain   = "tss-lib signature commitment"
+	signatureCommitmentNonceLen = 32
+)
+
+// CommitToSignature returns a hiding commitment to a signature for commit-reveal schemes, with the random nonce that
+// opens it. The commitment may be published before the signature; it reveals nothing of the signature until the nonce
+// and signature are revealed and checked with OpenSignatureCommitment.
+func CommitToSignature(sig *ECSignature) (commitment, nonce []byte, err error) {
+	if sig == nil {
+		return nil, nil, errors.New("CommitToSignature() received a nil signature")
+	}
+	nonce = make([]byte, signatureCommitmentNonceLen)
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return signatureCommitment(nonce, sig), nonce, nil
+}
+
+// OpenSignatureCommitment reports whether the revealed nonce and signature open the commitment made by
+// CommitToSignature. The signature itself should still be verified against the public key.
+func OpenSignatureCommitment(commitment, nonce []byte, sig *ECSignature) bool {
+	if sig == nil || len(nonce) != signatureCommitmentNonceLen {
+		return false
+	}
+	return hmac.Equal(commitment, signatureCommitment(nonce, sig))
+}
+
+func signatureCommitment(nonce []byte, sig *ECSignature) []byte {
+	return SHA512_256(
+		[]byte(signatureCommitmentDomain), nonce,
+		sig.GetSignature(), sig.GetSignatureRecovery(), sig.GetR(), sig.GetS(), sig.GetM())
+}
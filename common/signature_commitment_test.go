@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+)
+
+func TestSignatureCommitment(t *testing.T) {
+	sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	digest := sha256.Sum256([]byte("commit, then reveal"))
+	r, s, err := ecdsa.Sign(rand.Reader, sk, digest[:])
+	if !assert.NoError(t, err) {
+		return
+	}
+	sig := &common.ECSignature{
+		Signature:         append(r.Bytes(), s.Bytes()...),
+		SignatureRecovery: []byte{0},
+		R:                 r.Bytes(),
+		S:                 s.Bytes(),
+		M:                 digest[:],
+	}
+
+	// commit
+	commitment, nonce, err := common.CommitToSignature(sig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	again, _, err := common.CommitToSignature(sig)
+	assert.NoError(t, err)
+	assert.NotEqual(t, commitment, again, "commitments to the same signature must differ by their nonce")
+
+	// reveal and verify
+	assert.True(t, common.OpenSignatureCommitment(commitment, nonce, sig))
+	revealedR, revealedS := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+	assert.True(t, ecdsa.Verify(&sk.PublicKey, sig.M, revealedR, revealedS))
+
+	other := &common.ECSignature{
+		Signature:         sig.Signature,
+		SignatureRecovery: sig.SignatureRecovery,
+		R:                 sig.R,
+		S:                 new(big.Int).Sub(sk.Params().N, s).Bytes(),
+		M:                 sig.M,
+	}
+	assert.False(t, common.OpenSignatureCommitment(commitment, nonce, other), "another signature must not open it")
+	badNonce := append([]byte(nil), nonce...)
+	badNonce[0] ^= 1
+	assert.False(t, common.OpenSignatureCommitment(commitment, badNonce, sig), "another nonce must not open it")
+}
@@ -7,6 +7,7 @@
 package common
 
 import (
+	"fmt"
 	"math/big"
 )
 
@@ -21,6 +22,30 @@ func BigIntsToBytes(bigInts []*big.Int) [][]byte {
 	return bzs
 }
 
+// ByteLen returns the number of bytes that hold any value less than n, e.g. the width of the scalars of a curve of
+// order n
+func ByteLen(n *big.Int) int {
+	return (n.BitLen() + 7) / 8
+}
+
+// PadToLen returns the big-endian encoding of the non-negative x left-padded with zeros to byteLen bytes, so that
+// values of one kind, such as the r and s of a signature, always serialize to the same width. An x that does not fit
+// in byteLen bytes is returned in its minimal encoding, which is longer; it is never truncated.
+func PadToLen(x *big.Int, byteLen int) []byte {
+	if byteLen < len(x.Bytes()) {
+		return x.Bytes()
+	}
+	return x.FillBytes(make([]byte, byteLen))
+}
+
+// UnpadFromLen decodes a value encoded by PadToLen, checking that it is exactly byteLen bytes long
+func UnpadFromLen(bz []byte, byteLen int) (*big.Int, error) {
+	if len(bz) != byteLen {
+		return nil, fmt.Errorf("expected a value of %d bytes, got %d", byteLen, len(bz))
+	}
+	return new(big.Int).SetBytes(bz), nil
+}
+
 func ByteSlicesToBigInts(bytes [][]byte) []*big.Int {
 	ints := make([]*big.Int, len(bytes))
 	for i := range ints {
@@ -61,3 +86,12 @@ func NonEmptyMultiBytes(bzs [][]byte, expectLen ...int) bool {
 	}
 	return true
 }
+
+// ZeroBytes overwrites each of the byte slices with zeros
+func ZeroBytes(bzs ...[]byte) {
+	for _, bz := range bzs {
+		for i := range bz {
+			bz[i] = 0
+		}
+	}
+}
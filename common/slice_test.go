@@ -0,0 +1,44 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+)
+
+func TestPadToLen(t *testing.T) {
+	orderLen := common.ByteLen(btcec.S256().Params().N)
+	assert.Equal(t, 32, orderLen)
+
+	// an r with a leading zero byte
+	r, _ := new(big.Int).SetString("00ff"+"11223344556677889900aabbccddeeff11223344556677889900aabbccdd", 16)
+	assert.Len(t, r.Bytes(), 31)
+	bz := common.PadToLen(r, orderLen)
+	if assert.Len(t, bz, 32) {
+		assert.Equal(t, byte(0), bz[0])
+		assert.Equal(t, r.Bytes(), bz[1:])
+	}
+	back, err := common.UnpadFromLen(bz, orderLen)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, r.Cmp(back))
+
+	assert.Equal(t, make([]byte, 32), common.PadToLen(big.NewInt(0), orderLen))
+	assert.Equal(t, append(make([]byte, 31), 7), common.PadToLen(big.NewInt(7), orderLen))
+
+	// a value too large for the width is not truncated
+	large := new(big.Int).Lsh(big.NewInt(1), 256)
+	assert.Equal(t, large.Bytes(), common.PadToLen(large, orderLen))
+
+	_, err = common.UnpadFromLen(r.Bytes(), orderLen)
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package ecies implements ECIES over the curve of the recipient's key: an ephemeral ECDH key agreement with the
+// recipient's public key, SHA-512/256 as the key derivation function and AES-256-GCM for authenticated encryption.
+// A ciphertext is laid out as: ephemeral public key (X || Y, each padded to (BitSize+7)/8 bytes) || GCM nonce ||
+// sealed message.
+package ecies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+)
+
+var (
+	ErrInvalidCiphertext = errors.New("ecies: invalid ciphertext")
+)
+
+// Encrypt encrypts the plaintext to the public key pk.
+func Encrypt(pk *crypto.ECPoint, plaintext []byte) ([]byte, error) {
+	if pk == nil || !pk.ValidateBasic() {
+		return nil, errors.New("ecies: Encrypt() received a nil or invalid public key")
+	}
+	ec := pk.Curve()
+	r := common.GetRandomPositiveInt(ec.Params().N)
+	R := crypto.ScalarBaseMult(ec, r)
+	shared := pk.ScalarMult(r)
+	aead, err := newAEAD(R, shared)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(pointBytes(R), nonce...)
+	return aead.Seal(out, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt using the private key sk on the curve ec.
+func Decrypt(ec elliptic.Curve, sk *big.Int, ciphertext []byte) ([]byte, error) {
+	if sk == nil {
+		return nil, errors.New("ecies: Decrypt() received a nil private key")
+	}
+	R, err := EphemeralKey(ec, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptWithSharedPoint(ec, R.ScalarMult(sk), ciphertext)
+}
+
+// EphemeralKey returns the ephemeral public key R carried in a ciphertext produced by Encrypt.
+// The recipient's shared point is sk*R; a holder of a sharing of sk can compute it without reconstructing sk.
+func EphemeralKey(ec elliptic.Curve, ciphertext []byte) (*crypto.ECPoint, error) {
+	byteSize := coordSize(ec)
+	if len(ciphertext) < 2*byteSize {
+		return nil, ErrInvalidCiphertext
+	}
+	R, err := crypto.NewECPoint(ec,
+		new(big.Int).SetBytes(ciphertext[:byteSize]),
+		new(big.Int).SetBytes(ciphertext[byteSize:2*byteSize]))
+	if err != nil || !R.IsInPrimeOrderSubgroup() {
+		return nil, ErrInvalidCiphertext
+	}
+	return R, nil
+}
+
+// DecryptWithSharedPoint decrypts a ciphertext produced by Encrypt given the shared point sk*R, where R is its EphemeralKey.
+func DecryptWithSharedPoint(ec elliptic.Curve, shared *crypto.ECPoint, ciphertext []byte) ([]byte, error) {
+	if shared == nil || !shared.ValidateBasic() {
+		return nil, errors.New("ecies: DecryptWithSharedPoint() received a nil or invalid shared point")
+	}
+	R, err := EphemeralKey(ec, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(R, shared)
+	if err != nil {
+		return nil, err
+	}
+	rest := ciphertext[2*coordSize(ec):]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// the key is bound to the ephemeral public key as well as to the shared secret
+func newAEAD(R, shared *crypto.ECPoint) (cipher.AEAD, error) {
+	key := common.SHA512_256(pointBytes(R), shared.X().Bytes())
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// coordSize is the number of bytes of a coordinate of a point on `ec`, rounded up for curves such as P-521 whose bit
+// size is not a multiple of 8
+func coordSize(ec elliptic.Curve) int {
+	return (ec.Params().BitSize + 7) / 8
+}
+
+// pointBytes is X || Y, each padded to coordSize bytes
+func pointBytes(p *crypto.ECPoint) []byte {
+	byteSize := coordSize(p.Curve())
+	bz := make([]byte, 2*byteSize)
+	p.X().FillBytes(bz[:byteSize])
+	p.Y().FillBytes(bz[byteSize:])
+	return bz
+}
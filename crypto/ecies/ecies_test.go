@@ -0,0 +1,70 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package ecies_test
+
+import (
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	. "github.com/ordinox/thorchain-tss-lib/crypto/ecies"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	sk := common.GetRandomPositiveInt(tss.EC().Params().N)
+	pk := crypto.ScalarBaseMult(tss.EC(), sk)
+	msg := []byte("hello world")
+
+	ct, err := Encrypt(pk, msg)
+	assert.NoError(t, err)
+	pt, err := Decrypt(tss.EC(), sk, ct)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	ct2, err := Encrypt(pk, msg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, ct, ct2, "encryption should be randomised")
+}
+
+func TestDecryptFails(t *testing.T) {
+	sk := common.GetRandomPositiveInt(tss.EC().Params().N)
+	pk := crypto.ScalarBaseMult(tss.EC(), sk)
+	ct, err := Encrypt(pk, []byte("hello world"))
+	assert.NoError(t, err)
+
+	otherSK := common.GetRandomPositiveInt(tss.EC().Params().N)
+	_, err = Decrypt(tss.EC(), otherSK, ct)
+	assert.Equal(t, ErrInvalidCiphertext, err, "decryption with the wrong key must fail")
+
+	tampered := append([]byte{}, ct...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = Decrypt(tss.EC(), sk, tampered)
+	assert.Equal(t, ErrInvalidCiphertext, err, "decryption of a tampered ciphertext must fail")
+
+	_, err = Decrypt(tss.EC(), sk, ct[:40])
+	assert.Equal(t, ErrInvalidCiphertext, err, "decryption of a truncated ciphertext must fail")
+}
+
+func TestEncryptDecryptP521(t *testing.T) {
+	// the coordinates of P-521 take 66 bytes, more than BitSize/8
+	ec := elliptic.P521()
+	sk := common.GetRandomPositiveInt(ec.Params().N)
+	pk := crypto.ScalarBaseMult(ec, sk)
+	msg := []byte("hello world")
+
+	ct, err := Encrypt(pk, msg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pt, err := Decrypt(ec, sk, ct)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+}
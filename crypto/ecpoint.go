@@ -51,11 +51,15 @@ func NewECPointNoCurveCheck(curve elliptic.Curve, X, Y *big.Int) *ECPoint {
 	return &ECPoint{curve, [2]*big.Int{X, Y}, 0}
 }
 
-func NewECPointFromProtobuf(p *common.ECPoint) (*ECPoint, error) {
+func NewECPointFromProtobuf(curve elliptic.Curve, p *common.ECPoint) (*ECPoint, error) {
 	if p == nil || p.GetX() == nil || p.GetY() == nil {
 		return nil, errors.New("nil protobuf point provided")
 	}
-	return NewECPoint(tss.EC(), new(big.Int).SetBytes(p.GetX()), new(big.Int).SetBytes(p.GetY()))
+	return NewECPoint(curve, new(big.Int).SetBytes(p.GetX()), new(big.Int).SetBytes(p.GetY()))
+}
+
+func (p *ECPoint) Curve() elliptic.Curve {
+	return p.curve
 }
 
 func (p *ECPoint) X() *big.Int {
@@ -66,6 +70,11 @@ func (p *ECPoint) Y() *big.Int {
 	return new(big.Int).Set(p.coords[1])
 }
 
+// MemSize estimates the heap memory in bytes held by the coordinates of the point; see common.MemSize
+func (p *ECPoint) MemSize() int {
+	return common.MemSize(p.coords[:])
+}
+
 func (p *ECPoint) Add(b *ECPoint) (*ECPoint, error) {
 	x, y := p.curve.Add(p.X(), p.Y(), b.X(), b.Y())
 	return NewECPoint(p.curve, x, y)
@@ -131,6 +140,33 @@ func (p *ECPoint) Bytes() []byte {
 	return append(tmpX, tmpY...)
 }
 
+// BytesCompressed returns the SEC 1 compressed encoding of the point: 0x02 or 0x03 depending on the parity of Y,
+// followed by X padded to the byte size of the curve.
+func (p *ECPoint) BytesCompressed() []byte {
+	byteSize := (p.curve.Params().BitSize + 7) / 8
+	bz := make([]byte, 1+byteSize)
+	bz[0] = 0x02 | byte(p.Y().Bit(0))
+	p.X().FillBytes(bz[1:])
+	return bz
+}
+
+// IsInPrimeOrderSubgroup reports whether the point lies in the prime-order subgroup generated by the base point,
+// by checking that q*P is the identity. On curves with a cofactor of 1 (e.g. secp256k1) this is true of every point.
+// The cofactor is that of the curve's tss.Group, so a custom group set with Parameters.SetGroup is checked as well.
+func (p *ECPoint) IsInPrimeOrderSubgroup() bool {
+	if p == nil || !p.ValidateBasic() {
+		return false
+	}
+	if tss.NewCurveGroup(p.curve).Cofactor().Cmp(big.NewInt(1)) == 0 {
+		return true
+	}
+	// q*G is the identity in whatever coordinates the curve represents it, e.g. (0, 1) on a twisted Edwards curve
+	q := p.curve.Params().N.Bytes()
+	idX, idY := p.curve.ScalarBaseMult(q)
+	x, y := p.curve.ScalarMult(p.X(), p.Y(), q)
+	return x != nil && y != nil && x.Cmp(idX) == 0 && y.Cmp(idY) == 0
+}
+
 func (p *ECPoint) EightInvEight() *ECPoint {
 	return p.ScalarMult(eight).ScalarMult(eightInv)
 }
@@ -235,6 +271,26 @@ func decompressPoint_P256(curve elliptic.Curve, x *big.Int, sign byte) (*ECPoint
 	}, nil
 }
 
+// NewECPointFromBytesCompressed decodes a point produced by BytesCompressed, checking that it lies on the curve.
+func NewECPointFromBytesCompressed(curve elliptic.Curve, bz []byte) (*ECPoint, error) {
+	if curve == nil {
+		return nil, errors.New("NewECPointFromBytesCompressed() received a nil curve")
+	}
+	byteSize := (curve.Params().BitSize + 7) / 8
+	if len(bz) != 1+byteSize || (bz[0] != 0x02 && bz[0] != 0x03) {
+		return nil, errors.New("NewECPointFromBytesCompressed() invalid encoding")
+	}
+	x := new(big.Int).SetBytes(bz[1:])
+	if x.Cmp(curve.Params().P) >= 0 {
+		return nil, errors.New("NewECPointFromBytesCompressed() invalid point")
+	}
+	point, err := DecompressPoint(curve, x, bz[0])
+	if err != nil {
+		return nil, err
+	}
+	return NewECPoint(curve, point.X(), point.Y())
+}
+
 // ----- //
 
 func FlattenECPoints(in []*ECPoint) ([]*big.Int, error) {
@@ -300,6 +356,14 @@ func (p *ECPoint) GobEncode() ([]byte, error) {
 		return nil, err
 	}
 	buf.Write(y)
+	// the curve name is optional so that points encoded by older versions can still be decoded
+	if name, ok := tss.GetCurveName(p.curve); ok {
+		err = binary.Write(buf, binary.LittleEndian, uint32(len(name)))
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(string(name))
+	}
 
 	return buf.Bytes(), nil
 }
@@ -332,7 +396,19 @@ func (p *ECPoint) GobDecode(buf []byte) error {
 	if err := Y.GobDecode(y); err != nil {
 		return err
 	}
-	p.curve = tss.EC()
+	curve := tss.EC()
+	if err := binary.Read(reader, binary.LittleEndian, &length); err == nil {
+		name := make([]byte, length)
+		n, err = reader.Read(name)
+		if n != int(length) || err != nil {
+			return fmt.Errorf("gob decode failed: %v", err)
+		}
+		var ok bool
+		if curve, ok = tss.GetCurveByName(tss.CurveName(name)); !ok {
+			return fmt.Errorf("gob decode failed: unknown curve %q", name)
+		}
+	}
+	p.curve = curve
 	p.coords = [2]*big.Int{X, Y}
 	if !p.IsOnCurve() {
 		return errors.New("ECPoint.UnmarshalJSON: the point is not on the elliptic curve")
@@ -343,22 +419,35 @@ func (p *ECPoint) GobDecode(buf []byte) error {
 // ----- //
 
 // crypto.ECPoint is not inherently json marshal-able
+// The curve is named when it is known, so that points on different curves can be decoded side by side.
 func (p *ECPoint) MarshalJSON() ([]byte, error) {
+	name, _ := tss.GetCurveName(p.curve)
 	return json.Marshal(&struct {
+		Curve  tss.CurveName `json:",omitempty"`
 		Coords [2]*big.Int
 	}{
+		Curve:  name,
 		Coords: p.coords,
 	})
 }
 
+// UnmarshalJSON decodes a point on the curve named in the payload, or on the default tss.EC() when none is named.
 func (p *ECPoint) UnmarshalJSON(payload []byte) error {
 	aux := &struct {
+		Curve  tss.CurveName
 		Coords [2]*big.Int
 	}{}
 	if err := json.Unmarshal(payload, &aux); err != nil {
 		return err
 	}
 	p.curve = tss.EC()
+	if aux.Curve != "" {
+		curve, ok := tss.GetCurveByName(aux.Curve)
+		if !ok {
+			return fmt.Errorf("ECPoint.UnmarshalJSON: unknown curve %q", aux.Curve)
+		}
+		p.curve = curve
+	}
 	p.coords = [2]*big.Int{aux.Coords[0], aux.Coords[1]}
 	if !p.IsOnCurve() {
 		return errors.New("ECPoint.UnmarshalJSON: the point is not on the elliptic curve")
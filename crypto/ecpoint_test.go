@@ -7,10 +7,15 @@
 package crypto_test
 
 import (
+	"crypto/elliptic"
 	"math/big"
 	"reflect"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
 	. "github.com/ordinox/thorchain-tss-lib/crypto"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -113,3 +118,115 @@ func TestUnFlattenECPoints(t *testing.T) {
 		})
 	}
 }
+
+func TestIsInPrimeOrderSubgroup(t *testing.T) {
+	ec := edwards.Edwards()
+	P := ec.Params().P
+
+	G := ScalarBaseMult(ec, big.NewInt(1))
+	assert.True(t, G.IsInPrimeOrderSubgroup(), "the base point is in the subgroup")
+	assert.True(t, ScalarBaseMult(ec, big.NewInt(12345)).IsInPrimeOrderSubgroup(), "a multiple of the base point is in the subgroup")
+
+	// (0, -1) has order 2
+	order2, err := NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(P, big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.False(t, order2.IsInPrimeOrderSubgroup(), "a point of order 2 must be rejected")
+
+	// (sqrt(-1), 0) has order 4
+	sqrtMinusOne := new(big.Int).Exp(big.NewInt(2), new(big.Int).Rsh(new(big.Int).Sub(P, big.NewInt(1)), 2), P)
+	order4, err := NewECPoint(ec, sqrtMinusOne, big.NewInt(0))
+	assert.NoError(t, err)
+	assert.False(t, order4.IsInPrimeOrderSubgroup(), "a point of order 4 must be rejected")
+
+	// a point with a small-order component mixed in
+	mixed, err := G.Add(order2)
+	assert.NoError(t, err)
+	assert.False(t, mixed.IsInPrimeOrderSubgroup(), "a point with a torsion component must be rejected")
+
+	// secp256k1 has a cofactor of 1
+	assert.True(t, ScalarBaseMult(btcec.S256(), big.NewInt(12345)).IsInPrimeOrderSubgroup())
+}
+
+func TestIsInPrimeOrderSubgroupCustomGroup(t *testing.T) {
+	ec := tss.GroupCurve(ed25519Group{edwards.Edwards()})
+	P := ec.Params().P
+
+	G := ScalarBaseMult(ec, big.NewInt(12345))
+	assert.True(t, G.IsInPrimeOrderSubgroup(), "a multiple of the base point is in the subgroup")
+
+	order2, err := NewECPoint(ec, big.NewInt(0), new(big.Int).Sub(P, big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.False(t, order2.IsInPrimeOrderSubgroup(), "a point of order 2 must be rejected on a custom group")
+
+	mixed, err := G.Add(order2)
+	assert.NoError(t, err)
+	assert.False(t, mixed.IsInPrimeOrderSubgroup(), "a point with a torsion component must be rejected on a custom group")
+}
+
+// ed25519Group is a tss.Group that implements ed25519 without being the curve of the edwards package
+type ed25519Group struct {
+	c *edwards.TwistedEdwardsCurve
+}
+
+func (g ed25519Group) Order() *big.Int                         { return g.c.Params().N }
+func (g ed25519Group) Cofactor() *big.Int                      { return big.NewInt(int64(g.c.H)) }
+func (g ed25519Group) Generator() (x, y *big.Int)              { return g.c.Params().Gx, g.c.Params().Gy }
+func (g ed25519Group) FieldOrder() *big.Int                    { return g.c.Params().P }
+func (g ed25519Group) IsOnCurve(x, y *big.Int) bool            { return g.c.IsOnCurve(x, y) }
+func (g ed25519Group) ScalarBaseMult(k []byte) (x, y *big.Int) { return g.c.ScalarBaseMult(k) }
+func (g ed25519Group) ScalarMult(x, y *big.Int, k []byte) (kx, ky *big.Int) {
+	return g.c.ScalarMult(x, y, k)
+}
+func (g ed25519Group) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) { return g.c.Add(x1, y1, x2, y2) }
+
+func TestBytesCompressed(t *testing.T) {
+	for _, ec := range []elliptic.Curve{btcec.S256(), elliptic.P256()} {
+		for _, k := range []int64{1, 2, 3, 12345} {
+			point := ScalarBaseMult(ec, big.NewInt(k))
+			bz := point.BytesCompressed()
+			assert.Equal(t, 33, len(bz))
+			decoded, err := NewECPointFromBytesCompressed(ec, bz)
+			if assert.NoError(t, err) {
+				assert.True(t, point.Equals(decoded))
+			}
+		}
+	}
+
+	ec := btcec.S256()
+	bz := ScalarBaseMult(ec, big.NewInt(1)).BytesCompressed()
+	_, err := NewECPointFromBytesCompressed(ec, bz[:32])
+	assert.Error(t, err, "a truncated encoding must be rejected")
+	bad := append([]byte{0x04}, bz[1:]...)
+	_, err = NewECPointFromBytesCompressed(ec, bad)
+	assert.Error(t, err, "an unknown prefix must be rejected")
+	tooBig := append([]byte{0x02}, ec.Params().P.Bytes()...)
+	_, err = NewECPointFromBytesCompressed(ec, tooBig)
+	assert.Error(t, err, "an X coordinate outside the field must be rejected")
+}
+
+func TestEncodingKeepsCurve(t *testing.T) {
+	for _, ec := range []elliptic.Curve{btcec.S256(), elliptic.P256(), edwards.Edwards()} {
+		point := ScalarBaseMult(ec, big.NewInt(42))
+
+		bz, err := point.MarshalJSON()
+		assert.NoError(t, err)
+		decoded := new(ECPoint)
+		if assert.NoError(t, decoded.UnmarshalJSON(bz)) {
+			assert.Equal(t, ec, decoded.Curve())
+			assert.True(t, point.Equals(decoded))
+		}
+
+		bz, err = point.GobEncode()
+		assert.NoError(t, err)
+		decoded = new(ECPoint)
+		if assert.NoError(t, decoded.GobDecode(bz)) {
+			assert.Equal(t, ec, decoded.Curve())
+			assert.True(t, point.Equals(decoded))
+		}
+	}
+
+	// points encoded without a curve name decode on the default curve
+	decoded := new(ECPoint)
+	assert.NoError(t, decoded.UnmarshalJSON([]byte(`{"Coords":[`+tss.EC().Params().Gx.String()+`,`+tss.EC().Params().Gy.String()+`]}`)))
+	assert.Equal(t, tss.EC(), decoded.Curve())
+}
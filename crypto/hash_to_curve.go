@@ -0,0 +1,179 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"crypto/elliptic"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// HashToCurveSuite is the RFC 9380 suite implemented by HashToCurve
+const HashToCurveSuite = "edwards25519_XMD:SHA-512_ELL2_RO_"
+
+// the constants of edwards25519 and of curve25519, its birationally equivalent Montgomery curve
+var (
+	ed25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	// d = -121665/121666
+	ed25519D = new(big.Int).Mod(new(big.Int).Mul(big.NewInt(-121665), new(big.Int).ModInverse(big.NewInt(121666), ed25519P)), ed25519P)
+	// J of the Montgomery curve t^2 = s^3 + J*s^2 + s
+	curve25519J = big.NewInt(486662)
+	// c1 = sqrt(-486664) with sgn0(c1) == 0, for the rational map to edwards25519
+	ell2C1 = sqrtWithSign(new(big.Int).Mod(big.NewInt(-486664), ed25519P), 0)
+)
+
+// HashToCurve hashes `msg` to a point of the prime-order subgroup of edwards25519 per the RFC 9380 suite
+// edwards25519_XMD:SHA-512_ELL2_RO_, with the domain separation tag `domain`. The point is distributed as a random
+// oracle output, so no one knows its discrete log, and can be mixed into the derivation of EdDSA signing nonces.
+func HashToCurve(curve elliptic.Curve, domain, msg []byte) (*ECPoint, error) {
+	if !isEdwards25519(curve) {
+		return nil, fmt.Errorf("HashToCurve: only edwards25519 is supported, by the suite %s", HashToCurveSuite)
+	}
+	if len(domain) == 0 || 255 < len(domain) {
+		return nil, errors.New("HashToCurve: the domain separation tag must be 1 to 255 bytes long")
+	}
+	// hash_to_field with count = 2, m = 1 and L = ceil((ceil(log2(p)) + k) / 8) = 48 for k = 128
+	const L = 48
+	uniform, err := expandMessageXMD(msg, domain, 2*L)
+	if err != nil {
+		return nil, err
+	}
+	u0 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[:L]), ed25519P)
+	u1 := new(big.Int).Mod(new(big.Int).SetBytes(uniform[L:]), ed25519P)
+
+	x0, y0 := mapToCurveElligator2Edwards25519(u0)
+	x1, y1 := mapToCurveElligator2Edwards25519(u1)
+	x, y := edwards25519Add(x0, y0, x1, y1)
+	// clear_cofactor: multiply by h = 8
+	for i := 0; i < 3; i++ {
+		x, y = edwards25519Add(x, y, x, y)
+	}
+	return NewECPoint(curve, x, y)
+}
+
+func isEdwards25519(curve elliptic.Curve) bool {
+	if curve == nil {
+		return false
+	}
+	params, ed := curve.Params(), edwards.Edwards().Params()
+	return params.P.Cmp(ed.P) == 0 && params.N.Cmp(ed.N) == 0 && params.Gx.Cmp(ed.Gx) == 0 && params.Gy.Cmp(ed.Gy) == 0
+}
+
+// expandMessageXMD is expand_message_xmd of RFC 9380 section 5.3.1 with SHA-512
+func expandMessageXMD(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	const bInBytes, sInBytes = sha512.Size, sha512.BlockSize
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if 255 < ell || 65535 < lenInBytes || 255 < len(dst) {
+		return nil, errors.New("expand_message_xmd: the requested length or the DST is too long")
+	}
+	dstPrime := append(append([]byte(nil), dst...), byte(len(dst)))
+
+	h := sha512.New()
+	h.Write(make([]byte, sInBytes)) // Z_pad
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	uniform := make([]byte, 0, ell*bInBytes)
+	bi := make([]byte, bInBytes)
+	for i := 1; i <= ell; i++ {
+		// b_1 = H(b_0 || 1 || DST_prime), b_i = H(strxor(b_0, b_(i-1)) || i || DST_prime)
+		for k := range bi {
+			bi[k] ^= b0[k]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		uniform = append(uniform, bi...)
+	}
+	return uniform[:lenInBytes], nil
+}
+
+// mapToCurveElligator2Edwards25519 maps a field element to edwards25519 by the Elligator 2 map to curve25519 (RFC 9380
+// section 6.7.1 with Z = 2) followed by the rational map of appendix D.1
+func mapToCurveElligator2Edwards25519(u *big.Int) (x, y *big.Int) {
+	p := ed25519P
+	modP := func(v *big.Int) *big.Int { return v.Mod(v, p) }
+	negJ := new(big.Int).Sub(p, curve25519J)
+	// g(x) = x^3 + J*x^2 + x, as K = 1
+	g := func(v *big.Int) *big.Int {
+		v2 := modP(new(big.Int).Mul(v, v))
+		gv := modP(new(big.Int).Mul(v2, v))
+		gv.Add(gv, new(big.Int).Mul(curve25519J, v2))
+		gv.Add(gv, v)
+		return modP(gv)
+	}
+
+	// 1-2. x1 = -J * inv0(1 + Z * u^2), or -J if that is 0
+	tv := modP(new(big.Int).Mul(u, u))
+	tv.Lsh(tv, 1).Add(tv, big.NewInt(1))
+	x1 := modP(new(big.Int).Mul(negJ, inv0(modP(tv))))
+	if x1.Sign() == 0 {
+		x1.Set(negJ)
+	}
+	// 3-7.
+	var s, t *big.Int
+	if gx1 := g(x1); isSquare(gx1) {
+		s, t = x1, sqrtWithSign(gx1, 1)
+	} else {
+		x2 := modP(new(big.Int).Sub(negJ, x1))
+		s, t = x2, sqrtWithSign(g(x2), 0)
+	}
+
+	// the rational map: x = c1 * s / t, y = (s - 1) / (s + 1), or the identity where either is undefined
+	sPlus1 := modP(new(big.Int).Add(s, big.NewInt(1)))
+	if t.Sign() == 0 || sPlus1.Sign() == 0 {
+		return big.NewInt(0), big.NewInt(1)
+	}
+	x = modP(new(big.Int).Mul(ell2C1, s))
+	x = modP(x.Mul(x, inv0(t)))
+	y = modP(new(big.Int).Sub(s, big.NewInt(1)))
+	y = modP(y.Mul(y, inv0(sPlus1)))
+	return x, y
+}
+
+// edwards25519Add adds two points with the complete addition law of the twisted Edwards curve -x^2 + y^2 = 1 + d*x^2*y^2
+func edwards25519Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	p := ed25519P
+	x1y2 := new(big.Int).Mul(x1, y2)
+	y1x2 := new(big.Int).Mul(y1, x2)
+	y1y2 := new(big.Int).Mul(y1, y2)
+	x1x2 := new(big.Int).Mul(x1, x2)
+	dxxyy := new(big.Int).Mul(ed25519D, new(big.Int).Mod(new(big.Int).Mul(x1x2, y1y2), p))
+	dxxyy.Mod(dxxyy, p)
+
+	x = new(big.Int).Add(x1y2, y1x2)
+	x.Mul(x, inv0(new(big.Int).Mod(new(big.Int).Add(big.NewInt(1), dxxyy), p)))
+	y = new(big.Int).Add(y1y2, x1x2)
+	y.Mul(y, inv0(new(big.Int).Mod(new(big.Int).Sub(big.NewInt(1), dxxyy), p)))
+	return x.Mod(x, p), y.Mod(y, p)
+}
+
+// inv0 is the inverse mod p, mapping 0 to 0
+func inv0(v *big.Int) *big.Int {
+	return new(big.Int).Exp(v, new(big.Int).Sub(ed25519P, big.NewInt(2)), ed25519P)
+}
+
+func isSquare(v *big.Int) bool {
+	return big.Jacobi(v, ed25519P) >= 0
+}
+
+// sqrtWithSign returns the square root of a square mod p whose sgn0, its parity, is `sign`
+func sqrtWithSign(v *big.Int, sign uint) *big.Int {
+	root := new(big.Int).ModSqrt(v, ed25519P)
+	if root.Bit(0) != sign {
+		root.Sub(ed25519P, root).Mod(root, ed25519P)
+	}
+	return root
+}
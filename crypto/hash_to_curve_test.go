@@ -0,0 +1,55 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/crypto"
+)
+
+// the known-answer vectors of RFC 9380 appendix J.5.1
+func TestHashToCurveRFC9380Vectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_")
+	vectors := []struct {
+		msg, x, y string
+	}{
+		{"",
+			"3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6",
+			"09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21"},
+		{"abc",
+			"608040b42285cc0d72cbb3985c6b04c935370c7361f4b7fbdb1ae7f8c1a8ecad",
+			"1a8395b88338f22e435bbd301183e7f20a5f9de643f11882fb237f88268a5531"},
+		{"abcdef0123456789",
+			"6d7fabf47a2dc03fe7d47f7dddd21082c5fb8f86743cd020f3fb147d57161472",
+			"53060a3d140e7fbcda641ed3cf42c88a75411e648a1add71217f70ea8ec561a6"},
+	}
+	for _, v := range vectors {
+		t.Run(fmt.Sprintf("msg=%q", v.msg), func(t *testing.T) {
+			P, err := HashToCurve(edwards.Edwards(), dst, []byte(v.msg))
+			if !assert.NoError(t, err) {
+				return
+			}
+			x, _ := new(big.Int).SetString(v.x, 16)
+			y, _ := new(big.Int).SetString(v.y, 16)
+			assert.Equal(t, 0, x.Cmp(P.X()), "P.x")
+			assert.Equal(t, 0, y.Cmp(P.Y()), "P.y")
+			assert.True(t, P.IsInPrimeOrderSubgroup())
+		})
+	}
+
+	_, err := HashToCurve(btcec.S256(), dst, []byte("abc"))
+	assert.Error(t, err, "only edwards25519 is supported")
+	_, err = HashToCurve(edwards.Edwards(), nil, []byte("abc"))
+	assert.Error(t, err, "an empty domain separation tag must be rejected")
+}
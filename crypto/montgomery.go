@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+)
+
+// X25519KeyLen is the length of an X25519 public key, the little-endian u-coordinate of a curve25519 point
+const X25519KeyLen = 32
+
+// EdwardsToMontgomery maps an edwards25519 point, such as an EdDSA public key, to the X25519 public key of the same
+// key pair by the birational map u = (1 + y) / (1 - y) of RFC 7748 section 4.1
+func EdwardsToMontgomery(p *ECPoint) ([]byte, error) {
+	if p == nil || !isEdwards25519(p.Curve()) {
+		return nil, errors.New("EdwardsToMontgomery: expected a point of edwards25519")
+	}
+	oneMinusY := new(big.Int).Sub(big.NewInt(1), p.Y())
+	if oneMinusY.Mod(oneMinusY, ed25519P).Sign() == 0 {
+		return nil, errors.New("EdwardsToMontgomery: the identity has no X25519 public key")
+	}
+	u := new(big.Int).Add(big.NewInt(1), p.Y())
+	u.Mul(u, inv0(oneMinusY)).Mod(u, ed25519P)
+
+	bz := make([]byte, X25519KeyLen)
+	u.FillBytes(bz)
+	reverseBytes(bz)
+	return bz, nil
+}
+
+// MontgomeryToEdwards maps an X25519 public key to the edwards25519 point on `curve` by the birational map
+// y = (u - 1) / (u + 1). The u-coordinate does not determine the sign of x, so the point whose x has parity `sign`
+// is returned, as the sign bit of an Ed25519 public key would select.
+func MontgomeryToEdwards(curve elliptic.Curve, x25519Key []byte, sign uint) (*ECPoint, error) {
+	if !isEdwards25519(curve) {
+		return nil, errors.New("MontgomeryToEdwards: expected edwards25519")
+	}
+	if len(x25519Key) != X25519KeyLen || 1 < sign {
+		return nil, errors.New("MontgomeryToEdwards: expected a 32-byte key and a sign of 0 or 1")
+	}
+	bz := append([]byte(nil), x25519Key...)
+	reverseBytes(bz)
+	bz[0] &= 0x7f // the most significant bit is masked, as in RFC 7748 section 5
+	u := new(big.Int).SetBytes(bz)
+	u.Mod(u, ed25519P)
+
+	uPlusOne := new(big.Int).Add(u, big.NewInt(1))
+	if uPlusOne.Mod(uPlusOne, ed25519P).Sign() == 0 {
+		return nil, errors.New("MontgomeryToEdwards: u = -1 has no edwards25519 point")
+	}
+	y := new(big.Int).Sub(u, big.NewInt(1))
+	y.Mul(y, inv0(uPlusOne)).Mod(y, ed25519P)
+
+	// -x^2 + y^2 = 1 + d*x^2*y^2, so x^2 = (y^2 - 1) / (d*y^2 + 1)
+	y2 := new(big.Int).Mul(y, y)
+	num := new(big.Int).Sub(y2, big.NewInt(1))
+	den := new(big.Int).Mul(ed25519D, y2)
+	den.Add(den, big.NewInt(1)).Mod(den, ed25519P)
+	x2 := num.Mul(num, inv0(den)).Mod(num, ed25519P)
+	if !isSquare(x2) {
+		return nil, errors.New("MontgomeryToEdwards: the key is a point of the twist of curve25519")
+	}
+	if x2.Sign() == 0 && sign == 1 {
+		return nil, errors.New("MontgomeryToEdwards: x = 0 has no negative form")
+	}
+	return NewECPoint(curve, sqrtWithSign(x2, sign), y)
+}
+
+func reverseBytes(bz []byte) {
+	for i, j := 0, len(bz)-1; i < j; i, j = i+1, j-1 {
+		bz[i], bz[j] = bz[j], bz[i]
+	}
+}
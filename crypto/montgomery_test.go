@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package crypto_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/crypto"
+)
+
+// the public keys of the RFC 8032 section 7.1 tests 1 and 2, with the X25519 public keys of their secret scalars
+func TestEdwardsToMontgomery(t *testing.T) {
+	vectors := []struct {
+		ed25519, x25519 string
+	}{
+		{"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+			"d85e07ec22b0ad881537c2f44d662d1a143cf830c57aca4305d85c7a90f6b62e"},
+		{"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+			"25c704c594b88afc00a76b69d1ed2b984d7e22550f3ed0802d04fbcd07d38d47"},
+	}
+	ec := edwards.Edwards()
+	for _, v := range vectors {
+		bz, _ := hex.DecodeString(v.ed25519)
+		pk, err := edwards.ParsePubKey(bz)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		P, err := NewECPoint(ec, pk.X, pk.Y)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		u, err := EdwardsToMontgomery(P)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, v.x25519, hex.EncodeToString(u))
+
+		// the map back recovers the point given the sign of x, which is the top bit of the Ed25519 key
+		back, err := MontgomeryToEdwards(ec, u, uint(bz[31]>>7))
+		if assert.NoError(t, err) {
+			assert.True(t, back.Equals(P))
+		}
+		neg, err := MontgomeryToEdwards(ec, u, 1-uint(bz[31]>>7))
+		if assert.NoError(t, err) {
+			negX := new(big.Int).Sub(ec.Params().P, P.X())
+			assert.Equal(t, negX, neg.X())
+			assert.Equal(t, P.Y(), neg.Y())
+		}
+	}
+
+	_, err := EdwardsToMontgomery(ScalarBaseMult(btcec.S256(), big.NewInt(1)))
+	assert.Error(t, err, "only edwards25519 points have an X25519 key")
+	_, err = EdwardsToMontgomery(NewECPointNoCurveCheck(ec, big.NewInt(0), big.NewInt(1)))
+	assert.Error(t, err, "the identity has no X25519 key")
+	_, err = MontgomeryToEdwards(ec, make([]byte, 31), 0)
+	assert.Error(t, err)
+}
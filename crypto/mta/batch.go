@@ -0,0 +1,176 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mta
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+)
+
+// the random weights of the combined checks have this many bits, which bounds the chance that a bad proof passes them
+const batchWeightBits = 128
+
+// ProofBobWCBatchItem is a proof given to BatchVerifyProofBobWC with its public inputs: the ciphertexts c1 and c2 and,
+// for a proof with check, the point X
+type ProofBobWCBatchItem struct {
+	Proof  *ProofBobWC
+	C1, C2 *big.Int
+	X      *crypto.ECPoint
+}
+
+// BatchVerifyProofBobWC verifies proofs that were all made for the same Paillier key and NTilde, h1, h2, as the proofs
+// that Alice receives from her counterparties in signing, and reports for each item whether its proof verified.
+//
+// The checks of each proof's values, its steps 3 and 4 and its equalities of steps 5-6 mod NTilde are run one by one.
+// The equalities of step 7 mod N^2 are checked for all the proofs at once, each raised to a random weight, so that
+// s^N is computed once rather than once per proof. If the combined check fails, step 7 is checked for each proof on
+// its own, so that the culprits are still identified. The combination is sound because N^2 is the verifier's own
+// modulus: the only elements of small order that a prover can produce without its factors are 1 and -1, so the
+// combined check holds up to sign exactly when the check of every proof does. Step 7 is therefore accepted up to sign
+// in a batch, unlike Verify, which checks it exactly: a proof whose equality holds only with -1 is the valid proof with
+// N-s for s, which is not hashed into the challenge, so accepting it admits no statement that could not be proven
+// anyway. The equalities mod NTilde are not combined, as they are checked exactly and a weighted product could not tell
+// an even number of proofs that are off by -1 from valid ones.
+func BatchVerifyProofBobWC(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, items []ProofBobWCBatchItem, optionalBackend ...common.ModExpBackend) []bool {
+	return BatchVerifyProofBobWCWithHash(ec, nil, pk, NTilde, h1, h2, items, optionalBackend...)
+}
+
+// BatchVerifyProofBobWCWithHash is BatchVerifyProofBobWC for proofs whose challenges were derived with the hash made by
+// `newHash`, or SHA-512/256 if it is nil (see ProveBobWCWithHash)
+func BatchVerifyProofBobWCWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, items []ProofBobWCBatchItem, optionalBackend ...common.ModExpBackend) []bool {
+	verified := make([]bool, len(items))
+	backend := modExpBackend(optionalBackend)
+
+	es := make([]*big.Int, len(items))
+	batch := make([]int, 0, len(items))
+	for k, item := range items {
+		e, err := item.Proof.checkValuesAndChallenge(ec, newHash, pk, NTilde, h1, h2, item.C1, item.C2, item.X)
+		if err != nil || item.Proof.checkNTildeRelations(proofFig(item.X), backend, e, NTilde, h1, h2) != nil {
+			continue
+		}
+		es[k] = e
+		batch = append(batch, k)
+	}
+	if 1 < len(batch) && checkCombined(backend, pk, items, es, batch) == nil {
+		for _, k := range batch {
+			verified[k] = true
+		}
+		return verified
+	}
+	for _, k := range batch {
+		item := items[k]
+		verified[k] = item.Proof.checkCiphertextRelation(proofFig(item.X), backend, es[k], pk, item.C1, item.C2, true) == nil
+	}
+	return verified
+}
+
+// BatchVerifyBobWC verifies proofs[k] against pks[k], NTildes[k], h1s[k], h2s[k], c1s[k], c2s[k] and Xs[k], and returns
+// the index of the first proof that fails, or -1 if they all verify. The proofs that share a Paillier key and NTilde,
+// h1, h2 are verified together by BatchVerifyProofBobWC; combining proofs made for different moduli would not be sound.
+func BatchVerifyBobWC(ec elliptic.Curve, pks []*paillier.PublicKey, NTildes, h1s, h2s, c1s, c2s []*big.Int, Xs []*crypto.ECPoint, proofs []*ProofBobWC, optionalBackend ...common.ModExpBackend) (int, error) {
+	return BatchVerifyBobWCWithHash(ec, nil, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs, optionalBackend...)
+}
+
+// BatchVerifyBobWCWithHash is BatchVerifyBobWC for proofs whose challenges were derived with the hash made by
+// `newHash`, or SHA-512/256 if it is nil
+func BatchVerifyBobWCWithHash(ec elliptic.Curve, newHash func() hash.Hash, pks []*paillier.PublicKey, NTildes, h1s, h2s, c1s, c2s []*big.Int, Xs []*crypto.ECPoint, proofs []*ProofBobWC, optionalBackend ...common.ModExpBackend) (int, error) {
+	n := len(proofs)
+	if len(pks) != n || len(NTildes) != n || len(h1s) != n || len(h2s) != n || len(c1s) != n || len(c2s) != n || len(Xs) != n {
+		return -1, errors.New("BatchVerifyBobWC() expects one of each input per proof")
+	}
+	first := -1
+	grouped := make([]bool, n)
+	for k := range proofs {
+		// the proofs that are left all come after k
+		if first != -1 && first < k {
+			return first, nil
+		}
+		if grouped[k] {
+			continue
+		}
+		if pks[k] == nil || NTildes[k] == nil || h1s[k] == nil || h2s[k] == nil {
+			return k, nil
+		}
+		var indices []int
+		var items []ProofBobWCBatchItem
+		for l := k; l < n; l++ {
+			if grouped[l] || pks[l] == nil || pks[l].N.Cmp(pks[k].N) != 0 ||
+				NTildes[l] == nil || NTildes[l].Cmp(NTildes[k]) != 0 ||
+				h1s[l] == nil || h1s[l].Cmp(h1s[k]) != 0 || h2s[l] == nil || h2s[l].Cmp(h2s[k]) != 0 {
+				continue
+			}
+			grouped[l] = true
+			indices = append(indices, l)
+			items = append(items, ProofBobWCBatchItem{Proof: proofs[l], C1: c1s[l], C2: c2s[l], X: Xs[l]})
+		}
+		for m, ok := range BatchVerifyProofBobWCWithHash(ec, newHash, pks[k], NTildes[k], h1s[k], h2s[k], items, optionalBackend...) {
+			if !ok && (first == -1 || indices[m] < first) {
+				first = indices[m]
+			}
+		}
+	}
+	return first, nil
+}
+
+// proofFig is the figure of GG18Spec (9) whose proof is verified: 10 with the check of X, 11 without
+func proofFig(X *crypto.ECPoint) int {
+	if X == nil {
+		return 11
+	}
+	return 10
+}
+
+// checkCombined checks step 7 of the proofs of `batch` weighted by random rho_k, up to sign:
+//
+//	Gamma^(sum rho*t1) * (prod s^rho)^N * prod c1^(rho*s1) = +-prod c2^(rho*e) * v^rho mod N^2
+func checkCombined(backend common.ModExpBackend, pk *paillier.PublicKey, items []ProofBobWCBatchItem, es []*big.Int, batch []int) error {
+	NSq := pk.NSquare()
+	weightBound := new(big.Int).Lsh(one, batchWeightBits)
+	gammaExp := new(big.Int)
+	nSqBases, nSqExps := make([]*big.Int, 0, 3*len(batch)), make([]*big.Int, 0, 3*len(batch))
+	c1Bases, c1Exps := make([]*big.Int, 0, len(batch)), make([]*big.Int, 0, len(batch))
+	for _, k := range batch {
+		pf, e := items[k].Proof, es[k]
+		rho := common.GetRandomPositiveInt(weightBound)
+		gammaExp.Add(gammaExp, new(big.Int).Mul(rho, pf.T1))
+		nSqBases = append(nSqBases, pf.S, items[k].C2, pf.V)
+		nSqExps = append(nSqExps, rho, new(big.Int).Mul(rho, e), rho)
+		c1Bases = append(c1Bases, items[k].C1)
+		c1Exps = append(c1Exps, new(big.Int).Mul(rho, pf.S1))
+	}
+
+	exps, err := common.BatchModExp(backend, NSq,
+		[][]*big.Int{nSqBases, c1Bases, {pk.Gamma()}},
+		[][]*big.Int{nSqExps, c1Exps, {gammaExp}})
+	if err != nil {
+		return err
+	}
+	modNSq := common.ModInt(NSq)
+	sProd, right := big.NewInt(1), big.NewInt(1)
+	for i := 0; i < len(exps[0]); i += 3 {
+		sProd = modNSq.Mul(sProd, exps[0][i])
+		right = modNSq.Mul(right, modNSq.Mul(exps[0][i+1], exps[0][i+2]))
+	}
+	sProdExpN, err := common.BatchModExp(backend, NSq, [][]*big.Int{{sProd}}, [][]*big.Int{{pk.N}})
+	if err != nil {
+		return err
+	}
+	left := modNSq.Mul(exps[2][0], sProdExpN[0][0])
+	for _, x := range exps[1] {
+		left = modNSq.Mul(left, x)
+	}
+	if left.Cmp(right) != 0 && left.Cmp(new(big.Int).Sub(NSq, right)) != 0 {
+		return errors.New("the combined check of step 7 failed")
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mta
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// batchItems makes the proofs of `n` counterparties for Alice, whose Paillier key and NTilde, h1, h2 are those of the
+// test fixture `alice`
+func batchItems(n, alice int) (pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, items []ProofBobWCBatchItem, err error) {
+	return batchItemsWithHash(n, alice, nil)
+}
+
+// batchItemsWithHash is batchItems with the challenges derived with the hash made by `newHash`
+func batchItemsWithHash(n, alice int, newHash func() hash.Hash) (pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, items []ProofBobWCBatchItem, err error) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(alice + 2)
+	if err != nil {
+		return
+	}
+	ec, q := tss.EC(), tss.EC().Params().N
+	pk = &keys[alice].PaillierSK.PublicKey
+	NTilde, h1, h2 = keys[alice].NTildei, keys[alice].H1i, keys[alice].H2i
+	NTildeB, h1B, h2B := keys[alice+1].NTildei, keys[alice+1].H1i, keys[alice+1].H2i
+
+	a := common.GetRandomPositiveInt(q)
+	cA, rA, err := pk.EncryptAndReturnRandomness(a)
+	if err != nil {
+		return
+	}
+	pfA, err := AliceInit(ec, pk, a, cA, rA, NTildeB, h1B, h2B)
+	if err != nil {
+		return
+	}
+	items = make([]ProofBobWCBatchItem, n)
+	for k := range items {
+		b := common.GetRandomPositiveInt(q)
+		gB := crypto.ScalarBaseMult(ec, b)
+		var cB *big.Int
+		var pfB *ProofBobWC
+		if _, cB, pfB, err = BobMidWCWithHash(ec, newHash, pk, pfA, b, cA, NTilde, h1, h2, NTildeB, h1B, h2B, gB); err != nil {
+			return
+		}
+		items[k] = ProofBobWCBatchItem{Proof: pfB, C1: cA, C2: cB, X: gB}
+	}
+	return
+}
+
+func TestBatchVerifyProofBobWC(t *testing.T) {
+	ec := tss.EC()
+	pk, NTilde, h1, h2, items, err := batchItems(6, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []bool{true, true, true, true, true, true}, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items))
+	assert.Empty(t, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, nil))
+
+	// the culprits are identified: one proof fails step 5, one fails step 7, which only the combined check sees, and
+	// one fails the range check of step 3
+	tamper := func(k int, tamper func(pf *ProofBob)) {
+		values := *items[k].Proof.ProofBob
+		tamper(&values)
+		items[k].Proof = &ProofBobWC{ProofBob: &values, U: items[k].Proof.U}
+	}
+	tamper(1, func(pf *ProofBob) { pf.S2 = new(big.Int).Add(pf.S2, big.NewInt(1)) })
+	tamper(3, func(pf *ProofBob) { pf.S = common.ModInt(pk.N).Mul(pf.S, big.NewInt(2)) })
+	tamper(4, func(pf *ProofBob) { pf.T1 = new(big.Int).Add(tss.CurvePowersOf(ec).Q7, big.NewInt(1)) })
+	expected := []bool{true, false, true, false, false, true}
+	assert.Equal(t, expected, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items))
+	for k, item := range items {
+		assert.Equal(t, expected[k], item.Proof.Verify(ec, pk, NTilde, h1, h2, item.C1, item.C2, item.X),
+			"the batch should agree with Verify on proof %d", k)
+	}
+
+	// a proof paired with the wrong ciphertext fails too
+	items[0].C2, items[2].C2 = items[2].C2, items[0].C2
+	assert.Equal(t, []bool{false, false, false, false, false, true}, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items))
+}
+
+func TestBatchVerifyProofBobWCSignFlips(t *testing.T) {
+	ec := tss.EC()
+	pk, NTilde, h1, h2, items, err := batchItems(4, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// N-s is still a unit mod N, and (N-s)^N = -s^N mod N^2 turns step 7 by -1: Verify rejects the proof, and the batch
+	// accepts it, as it is the valid proof with s
+	for _, k := range []int{2, 3} {
+		values := *items[k].Proof.ProofBob
+		values.S = new(big.Int).Sub(pk.N, values.S)
+		items[k].Proof = &ProofBobWC{ProofBob: &values, U: items[k].Proof.U}
+	}
+	// two proofs whose step 5 holds only up to sign, which would cancel out in a weighted product mod NTilde
+	cA := items[0].C1
+	for k := 0; k < 2; k++ {
+		x, y := common.GetRandomPositiveInt(ec.Params().N), common.GetRandomPositiveInt(ec.Params().N)
+		cY, r, err := pk.EncryptAndReturnRandomness(y)
+		if !assert.NoError(t, err) {
+			return
+		}
+		cB, err := pk.HomoMult(x, cA)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if cB, err = pk.HomoAdd(cB, cY); !assert.NoError(t, err) {
+			return
+		}
+		X := crypto.ScalarBaseMult(ec, x)
+		items = append(items, ProofBobWCBatchItem{
+			Proof: proveBobWCWithNegatedZPrm(ec, pk, NTilde, h1, h2, cA, cB, x, y, r, X), C1: cA, C2: cB, X: X,
+		})
+	}
+
+	verified := []bool{true, true, false, false, false, false}
+	for k, item := range items {
+		assert.Equal(t, verified[k], item.Proof.Verify(ec, pk, NTilde, h1, h2, item.C1, item.C2, item.X), "proof %d", k)
+	}
+	batched := []bool{true, true, true, true, false, false}
+	for i := 0; i < 8; i++ {
+		assert.Equal(t, batched, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items),
+			"the batch should accept step 7 up to sign and step 5 only exactly")
+	}
+
+	// a proof that fails step 7 makes the batch check each proof on its own, still up to sign
+	values := *items[1].Proof.ProofBob
+	values.V = new(big.Int).Add(values.V, big.NewInt(1))
+	items[1].Proof = &ProofBobWC{ProofBob: &values, U: items[1].Proof.U}
+	assert.Equal(t, []bool{true, false, true, true, false, false}, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items))
+}
+
+// proveBobWCWithNegatedZPrm is ProveBobWC by a prover that sends -z' in place of z', so that step 5 holds only up to
+// sign
+func proveBobWCWithNegatedZPrm(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint) *ProofBobWC {
+	powers := tss.CurvePowersOf(ec)
+	q, q3, q7 := powers.Q, powers.Q3, powers.Q7
+	qNTilde, q3NTilde := new(big.Int).Mul(q, NTilde), new(big.Int).Mul(q3, NTilde)
+	alpha := common.GetRandomPositiveInt(q3)
+	rho, sigma, tau := common.GetRandomPositiveInt(qNTilde), common.GetRandomPositiveInt(qNTilde), common.GetRandomPositiveInt(q3NTilde)
+	rhoPrm := common.GetRandomPositiveInt(q3NTilde)
+	beta, gamma := common.GetRandomPositiveRelativelyPrimeInt(pk.N), common.GetRandomPositiveInt(q7)
+
+	modNTilde, modNSq, modN := common.ModInt(NTilde), common.ModInt(pk.NSquare()), common.ModInt(pk.N)
+	u := crypto.ScalarBaseMult(ec, alpha)
+	z := modNTilde.Mul(modNTilde.Exp(h1, x), modNTilde.Exp(h2, rho))
+	zPrm := modNTilde.Mul(modNTilde.Exp(h1, alpha), modNTilde.Exp(h2, rhoPrm))
+	zPrm.Sub(NTilde, zPrm)
+	t := modNTilde.Mul(modNTilde.Exp(h1, y), modNTilde.Exp(h2, sigma))
+	v := modNSq.Mul(modNSq.Mul(modNSq.Exp(c1, alpha), modNSq.Exp(pk.Gamma(), gamma)), modNSq.Exp(beta, pk.N))
+	w := modNTilde.Mul(modNTilde.Exp(h1, gamma), modNTilde.Exp(h2, tau))
+	e := common.RejectionSample(q, common.HashInts(challengeHash(nil),
+		append(pk.AsInts(), X.X(), X.Y(), c1, c2, u.X(), u.Y(), z, zPrm, t, v, w)...))
+
+	pf := &ProofBob{
+		Z: z, ZPrm: zPrm, T: t, V: v, W: w,
+		S:  modN.Mul(modN.Exp(r, e), beta),
+		S1: new(big.Int).Add(new(big.Int).Mul(e, x), alpha),
+		S2: new(big.Int).Add(new(big.Int).Mul(e, rho), rhoPrm),
+		T1: new(big.Int).Add(new(big.Int).Mul(e, y), gamma),
+		T2: new(big.Int).Add(new(big.Int).Mul(e, sigma), tau),
+	}
+	return &ProofBobWC{ProofBob: pf, U: u}
+}
+
+func TestBatchVerifyProofBobWCWithHash(t *testing.T) {
+	ec := tss.EC()
+	pk, NTilde, h1, h2, items, err := batchItemsWithHash(3, 0, sha256.New)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []bool{true, true, true}, BatchVerifyProofBobWCWithHash(ec, sha256.New, pk, NTilde, h1, h2, items))
+	assert.Equal(t, []bool{false, false, false}, BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items),
+		"the default hash must reject SHA-256 proofs")
+
+	pks := []*paillier.PublicKey{pk, pk, pk}
+	NTildes, h1s, h2s := []*big.Int{NTilde, NTilde, NTilde}, []*big.Int{h1, h1, h1}, []*big.Int{h2, h2, h2}
+	var c1s, c2s []*big.Int
+	var Xs []*crypto.ECPoint
+	var proofs []*ProofBobWC
+	for _, item := range items {
+		c1s, c2s, Xs, proofs = append(c1s, item.C1), append(c2s, item.C2), append(Xs, item.X), append(proofs, item.Proof)
+	}
+	first, err := BatchVerifyBobWCWithHash(ec, sha256.New, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, first)
+	first, err = BatchVerifyBobWC(ec, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, first)
+}
+
+func TestBatchVerifyBobWC(t *testing.T) {
+	ec := tss.EC()
+	pk, NTilde, h1, h2, items, err := batchItems(4, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pkB, NTildeB, h1B, h2B, itemsB, err := batchItems(2, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the proofs received by two parties, which were made for different moduli, interleaved
+	var pks []*paillier.PublicKey
+	var NTildes, h1s, h2s, c1s, c2s []*big.Int
+	var Xs []*crypto.ECPoint
+	var proofs []*ProofBobWC
+	add := func(pk *paillier.PublicKey, NTilde, h1, h2 *big.Int, item ProofBobWCBatchItem) {
+		pks, NTildes, h1s, h2s = append(pks, pk), append(NTildes, NTilde), append(h1s, h1), append(h2s, h2)
+		c1s, c2s, Xs, proofs = append(c1s, item.C1), append(c2s, item.C2), append(Xs, item.X), append(proofs, item.Proof)
+	}
+	add(pk, NTilde, h1, h2, items[0])
+	add(pkB, NTildeB, h1B, h2B, itemsB[0])
+	add(pk, NTilde, h1, h2, items[1])
+	add(pk, NTilde, h1, h2, items[2])
+	add(pkB, NTildeB, h1B, h2B, itemsB[1])
+	add(pk, NTilde, h1, h2, items[3])
+
+	first, err := BatchVerifyBobWC(ec, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, first)
+
+	// the first failing proof is reported, whichever group it is in
+	c2s[5], c2s[4] = c2s[4], c2s[5]
+	first, err = BatchVerifyBobWC(ec, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, first)
+	c2s[2], c2s[3] = c2s[3], c2s[2]
+	first, err = BatchVerifyBobWC(ec, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, first)
+	proofs[1] = nil
+	first, err = BatchVerifyBobWC(ec, pks, NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first)
+
+	_, err = BatchVerifyBobWC(ec, pks[1:], NTildes, h1s, h2s, c1s, c2s, Xs, proofs)
+	assert.Error(t, err)
+}
+
+func BenchmarkBatchVerifyProofBobWC(b *testing.B) {
+	ec := tss.EC()
+	for _, n := range []int{10, 50} {
+		pk, NTilde, h1, h2, items, err := batchItems(n, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("batch-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				BatchVerifyProofBobWC(ec, pk, NTilde, h1, h2, items)
+			}
+		})
+		b.Run(fmt.Sprintf("serial-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, item := range items {
+					item.Proof.Verify(ec, pk, NTilde, h1, h2, item.C1, item.C2, item.X)
+				}
+			}
+		})
+	}
+}
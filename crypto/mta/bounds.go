@@ -0,0 +1,107 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mta
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+)
+
+// smallBoundBits is the largest bound size that is checked with native integers rather than big.Int
+const smallBoundBits = 128
+
+type (
+	// rangeBound is an upper bound such as q^3 or q^7 used in the range checks of the MtA proofs.
+	// On small curves (mostly used in tests and development) the bound fits in 128 bits and the checks against it
+	// take a fast path on native integers; otherwise they fall back to big.Int.
+	rangeBound struct {
+		bound   *big.Int
+		isSmall bool
+		hi, lo  uint64
+	}
+)
+
+// PaillierCurveCompatible checks that the modulus N of a Paillier key has at least paillier.MinPaillierBits bits for
+// `curve`, the length that keygen requires. The values that the MtA proofs encrypt under the key range up to q^7; with
+// a smaller N they wrap around mod N without any error, and the proofs are no longer sound.
+func PaillierCurveCompatible(pk *paillier.PublicKey, curve elliptic.Curve) error {
+	if pk == nil || pk.N == nil {
+		return errors.New("the Paillier public key is missing")
+	}
+	if minBits := paillier.MinPaillierBits(curve); pk.N.BitLen() < minBits {
+		return fmt.Errorf("a %d-bit Paillier modulus is too small for a curve with a %d-bit order: MtA needs a modulus "+
+			"of at least %d bits", pk.N.BitLen(), curve.Params().N.BitLen(), minBits)
+	}
+	return nil
+}
+
+func newRangeBound(bound *big.Int) rangeBound {
+	rb := rangeBound{bound: bound}
+	if bound.Sign() >= 0 && bound.BitLen() <= smallBoundBits {
+		rb.isSmall = true
+		rb.hi, rb.lo = toUint128(bound)
+	}
+	return rb
+}
+
+// exceededBy reports whether x > bound
+func (rb rangeBound) exceededBy(x *big.Int) bool {
+	if !rb.isSmall {
+		return x.Cmp(rb.bound) > 0
+	}
+	if x.Sign() < 0 {
+		return false
+	}
+	if x.BitLen() > smallBoundBits {
+		return true
+	}
+	hi, lo := toUint128(x)
+	return hi > rb.hi || (hi == rb.hi && lo > rb.lo)
+}
+
+// toUint128 splits a non-negative x of at most 128 bits into its high and low 64-bit halves
+func toUint128(x *big.Int) (hi, lo uint64) {
+	words := x.Bits()
+	if bits.UintSize == 64 {
+		if 0 < len(words) {
+			lo = uint64(words[0])
+		}
+		if 1 < len(words) {
+			hi = uint64(words[1])
+		}
+		return
+	}
+	for i := len(words) - 1; i >= 0; i-- {
+		hi = hi<<32 | lo>>32
+		lo = lo<<32 | uint64(words[i])
+	}
+	return
+}
+
+// sizeBound is an upper bound on the bit length of a proof value received from a peer
+type sizeBound struct {
+	name string
+	x    *big.Int
+	bits int
+}
+
+// firstOversized returns the name of the first value that is absent or longer than its bound. big.Int keeps the
+// length of its value, so this takes constant time per value however large the value is, and running it before the
+// GCD, interval and exponentiation checks keeps a peer from making those expensive with an oversized value.
+func firstOversized(bounds ...sizeBound) (string, bool) {
+	for _, b := range bounds {
+		if b.x == nil || b.x.Sign() < 0 || b.bits < b.x.BitLen() {
+			return b.name, true
+		}
+	}
+	return "", false
+}
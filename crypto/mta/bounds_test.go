@@ -0,0 +1,80 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mta
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestRangeBoundExceededBy(t *testing.T) {
+	q := tss.EC().Params().N
+	bounds := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(7919),
+		new(big.Int).Lsh(one, 64),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 128), one),
+		new(big.Int).Lsh(one, 128),
+		new(big.Int).Exp(q, big.NewInt(3), nil),
+	}
+	for _, bound := range bounds {
+		rb := newRangeBound(bound)
+		assert.Equal(t, bound.BitLen() <= smallBoundBits, rb.isSmall)
+		xs := []*big.Int{
+			big.NewInt(0),
+			big.NewInt(-5),
+			new(big.Int).Set(bound),
+			new(big.Int).Add(bound, one),
+			new(big.Int).Sub(bound, one),
+			new(big.Int).Lsh(bound, 64),
+			common.MustGetRandomInt(bound.BitLen() + 1),
+			common.MustGetRandomInt(200),
+		}
+		for _, x := range xs {
+			assert.Equal(t, x.Cmp(bound) > 0, rb.exceededBy(x), "bound %s, x %s", bound, x)
+		}
+	}
+}
+
+func BenchmarkRangeBoundSmall(b *testing.B) {
+	bound := big.NewInt(7919 * 7919 * 7919)
+	x := big.NewInt(7919 * 7919)
+	b.Run("big.Int", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = x.Cmp(bound) > 0
+		}
+	})
+	b.Run("rangeBound", func(b *testing.B) {
+		rb := newRangeBound(bound)
+		for i := 0; i < b.N; i++ {
+			_ = rb.exceededBy(x)
+		}
+	})
+}
+
+func TestPaillierCurveCompatible(t *testing.T) {
+	_, pk, err := paillier.GenerateKeyPair(testPaillierKeyLength, 10*time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, PaillierCurveCompatible(pk, tss.EC()))
+	assert.NoError(t, PaillierCurveCompatible(pk, elliptic.P256()))
+
+	err = PaillierCurveCompatible(pk, elliptic.P521())
+	if assert.Error(t, err, "a 2048-bit modulus must be rejected for P-521") {
+		assert.Contains(t, err.Error(), "2048-bit Paillier modulus is too small for a curve with a 521-bit order")
+	}
+	assert.Error(t, PaillierCurveCompatible(nil, tss.EC()))
+}
@@ -0,0 +1,91 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mta
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+)
+
+const (
+	// ProofBobVersion1 is the original encoding of a ProofBob: its parts, with no version
+	ProofBobVersion1 byte = 1
+	// ProofBobVersion2 is the encoding of a ProofBob that leads with a part holding the version byte
+	ProofBobVersion2 byte = 2
+
+	ProofBobV2BytesParts   = ProofBobBytesParts + 1
+	ProofBobWCV2BytesParts = ProofBobWCBytesParts + 1
+)
+
+// BytesV2 encodes the proof in the version 2 format
+func (pf *ProofBob) BytesV2() [ProofBobV2BytesParts][]byte {
+	var out [ProofBobV2BytesParts][]byte
+	out[0] = []byte{ProofBobVersion2}
+	bzs := pf.Bytes()
+	copy(out[1:], bzs[:])
+	return out
+}
+
+// BytesV2 encodes the proof in the version 2 format
+func (pf *ProofBobWC) BytesV2() [ProofBobWCV2BytesParts][]byte {
+	var out [ProofBobWCV2BytesParts][]byte
+	out[0] = []byte{ProofBobVersion2}
+	bzs := pf.Bytes()
+	copy(out[1:], bzs[:])
+	return out
+}
+
+// ProofBobVersionOf detects the version of an encoded ProofBob or ProofBobWC from its parts
+func ProofBobVersionOf(bzs [][]byte) (byte, error) {
+	// a version part is a single byte, which the first value of a version 1 proof, z, never is, so that a version 1
+	// ProofBobWC with a curve tag is not taken for a version 2 one
+	switch versioned := 0 < len(bzs) && len(bzs[0]) == 1; {
+	case !versioned && (len(bzs) == ProofBobBytesParts || len(bzs) == ProofBobWCBytesParts):
+		return ProofBobVersion1, nil
+	case versioned && (len(bzs) == ProofBobV2BytesParts || len(bzs) == ProofBobWCV2BytesParts):
+		if version := bzs[0][0]; version != ProofBobVersion2 {
+			return 0, fmt.Errorf("unsupported ProofBob version %d", version)
+		}
+		return ProofBobVersion2, nil
+	}
+	return 0, fmt.Errorf("%d byte parts are not a ProofBob of any known version", len(bzs))
+}
+
+// ProofBobFromBytesAnyVersion decodes a ProofBob encoded in any supported version and returns it with that version
+func ProofBobFromBytesAnyVersion(bzs [][]byte) (*ProofBob, byte, error) {
+	version, err := ProofBobVersionOf(bzs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version == ProofBobVersion2 {
+		bzs = bzs[1:]
+	}
+	pf, err := ProofBobFromBytes(bzs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pf, version, nil
+}
+
+// VerifyAnyVersion verifies an encoded ProofBob as ProofBob.VerifyWithReason does, dispatching on the version
+// detected in its encoding. It lets nodes verify the proofs of peers on an older version during a migration.
+func VerifyAnyVersion(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, bzs [][]byte, optionalBackend ...common.ModExpBackend) error {
+	pf, version, err := ProofBobFromBytesAnyVersion(bzs)
+	if err != nil {
+		return err
+	}
+	switch version {
+	case ProofBobVersion1, ProofBobVersion2:
+		// the versions differ only in their encoding
+		return pf.VerifyWithReason(ec, pk, NTilde, h1, h2, c1, c2, optionalBackend...)
+	}
+	return fmt.Errorf("unsupported ProofBob version %d", version)
+}
@@ -7,8 +7,11 @@
 package mta
 
 import (
+	"crypto/elliptic"
+	"crypto/sha512"
 	"errors"
 	"fmt"
+	"hash"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -20,6 +23,8 @@ import (
 const (
 	ProofBobBytesParts   = 10
 	ProofBobWCBytesParts = 12
+	// a ProofBobWC may carry a trailing part naming the curve it was made on
+	ProofBobWCTaggedBytesParts = ProofBobWCBytesParts + 1
 )
 
 type (
@@ -31,22 +36,50 @@ type (
 		*ProofBob
 		U *crypto.ECPoint
 	}
+
+	// ProofCheck names the kind of check of an MtA proof that failed to verify
+	ProofCheck string
+
+	// ProofStepError is the error of VerifyWithReason. It cites the figure and step of GG18Spec (9) whose check a proof
+	// failed and names the kind of check, so that a caller can attribute the fault without parsing the message.
+	ProofStepError struct {
+		Fig    int
+		Step   string
+		Check  ProofCheck
+		Reason string
+	}
+)
+
+const (
+	// ProofCheckValues is a proof value that is missing, too large or not a unit of its modulus
+	ProofCheckValues ProofCheck = "values"
+	// ProofCheckRange is the range bound on s1 and, in Bob's proofs, t1 of step 3
+	ProofCheckRange ProofCheck = "range"
+	// ProofCheckDLog is the discrete log relation g^s1 = X^e * u of step 4 of Fig. 10
+	ProofCheckDLog ProofCheck = "dlog"
+	// ProofCheckModNTilde is an equality mod NTilde: steps 5 and 6 of Figs. 10 and 11, step 5 of Fig. 9
+	ProofCheckModNTilde ProofCheck = "mod-ntilde"
+	// ProofCheckModNSquare is an equality mod N^2: step 7 of Figs. 10 and 11, step 4 of Fig. 9
+	ProofCheckModNSquare ProofCheck = "mod-nsquare"
 )
 
 // ProveBobWC implements Bob's proof both with or without check "ProveMtawc_Bob" and "ProveMta_Bob" used in the MtA protocol from GG18Spec (9) Figs. 10 & 11.
 // an absent `X` generates the proof without the X consistency check X = g^x
-func ProveBobWC(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint) (*ProofBobWC, error) {
+func ProveBobWC(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint) (*ProofBobWC, error) {
+	return ProveBobWCWithHash(ec, nil, pk, NTilde, h1, h2, c1, c2, x, y, r, X)
+}
+
+// ProveBobWCWithHash is ProveBobWC, deriving the challenge with the hash made by `newHash`, or SHA-512/256 if it is nil.
+// The proof only verifies with the same hash.
+func ProveBobWCWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int, X *crypto.ECPoint) (*ProofBobWC, error) {
 	if pk == nil || NTilde == nil || h1 == nil || h2 == nil || c1 == nil || c2 == nil || x == nil || y == nil || r == nil {
 		return nil, errors.New("ProveBob() received a nil argument")
 	}
 
 	NSq := pk.NSquare()
 
-	q := tss.EC().Params().N
-	q2 := new(big.Int).Mul(q, q)
-	q3 := new(big.Int).Mul(q2, q)
-	q6 := new(big.Int).Mul(q3, q3)
-	q7 := new(big.Int).Mul(q6, q)
+	powers := tss.CurvePowersOf(ec)
+	q, q3, q7 := powers.Q, powers.Q3, powers.Q7
 	qNTilde := new(big.Int).Mul(q, NTilde)
 	q3NTilde := new(big.Int).Mul(q3, NTilde)
 
@@ -67,9 +100,9 @@ func ProveBobWC(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int
 	gamma := common.GetRandomPositiveInt(q7)
 
 	// 5.
-	u := crypto.NewECPointNoCurveCheck(tss.EC(), zero, zero) // initialization suppresses an IDE warning
+	u := crypto.NewECPointNoCurveCheck(ec, zero, zero) // initialization suppresses an IDE warning
 	if X != nil {
-		u = crypto.ScalarBaseMult(tss.EC(), alpha)
+		u = crypto.ScalarBaseMult(ec, alpha)
 	}
 
 	// 6.
@@ -101,9 +134,9 @@ func ProveBobWC(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int
 		var eHash *big.Int
 		// X is nil if called by ProveBob (Bob's proof "without check")
 		if X == nil {
-			eHash = common.SHA512_256i(append(pk.AsInts(), c1, c2, z, zPrm, t, v, w)...)
+			eHash = common.HashInts(challengeHash(newHash), append(pk.AsInts(), c1, c2, z, zPrm, t, v, w)...)
 		} else {
-			eHash = common.SHA512_256i(append(pk.AsInts(), X.X(), X.Y(), c1, c2, u.X(), u.Y(), z, zPrm, t, v, w)...)
+			eHash = common.HashInts(challengeHash(newHash), append(pk.AsInts(), X.X(), X.Y(), c1, c2, u.X(), u.Y(), z, zPrm, t, v, w)...)
 		}
 		e = common.RejectionSample(q, eHash)
 	}
@@ -137,22 +170,74 @@ func ProveBobWC(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int
 }
 
 // ProveBob implements Bob's proof "ProveMta_Bob" used in the MtA protocol from GG18Spec (9) Fig. 11.
-func ProveBob(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int) (*ProofBob, error) {
+func ProveBob(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int) (*ProofBob, error) {
+	return ProveBobWithHash(ec, nil, pk, NTilde, h1, h2, c1, c2, x, y, r)
+}
+
+// ProveBobWithHash is ProveBob, deriving the challenge with the hash made by `newHash`, or SHA-512/256 if it is nil
+func ProveBobWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2, x, y, r *big.Int) (*ProofBob, error) {
 	// the Bob proof ("with check") contains the ProofBob "without check"; this method extracts and returns it
 	// X is supplied as nil to exclude it from the proof hash
-	pf, err := ProveBobWC(pk, NTilde, h1, h2, c1, c2, x, y, r, nil)
+	pf, err := ProveBobWCWithHash(ec, newHash, pk, NTilde, h1, h2, c1, c2, x, y, r, nil)
 	if err != nil {
 		return nil, err
 	}
-	return pf.ProofBob, nil
+	return pf.ToBob(), nil
 }
 
-func ProofBobWCFromBytes(bzs [][]byte) (*ProofBobWC, error) {
+// ToWC returns the proof with check made of a copy of the ten components of this proof and the point U = g^alpha of a
+// proof with check. A nil U gives the form in which a proof without check is verified, as the challenge of one hashes
+// neither U nor X. A U that is not on its curve is rejected.
+func (pf *ProofBob) ToWC(U *crypto.ECPoint) (*ProofBobWC, error) {
+	if pf == nil {
+		return nil, errors.New("ProofBob.ToWC() received a nil proof")
+	}
+	if U != nil && !U.ValidateBasic() {
+		return nil, errors.New("ProofBob.ToWC() received a U that is not on its curve")
+	}
+	// U is shared, as an ECPoint is immutable
+	return &ProofBobWC{ProofBob: pf.copy(), U: U}, nil
+}
+
+// ToBob returns a copy of the ten components of this proof that a proof without check shares, dropping U. The result
+// only verifies as a ProofBob if this proof was made without check (see ProveBob), as U and X are hashed into the
+// challenge of a proof with check.
+func (pf *ProofBobWC) ToBob() *ProofBob {
+	if pf == nil || pf.ProofBob == nil {
+		return nil
+	}
+	return pf.ProofBob.copy()
+}
+
+// copy returns a deep copy of the proof, so that changes to the values of one do not show in the other
+func (pf *ProofBob) copy() *ProofBob {
+	cp := func(x *big.Int) *big.Int {
+		if x == nil {
+			return nil
+		}
+		return new(big.Int).Set(x)
+	}
+	return &ProofBob{
+		Z: cp(pf.Z), ZPrm: cp(pf.ZPrm), T: cp(pf.T), V: cp(pf.V), W: cp(pf.W),
+		S: cp(pf.S), S1: cp(pf.S1), S2: cp(pf.S2), T1: cp(pf.T1), T2: cp(pf.T2),
+	}
+}
+
+// ProofBobWCFromBytes parses a ProofBobWC of `ec`. A proof that carries a curve tag (see BytesWithCurveTag) is rejected
+// if it was made on another curve, rather than failing verification later with a less telling error.
+func ProofBobWCFromBytes(ec elliptic.Curve, bzs [][]byte) (*ProofBobWC, error) {
+	if len(bzs) == ProofBobWCTaggedBytesParts {
+		tag := tss.CurveName(bzs[ProofBobWCBytesParts])
+		if name, ok := tss.GetCurveName(ec); !ok || tag != name {
+			return nil, fmt.Errorf("the ProofBobWC was made on the curve %q, not on this session's curve %q", tag, name)
+		}
+		bzs = bzs[:ProofBobWCBytesParts]
+	}
 	proofBob, err := ProofBobFromBytes(bzs)
 	if err != nil {
 		return nil, err
 	}
-	point, err := crypto.NewECPoint(tss.EC(),
+	point, err := crypto.NewECPoint(ec,
 		new(big.Int).SetBytes(bzs[10]),
 		new(big.Int).SetBytes(bzs[11]))
 	if err != nil {
@@ -187,147 +272,298 @@ func ProofBobFromBytes(bzs [][]byte) (*ProofBob, error) {
 
 // ProveBobWC.Verify implements verification of Bob's proof with check "VerifyMtawc_Bob" used in the MtA protocol from GG18Spec (9) Fig. 10.
 // an absent `X` verifies a proof generated without the X consistency check X = g^x
-func (pf *ProofBobWC) Verify(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, X *crypto.ECPoint) bool {
-	if pk == nil || NTilde == nil || h1 == nil || h2 == nil || c1 == nil || c2 == nil {
-		return false
-	}
+func (pf *ProofBobWC) Verify(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, X *crypto.ECPoint) bool {
+	return pf.VerifyWithReason(ec, pk, NTilde, h1, h2, c1, c2, X) == nil
+}
 
-	q := tss.EC().Params().N
-	q2 := new(big.Int).Mul(q, q)
-	q3 := new(big.Int).Mul(q, q2)
-	q6 := new(big.Int).Mul(q3, q3) // q^6
-	q7 := new(big.Int).Mul(q6, q)  // q^7
+// VerifyWithReason is Verify, returning an error that cites the figure and step of GG18Spec (9) whose check failed.
+// The modular exponentiations are run on the given backend, if any.
+func (pf *ProofBobWC) VerifyWithReason(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, X *crypto.ECPoint, optionalBackend ...common.ModExpBackend) error {
+	return pf.VerifyWithHash(ec, nil, pk, NTilde, h1, h2, c1, c2, X, optionalBackend...)
+}
 
-	if !common.IsInInterval(pf.Z, NTilde) {
-		return false
+// VerifyWithHash is VerifyWithReason for a proof whose challenge was derived with the hash made by `newHash`, or
+// SHA-512/256 if it is nil (see ProveBobWCWithHash)
+func (pf *ProofBobWC) VerifyWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, X *crypto.ECPoint, optionalBackend ...common.ModExpBackend) error {
+	fig := 10
+	if X == nil {
+		fig = 11
 	}
-	if !common.IsInInterval(pf.ZPrm, NTilde) {
-		return false
+	e, err := pf.checkValuesAndChallenge(ec, newHash, pk, NTilde, h1, h2, c1, c2, X)
+	if err != nil {
+		return err
 	}
-	if !common.IsInInterval(pf.T, NTilde) {
-		return false
+
+	backend := modExpBackend(optionalBackend)
+	if err := pf.checkNTildeRelations(fig, backend, e, NTilde, h1, h2); err != nil {
+		return err
 	}
-	if !common.IsInInterval(pf.V, pk.NSquare()) {
-		return false
+
+	// 7.
+	return pf.checkCiphertextRelation(fig, backend, e, pk, c1, c2, false)
+}
+
+// checkNTildeRelations checks steps 5 and 6, the equalities mod NTilde, for the challenge e
+func (pf *ProofBobWC) checkNTildeRelations(fig int, backend common.ModExpBackend, e, NTilde, h1, h2 *big.Int) error {
+	modNTilde := common.ModInt(NTilde)
+	exps, err := common.BatchModExp(backend, NTilde,
+		[][]*big.Int{{h1, h2, pf.Z}, {h1, h2, pf.T}},
+		[][]*big.Int{{pf.S1, pf.S2, e}, {pf.T1, pf.T2, e}})
+	if err != nil {
+		return proofStepError(fig, "5", err.Error())
 	}
-	if !common.IsInInterval(pf.W, NTilde) {
-		return false
+
+	{ // 5.
+		h1ExpS1, h2ExpS2, zExpE := exps[0][0], exps[0][1], exps[0][2]
+		left := modNTilde.Mul(h1ExpS1, h2ExpS2)
+		right := modNTilde.Mul(zExpE, pf.ZPrm)
+		if left.Cmp(right) != 0 {
+			return proofStepError(fig, "5", "h1^s1 * h2^s2 != z^e * z' mod NTilde")
+		}
 	}
-	if !common.IsInInterval(pf.S, pk.N) {
-		return false
+
+	{ // 6.
+		h1ExpT1, h2ExpT2, tExpE := exps[1][0], exps[1][1], exps[1][2]
+		left := modNTilde.Mul(h1ExpT1, h2ExpT2)
+		right := modNTilde.Mul(tExpE, pf.W)
+		if left.Cmp(right) != 0 {
+			return proofStepError(fig, "6", "h1^t1 * h2^t2 != t^e * w mod NTilde")
+		}
 	}
-	if new(big.Int).GCD(nil, nil, pf.Z, NTilde).Cmp(one) != 0 {
-		return false
+	return nil
+}
+
+// checkValuesAndChallenge runs the checks of VerifyWithReason that need no modular exponentiation, those of the proof's
+// values, of step 3 and of step 4, and returns the challenge e of steps 1-2
+func (pf *ProofBobWC) checkValuesAndChallenge(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, X *crypto.ECPoint) (*big.Int, error) {
+	fig := 10
+	if X == nil {
+		fig = 11
+	}
+	if pf == nil || pf.ProofBob == nil || (X != nil && pf.U == nil) ||
+		pk == nil || NTilde == nil || h1 == nil || h2 == nil || c1 == nil || c2 == nil {
+		return nil, errors.New("ProofBobWC.Verify() received a nil argument")
+	}
+
+	powers := tss.CurvePowersOf(ec)
+	q, q3, q7 := powers.Q, powers.Q3, powers.Q7
+
+	// s2 = e*rho + rho' and t2 = e*sigma + tau are less than 2*q^3*NTilde
+	nTildeBits, s2t2Bits := NTilde.BitLen(), q3.BitLen()+NTilde.BitLen()+1
+	if name, over := firstOversized(
+		sizeBound{"z", pf.Z, nTildeBits}, sizeBound{"z'", pf.ZPrm, nTildeBits}, sizeBound{"t", pf.T, nTildeBits},
+		sizeBound{"v", pf.V, pk.NSquare().BitLen()}, sizeBound{"w", pf.W, nTildeBits}, sizeBound{"s", pf.S, pk.N.BitLen()},
+		sizeBound{"s1", pf.S1, q3.BitLen()}, sizeBound{"s2", pf.S2, s2t2Bits},
+		sizeBound{"t1", pf.T1, q7.BitLen()}, sizeBound{"t2", pf.T2, s2t2Bits},
+	); over {
+		return nil, proofStepError(fig, "0", name+" is missing or too large")
+	}
+	for _, in := range []struct {
+		name   string
+		v, mod *big.Int
+	}{
+		{"z", pf.Z, NTilde}, {"z'", pf.ZPrm, NTilde}, {"t", pf.T, NTilde}, {"v", pf.V, pk.NSquare()}, {"w", pf.W, NTilde},
+	} {
+		if !common.IsInInterval(in.v, in.mod) || new(big.Int).GCD(nil, nil, in.v, in.mod).Cmp(one) != 0 {
+			return nil, proofStepError(fig, "0", in.name+" is not a unit of its modulus")
+		}
 	}
-	if new(big.Int).GCD(nil, nil, pf.ZPrm, NTilde).Cmp(one) != 0 {
-		return false
+	if !common.IsInInterval(pf.S, pk.N) || pf.S.Cmp(zero) == 0 || new(big.Int).GCD(nil, nil, pf.S, pk.N).Cmp(one) != 0 {
+		return nil, proofStepError(fig, "0", "s is not a unit mod N")
 	}
-	if new(big.Int).GCD(nil, nil, pf.T, NTilde).Cmp(one) != 0 {
-		return false
+	if new(big.Int).GCD(nil, nil, pf.V, pk.N).Cmp(one) != 0 {
+		return nil, proofStepError(fig, "0", "v is not coprime to N")
 	}
-	if new(big.Int).GCD(nil, nil, pf.V, pk.NSquare()).Cmp(one) != 0 {
-		return false
+	// 3.
+	if newRangeBound(q3).exceededBy(pf.S1) {
+		return nil, proofStepError(fig, "3", "s1 > q^3")
 	}
-	if new(big.Int).GCD(nil, nil, pf.W, NTilde).Cmp(one) != 0 {
-		return false
+	if newRangeBound(q7).exceededBy(pf.T1) {
+		return nil, proofStepError(fig, "3", "t1 > q^7")
 	}
 
-	gcd := big.NewInt(0)
-	if pf.S.Cmp(zero) == 0 {
-		return false
+	// 1-2. e'
+	e := pf.challenge(newHash, q, pk, c1, c2, X)
+
+	// 4. runs only in the "with check" mode from Fig. 10
+	if X != nil {
+		if err := pf.checkCommitmentRelation(ec, e, X); err != nil {
+			return nil, err
+		}
 	}
-	if gcd.GCD(nil, nil, pf.S, pk.N).Cmp(one) != 0 {
-		return false
+	return e, nil
+}
+
+// CheckCiphertextRelation recomputes the challenge e of the proof from pk, cA, cB and gB, the commitment g^b to Bob's
+// share, and checks exactly these two equations of GG18Spec (9) Fig. 10:
+//
+//	g^s1 = gB^e * u                               (4.)
+//	cA^s1 * s^N * Gamma^t1 = cB^e * v mod N^2     (7.)
+//
+// Nothing else is checked: not the ranges of s1 and t1, not the equations mod NTilde, and not that the values are in
+// their groups. Passing does not show that cB is well formed, as without those checks the equations can be satisfied
+// by a cheating prover; it is meant for debugging a rejected MtA and does not replace VerifyWithReason.
+func (pf *ProofBobWC) CheckCiphertextRelation(ec elliptic.Curve, pk *paillier.PublicKey, cA, cB *big.Int, gB *crypto.ECPoint, optionalBackend ...common.ModExpBackend) error {
+	return pf.CheckCiphertextRelationWithHash(ec, nil, pk, cA, cB, gB, optionalBackend...)
+}
+
+// CheckCiphertextRelationWithHash is CheckCiphertextRelation for a proof whose challenge was derived with the hash made
+// by `newHash`, or SHA-512/256 if it is nil
+func (pf *ProofBobWC) CheckCiphertextRelationWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, cA, cB *big.Int, gB *crypto.ECPoint, optionalBackend ...common.ModExpBackend) error {
+	if pf == nil || pf.ProofBob == nil || pf.U == nil || pf.S == nil || pf.S1 == nil || pf.T1 == nil || pf.V == nil ||
+		pk == nil || cA == nil || cB == nil || gB == nil {
+		return errors.New("ProofBobWC.CheckCiphertextRelation() received a nil argument")
 	}
-	if pf.V.Cmp(zero) == 0 {
-		return false
+	e := pf.challenge(newHash, tss.CurvePowersOf(ec).Q, pk, cA, cB, gB)
+	if err := pf.checkCommitmentRelation(ec, e, gB); err != nil {
+		return err
 	}
-	if gcd.GCD(nil, nil, pf.V, pk.N).Cmp(one) != 0 {
-		return false
+	return pf.checkCiphertextRelation(10, modExpBackend(optionalBackend), e, pk, cA, cB, false)
+}
+
+// Challenge returns the Fiat-Shamir challenge e that VerifyWithReason derives for this proof from the public inputs,
+// so that an auditor can confirm that they recompute the proof's transcript from the same inputs
+func (pf *ProofBob) Challenge(ec elliptic.Curve, pk *paillier.PublicKey, c1, c2 *big.Int) *big.Int {
+	return pf.ChallengeWithHash(ec, nil, pk, c1, c2)
+}
+
+// ChallengeWithHash is Challenge for a proof whose challenge was derived with the hash made by `newHash`, or
+// SHA-512/256 if it is nil
+func (pf *ProofBob) ChallengeWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, c1, c2 *big.Int) *big.Int {
+	return (&ProofBobWC{ProofBob: pf}).ChallengeWithHash(ec, newHash, pk, c1, c2, nil)
+}
+
+// Challenge returns the Fiat-Shamir challenge e that VerifyWithReason derives for this proof from the public inputs;
+// an absent `X` derives the challenge of a proof generated without the X consistency check
+func (pf *ProofBobWC) Challenge(ec elliptic.Curve, pk *paillier.PublicKey, c1, c2 *big.Int, X *crypto.ECPoint) *big.Int {
+	return pf.ChallengeWithHash(ec, nil, pk, c1, c2, X)
+}
+
+// ChallengeWithHash is Challenge for a proof whose challenge was derived with the hash made by `newHash`, or
+// SHA-512/256 if it is nil
+func (pf *ProofBobWC) ChallengeWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, c1, c2 *big.Int, X *crypto.ECPoint) *big.Int {
+	if pf == nil || pf.ProofBob == nil || (X != nil && pf.U == nil) || pk == nil || c1 == nil || c2 == nil {
+		return nil
 	}
-	// 3.
-	if pf.S1.Cmp(q3) > 0 {
-		return false
+	return pf.challenge(newHash, tss.CurvePowersOf(ec).Q, pk, c1, c2, X)
+}
+
+// challenge derives e of GG18Spec (9) Fig. 10 or, when X is nil, Fig. 11, with the hash made by `newHash`
+func (pf *ProofBobWC) challenge(newHash func() hash.Hash, q *big.Int, pk *paillier.PublicKey, c1, c2 *big.Int, X *crypto.ECPoint) *big.Int {
+	// must use RejectionSample
+	var eHash *big.Int
+	// X is nil if called on a ProveBob (Bob's proof "without check")
+	if X == nil {
+		eHash = common.HashInts(challengeHash(newHash), append(pk.AsInts(), c1, c2, pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
+	} else {
+		eHash = common.HashInts(challengeHash(newHash), append(pk.AsInts(), X.X(), X.Y(), c1, c2, pf.U.X(), pf.U.Y(), pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
+	}
+	return common.RejectionSample(q, eHash)
+}
+
+// checkCommitmentRelation is step 4. of GG18Spec (9) Fig. 10
+func (pf *ProofBobWC) checkCommitmentRelation(ec elliptic.Curve, e *big.Int, X *crypto.ECPoint) error {
+	s1ModQ := new(big.Int).Mod(pf.S1, ec.Params().N)
+	gS1 := crypto.ScalarBaseMult(ec, s1ModQ)
+	xEU, err := X.ScalarMult(e).Add(pf.U)
+	if err != nil || !gS1.Equals(xEU) {
+		return proofStepError(10, "4", "g^s1 != X^e * u")
 	}
-	if pf.T1.Cmp(q7) > 0 {
-		return false
+	return nil
+}
+
+// checkCiphertextRelation is step 7. of GG18Spec (9) Fig. 10 and 11. With `upToSign`, as BatchVerifyProofBobWC checks
+// it, the equality may also hold with -1: x^N mod N^2 depends only on x mod N, and N is odd, so (N-s)^N = -s^N mod N^2,
+// and such a proof is the valid proof with N-s for s, which is not hashed into the challenge.
+func (pf *ProofBobWC) checkCiphertextRelation(fig int, backend common.ModExpBackend, e *big.Int, pk *paillier.PublicKey, c1, c2 *big.Int, upToSign bool) error {
+	modNSq := common.ModInt(pk.NSquare())
+	exps, err := common.BatchModExp(backend, pk.NSquare(),
+		[][]*big.Int{{c1, pf.S, pk.Gamma(), c2}},
+		[][]*big.Int{{pf.S1, pk.N, pf.T1, e}})
+	if err != nil {
+		return proofStepError(fig, "7", err.Error())
 	}
 
-	// 1-2. e'
-	var e *big.Int
-	{ // must use RejectionSample
-		var eHash *big.Int
-		// X is nil if called on a ProveBob (Bob's proof "without check")
-		if X == nil {
-			eHash = common.SHA512_256i(append(pk.AsInts(), c1, c2, pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
-		} else {
-			eHash = common.SHA512_256i(append(pk.AsInts(), X.X(), X.Y(), c1, c2, pf.U.X(), pf.U.Y(), pf.Z, pf.ZPrm, pf.T, pf.V, pf.W)...)
-		}
-		e = common.RejectionSample(q, eHash)
+	c1ExpS1, sExpN, gammaExpT1, c2ExpE := exps[0][0], exps[0][1], exps[0][2], exps[0][3]
+	left := modNSq.Mul(c1ExpS1, sExpN)
+	left = modNSq.Mul(left, gammaExpT1)
+	right := modNSq.Mul(c2ExpE, pf.V)
+	if left.Cmp(right) == 0 || upToSign && left.Cmp(new(big.Int).Sub(pk.NSquare(), right)) == 0 {
+		return nil
 	}
+	return proofStepError(fig, "7", "c1^s1 * s^N * Gamma^t1 != c2^e * v mod N^2")
+}
 
-	var left, right *big.Int // for the following conditionals
+// ProveBob.Verify implements verification of Bob's proof without check "VerifyMta_Bob" used in the MtA protocol from GG18Spec (9) Fig. 11.
+func (pf *ProofBob) Verify(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int) bool {
+	return pf.VerifyWithReason(ec, pk, NTilde, h1, h2, c1, c2) == nil
+}
 
-	// 4. runs only in the "with check" mode from Fig. 10
-	if X != nil {
-		s1ModQ := new(big.Int).Mod(pf.S1, tss.EC().Params().N)
-		gS1 := crypto.ScalarBaseMult(tss.EC(), s1ModQ)
-		xEU, err := X.ScalarMult(e).Add(pf.U)
-		if err != nil || !gS1.Equals(xEU) {
-			return false
-		}
+// VerifyWithReason is Verify, returning an error that cites the step of GG18Spec (9) Fig. 11 whose check failed.
+// The modular exponentiations are run on the given backend, if any.
+func (pf *ProofBob) VerifyWithReason(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, optionalBackend ...common.ModExpBackend) error {
+	return pf.VerifyWithHash(ec, nil, pk, NTilde, h1, h2, c1, c2, optionalBackend...)
+}
+
+// VerifyWithHash is VerifyWithReason for a proof whose challenge was derived with the hash made by `newHash`, or
+// SHA-512/256 if it is nil (see ProveBobWithHash)
+func (pf *ProofBob) VerifyWithHash(ec elliptic.Curve, newHash func() hash.Hash, pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int, optionalBackend ...common.ModExpBackend) error {
+	if pf == nil {
+		return errors.New("ProofBob.Verify() received a nil proof")
+	}
+	pfWC, err := pf.ToWC(nil)
+	if err != nil {
+		return err
 	}
+	return pfWC.VerifyWithHash(ec, newHash, pk, NTilde, h1, h2, c1, c2, nil, optionalBackend...)
+}
 
-	{ // 5-6.
-		modNTilde := common.ModInt(NTilde)
+func (err *ProofStepError) Error() string {
+	return fmt.Sprintf("GG18Spec (9) Fig. %d step %s: %s", err.Fig, err.Step, err.Reason)
+}
 
-		{ // 5.
-			h1ExpS1 := modNTilde.Exp(h1, pf.S1)
-			h2ExpS2 := modNTilde.Exp(h2, pf.S2)
-			left = modNTilde.Mul(h1ExpS1, h2ExpS2)
-			zExpE := modNTilde.Exp(pf.Z, e)
-			right = modNTilde.Mul(zExpE, pf.ZPrm)
-			if left.Cmp(right) != 0 {
-				return false
-			}
-		}
+// proofStepError cites the figure and step of GG18Spec (9) whose check a proof failed.
+// Step 0 stands for the checks on the proof's values that precede the numbered steps.
+func proofStepError(fig int, step, reason string) error {
+	return &ProofStepError{Fig: fig, Step: step, Check: proofCheckOf(fig, step), Reason: reason}
+}
 
-		{ // 6.
-			h1ExpT1 := modNTilde.Exp(h1, pf.T1)
-			h2ExpT2 := modNTilde.Exp(h2, pf.T2)
-			left = modNTilde.Mul(h1ExpT1, h2ExpT2)
-			tExpE := modNTilde.Exp(pf.T, e)
-			right = modNTilde.Mul(tExpE, pf.W)
-			if left.Cmp(right) != 0 {
-				return false
-			}
+// proofCheckOf is the kind of check of a step of Fig. 9 (Alice's range proof) or Figs. 10 and 11 (Bob's proofs)
+func proofCheckOf(fig int, step string) ProofCheck {
+	switch step {
+	case "0":
+		return ProofCheckValues
+	case "3":
+		return ProofCheckRange
+	case "4":
+		if fig == 9 {
+			return ProofCheckModNSquare
 		}
+		return ProofCheckDLog
+	case "5", "6":
+		return ProofCheckModNTilde
+	default:
+		return ProofCheckModNSquare
 	}
+}
 
-	{ // 7.
-		modNSq := common.ModInt(pk.NSquare())
-
-		c1ExpS1 := modNSq.Exp(c1, pf.S1)
-		sExpN := modNSq.Exp(pf.S, pk.N)
-		gammaExpT1 := modNSq.Exp(pk.Gamma(), pf.T1)
-		left = modNSq.Mul(c1ExpS1, sExpN)
-		left = modNSq.Mul(left, gammaExpT1)
-		c2ExpE := modNSq.Exp(c2, e)
-		right = modNSq.Mul(c2ExpE, pf.V)
-		if left.Cmp(right) != 0 {
-			return false
-		}
+// modExpBackend returns the backend passed to a verifier, or nil for the default
+func modExpBackend(optionalBackend []common.ModExpBackend) common.ModExpBackend {
+	if 1 < len(optionalBackend) {
+		panic(errors.New("expected 0 or 1 item in `optionalBackend`"))
+	}
+	if len(optionalBackend) == 0 {
+		return nil
 	}
-	return true
+	return optionalBackend[0]
 }
 
-// ProveBob.Verify implements verification of Bob's proof without check "VerifyMta_Bob" used in the MtA protocol from GG18Spec (9) Fig. 11.
-func (pf *ProofBob) Verify(pk *paillier.PublicKey, NTilde, h1, h2, c1, c2 *big.Int) bool {
-	if pf == nil {
-		return false
+// challengeHash returns the hash constructor passed to a prover or verifier, or SHA-512/256 for the default
+func challengeHash(newHash func() hash.Hash) func() hash.Hash {
+	if newHash == nil {
+		return sha512.New512_256
 	}
-	pfWC := &ProofBobWC{ProofBob: pf, U: nil}
-	return pfWC.Verify(pk, NTilde, h1, h2, c1, c2, nil)
+	return newHash
 }
 
 func (pf *ProofBob) ValidateBasic() bool {
@@ -343,8 +579,10 @@ func (pf *ProofBob) ValidateBasic() bool {
 		pf.T2 != nil
 }
 
+// ValidateBasic checks that the proof holds all of its components and that U is on its curve, so that a point crafted
+// off the curve is rejected before Verify
 func (pf *ProofBobWC) ValidateBasic() bool {
-	return pf.ProofBob.ValidateBasic() && pf.U != nil
+	return pf != nil && pf.ProofBob != nil && pf.ProofBob.ValidateBasic() && pf.U != nil && pf.U.ValidateBasic()
 }
 
 func (pf *ProofBob) Bytes() [ProofBobBytesParts][]byte {
@@ -371,3 +609,17 @@ func (pf *ProofBobWC) Bytes() [ProofBobWCBytesParts][]byte {
 	copy(out[:], bobBzsSlice[:12])
 	return out
 }
+
+// BytesWithCurveTag is Bytes followed by a part naming the curve `ec` that the proof was made on, which
+// ProofBobWCFromBytes checks. The curve must be one registered with the tss package.
+func (pf *ProofBobWC) BytesWithCurveTag(ec elliptic.Curve) ([ProofBobWCTaggedBytesParts][]byte, error) {
+	var out [ProofBobWCTaggedBytesParts][]byte
+	name, ok := tss.GetCurveName(ec)
+	if !ok {
+		return out, errors.New("the curve has no registered name to tag the proof with")
+	}
+	bzs := pf.Bytes()
+	copy(out[:], bzs[:])
+	out[ProofBobWCBytesParts] = []byte(name)
+	return out, nil
+}
@@ -7,8 +7,11 @@
 package mta
 
 import (
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -32,27 +35,28 @@ type (
 )
 
 // ProveRangeAlice implements Alice's range proof used in the MtA and MtAwc protocols from GG18Spec (9) Fig. 9.
-func ProveRangeAlice(pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int) (*RangeProofAlice, error) {
+// Its randomness is read from the optional reader, or from crypto/rand if none is given.
+func ProveRangeAlice(ec elliptic.Curve, pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int, optionalReader ...io.Reader) (*RangeProofAlice, error) {
 	if pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || m == nil || r == nil {
 		return nil, errors.New("ProveRangeAlice constructor received nil value(s)")
 	}
 
-	q := tss.EC().Params().N
-	q3 := new(big.Int).Mul(q, q)
-	q3.Mul(q3, q)
+	powers := tss.CurvePowersOf(ec)
+	q, q3 := powers.Q, powers.Q3
 	qNTilde := new(big.Int).Mul(q, NTilde)
 	q3NTilde := new(big.Int).Mul(q3, NTilde)
 
+	reader := randReader(optionalReader)
 	// 1.
-	alpha := common.GetRandomPositiveInt(q3)
+	alpha := common.GetRandomPositiveIntFrom(reader, q3)
 	// 2.
-	beta := common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+	beta := common.GetRandomPositiveRelativelyPrimeIntFrom(reader, pk.N)
 
 	// 3.
-	gamma := common.GetRandomPositiveInt(q3NTilde)
+	gamma := common.GetRandomPositiveIntFrom(reader, q3NTilde)
 
 	// 4.
-	rho := common.GetRandomPositiveInt(qNTilde)
+	rho := common.GetRandomPositiveIntFrom(reader, qNTilde)
 
 	// 5.
 	modNTilde := common.ModInt(NTilde)
@@ -90,6 +94,7 @@ func ProveRangeAlice(pk *paillier.PublicKey, c, NTilde, h1, h2, m, r *big.Int) (
 	return &RangeProofAlice{Z: z, U: u, W: w, S: s, S1: s1, S2: s2}, nil
 }
 
+// RangeProofAliceFromBytes parses the parts of a RangeProofAlice made by Bytes, rejecting a missing or empty part
 func RangeProofAliceFromBytes(bzs [][]byte) (*RangeProofAlice, error) {
 	if !common.NonEmptyMultiBytes(bzs, RangeProofAliceBytesParts) {
 		return nil, fmt.Errorf("expected %d byte parts to construct RangeProofAlice", RangeProofAliceBytesParts)
@@ -104,41 +109,48 @@ func RangeProofAliceFromBytes(bzs [][]byte) (*RangeProofAlice, error) {
 	}, nil
 }
 
-func (pf *RangeProofAlice) Verify(pk *paillier.PublicKey, NTilde, h1, h2, c *big.Int) bool {
+func (pf *RangeProofAlice) Verify(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c *big.Int) bool {
+	return pf.VerifyWithReason(ec, pk, NTilde, h1, h2, c) == nil
+}
+
+// VerifyWithReason is Verify, returning an error that cites the step of GG18Spec (9) Fig. 9 whose check failed.
+// The modular exponentiations are run on the given backend, if any.
+func (pf *RangeProofAlice) VerifyWithReason(ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c *big.Int, optionalBackend ...common.ModExpBackend) error {
+	const fig = 9
 	if pf == nil || !pf.ValidateBasic() || pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil {
-		return false
+		return errors.New("RangeProofAlice.Verify() received a nil or malformed argument")
 	}
 
 	NSq := new(big.Int).Mul(pk.N, pk.N)
-	q := tss.EC().Params().N
-	q3 := new(big.Int).Mul(q, q)
-	q3.Mul(q3, q)
-
-	if !common.IsInInterval(pf.Z, NTilde) {
-		return false
-	}
-	if !common.IsInInterval(pf.U, pk.NSquare()) {
-		return false
-	}
-	if !common.IsInInterval(pf.W, NTilde) {
-		return false
+	powers := tss.CurvePowersOf(ec)
+	q, q3 := powers.Q, powers.Q3
+
+	// s2 = e*rho + gamma is less than 2*q^3*NTilde
+	nTildeBits := NTilde.BitLen()
+	if name, over := firstOversized(
+		sizeBound{"z", pf.Z, nTildeBits}, sizeBound{"u", pf.U, NSq.BitLen()}, sizeBound{"w", pf.W, nTildeBits},
+		sizeBound{"s", pf.S, pk.N.BitLen()}, sizeBound{"s1", pf.S1, q3.BitLen()},
+		sizeBound{"s2", pf.S2, q3.BitLen() + nTildeBits + 1},
+	); over {
+		return proofStepError(fig, "0", name+" is too large")
+	}
+	for _, in := range []struct {
+		name   string
+		v, mod *big.Int
+	}{
+		{"z", pf.Z, NTilde}, {"u", pf.U, pk.NSquare()}, {"w", pf.W, NTilde},
+	} {
+		if !common.IsInInterval(in.v, in.mod) || new(big.Int).GCD(nil, nil, in.v, in.mod).Cmp(one) != 0 {
+			return proofStepError(fig, "0", in.name+" is not a unit of its modulus")
+		}
 	}
 	if !common.IsInInterval(pf.S, pk.N) {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.Z, NTilde).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.U, pk.NSquare()).Cmp(one) != 0 {
-		return false
-	}
-	if new(big.Int).GCD(nil, nil, pf.W, NTilde).Cmp(one) != 0 {
-		return false
+		return proofStepError(fig, "0", "s is not in Z_N")
 	}
 
 	// 3.
-	if pf.S1.Cmp(q3) == 1 {
-		return false
+	if newRangeBound(q3).exceededBy(pf.S1) {
+		return proofStepError(fig, "3", "s1 > q^3")
 	}
 
 	// 1-2. e'
@@ -148,37 +160,52 @@ func (pf *RangeProofAlice) Verify(pk *paillier.PublicKey, NTilde, h1, h2, c *big
 		e = common.RejectionSample(q, eHash)
 	}
 
+	backend := modExpBackend(optionalBackend)
 	var products *big.Int // for the following conditionals
-	minusE := new(big.Int).Sub(zero, e)
 
 	{ // 4. gamma^s_1 * s^N * c^-e
 		modNSq := common.ModInt(NSq)
+		// c^-e is computed as (c^-1)^e, as the backend takes no negative exponents
+		cInv := new(big.Int).ModInverse(c, NSq)
+		if cInv == nil {
+			return proofStepError(fig, "4", "c is not a unit mod N^2")
+		}
+		exps, err := common.BatchModExp(backend, NSq,
+			[][]*big.Int{{cInv, pf.S, pk.Gamma()}},
+			[][]*big.Int{{e, pk.N, pf.S1}})
+		if err != nil {
+			return proofStepError(fig, "4", err.Error())
+		}
 
-		cExpMinusE := modNSq.Exp(c, minusE)
-		sExpN := modNSq.Exp(pf.S, pk.N)
-		gammaExpS1 := modNSq.Exp(pk.Gamma(), pf.S1)
+		cExpMinusE, sExpN, gammaExpS1 := exps[0][0], exps[0][1], exps[0][2]
 		// u != (4)
 		products = modNSq.Mul(gammaExpS1, sExpN)
 		products = modNSq.Mul(products, cExpMinusE)
 		if pf.U.Cmp(products) != 0 {
-			return false
+			return proofStepError(fig, "4", "u != Gamma^s1 * s^N * c^-e mod N^2")
 		}
 	}
 
 	{ // 5. h_1^s_1 * h_2^s_2 * z^-e
 		modNTilde := common.ModInt(NTilde)
+		// z is a unit of NTilde, checked above
+		zInv := new(big.Int).ModInverse(pf.Z, NTilde)
+		exps, err := common.BatchModExp(backend, NTilde,
+			[][]*big.Int{{h1, h2, zInv}},
+			[][]*big.Int{{pf.S1, pf.S2, e}})
+		if err != nil {
+			return proofStepError(fig, "5", err.Error())
+		}
 
-		h1ExpS1 := modNTilde.Exp(h1, pf.S1)
-		h2ExpS2 := modNTilde.Exp(h2, pf.S2)
-		zExpMinusE := modNTilde.Exp(pf.Z, minusE)
+		h1ExpS1, h2ExpS2, zExpMinusE := exps[0][0], exps[0][1], exps[0][2]
 		// w != (5)
 		products = modNTilde.Mul(h1ExpS1, h2ExpS2)
 		products = modNTilde.Mul(products, zExpMinusE)
 		if pf.W.Cmp(products) != 0 {
-			return false
+			return proofStepError(fig, "5", "w != h1^s1 * h2^s2 * z^-e mod NTilde")
 		}
 	}
-	return true
+	return nil
 }
 
 func (pf *RangeProofAlice) ValidateBasic() bool {
@@ -190,6 +217,7 @@ func (pf *RangeProofAlice) ValidateBasic() bool {
 		pf.S2 != nil
 }
 
+// Bytes returns the parts of the proof in the order read by RangeProofAliceFromBytes
 func (pf *RangeProofAlice) Bytes() [RangeProofAliceBytesParts][]byte {
 	return [...][]byte{
 		pf.Z.Bytes(),
@@ -200,3 +228,14 @@ func (pf *RangeProofAlice) Bytes() [RangeProofAliceBytesParts][]byte {
 		pf.S2.Bytes(),
 	}
 }
+
+// randReader returns the reader passed to a prover, or crypto/rand for the default
+func randReader(optionalReader []io.Reader) io.Reader {
+	if 1 < len(optionalReader) {
+		panic(errors.New("expected 0 or 1 item in `optionalReader`"))
+	}
+	if len(optionalReader) == 0 || optionalReader[0] == nil {
+		return rand.Reader
+	}
+	return optionalReader[0]
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -37,9 +38,42 @@ func TestProveRangeAlice(t *testing.T) {
 	primes := [2]*big.Int{common.GetRandomPrimeInt(testSafePrimeBits), common.GetRandomPrimeInt(testSafePrimeBits)}
 	NTildei, h1i, h2i, err := crypto.GenerateNTildei(primes)
 	assert.NoError(t, err)
-	proof, err := ProveRangeAlice(pk, c, NTildei, h1i, h2i, m, r)
+	proof, err := ProveRangeAlice(tss.EC(), pk, c, NTildei, h1i, h2i, m, r)
 	assert.NoError(t, err)
 
-	ok := proof.Verify(pk, NTildei, h1i, h2i, c)
+	ok := proof.Verify(tss.EC(), pk, NTildei, h1i, h2i, c)
 	assert.True(t, ok, "proof must verify")
 }
+
+func TestRangeProofAliceBytesRoundTrip(t *testing.T) {
+	q := tss.EC().Params().N
+
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	sk, pk := keys[0].PaillierSK, &keys[0].PaillierSK.PublicKey
+
+	m := common.GetRandomPositiveInt(q)
+	c, r, err := sk.EncryptAndReturnRandomness(m)
+	assert.NoError(t, err)
+	NTildei, h1i, h2i, err := keygen.LoadNTildeH1H2FromTestFixture(1)
+	assert.NoError(t, err)
+	proof, err := AliceInit(tss.EC(), pk, m, c, r, NTildei, h1i, h2i)
+	assert.NoError(t, err)
+
+	bzs := proof.Bytes()
+	parsed, err := RangeProofAliceFromBytes(bzs[:])
+	if assert.NoError(t, err) {
+		assert.Equal(t, proof, parsed)
+		assert.True(t, parsed.Verify(tss.EC(), pk, NTildei, h1i, h2i, c), "the parsed proof must verify")
+	}
+
+	// truncated input is rejected
+	_, err = RangeProofAliceFromBytes(bzs[:RangeProofAliceBytesParts-1])
+	assert.Error(t, err)
+	truncated := bzs
+	truncated[RangeProofAliceBytesParts-1] = nil
+	_, err = RangeProofAliceFromBytes(truncated[:])
+	assert.Error(t, err)
+}
@@ -7,7 +7,11 @@
 package mta
 
 import (
+	"crypto/elliptic"
 	"errors"
+	"fmt"
+	"hash"
+	"io"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -16,27 +20,74 @@ import (
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
+// VerifyError is the error of AliceEnd and AliceEndWC when Bob's proof fails to verify. It names the proof and the kind
+// of check that failed, and wraps the *ProofStepError of VerifyWithReason. The caller, who knows which counterparty
+// played Bob, sets Culprit so that the fault can be attributed to them.
+type VerifyError struct {
+	Culprit *tss.PartyID
+	Proof   string
+	Check   ProofCheck
+	Err     error
+}
+
+func (err *VerifyError) Error() string {
+	if err.Culprit != nil {
+		return fmt.Sprintf("%s.Verify() of %s returned false: %v", err.Proof, err.Culprit, err.Err)
+	}
+	return fmt.Sprintf("%s.Verify() returned false: %v", err.Proof, err.Err)
+}
+
+func (err *VerifyError) Unwrap() error {
+	return err.Err
+}
+
+// newVerifyError makes the VerifyError of the failed `proof`, whose VerifyWithReason returned `err`
+func newVerifyError(proof string, err error) *VerifyError {
+	verifyErr := &VerifyError{Proof: proof, Err: err}
+	var stepErr *ProofStepError
+	if errors.As(err, &stepErr) {
+		verifyErr.Check = stepErr.Check
+	}
+	return verifyErr
+}
+
+// AliceInit makes Alice's range proof for Bob. Its randomness is read from the optional reader, or from crypto/rand if
+// none is given.
 func AliceInit(
+	ec elliptic.Curve,
 	pkA *paillier.PublicKey,
 	a, cA, rA, NTildeB, h1B, h2B *big.Int,
+	optionalReader ...io.Reader,
 ) (pf *RangeProofAlice, err error) {
-	return ProveRangeAlice(pkA, cA, NTildeB, h1B, h2B, a, rA)
+	return ProveRangeAlice(ec, pkA, cA, NTildeB, h1B, h2B, a, rA, optionalReader...)
 }
 
 func BobMid(
+	ec elliptic.Curve,
 	pkA *paillier.PublicKey,
 	pf *RangeProofAlice,
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	optionalBackend ...common.ModExpBackend,
 ) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
-	if !pf.Verify(pkA, NTildeB, h1B, h2B, cA) {
-		err = errors.New("RangeProofAlice.Verify() returned false")
+	return BobMidWithHash(ec, nil, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, optionalBackend...)
+}
+
+// BobMidWithHash is BobMid, deriving the challenge of Bob's proof with the hash made by `newHash`, or SHA-512/256 if
+// it is nil
+func BobMidWithHash(
+	ec elliptic.Curve,
+	newHash func() hash.Hash,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	optionalBackend ...common.ModExpBackend,
+) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
+	if err = pf.VerifyWithReason(ec, pkA, NTildeB, h1B, h2B, cA, optionalBackend...); err != nil {
+		err = fmt.Errorf("RangeProofAlice.Verify() returned false: %v", err)
 		return
 	}
-	q := tss.EC().Params().N
-	q5 := new(big.Int).Mul(q, q)  // q^2
-	q5 = new(big.Int).Mul(q5, q5) // q^4
-	q5 = new(big.Int).Mul(q5, q)  // q^5
-	betaPrm = common.GetRandomPositiveInt(q5)
+	powers := tss.CurvePowersOf(ec)
+	betaPrm = common.GetRandomPositiveInt(powers.Q5)
 	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomness(betaPrm)
 	if err != nil {
 		return
@@ -47,26 +98,38 @@ func BobMid(
 	if cB, err = pkA.HomoAdd(cB, cBetaPrm); err != nil {
 		return
 	}
-	beta = common.ModInt(q).Sub(zero, betaPrm)
-	piB, err = ProveBob(pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand)
+	beta = common.ModInt(powers.Q).Sub(zero, betaPrm)
+	piB, err = ProveBobWithHash(ec, newHash, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand)
 	return
 }
 
 func BobMidWC(
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	B *crypto.ECPoint,
+	optionalBackend ...common.ModExpBackend,
+) (betaPrm, cB *big.Int, piB *ProofBobWC, err error) {
+	return BobMidWCWithHash(ec, nil, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, B, optionalBackend...)
+}
+
+// BobMidWCWithHash is BobMidWC, deriving the challenge of Bob's proof with the hash made by `newHash`, or SHA-512/256
+// if it is nil
+func BobMidWCWithHash(
+	ec elliptic.Curve,
+	newHash func() hash.Hash,
 	pkA *paillier.PublicKey,
 	pf *RangeProofAlice,
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
 	B *crypto.ECPoint,
+	optionalBackend ...common.ModExpBackend,
 ) (betaPrm, cB *big.Int, piB *ProofBobWC, err error) {
-	if !pf.Verify(pkA, NTildeB, h1B, h2B, cA) {
-		err = errors.New("RangeProofAlice.Verify() returned false")
+	if err = pf.VerifyWithReason(ec, pkA, NTildeB, h1B, h2B, cA, optionalBackend...); err != nil {
+		err = fmt.Errorf("RangeProofAlice.Verify() returned false: %v", err)
 		return
 	}
-	q := tss.EC().Params().N
-	q5 := new(big.Int).Mul(q, q)  // q^2
-	q5 = new(big.Int).Mul(q5, q5) // q^4
-	q5 = new(big.Int).Mul(q5, q)  // q^5
-	betaPrm = common.GetRandomPositiveInt(q5)
+	betaPrm = common.GetRandomPositiveInt(tss.CurvePowersOf(ec).Q5)
 	cBetaPrm, cRand, err := pkA.EncryptAndReturnRandomness(betaPrm)
 	if err != nil {
 		return
@@ -79,43 +142,78 @@ func BobMidWC(
 	if err != nil {
 		return
 	}
-	piB, err = ProveBobWC(pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B)
+	piB, err = ProveBobWCWithHash(ec, newHash, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B)
 	return
 }
 
+// AliceEnd verifies Bob's proof and decrypts cB. A proof that fails to verify returns a *VerifyError.
 func AliceEnd(
+	ec elliptic.Curve,
 	pkA *paillier.PublicKey,
 	pf *ProofBob,
 	h1A, h2A, cA, cB, NTildeA *big.Int,
 	sk *paillier.PrivateKey,
+	optionalBackend ...common.ModExpBackend,
 ) (alphaIJ *big.Int, err error) {
-	if !pf.Verify(pkA, NTildeA, h1A, h2A, cA, cB) {
-		err = errors.New("ProofBob.Verify() returned false")
+	return AliceEndWithHash(ec, nil, pkA, pf, h1A, h2A, cA, cB, NTildeA, sk, optionalBackend...)
+}
+
+// AliceEndWithHash is AliceEnd for a proof whose challenge was derived with the hash made by `newHash`, or SHA-512/256
+// if it is nil
+func AliceEndWithHash(
+	ec elliptic.Curve,
+	newHash func() hash.Hash,
+	pkA *paillier.PublicKey,
+	pf *ProofBob,
+	h1A, h2A, cA, cB, NTildeA *big.Int,
+	sk *paillier.PrivateKey,
+	optionalBackend ...common.ModExpBackend,
+) (alphaIJ *big.Int, err error) {
+	if err = pf.VerifyWithHash(ec, newHash, pkA, NTildeA, h1A, h2A, cA, cB, optionalBackend...); err != nil {
+		err = newVerifyError("ProofBob", err)
 		return
 	}
 	if alphaIJ, err = sk.Decrypt(cB); err != nil {
 		return
 	}
-	q := tss.EC().Params().N
+	q := ec.Params().N
 	alphaIJ.Mod(alphaIJ, q)
 	return
 }
 
+// AliceEndWC verifies Bob's proof and decrypts cB. A proof that fails to verify returns a *VerifyError.
 func AliceEndWC(
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *ProofBobWC,
+	B *crypto.ECPoint,
+	cA, cB, NTildeA, h1A, h2A *big.Int,
+	sk *paillier.PrivateKey,
+	optionalBackend ...common.ModExpBackend,
+) (muIJ, muIJRec, muIJRand *big.Int, err error) {
+	return AliceEndWCWithHash(ec, nil, pkA, pf, B, cA, cB, NTildeA, h1A, h2A, sk, optionalBackend...)
+}
+
+// AliceEndWCWithHash is AliceEndWC for a proof whose challenge was derived with the hash made by `newHash`, or
+// SHA-512/256 if it is nil
+func AliceEndWCWithHash(
+	ec elliptic.Curve,
+	newHash func() hash.Hash,
 	pkA *paillier.PublicKey,
 	pf *ProofBobWC,
 	B *crypto.ECPoint,
 	cA, cB, NTildeA, h1A, h2A *big.Int,
 	sk *paillier.PrivateKey,
+	optionalBackend ...common.ModExpBackend,
 ) (muIJ, muIJRec, muIJRand *big.Int, err error) {
-	if !pf.Verify(pkA, NTildeA, h1A, h2A, cA, cB, B) {
-		err = errors.New("ProofBobWC.Verify() returned false")
+	if err = pf.VerifyWithHash(ec, newHash, pkA, NTildeA, h1A, h2A, cA, cB, B, optionalBackend...); err != nil {
+		err = newVerifyError("ProofBobWC", err)
 		return
 	}
 	if muIJRec, muIJRand, err = sk.DecryptAndRecoverRandomness(cB); err != nil {
 		return
 	}
-	q := tss.EC().Params().N
+	q := ec.Params().N
 	muIJ = new(big.Int).Mod(muIJRec, q)
 	return
 }
@@ -7,7 +7,11 @@
 package mta
 
 import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
 	"math/big"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -41,13 +45,13 @@ func TestShareProtocol(t *testing.T) {
 
 	cA, rA, err := pk.EncryptAndReturnRandomness(a)
 	assert.NoError(t, err)
-	pf, err := AliceInit(pk, a, cA, rA, NTildej, h1j, h2j)
+	pf, err := AliceInit(tss.EC(), pk, a, cA, rA, NTildej, h1j, h2j)
 	assert.NoError(t, err)
 
-	_, cB, betaPrm, pfB, err := BobMid(pk, pf, b, cA, NTildei, h1i, h2i, NTildej, h1j, h2j)
+	_, cB, betaPrm, pfB, err := BobMid(tss.EC(), pk, pf, b, cA, NTildei, h1i, h2i, NTildej, h1j, h2j)
 	assert.NoError(t, err)
 
-	alpha, err := AliceEnd(pk, pfB, h1i, h2i, cA, cB, NTildei, sk)
+	alpha, err := AliceEnd(tss.EC(), pk, pfB, h1i, h2i, cA, cB, NTildei, sk)
 	assert.NoError(t, err)
 
 	// expect: alpha = ab + betaPrm
@@ -57,6 +61,84 @@ func TestShareProtocol(t *testing.T) {
 	assert.Equal(t, 0, alpha.Cmp(aTimesBPlusBetaModQ))
 }
 
+// mtaSession holds the inputs of one MtA exchange and Alice's first message. Alice's Paillier key is that of the
+// first keygen test fixture; Bob's NTilde, h1, h2 (i) and Alice's (j) are those of the first two.
+type mtaSession struct {
+	sk                                   *paillier.PrivateKey
+	pk                                   *paillier.PublicKey
+	a, b                                 *big.Int
+	gB                                   *crypto.ECPoint
+	NTildei, h1i, h2i, NTildej, h1j, h2j *big.Int
+	cA                                   *big.Int
+	pfA                                  *RangeProofAlice
+}
+
+func newMtASession(t *testing.T) *mtaSession {
+	q := tss.EC().Params().N
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	s := &mtaSession{sk: keys[0].PaillierSK, pk: &keys[0].PaillierSK.PublicKey}
+	s.a, s.b = common.GetRandomPositiveInt(q), common.GetRandomPositiveInt(q)
+	s.gB = crypto.ScalarBaseMult(tss.EC(), s.b)
+	s.NTildei, s.h1i, s.h2i, err = keygen.LoadNTildeH1H2FromTestFixture(0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	s.NTildej, s.h1j, s.h2j, err = keygen.LoadNTildeH1H2FromTestFixture(1)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var rA *big.Int
+	if s.cA, rA, err = s.pk.EncryptAndReturnRandomness(s.a); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if s.pfA, err = AliceInit(tss.EC(), s.pk, s.a, s.cA, rA, s.NTildej, s.h1j, s.h2j); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return s
+}
+
+// bobMid runs Bob's step without check, returning cB, beta' and the proof
+func (s *mtaSession) bobMid(t *testing.T) (cB, betaPrm *big.Int, pfB *ProofBob) {
+	_, cB, betaPrm, pfB, err := BobMid(tss.EC(), s.pk, s.pfA, s.b, s.cA, s.NTildei, s.h1i, s.h2i, s.NTildej, s.h1j, s.h2j)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return
+}
+
+// bobMidWC runs Bob's step with check, returning cB, beta' and the proof
+func (s *mtaSession) bobMidWC(t *testing.T, optionalBackend ...common.ModExpBackend) (cB, betaPrm *big.Int, pfB *ProofBobWC) {
+	betaPrm, cB, pfB, err := BobMidWC(
+		tss.EC(), s.pk, s.pfA, s.b, s.cA, s.NTildei, s.h1i, s.h2i, s.NTildej, s.h1j, s.h2j, s.gB, optionalBackend...)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return
+}
+
+func TestVerifyAnyVersion(t *testing.T) {
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMid(t)
+
+	v1, v2 := pfB.Bytes(), pfB.BytesV2()
+	for version, bzs := range map[byte][][]byte{ProofBobVersion1: v1[:], ProofBobVersion2: v2[:]} {
+		detected, err := ProofBobVersionOf(bzs)
+		assert.NoError(t, err)
+		assert.Equal(t, version, detected)
+		assert.NoError(t, VerifyAnyVersion(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, bzs), "version %d", version)
+		assert.Error(t, VerifyAnyVersion(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cB, s.cA, bzs), "version %d", version)
+	}
+
+	v2[0] = []byte{3}
+	err := VerifyAnyVersion(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, v2[:])
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unsupported ProofBob version 3")
+	}
+}
+
 func TestShareProtocolWC(t *testing.T) {
 	q := tss.EC().Params().N
 
@@ -74,15 +156,15 @@ func TestShareProtocolWC(t *testing.T) {
 
 	cA, rA, err := pk.EncryptAndReturnRandomness(a)
 	assert.NoError(t, err)
-	pf, err := AliceInit(pk, a, cA, rA, NTildej, h1j, h2j)
+	pf, err := AliceInit(tss.EC(), pk, a, cA, rA, NTildej, h1j, h2j)
 	assert.NoError(t, err)
 
 	gBPoint, err := crypto.NewECPoint(tss.EC(), gBX, gBY)
 	assert.NoError(t, err)
-	betaPrm, cB, pfB, err := BobMidWC(pk, pf, b, cA, NTildei, h1i, h2i, NTildej, h1j, h2j, gBPoint)
+	betaPrm, cB, pfB, err := BobMidWC(tss.EC(), pk, pf, b, cA, NTildei, h1i, h2i, NTildej, h1j, h2j, gBPoint)
 	assert.NoError(t, err)
 
-	muIJ, _, muRandIJ, err := AliceEndWC(pk, pfB, gBPoint, cA, cB, NTildei, h1i, h2i, sk)
+	muIJ, _, muRandIJ, err := AliceEndWC(tss.EC(), pk, pfB, gBPoint, cA, cB, NTildei, h1i, h2i, sk)
 	assert.NoError(t, err)
 	assert.NotNil(t, muRandIJ)
 
@@ -92,3 +174,312 @@ func TestShareProtocolWC(t *testing.T) {
 	aTimesBPlusBetaModQ := new(big.Int).Mod(aTimesBPlusBeta, q)
 	assert.Equal(t, 0, muIJ.Cmp(aTimesBPlusBetaModQ))
 }
+
+func TestProofBobWCVerifyWithReason(t *testing.T) {
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMidWC(t)
+	assert.NoError(t, pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB))
+
+	// each kind of failed check is named
+	tamperedCheck := func(tamper func(pf *ProofBobWC)) ProofCheck {
+		values := *pfB.ProofBob
+		tampered := &ProofBobWC{ProofBob: &values, U: pfB.U}
+		tamper(tampered)
+		var stepErr *ProofStepError
+		if err := tampered.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB); assert.True(t, errors.As(err, &stepErr)) {
+			return stepErr.Check
+		}
+		return ""
+	}
+	q7 := tss.CurvePowersOf(tss.EC()).Q7
+	assert.Equal(t, ProofCheckRange, tamperedCheck(func(pf *ProofBobWC) { pf.T1 = new(big.Int).Add(q7, big.NewInt(1)) }))
+	assert.Equal(t, ProofCheckDLog, tamperedCheck(func(pf *ProofBobWC) { pf.U = s.gB }))
+	assert.Equal(t, ProofCheckModNTilde, tamperedCheck(func(pf *ProofBobWC) { pf.S2 = new(big.Int).Add(pf.S2, big.NewInt(1)) }))
+
+	// s is not hashed into the challenge and is only checked in step 7
+	pfB.S = common.ModInt(s.pk.N).Mul(pfB.S, big.NewInt(2))
+	err := pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "GG18Spec (9) Fig. 10 step 7:")
+	}
+	assert.False(t, pfB.Verify(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB))
+
+	_, _, _, err = AliceEndWC(tss.EC(), s.pk, pfB, s.gB, s.cA, cB, s.NTildei, s.h1i, s.h2i, s.sk)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "step 7", "AliceEndWC should surface the failed step")
+		var verifyErr *VerifyError
+		if assert.True(t, errors.As(err, &verifyErr)) {
+			assert.Equal(t, "ProofBobWC", verifyErr.Proof)
+			assert.Equal(t, ProofCheckModNSquare, verifyErr.Check)
+			assert.Nil(t, verifyErr.Culprit, "AliceEndWC does not know who played Bob")
+		}
+		var stepErr *ProofStepError
+		if assert.True(t, errors.As(err, &stepErr)) {
+			assert.Equal(t, 10, stepErr.Fig)
+			assert.Equal(t, ProofCheckModNSquare, stepErr.Check)
+		}
+	}
+}
+
+func TestShareProtocolWithHash(t *testing.T) {
+	s := newMtASession(t)
+
+	// a proof made under SHA-256 verifies only under SHA-256
+	_, cB, pfBWC, err := BobMidWCWithHash(tss.EC(), sha256.New, s.pk, s.pfA, s.b, s.cA, s.NTildei, s.h1i, s.h2i, s.NTildej, s.h1j, s.h2j, s.gB)
+	assert.NoError(t, err)
+	_, _, _, err = AliceEndWCWithHash(tss.EC(), sha256.New, s.pk, pfBWC, s.gB, s.cA, cB, s.NTildei, s.h1i, s.h2i, s.sk)
+	assert.NoError(t, err)
+	_, _, _, err = AliceEndWC(tss.EC(), s.pk, pfBWC, s.gB, s.cA, cB, s.NTildei, s.h1i, s.h2i, s.sk)
+	var verifyErr *VerifyError
+	assert.True(t, errors.As(err, &verifyErr), "the default hash must reject a SHA-256 proof")
+
+	// the helpers that recompute the challenge take the hash too
+	q := tss.EC().Params().N
+	assert.Equal(t, pfBWC.challenge(sha256.New, q, s.pk, s.cA, cB, s.gB), pfBWC.ChallengeWithHash(tss.EC(), sha256.New, s.pk, s.cA, cB, s.gB))
+	assert.NotEqual(t, pfBWC.Challenge(tss.EC(), s.pk, s.cA, cB, s.gB), pfBWC.ChallengeWithHash(tss.EC(), sha256.New, s.pk, s.cA, cB, s.gB))
+	assert.NoError(t, pfBWC.CheckCiphertextRelationWithHash(tss.EC(), sha256.New, s.pk, s.cA, cB, s.gB))
+	assert.Error(t, pfBWC.CheckCiphertextRelation(tss.EC(), s.pk, s.cA, cB, s.gB))
+
+	_, cB, _, pfB, err := BobMidWithHash(tss.EC(), sha256.New, s.pk, s.pfA, s.b, s.cA, s.NTildei, s.h1i, s.h2i, s.NTildej, s.h1j, s.h2j)
+	assert.NoError(t, err)
+	assert.Equal(t, (&ProofBobWC{ProofBob: pfB}).challenge(sha256.New, q, s.pk, s.cA, cB, nil), pfB.ChallengeWithHash(tss.EC(), sha256.New, s.pk, s.cA, cB))
+	assert.NoError(t, pfB.VerifyWithHash(tss.EC(), sha256.New, s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB))
+	assert.Error(t, pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB), "the default hash must reject a SHA-256 proof")
+
+	// and a proof made under the default does not verify under SHA-256
+	cB, _, pfBWC = s.bobMidWC(t)
+	assert.NoError(t, pfBWC.VerifyWithHash(tss.EC(), nil, s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB))
+	assert.Error(t, pfBWC.VerifyWithHash(tss.EC(), sha256.New, s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB))
+}
+
+func TestProofBobWCCurveTag(t *testing.T) {
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMidWC(t)
+
+	tagged, err := pfB.BytesWithCurveTag(tss.EC())
+	if !assert.NoError(t, err) {
+		return
+	}
+	parsed, err := ProofBobWCFromBytes(tss.EC(), tagged[:])
+	if assert.NoError(t, err) {
+		assert.NoError(t, parsed.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB))
+	}
+
+	// a proof tagged for secp256k1 is rejected by name on a session over another curve
+	_, err = ProofBobWCFromBytes(elliptic.P256(), tagged[:])
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `made on the curve "secp256k1", not on this session's curve "nist256p1"`)
+	}
+	untagged := pfB.Bytes()
+	_, err = ProofBobWCFromBytes(tss.EC(), untagged[:])
+	assert.NoError(t, err, "the tag is optional")
+}
+
+func TestProofBobWCValidateBasic(t *testing.T) {
+	ints := func() []*big.Int {
+		return []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5),
+			big.NewInt(6), big.NewInt(7), big.NewInt(8), big.NewInt(9), big.NewInt(10)}
+	}
+	newProof := func(U *crypto.ECPoint) *ProofBobWC {
+		v := ints()
+		return &ProofBobWC{
+			ProofBob: &ProofBob{Z: v[0], ZPrm: v[1], T: v[2], V: v[3], W: v[4], S: v[5], S1: v[6], S2: v[7], T1: v[8], T2: v[9]},
+			U:        U,
+		}
+	}
+	onCurve := crypto.ScalarBaseMult(tss.EC(), big.NewInt(3))
+	assert.True(t, newProof(onCurve).ValidateBasic())
+
+	offCurve := crypto.NewECPointNoCurveCheck(tss.EC(), onCurve.X(), new(big.Int).Add(onCurve.Y(), big.NewInt(1)))
+	assert.False(t, newProof(offCurve).ValidateBasic(), "a U off the curve must be rejected")
+	assert.False(t, newProof(nil).ValidateBasic())
+	assert.False(t, (&ProofBobWC{U: onCurve}).ValidateBasic())
+}
+
+func TestProofBobWCCheckCiphertextRelation(t *testing.T) {
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMidWC(t)
+
+	assert.NoError(t, pfB.CheckCiphertextRelation(tss.EC(), s.pk, s.cA, cB, s.gB))
+
+	// cB * Enc(1) decrypts to one more than Bob's share
+	encOne, err := s.pk.Encrypt(big.NewInt(1))
+	assert.NoError(t, err)
+	tampered, err := s.pk.HomoAdd(cB, encOne)
+	assert.NoError(t, err)
+	err = pfB.CheckCiphertextRelation(tss.EC(), s.pk, s.cA, tampered, s.gB)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "step 4")
+	}
+	// a cB that is not a homomorphic function of cA
+	err = pfB.CheckCiphertextRelation(tss.EC(), s.pk, encOne, cB, s.gB)
+	assert.Error(t, err)
+	// a commitment to another b
+	err = pfB.CheckCiphertextRelation(tss.EC(), s.pk, s.cA, cB, crypto.ScalarBaseMult(tss.EC(), s.a))
+	assert.Error(t, err)
+}
+
+func TestProofBobChallenge(t *testing.T) {
+	q := tss.EC().Params().N
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMid(t)
+	cBWC, _, pfBWC := s.bobMidWC(t)
+
+	// the exposed challenge is the one that verification derives
+	e := pfB.Challenge(tss.EC(), s.pk, s.cA, cB)
+	assert.Equal(t, (&ProofBobWC{ProofBob: pfB}).challenge(nil, q, s.pk, s.cA, cB, nil), e)
+	eWC := pfBWC.Challenge(tss.EC(), s.pk, s.cA, cBWC, s.gB)
+	assert.Equal(t, pfBWC.challenge(nil, q, s.pk, s.cA, cBWC, s.gB), eWC)
+
+	// and the one that the prover answered: h1^s1 * h2^s2 = z^e * z' mod NTilde
+	modNTilde := common.ModInt(s.NTildei)
+	answers := func(pf *ProofBob, e *big.Int) bool {
+		left := modNTilde.Mul(modNTilde.Exp(s.h1i, pf.S1), modNTilde.Exp(s.h2i, pf.S2))
+		right := modNTilde.Mul(modNTilde.Exp(pf.Z, e), pf.ZPrm)
+		return left.Cmp(right) == 0
+	}
+	assert.True(t, answers(pfB, e))
+	assert.True(t, answers(pfBWC.ProofBob, eWC))
+	assert.False(t, answers(pfB, new(big.Int).Add(e, big.NewInt(1))))
+
+	// other public inputs give another challenge
+	assert.NotEqual(t, e, pfB.Challenge(tss.EC(), s.pk, s.cA, s.cA))
+	assert.NotEqual(t, eWC, pfBWC.Challenge(tss.EC(), s.pk, s.cA, cBWC, nil))
+	assert.Nil(t, pfBWC.Challenge(tss.EC(), nil, s.cA, cBWC, s.gB))
+}
+
+func TestProofBobWCSizeBounds(t *testing.T) {
+	s := newMtASession(t)
+	cB, _, pfB := s.bobMidWC(t)
+
+	verify := func() error {
+		return pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, s.cA, cB, s.gB)
+	}
+	assert.NoError(t, verify())
+
+	// the exponents s2 and t2 at the largest size allowed pass the size checks
+	s2, t2 := pfB.S2, pfB.T2
+	maxBits := tss.CurvePowers().Q3.BitLen() + s.NTildei.BitLen() + 1
+	pfB.S2 = new(big.Int).Sub(new(big.Int).Lsh(one, uint(maxBits)), one)
+	pfB.T2 = new(big.Int).Set(pfB.S2)
+	if err := verify(); assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "step 0:", "values at the bound should pass the size checks")
+	}
+
+	// one bit more is rejected before any arithmetic is done with it
+	pfB.S2, pfB.T2 = new(big.Int).Lsh(one, uint(maxBits)), t2
+	if err := verify(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "step 0: s2 is missing or too large")
+	}
+	pfB.S2 = new(big.Int).Lsh(one, 1<<22)
+	if err := verify(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "step 0: s2 is missing or too large")
+	}
+	pfB.S2 = s2
+}
+
+// squareAndMultiply is a ModExpBackend that computes each exponentiation bit by bit rather than with big.Int.Exp
+type squareAndMultiply struct {
+	batches int32
+}
+
+func (b *squareAndMultiply) BatchModExp(mod *big.Int, bases, exps [][]*big.Int) [][]*big.Int {
+	atomic.AddInt32(&b.batches, 1)
+	results := make([][]*big.Int, len(bases))
+	for i := range bases {
+		results[i] = make([]*big.Int, len(bases[i]))
+		for k, base := range bases[i] {
+			r, x := big.NewInt(1), new(big.Int).Mod(base, mod)
+			for bit := exps[i][k].BitLen() - 1; 0 <= bit; bit-- {
+				r.Mul(r, r).Mod(r, mod)
+				if exps[i][k].Bit(bit) == 1 {
+					r.Mul(r, x).Mod(r, mod)
+				}
+			}
+			results[i][k] = r
+		}
+	}
+	return results
+}
+
+func TestModExpBackend(t *testing.T) {
+	q := tss.EC().Params().N
+	s := newMtASession(t)
+
+	backend := &squareAndMultiply{}
+	cA, pf := s.cA, s.pfA
+	cB, betaPrm, pfB := s.bobMidWC(t, backend)
+	muIJ, _, _, err := AliceEndWC(tss.EC(), s.pk, pfB, s.gB, cA, cB, s.NTildei, s.h1i, s.h2i, s.sk, backend)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, muIJ.Cmp(new(big.Int).Mod(new(big.Int).Add(new(big.Int).Mul(s.a, s.b), betaPrm), q)))
+	assert.EqualValues(t, 4, atomic.LoadInt32(&backend.batches), "the range proof and Bob's proof should run on the backend")
+
+	// honest and tampered proofs get the same verdict from either backend
+	check := func(name string) {
+		assert.Equal(t,
+			pf.VerifyWithReason(tss.EC(), s.pk, s.NTildej, s.h1j, s.h2j, cA),
+			pf.VerifyWithReason(tss.EC(), s.pk, s.NTildej, s.h1j, s.h2j, cA, backend), name+": RangeProofAlice")
+		assert.Equal(t,
+			pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cA, cB, s.gB),
+			pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cA, cB, s.gB, backend), name+": ProofBobWC")
+		assert.Equal(t,
+			pfB.ProofBob.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cA, cB),
+			pfB.ProofBob.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cA, cB, backend), name+": ProofBob")
+	}
+	check("honest")
+	assert.NoError(t, pf.VerifyWithReason(tss.EC(), s.pk, s.NTildej, s.h1j, s.h2j, cA, backend))
+	pf.S2 = new(big.Int).Add(pf.S2, one)
+	pfB.S = common.ModInt(s.pk.N).Mul(pfB.S, big.NewInt(2))
+	check("tampered")
+	assert.Error(t, pf.VerifyWithReason(tss.EC(), s.pk, s.NTildej, s.h1j, s.h2j, cA, backend))
+	assert.Error(t, pfB.VerifyWithReason(tss.EC(), s.pk, s.NTildei, s.h1i, s.h2i, cA, cB, s.gB, backend))
+}
+
+func TestProofBobConversions(t *testing.T) {
+	q := tss.EC().Params().N
+	s := newMtASession(t)
+	pk, NTildei, h1i, h2i, cA, b, gB := s.pk, s.NTildei, s.h1i, s.h2i, s.cA, s.b, s.gB
+
+	betaPrm := common.GetRandomPositiveInt(q)
+	cBetaPrm, cRand, err := pk.EncryptAndReturnRandomness(betaPrm)
+	assert.NoError(t, err)
+	cB, err := pk.HomoMult(b, cA)
+	assert.NoError(t, err)
+	cB, err = pk.HomoAdd(cB, cBetaPrm)
+	assert.NoError(t, err)
+
+	// a proof with check round-trips through a ProofBob, keeping its ten shared components and U
+	pfWC, err := ProveBobWC(tss.EC(), pk, NTildei, h1i, h2i, cA, cB, b, betaPrm, cRand, gB)
+	assert.NoError(t, err)
+	bob := pfWC.ToBob()
+	assert.Equal(t, pfWC.ProofBob.Bytes(), bob.Bytes())
+	back, err := bob.ToWC(pfWC.U)
+	if assert.NoError(t, err) {
+		assert.Equal(t, pfWC.Bytes(), back.Bytes())
+		assert.True(t, back.Verify(tss.EC(), pk, NTildei, h1i, h2i, cA, cB, gB))
+	}
+	assert.False(t, bob.Verify(tss.EC(), pk, NTildei, h1i, h2i, cA, cB), "U and X are hashed into the challenge of a proof with check")
+
+	// the conversions copy the proof, down to its values
+	origZ, origT := new(big.Int).Set(pfWC.Z), new(big.Int).Set(pfWC.T)
+	bob.Z.SetInt64(1)
+	back.T.SetInt64(1)
+	assert.Equal(t, 0, origZ.Cmp(pfWC.Z), "changing a value of ToBob's result must not change the original")
+	assert.Equal(t, 0, origT.Cmp(bob.T), "changing a value of ToWC's result must not change the original")
+
+	// a proof without check verifies in the form with a nil U
+	pfBob, err := ProveBob(tss.EC(), pk, NTildei, h1i, h2i, cA, cB, b, betaPrm, cRand)
+	assert.NoError(t, err)
+	wc, err := pfBob.ToWC(nil)
+	if assert.NoError(t, err) {
+		assert.Nil(t, wc.U)
+		assert.True(t, wc.Verify(tss.EC(), pk, NTildei, h1i, h2i, cA, cB, nil))
+	}
+
+	offCurve := crypto.NewECPointNoCurveCheck(tss.EC(), gB.X(), new(big.Int).Add(gB.Y(), big.NewInt(1)))
+	_, err = pfBob.ToWC(offCurve)
+	assert.Error(t, err, "a U that is not on the curve should be rejected")
+	var nilBob *ProofBob
+	_, err = nilBob.ToWC(nil)
+	assert.Error(t, err)
+}
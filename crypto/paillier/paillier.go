@@ -16,8 +16,12 @@
 package paillier
 
 import (
+	"context"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	gmath "math"
 	"math/big"
 	"runtime"
@@ -34,6 +38,7 @@ const (
 	ProofIters         = 13
 	verifyPrimesUntil  = 1000 // Verify uses primes <1000
 	pQBitLenDifference = 3    // >1020-bit P-Q
+	blindingBits       = 64   // bits of the random multiples that blind the secret exponents of decryption
 )
 
 type (
@@ -53,6 +58,8 @@ type (
 
 var (
 	ErrMessageTooLong = fmt.Errorf("the message is too large or < 0")
+	ErrBadRandomness  = fmt.Errorf("the randomness is not in Z*_N")
+	ErrBadCiphertext  = fmt.Errorf("the ciphertext is not in Z*_N^2")
 
 	zero = big.NewInt(0)
 	one  = big.NewInt(1)
@@ -65,6 +72,17 @@ func init() {
 
 // len is the length of the modulus (each prime = len / 2)
 func GenerateKeyPair(modulusBitLen int, timeout time.Duration, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
+	return GenerateKeyPairWithLimiter(modulusBitLen, timeout, nil, optionalConcurrency...)
+}
+
+// GenerateKeyPairWithLimiter is GenerateKeyPair searching for the primes within the budget of a goroutine limiter
+func GenerateKeyPairWithLimiter(modulusBitLen int, timeout time.Duration, limiter *common.GoroutineLimiter, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
+	return GenerateKeyPairWithContext(context.Background(), modulusBitLen, timeout, limiter, optionalConcurrency...)
+}
+
+// GenerateKeyPairWithContext is GenerateKeyPairWithLimiter that stops the search for the primes when `ctx` is done,
+// returning its error
+func GenerateKeyPairWithContext(ctx context.Context, modulusBitLen int, timeout time.Duration, limiter *common.GoroutineLimiter, optionalConcurrency ...int) (privateKey *PrivateKey, publicKey *PublicKey, err error) {
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
@@ -80,7 +98,7 @@ func GenerateKeyPair(modulusBitLen int, timeout time.Duration, optionalConcurren
 	{
 		tmp := new(big.Int)
 		for {
-			sgps, err := common.GetRandomSafePrimesConcurrent(modulusBitLen/2, 2, timeout, concurrency)
+			sgps, err := common.GetRandomSafePrimesConcurrentWithContext(ctx, modulusBitLen/2, 2, timeout, concurrency, limiter)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -106,6 +124,13 @@ func GenerateKeyPair(modulusBitLen int, timeout time.Duration, optionalConcurren
 	return
 }
 
+// MinPaillierBits returns the minimum bit length of a Paillier modulus N for the MtA protocols to be sound over the
+// given curve. The MtA proofs admit values of up to q^7 with slack, so N must exceed q^8 for the homomorphic
+// operations never to wrap around the modulus. Keygen and mta.PaillierCurveCompatible both check moduli against it.
+func MinPaillierBits(curve elliptic.Curve) int {
+	return 8 * curve.Params().N.BitLen()
+}
+
 // ----- //
 
 func (pk *PublicKey) EncryptWithChosenRandomness(m, x *big.Int) (c *big.Int, err error) {
@@ -126,6 +151,16 @@ func (pk *PublicKey) EncryptWithChosenRandomness(m, x *big.Int) (c *big.Int, err
 	return
 }
 
+// EncryptWithRandomness encrypts m with the randomness r, which must be in Z*_N, so that the ciphertext is the one that
+// EncryptAndReturnRandomness would have made had it drawn r. A verifier can use it to re-derive a counterparty's
+// ciphertext, such as c_beta' of the MtA, from the m and r that were revealed in an identifiable abort.
+func (pk *PublicKey) EncryptWithRandomness(m, r *big.Int) (*big.Int, error) {
+	if !common.IsNumberInMultiplicativeGroup(pk.N, r) {
+		return nil, ErrBadRandomness
+	}
+	return pk.EncryptWithChosenRandomness(m, r)
+}
+
 func (pk *PublicKey) EncryptAndReturnRandomness(m *big.Int) (c *big.Int, x *big.Int, err error) {
 	if m.Cmp(zero) == -1 || m.Cmp(pk.N) != -1 { // m < 0 || m >= N ?
 		return nil, nil, ErrMessageTooLong
@@ -170,6 +205,19 @@ func (pk *PublicKey) HomoAdd(c1, c2 *big.Int) (*big.Int, error) {
 	return common.ModInt(NSq).Mul(c1, c2), nil
 }
 
+// HomoSub returns an encryption of m1 - m2 mod N given encryptions c1 of m1 and c2 of m2, computing c1 * c2^-1 mod N2
+func (pk *PublicKey) HomoSub(c1, c2 *big.Int) (*big.Int, error) {
+	NSq := pk.NSquare()
+	// both must be units of Z_N2: a ciphertext out of range or sharing a factor with N has no inverse and decrypts to
+	// nothing meaningful
+	if !common.IsNumberInMultiplicativeGroup(NSq, c1) || !common.IsNumberInMultiplicativeGroup(NSq, c2) {
+		return nil, ErrBadCiphertext
+	}
+	modNSq := common.ModInt(NSq)
+	// c1 * c2^-1 mod N2
+	return modNSq.Mul(c1, modNSq.Inverse(c2)), nil
+}
+
 func (pk *PublicKey) NSquare() *big.Int {
 	return new(big.Int).Mul(pk.N, pk.N)
 }
@@ -186,7 +234,12 @@ func (pk *PublicKey) Gamma() *big.Int {
 
 // ----- //
 
-func (sk *PrivateKey) Decrypt(c *big.Int) (m *big.Int, err error) {
+// Decrypt decrypts c. The secret exponent LambdaN is blinded with a fresh random multiple of lambda(N^2) = N*LambdaN,
+// and c with a fresh random r^N, neither of which changes c^LambdaN mod N^2. So the timing of math/big's exponentiation,
+// which is not constant-time, depends on neither the key nor the ciphertext alone. The blinding is drawn from the
+// optional reader, or crypto/rand by default.
+func (sk *PrivateKey) Decrypt(c *big.Int, optionalReader ...io.Reader) (m *big.Int, err error) {
+	reader := randReader(optionalReader)
 	NSq := sk.NSquare()
 	modN := common.ModInt(sk.N)
 	modNSq := common.ModInt(NSq)
@@ -194,17 +247,22 @@ func (sk *PrivateKey) Decrypt(c *big.Int) (m *big.Int, err error) {
 		return nil, ErrMessageTooLong
 	}
 	// 1. L(u) = (c^LambdaN-1 mod N2) / N
-	Lc := L(modNSq.Exp(c, sk.LambdaN), sk.N)
-	// 2. L(u) = (Gamma^LambdaN-1 mod N2) / N
-	Lg := L(modNSq.Exp(sk.Gamma(), sk.LambdaN), sk.N)
+	cBlind := modNSq.Mul(c, modNSq.Exp(common.GetRandomPositiveRelativelyPrimeIntFrom(reader, sk.N), sk.N))
+	Lc := L(modNSq.Exp(cBlind, blindExponent(reader, sk.LambdaN, new(big.Int).Mul(sk.N, sk.LambdaN))), sk.N)
+	// 2. L(u) = (Gamma^LambdaN-1 mod N2) / N, which is LambdaN mod N as Gamma^LambdaN = 1 + LambdaN*N mod N2
+	Lg := new(big.Int).Mod(sk.LambdaN, sk.N)
 	// 3. (1) * modInv(2) mod N
 	inv := modN.Inverse(Lg)
 	m = modN.Mul(Lc, inv)
 	return
 }
 
-func (sk *PrivateKey) DecryptAndRecoverRandomness(c *big.Int) (m, x *big.Int, err error) {
-	if m, err = sk.Decrypt(c); err != nil {
+// DecryptAndRecoverRandomness decrypts c and recovers its randomness x. The secret exponent N^-1 mod phi(N) is blinded
+// with a fresh random multiple of phi(N), and the base with a fresh random r^N that is removed from the result. Like
+// Decrypt it draws the blinding from the optional reader.
+func (sk *PrivateKey) DecryptAndRecoverRandomness(c *big.Int, optionalReader ...io.Reader) (m, x *big.Int, err error) {
+	reader := randReader(optionalReader)
+	if m, err = sk.Decrypt(c, reader); err != nil {
 		return
 	}
 	modN := common.ModInt(sk.N)
@@ -215,11 +273,30 @@ func (sk *PrivateKey) DecryptAndRecoverRandomness(c *big.Int) (m, x *big.Int, er
 	cDash := modNSq.Mul(c, new(big.Int).Sub(one, mN))
 	// M = N^-1 mod phi(N)
 	M := modPhiN.Inverse(sk.N)
-	// x = CDash^M mod N
-	x = modN.Exp(cDash, M)
+	// x = CDash^M mod N, where (CDash * r^N)^M = x * r mod N
+	r := common.GetRandomPositiveRelativelyPrimeIntFrom(reader, sk.N)
+	xr := modN.Exp(modN.Mul(cDash, modN.Exp(r, sk.N)), blindExponent(reader, M, sk.PhiN))
+	x = modN.Mul(xr, modN.Inverse(r))
 	return
 }
 
+// blindExponent returns e + k*order for a fresh random k of blindingBits bits
+func blindExponent(reader io.Reader, e, order *big.Int) *big.Int {
+	k := common.GetRandomPositiveIntFrom(reader, new(big.Int).Lsh(one, blindingBits))
+	return new(big.Int).Add(e, new(big.Int).Mul(k, order))
+}
+
+// randReader returns the reader passed to a decryption, or crypto/rand for the default
+func randReader(optionalReader []io.Reader) io.Reader {
+	if 1 < len(optionalReader) {
+		panic(errors.New("expected 0 or 1 item in `optionalReader`"))
+	}
+	if len(optionalReader) == 0 || optionalReader[0] == nil {
+		return rand.Reader
+	}
+	return optionalReader[0]
+}
+
 // ----- //
 
 // Proof is an implementation of Gennaro, R., Micciancio, D., Rabin, T.:
@@ -237,23 +314,26 @@ func (sk *PrivateKey) Proof(k *big.Int, ecdsaPub *crypto2.ECPoint) Proof {
 	return pi
 }
 
-func (pf Proof) Verify(pkN, k *big.Int, ecdsaPub *crypto2.ECPoint) (bool, error) {
+// Verify checks the proof. Its goroutines stay within the budget of the given limiter, if any: work that does not fit
+// is run in the calling goroutine.
+func (pf Proof) Verify(pkN, k *big.Int, ecdsaPub *crypto2.ECPoint, optionalLimiter ...*common.GoroutineLimiter) (bool, error) {
+	limiter := goroutineLimiter(optionalLimiter)
 	iters := ProofIters
 	pch, xch := make(chan bool, 1), make(chan []*big.Int, 1) // buffered to allow early exit
 	prms := primes.Until(verifyPrimesUntil).List()           // uses cache primed in init()
-	go func(ch chan<- bool) {
+	limiter.GoOrRun(func() {
 		for _, prm := range prms {
 			// If prm divides N then Return 0
 			if new(big.Int).Mod(pkN, big.NewInt(prm)).Cmp(zero) == 0 {
-				ch <- false // is divisible
+				pch <- false // is divisible
 				return
 			}
 		}
-		ch <- true
-	}(pch)
-	go func(ch chan<- []*big.Int) {
-		ch <- GenerateXs(iters, k, pkN, ecdsaPub)
-	}(xch)
+		pch <- true
+	})
+	limiter.GoOrRun(func() {
+		xch <- GenerateXs(iters, k, pkN, ecdsaPub, limiter)
+	})
 	for j := 0; j < 2; j++ {
 		select {
 		case ok := <-pch:
@@ -283,8 +363,9 @@ func L(u, N *big.Int) *big.Int {
 	return new(big.Int).Div(t, N)
 }
 
-// GenerateXs generates the challenges used in Paillier key Proof
-func GenerateXs(m int, k, N *big.Int, ecdsaPub *crypto2.ECPoint) []*big.Int {
+// GenerateXs generates the challenges used in Paillier key Proof, within the budget of the given goroutine limiter, if any
+func GenerateXs(m int, k, N *big.Int, ecdsaPub *crypto2.ECPoint, optionalLimiter ...*common.GoroutineLimiter) []*big.Int {
+	limiter := goroutineLimiter(optionalLimiter)
 	var i, n int
 	ret := make([]*big.Int, m)
 	sX, sY := ecdsaPub.X(), ecdsaPub.Y()
@@ -293,18 +374,19 @@ func GenerateXs(m int, k, N *big.Int, ecdsaPub *crypto2.ECPoint) []*big.Int {
 	blocks := int(gmath.Ceil(float64(bits) / 256))
 	chs := make([]chan []byte, blocks)
 	for k := range chs {
-		chs[k] = make(chan []byte)
+		chs[k] = make(chan []byte, 1) // buffered, as a block may be hashed in this goroutine
 	}
 	for i < m {
 		xi := make([]byte, 0, blocks*32)
 		ib := []byte(strconv.Itoa(i))
 		nb := []byte(strconv.Itoa(n))
 		for j := 0; j < blocks; j++ {
-			go func(j int) {
+			j := j
+			limiter.GoOrRun(func() {
 				jBz := []byte(strconv.Itoa(j))
 				hash := common.SHA512_256(ib, jBz, nb, kb, sXb, sYb, Nb)
 				chs[j] <- hash
-			}(j)
+			})
 		}
 		for _, ch := range chs { // must be in order
 			rx := <-ch
@@ -313,7 +395,8 @@ func GenerateXs(m int, k, N *big.Int, ecdsaPub *crypto2.ECPoint) []*big.Int {
 			}
 			xi = append(xi, rx...) // xi1||···||xib
 		}
-		ret[i] = new(big.Int).SetBytes(xi)
+		// the blocks are cut to the length of N, unless it is a multiple of 256 bits, so that xi is below N as often
+		ret[i] = new(big.Int).Rsh(new(big.Int).SetBytes(xi), uint(blocks*256-bits))
 		if common.IsNumberInMultiplicativeGroup(N, ret[i]) {
 			i++
 		} else {
@@ -322,3 +405,13 @@ func GenerateXs(m int, k, N *big.Int, ecdsaPub *crypto2.ECPoint) []*big.Int {
 	}
 	return ret
 }
+
+func goroutineLimiter(optionalLimiter []*common.GoroutineLimiter) *common.GoroutineLimiter {
+	if 0 < len(optionalLimiter) {
+		if 1 < len(optionalLimiter) {
+			panic(errors.New("expected 0 or 1 item in `optionalLimiter`"))
+		}
+		return optionalLimiter[0]
+	}
+	return nil
+}
@@ -7,7 +7,11 @@
 package paillier_test
 
 import (
+	"crypto/elliptic"
+	"errors"
+	"io"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 	"time"
 
@@ -46,6 +50,12 @@ func TestGenerateKeyPair(t *testing.T) {
 	t.Log(privateKey)
 }
 
+func TestMinPaillierBits(t *testing.T) {
+	assert.Equal(t, testPaillierKeyLength, MinPaillierBits(tss.EC()), "secp256k1 must need the default length")
+	assert.Equal(t, 8*521, MinPaillierBits(elliptic.P521()))
+	assert.Less(t, testPaillierKeyLength, MinPaillierBits(elliptic.P521()), "P-521 must need a longer modulus")
+}
+
 func TestEncrypt(t *testing.T) {
 	setUp(t)
 	cipher, err := publicKey.Encrypt(big.NewInt(1))
@@ -88,6 +98,29 @@ func TestEncryptDecryptAndRecoverRandomness(t *testing.T) {
 		"wrong randomness ", rand, " is not ", rec)
 }
 
+func TestEncryptWithRandomness(t *testing.T) {
+	setUp(t)
+	exp := big.NewInt(100)
+	r := common.GetRandomPositiveRelativelyPrimeInt(publicKey.N)
+	cypher, err := publicKey.EncryptWithRandomness(exp, r)
+	assert.NoError(t, err)
+	ret, rec, err := privateKey.DecryptAndRecoverRandomness(cypher)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, exp.Cmp(ret), "wrong decryption ", ret, " is not ", exp)
+	assert.Equal(t, 0, r.Cmp(rec), "wrong randomness ", rec, " is not ", r)
+
+	// the randomness must be in Z*_N: p is recovered from p + q = N - phi(N) + 1
+	sum := new(big.Int).Sub(privateKey.N, privateKey.PhiN)
+	sum.Add(sum, big.NewInt(1))
+	disc := new(big.Int).Sub(new(big.Int).Mul(sum, sum), new(big.Int).Lsh(privateKey.N, 2))
+	p := new(big.Int).Rsh(new(big.Int).Sub(sum, new(big.Int).Sqrt(disc)), 1)
+	assert.Equal(t, 0, new(big.Int).Mod(privateKey.N, p).Sign())
+	for _, bad := range []*big.Int{nil, big.NewInt(0), publicKey.N, new(big.Int).Neg(r), p, new(big.Int).Mul(p, big.NewInt(2))} {
+		_, err = publicKey.EncryptWithRandomness(exp, bad)
+		assert.Equal(t, ErrBadRandomness, err, "the randomness %v should be rejected", bad)
+	}
+}
+
 func TestEncryptDecryptAndRecoverRandomnessAndReEncrypt1(t *testing.T) {
 	setUp(t)
 	exp := big.NewInt(100)
@@ -123,6 +156,56 @@ func TestEncryptWithChosenRandomnessDecrypt(t *testing.T) {
 		"wrong decryption ", ret, " is not ", exp)
 }
 
+// TestDecryptBlinding checks that decryption blinds its secret exponents and bases with randomness from the reader
+// that it is given, so that its timing does not depend on the key or the ciphertext alone, and that the blinding does
+// not change the result. Unlike a measurement of the timing it does not depend on the load of the machine.
+func TestDecryptBlinding(t *testing.T) {
+	setUp(t)
+	NSq := privateKey.NSquare()
+	ms := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Sub(privateKey.N, big.NewInt(1)), common.GetRandomPositiveInt(privateKey.N)}
+	for _, m := range ms {
+		c, x, err := publicKey.EncryptAndReturnRandomness(m)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// the blinding drawn from different readers does not change the result
+		for seed := int64(1); seed <= 2; seed++ {
+			reader := &countingReader{r: mrand.New(mrand.NewSource(seed))}
+			gotM, gotX, err := privateKey.DecryptAndRecoverRandomness(c, reader)
+			if assert.NoError(t, err) {
+				assert.Equal(t, 0, m.Cmp(gotM))
+				assert.Equal(t, 0, x.Cmp(gotX))
+			}
+			// the bases are blinded with two values mod N and the exponents with two random multiples
+			assert.GreaterOrEqual(t, reader.n, 2*(privateKey.N.BitLen()/8+8), "the blinding should be drawn from the reader")
+		}
+	}
+
+	// the blinding is drawn from the reader that is passed, even for the edge cases of the ciphertext
+	for _, c := range []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(NSq, big.NewInt(1))} {
+		assert.Panics(t, func() { _, _ = privateKey.Decrypt(c, failingReader{}) })
+	}
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += n
+	return n, err
+}
+
+// failingReader fails every read
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
 func TestHomoMul(t *testing.T) {
 	setUp(t)
 	three, err := privateKey.Encrypt(big.NewInt(3))
@@ -156,6 +239,46 @@ func TestHomoAdd(t *testing.T) {
 	assert.Equal(t, new(big.Int).Add(num1, num2), plain)
 }
 
+func TestHomoSub(t *testing.T) {
+	setUp(t)
+	num1 := big.NewInt(32)
+	num2 := big.NewInt(10)
+
+	one, _ := publicKey.Encrypt(num1)
+	two, _ := publicKey.Encrypt(num2)
+
+	ciphered, err := publicKey.HomoSub(one, two)
+	assert.NoError(t, err)
+	plain, _ := privateKey.Decrypt(ciphered)
+	assert.Equal(t, new(big.Int).Sub(num1, num2), plain)
+
+	// the same as adding an encryption of the negated plaintext
+	negTwo, _ := publicKey.Encrypt(new(big.Int).Sub(publicKey.N, num2))
+	added, _ := publicKey.HomoAdd(one, negTwo)
+	plainAdded, _ := privateKey.Decrypt(added)
+	assert.Equal(t, plainAdded, plain)
+
+	// b > a wraps around to a - b mod N
+	wrapped, err := publicKey.HomoSub(two, one)
+	assert.NoError(t, err)
+	plainWrapped, _ := privateKey.Decrypt(wrapped)
+	assert.Equal(t, new(big.Int).Mod(new(big.Int).Sub(num2, num1), publicKey.N), plainWrapped)
+	assert.Equal(t, new(big.Int).Sub(publicKey.N, big.NewInt(22)), plainWrapped)
+
+	_, err = publicKey.HomoSub(one, publicKey.N)
+	assert.Equal(t, ErrBadCiphertext, err, "a ciphertext sharing a factor with N has no inverse")
+	_, err = publicKey.HomoSub(publicKey.N, two)
+	assert.Equal(t, ErrBadCiphertext, err, "c1 must be in the multiplicative group too")
+	_, err = publicKey.HomoSub(big.NewInt(0), two)
+	assert.Equal(t, ErrBadCiphertext, err)
+	_, err = publicKey.HomoSub(one, publicKey.NSquare())
+	assert.Equal(t, ErrBadCiphertext, err)
+	_, err = publicKey.HomoSub(new(big.Int).Add(publicKey.NSquare(), one), two)
+	assert.Equal(t, ErrBadCiphertext, err)
+	_, err = publicKey.HomoSub(one, big.NewInt(-1))
+	assert.Equal(t, ErrBadCiphertext, err)
+}
+
 func TestProofVerify(t *testing.T) {
 	setUp(t)
 	ki := common.MustGetRandomInt(256)                     // index
@@ -201,4 +324,13 @@ func TestGenerateXs(t *testing.T) {
 	for _, xi := range xs {
 		assert.True(t, common.IsNumberInMultiplicativeGroup(N, xi))
 	}
+
+	// a modulus whose length is not a multiple of 256 bits, as for a curve with an order longer than 512 bits
+	N = common.GetRandomPrimeInt(8 * 521)
+	xs = GenerateXs(13, k, N, crypto.NewECPointNoCurveCheck(tss.EC(), sX, sY))
+	assert.Equal(t, 13, len(xs))
+	for _, xi := range xs {
+		assert.True(t, common.IsNumberInMultiplicativeGroup(N, xi))
+	}
+	assert.Equal(t, xs, GenerateXs(13, k, N, crypto.NewECPointNoCurveCheck(tss.EC(), sX, sY)), "the challenges must be deterministic")
 }
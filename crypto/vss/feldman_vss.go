@@ -18,7 +18,6 @@ import (
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
-	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
 type (
@@ -60,14 +59,14 @@ func CheckIndexes(ec elliptic.Curve, indexes []*big.Int) ([]*big.Int, error) {
 // Returns a new array of secret shares created by Shamir's Secret Sharing Algorithm,
 // requiring a minimum number of shares to recreate, of length shares, from the input secret
 //
-func Create(threshold int, secret *big.Int, indexes []*big.Int) (Vs, Shares, error) {
+func Create(ec elliptic.Curve, threshold int, secret *big.Int, indexes []*big.Int) (Vs, Shares, error) {
 	if secret == nil || indexes == nil {
 		return nil, nil, fmt.Errorf("vss secret or indexes == nil: %v %v", secret, indexes)
 	}
 	if threshold < 1 {
 		return nil, nil, errors.New("vss threshold < 1")
 	}
-	ids, err := CheckIndexes(tss.EC(), indexes)
+	ids, err := CheckIndexes(ec, indexes)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -76,46 +75,60 @@ func Create(threshold int, secret *big.Int, indexes []*big.Int) (Vs, Shares, err
 		return nil, nil, ErrNumSharesBelowThreshold
 	}
 
-	poly := samplePolynomial(threshold, secret)
+	poly := samplePolynomial(ec, threshold, secret)
 	v := make(Vs, len(poly))
 	for i, ai := range poly {
-		v[i] = crypto.ScalarBaseMult(tss.EC(), ai)
+		v[i] = crypto.ScalarBaseMult(ec, ai)
 	}
 
 	shares := make(Shares, num)
 	for i := 0; i < num; i++ {
-		share := evaluatePolynomial(threshold, poly, ids[i])
+		share := evaluatePolynomial(ec, threshold, poly, ids[i])
 		shares[i] = &Share{Threshold: threshold, ID: ids[i], Share: share}
 	}
 	return v, shares, nil
 }
 
-func (share *Share) Verify(threshold int, vs Vs) bool {
+func (share *Share) Verify(ec elliptic.Curve, threshold int, vs Vs) bool {
 	if share.Threshold != threshold || vs == nil {
 		return false
 	}
 	var err error
-	modQ := common.ModInt(tss.EC().Params().N)
+	modQ := common.ModInt(ec.Params().N)
 	v, t := vs[0], one // YRO : we need to have our accumulator outside of the loop
 	for j := 1; j <= threshold; j++ {
 		// t = k_i^j
 		t = modQ.Mul(t, share.ID)
 		// v = v * v_j^t
-		vjt := vs[j].SetCurve(tss.EC()).ScalarMult(t)
-		v, err = v.SetCurve(tss.EC()).Add(vjt)
+		vjt := vs[j].SetCurve(ec).ScalarMult(t)
+		v, err = v.SetCurve(ec).Add(vjt)
 		if err != nil {
 			return false
 		}
 	}
-	sigmaGi := crypto.ScalarBaseMult(tss.EC(), share.Share)
+	sigmaGi := crypto.ScalarBaseMult(ec, share.Share)
 	return sigmaGi.Equals(v)
 }
 
-func (shares Shares) ReConstruct() (secret *big.Int, err error) {
+// VerifyVSSShare checks that `share` is the evaluation at `id` of the degree-`threshold` polynomial committed to by
+// `vs`. It needs only public data, so that a party that holds no share, e.g. an auditor, can verify one.
+func VerifyVSSShare(ec elliptic.Curve, threshold int, vs Vs, id, share *big.Int) bool {
+	if id == nil || share == nil || len(vs) != threshold+1 {
+		return false
+	}
+	for _, v := range vs {
+		if v == nil || !v.ValidateBasic() {
+			return false
+		}
+	}
+	return (&Share{Threshold: threshold, ID: id, Share: share}).Verify(ec, threshold, vs)
+}
+
+func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error) {
 	if shares != nil && shares[0].Threshold > len(shares) {
 		return nil, ErrNumSharesBelowThreshold
 	}
-	modN := common.ModInt(tss.EC().Params().N)
+	modN := common.ModInt(ec.Params().N)
 
 	// x coords
 	xs := make([]*big.Int, 0)
@@ -143,8 +156,8 @@ func (shares Shares) ReConstruct() (secret *big.Int, err error) {
 	return secret, nil
 }
 
-func samplePolynomial(threshold int, secret *big.Int) []*big.Int {
-	q := tss.EC().Params().N
+func samplePolynomial(ec elliptic.Curve, threshold int, secret *big.Int) []*big.Int {
+	q := ec.Params().N
 	v := make([]*big.Int, threshold+1)
 	v[0] = secret
 	for i := 1; i <= threshold; i++ {
@@ -158,8 +171,8 @@ func samplePolynomial(threshold int, secret *big.Int) []*big.Int {
 // evaluatePolynomial([a, b, c, d], x):
 // 		returns a + bx + cx^2 + dx^3
 //
-func evaluatePolynomial(threshold int, v []*big.Int, id *big.Int) (result *big.Int) {
-	q := tss.EC().Params().N
+func evaluatePolynomial(ec elliptic.Curve, threshold int, v []*big.Int, id *big.Int) (result *big.Int) {
+	q := ec.Params().N
 	modQ := common.ModInt(q)
 	result = new(big.Int).Set(v[0])
 	X := big.NewInt(int64(1))
@@ -171,3 +184,138 @@ func evaluatePolynomial(threshold int, v []*big.Int, id *big.Int) (result *big.I
 	}
 	return
 }
+
+// InterpolateInExponent evaluates at `at` the polynomial in the exponent through the points (xs[k], Xs[k])
+func InterpolateInExponent(xs []*big.Int, Xs []*crypto.ECPoint, at *big.Int) (*crypto.ECPoint, error) {
+	modQ := common.ModInt(Xs[0].Curve().Params().N)
+	var result *crypto.ECPoint
+	for k, xk := range xs {
+		lambda := big.NewInt(1)
+		for m, xm := range xs {
+			if m == k {
+				continue
+			}
+			lambda = modQ.Mul(lambda, modQ.Mul(modQ.Sub(at, xm), modQ.Inverse(modQ.Sub(xk, xm))))
+		}
+		if lambda.Sign() == 0 {
+			continue
+		}
+		term := Xs[k].ScalarMult(lambda)
+		if result == nil {
+			result = term
+			continue
+		}
+		var err error
+		if result, err = result.Add(term); err != nil {
+			return nil, err
+		}
+	}
+	if result == nil {
+		return nil, errors.New("InterpolateInExponent() has no points to interpolate")
+	}
+	return result, nil
+}
+
+// CheckPublicShares checks that the public shares Xs of the parties with the indexes xs lie on one polynomial of degree
+// `threshold` in the exponent, and that it interpolates to the public key `pub`. It interpolates from the first
+// threshold+1 shares; if they do not give `pub` it returns an error, as it cannot tell which of them is wrong.
+// Otherwise it returns the positions of the other shares that are not on the polynomial.
+func CheckPublicShares(xs []*big.Int, Xs []*crypto.ECPoint, threshold int, pub *crypto.ECPoint) ([]int, error) {
+	if len(xs) != len(Xs) || len(xs) <= threshold || pub == nil {
+		return nil, fmt.Errorf("expected more than %d public shares with their indexes and a public key", threshold)
+	}
+	for _, X := range Xs {
+		if X == nil {
+			return nil, errors.New("CheckPublicShares() received a nil public share")
+		}
+	}
+	first, firstXs := xs[:threshold+1], Xs[:threshold+1]
+	if y, err := InterpolateInExponent(first, firstXs, zero); err != nil || !y.Equals(pub) {
+		return nil, errors.New("the public shares do not interpolate to the public key")
+	}
+	var inconsistent []int
+	for j := threshold + 1; j < len(xs); j++ {
+		if Xj, err := InterpolateInExponent(first, firstXs, xs[j]); err != nil || !Xj.Equals(Xs[j]) {
+			inconsistent = append(inconsistent, j)
+		}
+	}
+	return inconsistent, nil
+}
+
+// FindCorrupted is an offline diagnostic that finds the shares that are inconsistent with the public key `pub`, e.g.
+// after a failed signing. It looks for threshold+1 of the shares that reconstruct the secret key of `pub` and returns
+// the positions of the other shares that are not on the same polynomial, or none if every share is consistent.
+// Locating a corrupted share needs at least threshold+2 shares; if no threshold+1 of them reconstruct the key it
+// returns an error, as it cannot tell which are corrupted.
+func (shares Shares) FindCorrupted(ec elliptic.Curve, threshold int, pub *crypto.ECPoint) ([]int, error) {
+	if len(shares) <= threshold {
+		return nil, ErrNumSharesBelowThreshold
+	}
+	if pub == nil {
+		return nil, errors.New("FindCorrupted() received a nil public key")
+	}
+	xs := make([]*big.Int, 0, len(shares))
+	for _, share := range shares {
+		if share == nil || share.ID == nil || share.Share == nil {
+			return nil, errors.New("FindCorrupted() received an incomplete share")
+		}
+		xs = append(xs, share.ID)
+	}
+	if _, err := CheckIndexes(ec, xs); err != nil {
+		return nil, err
+	}
+
+	// try the subsets of threshold+1 shares in lexicographic order until one reconstructs the key
+	subset := make([]int, threshold+1)
+	for k := range subset {
+		subset[k] = k
+	}
+	for {
+		picked := make(Shares, 0, len(subset))
+		for _, k := range subset {
+			picked = append(picked, shares[k])
+		}
+		if x := interpolateAt(ec, picked, zero); crypto.ScalarBaseMult(ec, x).Equals(pub) {
+			var corrupted []int
+			for j, share := range shares {
+				if interpolateAt(ec, picked, share.ID).Cmp(new(big.Int).Mod(share.Share, ec.Params().N)) != 0 {
+					corrupted = append(corrupted, j)
+				}
+			}
+			return corrupted, nil
+		}
+		// advance to the next subset
+		k := len(subset) - 1
+		for 0 <= k && subset[k] == len(shares)-len(subset)+k {
+			k--
+		}
+		if k < 0 {
+			break
+		}
+		subset[k]++
+		for m := k + 1; m < len(subset); m++ {
+			subset[m] = subset[m-1] + 1
+		}
+	}
+	if len(shares) == threshold+1 {
+		return nil, fmt.Errorf("the shares do not reconstruct the public key; %d shares are needed to tell which is corrupted", threshold+2)
+	}
+	return nil, errors.New("no threshold+1 of the shares reconstruct the public key; too many of them are corrupted")
+}
+
+// interpolateAt evaluates at `at` the polynomial through the shares
+func interpolateAt(ec elliptic.Curve, shares Shares, at *big.Int) *big.Int {
+	modN := common.ModInt(ec.Params().N)
+	result := new(big.Int)
+	for i, share := range shares {
+		lambda := big.NewInt(1)
+		for j, other := range shares {
+			if j == i {
+				continue
+			}
+			lambda = modN.Mul(lambda, modN.Mul(modN.Sub(at, other.ID), modN.Inverse(modN.Sub(share.ID, other.ID))))
+		}
+		result = modN.Add(result, modN.Mul(share.Share, lambda))
+	}
+	return result
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
 	. "github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -52,7 +53,7 @@ func TestCreate(t *testing.T) {
 		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
 	}
 
-	vs, _, err := Create(threshold, secret, ids)
+	vs, _, err := Create(tss.EC(), threshold, secret, ids)
 	assert.Nil(t, err)
 
 	assert.Equal(t, threshold+1, len(vs))
@@ -80,14 +81,37 @@ func TestVerify(t *testing.T) {
 		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
 	}
 
-	vs, shares, err := Create(threshold, secret, ids)
+	vs, shares, err := Create(tss.EC(), threshold, secret, ids)
 	assert.NoError(t, err)
 
 	for i := 0; i < num; i++ {
-		assert.True(t, shares[i].Verify(threshold, vs))
+		assert.True(t, shares[i].Verify(tss.EC(), threshold, vs))
 	}
 }
 
+func TestVerifyVSSShare(t *testing.T) {
+	num, threshold := 5, 3
+	q := tss.EC().Params().N
+
+	ids := make([]*big.Int, 0, num)
+	for i := 0; i < num; i++ {
+		ids = append(ids, common.GetRandomPositiveInt(q))
+	}
+	vs, shares, err := Create(tss.EC(), threshold, common.GetRandomPositiveInt(q), ids)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the verifier has only the commitments and a share with its id
+	for _, share := range shares {
+		assert.True(t, VerifyVSSShare(tss.EC(), threshold, vs, share.ID, share.Share))
+	}
+	assert.False(t, VerifyVSSShare(tss.EC(), threshold, vs, shares[0].ID, shares[1].Share), "a share at another id must fail")
+	assert.False(t, VerifyVSSShare(tss.EC(), threshold, vs, shares[0].ID, new(big.Int).Add(shares[0].Share, big.NewInt(1))))
+	assert.False(t, VerifyVSSShare(tss.EC(), threshold, vs[:threshold], shares[0].ID, shares[0].Share), "too few commitments must fail")
+	assert.False(t, VerifyVSSShare(tss.EC(), threshold, vs, nil, shares[0].Share))
+}
+
 func TestReconstruct(t *testing.T) {
 	num, threshold := 5, 3
 
@@ -98,18 +122,90 @@ func TestReconstruct(t *testing.T) {
 		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
 	}
 
-	_, shares, err := Create(threshold, secret, ids)
+	_, shares, err := Create(tss.EC(), threshold, secret, ids)
 	assert.NoError(t, err)
 
-	secret2, err2 := shares[:threshold-1].ReConstruct()
+	secret2, err2 := shares[:threshold-1].ReConstruct(tss.EC())
 	assert.Error(t, err2) // not enough shares to satisfy the threshold
 	assert.Nil(t, secret2)
 
-	secret3, err3 := shares[:threshold].ReConstruct()
+	secret3, err3 := shares[:threshold].ReConstruct(tss.EC())
 	assert.NoError(t, err3)
 	assert.NotZero(t, secret3)
 
-	secret4, err4 := shares[:num].ReConstruct()
+	secret4, err4 := shares[:num].ReConstruct(tss.EC())
 	assert.NoError(t, err4)
 	assert.NotZero(t, secret4)
 }
+
+func TestCheckPublicShares(t *testing.T) {
+	num, threshold := 5, 2
+
+	secret := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ids := make([]*big.Int, 0)
+	for i := 0; i < num; i++ {
+		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
+	}
+	_, shares, err := Create(tss.EC(), threshold, secret, ids)
+	assert.NoError(t, err)
+	pub := crypto.ScalarBaseMult(tss.EC(), secret)
+	bigXs := make([]*crypto.ECPoint, num)
+	for i, share := range shares {
+		bigXs[i] = crypto.ScalarBaseMult(tss.EC(), share.Share)
+	}
+
+	inconsistent, err := CheckPublicShares(ids, bigXs, threshold, pub)
+	assert.NoError(t, err)
+	assert.Empty(t, inconsistent)
+
+	// a share beyond the first threshold+1 is named
+	bigXs[num-1] = crypto.ScalarBaseMult(tss.EC(), big.NewInt(1))
+	inconsistent, err = CheckPublicShares(ids, bigXs, threshold, pub)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{num - 1}, inconsistent)
+
+	// the first threshold+1 shares do not interpolate to the key
+	bigXs[0] = crypto.ScalarBaseMult(tss.EC(), big.NewInt(1))
+	_, err = CheckPublicShares(ids, bigXs, threshold, pub)
+	assert.Error(t, err)
+
+	_, err = CheckPublicShares(ids[:threshold], bigXs[:threshold], threshold, pub)
+	assert.Error(t, err, "too few shares")
+}
+
+func TestFindCorrupted(t *testing.T) {
+	threshold := 2
+	num := threshold + 2
+
+	secret := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ids := make([]*big.Int, 0)
+	for i := 0; i < num; i++ {
+		ids = append(ids, common.GetRandomPositiveInt(tss.EC().Params().N))
+	}
+	_, shares, err := Create(tss.EC(), threshold, secret, ids)
+	assert.NoError(t, err)
+	pub := crypto.ScalarBaseMult(tss.EC(), secret)
+
+	corrupted, err := shares.FindCorrupted(tss.EC(), threshold, pub)
+	assert.NoError(t, err)
+	assert.Empty(t, corrupted)
+
+	// whichever share is corrupted, it is pinpointed
+	for j := range shares {
+		bad := make(Shares, len(shares))
+		copy(bad, shares)
+		bad[j] = &Share{Threshold: threshold, ID: shares[j].ID, Share: new(big.Int).Add(shares[j].Share, big.NewInt(1))}
+		corrupted, err = bad.FindCorrupted(tss.EC(), threshold, pub)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{j}, corrupted)
+
+		// threshold+1 shares tell that one is corrupted but not which
+		_, err = bad[:threshold+1].FindCorrupted(tss.EC(), threshold, pub)
+		if j <= threshold {
+			assert.Error(t, err)
+		}
+	}
+
+	_, err = shares[:threshold].FindCorrupted(tss.EC(), threshold, pub)
+	assert.Error(t, err, "too few shares")
+}
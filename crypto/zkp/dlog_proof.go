@@ -7,12 +7,12 @@
 package zkp
 
 import (
+	"crypto/elliptic"
 	"errors"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
-	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
 type (
@@ -24,20 +24,33 @@ type (
 )
 
 // NewDLogProof constructs a new Schnorr ZK of the discrete logarithm of pho_i such that A = g^pho (GG18)
-func NewDLogProof(x *big.Int, X *crypto.ECPoint) (*DLogProof, error) {
+func NewDLogProof(ec elliptic.Curve, x *big.Int, X *crypto.ECPoint) (*DLogProof, error) {
+	return newDLogProof(ec, x, X)
+}
+
+// NewDLogProofWithMessage is NewDLogProof with `msg` bound into the challenge, which makes the proof a Schnorr
+// signature of msg under X
+func NewDLogProofWithMessage(ec elliptic.Curve, x *big.Int, X *crypto.ECPoint, msg *big.Int) (*DLogProof, error) {
+	if msg == nil {
+		return nil, errors.New("NewDLogProofWithMessage received a nil message")
+	}
+	return newDLogProof(ec, x, X, msg)
+}
+
+func newDLogProof(ec elliptic.Curve, x *big.Int, X *crypto.ECPoint, msg ...*big.Int) (*DLogProof, error) {
 	if x == nil || X == nil || !X.ValidateBasic() {
 		return nil, errors.New("NewDLogProof received nil or invalid value(s)")
 	}
-	ecParams := tss.EC().Params()
+	ecParams := ec.Params()
 	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(tss.EC(), ecParams.Gx, ecParams.Gy) // already on the curve.
+	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy) // already on the curve.
 
 	a := common.GetRandomPositiveInt(q)
-	alpha := crypto.ScalarBaseMult(tss.EC(), a)
+	alpha := crypto.ScalarBaseMult(ec, a)
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i(X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		cHash := common.SHA512_256i(append([]*big.Int{X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y()}, msg...)...)
 		c = common.RejectionSample(q, cHash)
 	}
 	t := new(big.Int).Mul(c, x)
@@ -47,20 +60,29 @@ func NewDLogProof(x *big.Int, X *crypto.ECPoint) (*DLogProof, error) {
 }
 
 // NewDLogProof verifies a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
-func (pf *DLogProof) Verify(X *crypto.ECPoint) bool {
+func (pf *DLogProof) Verify(ec elliptic.Curve, X *crypto.ECPoint) bool {
+	return pf.verify(ec, X)
+}
+
+// VerifyWithMessage verifies a proof made by NewDLogProofWithMessage, which is a Schnorr signature of msg under X
+func (pf *DLogProof) VerifyWithMessage(ec elliptic.Curve, X *crypto.ECPoint, msg *big.Int) bool {
+	return msg != nil && pf.verify(ec, X, msg)
+}
+
+func (pf *DLogProof) verify(ec elliptic.Curve, X *crypto.ECPoint, msg ...*big.Int) bool {
 	if pf == nil || !pf.ValidateBasic() {
 		return false
 	}
-	ecParams := tss.EC().Params()
+	ecParams := ec.Params()
 	q := ecParams.N
-	g := crypto.NewECPointNoCurveCheck(tss.EC(), ecParams.Gx, ecParams.Gy)
+	g := crypto.NewECPointNoCurveCheck(ec, ecParams.Gx, ecParams.Gy)
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i(X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		cHash := common.SHA512_256i(append([]*big.Int{X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y()}, msg...)...)
 		c = common.RejectionSample(q, cHash)
 	}
-	tG := crypto.ScalarBaseMult(tss.EC(), pf.T)
+	tG := crypto.ScalarBaseMult(ec, pf.T)
 	Xc := X.ScalarMult(c)
 	aXc, err := pf.Alpha.Add(Xc)
 	if err != nil {
@@ -7,6 +7,7 @@
 package zkp_test
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,7 +22,7 @@ func TestSchnorrProof(t *testing.T) {
 	q := tss.EC().Params().N
 	u := common.GetRandomPositiveInt(q)
 	uG := crypto.ScalarBaseMult(tss.EC(), u)
-	proof, _ := NewDLogProof(u, uG)
+	proof, _ := NewDLogProof(tss.EC(), u, uG)
 
 	assert.True(t, proof.Alpha.IsOnCurve())
 	assert.NotZero(t, proof.Alpha.X())
@@ -34,8 +35,8 @@ func TestSchnorrProofVerify(t *testing.T) {
 	u := common.GetRandomPositiveInt(q)
 	X := crypto.ScalarBaseMult(tss.EC(), u)
 
-	proof, _ := NewDLogProof(u, X)
-	res := proof.Verify(X)
+	proof, _ := NewDLogProof(tss.EC(), u, X)
+	res := proof.Verify(tss.EC(), X)
 
 	assert.True(t, res, "verify result must be true")
 }
@@ -47,8 +48,21 @@ func TestSchnorrProofVerifyBadX(t *testing.T) {
 	X := crypto.ScalarBaseMult(tss.EC(), u)
 	X2 := crypto.ScalarBaseMult(tss.EC(), u2)
 
-	proof, _ := NewDLogProof(u2, X2)
-	res := proof.Verify(X)
+	proof, _ := NewDLogProof(tss.EC(), u2, X2)
+	res := proof.Verify(tss.EC(), X)
 
 	assert.False(t, res, "verify result must be false")
 }
+
+func TestSchnorrProofWithMessage(t *testing.T) {
+	q := tss.EC().Params().N
+	u := common.GetRandomPositiveInt(q)
+	X := crypto.ScalarBaseMult(tss.EC(), u)
+	msg := common.GetRandomPositiveInt(q)
+
+	proof, err := NewDLogProofWithMessage(tss.EC(), u, X, msg)
+	assert.NoError(t, err)
+	assert.True(t, proof.VerifyWithMessage(tss.EC(), X, msg))
+	assert.False(t, proof.VerifyWithMessage(tss.EC(), X, new(big.Int).Add(msg, big.NewInt(1))), "the proof must be bound to the message")
+	assert.False(t, proof.Verify(tss.EC(), X), "a proof bound to a message is not a plain proof")
+}
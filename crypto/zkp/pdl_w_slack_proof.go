@@ -9,6 +9,7 @@
 package zkp
 
 import (
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 
@@ -49,9 +50,8 @@ var (
 )
 
 func NewPDLwSlackProof(wit PDLwSlackWitness, st PDLwSlackStatement) PDLwSlackProof {
-	q := tss.EC().Params().N
-	q3 := new(big.Int).Mul(q, q)
-	q3.Mul(q3, q)
+	powers := tss.CurvePowersOf(st.G.Curve())
+	q, q3 := powers.Q, powers.Q3
 	qNTilde := new(big.Int).Mul(q, st.NTilde)
 	q3NTilde := new(big.Int).Mul(q3, st.NTilde)
 
@@ -78,7 +78,10 @@ func NewPDLwSlackProof(wit PDLwSlackWitness, st PDLwSlackStatement) PDLwSlackPro
 }
 
 func (pf PDLwSlackProof) Verify(st PDLwSlackStatement) bool {
-	q := tss.EC().Params().N
+	if !pf.ValidateBasic() {
+		return false
+	}
+	q := st.G.Curve().Params().N
 
 	e := common.SHA512_256i(st.G.X(), st.G.Y(), st.Q.X(), st.Q.Y(), st.CipherText, pf.Z, pf.U1.X(), pf.U1.Y(), pf.U2, pf.U3)
 	gS1 := st.G.ScalarMult(pf.S1)
@@ -117,6 +120,11 @@ func (pf PDLwSlackProof) Verify(st PDLwSlackStatement) bool {
 		pf.U3.Cmp(u3Test) == 0
 }
 
+func (pf PDLwSlackProof) ValidateBasic() bool {
+	return pf.Z != nil && pf.U1 != nil && pf.U1.ValidateBasic() && pf.U2 != nil && pf.U3 != nil &&
+		pf.S1 != nil && pf.S2 != nil && pf.S3 != nil
+}
+
 func (pf PDLwSlackProof) Marshal() ([][]byte, error) {
 	cb := cmts.NewBuilder()
 	cb = cb.AddPart(pf.Z)
@@ -137,7 +145,7 @@ func (pf PDLwSlackProof) Marshal() ([][]byte, error) {
 	return bzs, nil
 }
 
-func UnmarshalPDLwSlackProof(bzs [][]byte) (*PDLwSlackProof, error) {
+func UnmarshalPDLwSlackProof(ec elliptic.Curve, bzs [][]byte) (*PDLwSlackProof, error) {
 	bis := make([]*big.Int, len(bzs))
 	for i := range bis {
 		bis[i] = new(big.Int).SetBytes(bzs[i])
@@ -161,7 +169,7 @@ func UnmarshalPDLwSlackProof(bzs [][]byte) (*PDLwSlackProof, error) {
 	}
 	p := new(PDLwSlackProof)
 	p.Z = parsed[0][0]
-	U1, err := crypto.NewECPoint(tss.EC(), parsed[1][0], parsed[1][1])
+	U1, err := crypto.NewECPoint(ec, parsed[1][0], parsed[1][1])
 	if err != nil {
 		return nil, err
 	}
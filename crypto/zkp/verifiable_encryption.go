@@ -0,0 +1,157 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package zkp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+)
+
+// VerifiableEncryptionIters is the number of cut-and-choose iterations; a cheating prover passes with probability 2^-VerifiableEncryptionIters
+const VerifiableEncryptionIters = 128
+
+type (
+	// VerifiableEncryption is a Paillier encryption under an escrow's key of the discrete log x of Q = x*R, for public
+	// points R and Q, with a cut-and-choose proof that it decrypts to x (Camenisch & Damgård, "Verifiable Encryption,
+	// Group Encryption, and Their Applications to Separable Group Signatures and Signature Sharing Schemes", 2000).
+	// In each iteration the prover commits to T = a*R and encrypts both a and a-x, then opens one of the two
+	// ciphertexts as the Fiat-Shamir challenge asks. The escrow recovers x from any iteration whose two ciphertexts
+	// are honest, which a prover that passes the verification cannot avoid.
+	VerifiableEncryption struct {
+		T      [VerifiableEncryptionIters]*crypto.ECPoint
+		C0, C1 [VerifiableEncryptionIters]*big.Int
+		// the plaintext and randomness of the ciphertext that the challenge bit opens: C0 for 0 and C1 for 1
+		Z, Rho [VerifiableEncryptionIters]*big.Int
+	}
+)
+
+// NewVerifiableEncryption encrypts x under the escrow's key `pk` and proves that it is the discrete log of Q = x*R
+func NewVerifiableEncryption(pk *paillier.PublicKey, R, Q *crypto.ECPoint, x *big.Int) (*VerifiableEncryption, error) {
+	if pk == nil || R == nil || Q == nil || x == nil {
+		return nil, errors.New("NewVerifiableEncryption() received a nil argument")
+	}
+	q := R.Curve().Params().N
+	if pk.N.Cmp(q) != 1 {
+		return nil, errors.New("NewVerifiableEncryption() requires a Paillier modulus larger than the curve order")
+	}
+	modQ := common.ModInt(q)
+	ve := new(VerifiableEncryption)
+	var as, bs, rho0s, rho1s [VerifiableEncryptionIters]*big.Int
+	for i := range ve.T {
+		as[i] = common.GetRandomPositiveInt(q)
+		bs[i] = modQ.Sub(as[i], x)
+		rho0s[i] = common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+		rho1s[i] = common.GetRandomPositiveRelativelyPrimeInt(pk.N)
+		ve.T[i] = R.ScalarMult(as[i])
+		var err error
+		if ve.C0[i], err = pk.EncryptWithChosenRandomness(as[i], rho0s[i]); err != nil {
+			return nil, err
+		}
+		if ve.C1[i], err = pk.EncryptWithChosenRandomness(bs[i], rho1s[i]); err != nil {
+			return nil, err
+		}
+	}
+	c := ve.challenge(pk, R, Q)
+	for i := range ve.T {
+		if c.Bit(i) == 0 {
+			ve.Z[i], ve.Rho[i] = as[i], rho0s[i]
+		} else {
+			ve.Z[i], ve.Rho[i] = bs[i], rho1s[i]
+		}
+	}
+	return ve, nil
+}
+
+// Verify checks that the encryption decrypts under the escrow's key `pk` to the discrete log of Q to the base R
+func (ve *VerifiableEncryption) Verify(pk *paillier.PublicKey, R, Q *crypto.ECPoint) bool {
+	if !ve.ValidateBasic() || pk == nil || R == nil || Q == nil || !R.ValidateBasic() || !Q.ValidateBasic() {
+		return false
+	}
+	q, NSq := R.Curve().Params().N, pk.NSquare()
+	for i := range ve.T {
+		if !ve.T[i].ValidateBasic() || !ve.T[i].IsOnCurve() {
+			return false
+		}
+		for _, ct := range []*big.Int{ve.C0[i], ve.C1[i]} {
+			if ct.Sign() != 1 || ct.Cmp(NSq) != -1 || !common.IsNumberInMultiplicativeGroup(NSq, ct) {
+				return false
+			}
+		}
+		if ve.Z[i].Sign() == -1 || ve.Z[i].Cmp(q) != -1 || !common.IsNumberInMultiplicativeGroup(pk.N, ve.Rho[i]) {
+			return false
+		}
+	}
+	c := ve.challenge(pk, R, Q)
+	for i := range ve.T {
+		ct, zR := ve.C0[i], R.ScalarMult(ve.Z[i])
+		if c.Bit(i) == 1 {
+			ct = ve.C1[i]
+			var err error
+			if zR, err = zR.Add(Q); err != nil {
+				return false
+			}
+		}
+		if !zR.Equals(ve.T[i]) {
+			return false
+		}
+		opened, err := pk.EncryptWithChosenRandomness(ve.Z[i], ve.Rho[i])
+		if err != nil || opened.Cmp(ct) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Decrypt recovers the discrete log of Q to the base R with the escrow's key `sk`. It should only be called after
+// Verify has succeeded.
+func (ve *VerifiableEncryption) Decrypt(sk *paillier.PrivateKey, R, Q *crypto.ECPoint) (*big.Int, error) {
+	if !ve.ValidateBasic() || sk == nil || R == nil || Q == nil {
+		return nil, errors.New("Decrypt() received an incomplete verifiable encryption or a nil argument")
+	}
+	modQ := common.ModInt(R.Curve().Params().N)
+	for i := range ve.T {
+		a, err := sk.Decrypt(ve.C0[i])
+		if err != nil {
+			continue
+		}
+		b, err := sk.Decrypt(ve.C1[i])
+		if err != nil {
+			continue
+		}
+		if x := modQ.Sub(a, b); R.ScalarMult(x).Equals(Q) {
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("none of the %d iterations decrypts to the discrete log of Q", VerifiableEncryptionIters)
+}
+
+func (ve *VerifiableEncryption) ValidateBasic() bool {
+	if ve == nil {
+		return false
+	}
+	for i := range ve.T {
+		if ve.T[i] == nil || ve.C0[i] == nil || ve.C1[i] == nil || ve.Z[i] == nil || ve.Rho[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// challenge is the Fiat-Shamir challenge, whose first VerifiableEncryptionIters bits pick the ciphertexts to open
+func (ve *VerifiableEncryption) challenge(pk *paillier.PublicKey, R, Q *crypto.ECPoint) *big.Int {
+	msg := make([]*big.Int, 0, 5+4*VerifiableEncryptionIters)
+	msg = append(msg, pk.N, R.X(), R.Y(), Q.X(), Q.Y())
+	for i := range ve.T {
+		msg = append(msg, ve.T[i].X(), ve.T[i].Y(), ve.C0[i], ve.C1[i])
+	}
+	return common.SHA512_256i(msg...)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package zkp_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	. "github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestVerifiableEncryption(t *testing.T) {
+	sk, pk, err := paillier.GenerateKeyPair(1024, 10*time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	q := tss.EC().Params().N
+	R := crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(q))
+	x := common.GetRandomPositiveInt(q)
+	Q := R.ScalarMult(x)
+
+	ve, err := NewVerifiableEncryption(pk, R, Q, x)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, ve.Verify(pk, R, Q))
+	decrypted, err := ve.Decrypt(sk, R, Q)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, x.Cmp(decrypted))
+	}
+
+	// another point or escrow key
+	assert.False(t, ve.Verify(pk, R, R))
+	_, otherPK, err := paillier.GenerateKeyPair(1024, 10*time.Minute)
+	if assert.NoError(t, err) {
+		assert.False(t, ve.Verify(otherPK, R, Q))
+	}
+}
+
+func TestVerifiableEncryptionOfWrongValue(t *testing.T) {
+	_, pk, err := paillier.GenerateKeyPair(1024, 10*time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	q := tss.EC().Params().N
+	R := crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(q))
+	x := common.GetRandomPositiveInt(q)
+	Q := R.ScalarMult(x)
+
+	ve, err := NewVerifiableEncryption(pk, R, Q, new(big.Int).Add(x, big.NewInt(1)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, ve.Verify(pk, R, Q), "an encryption of another value must not verify")
+
+	ve, _ = NewVerifiableEncryption(pk, R, Q, x)
+	ve.C1[0], ve.C1[1] = ve.C1[1], ve.C1[0]
+	assert.False(t, ve.Verify(pk, R, Q), "a tampered encryption must not verify")
+}
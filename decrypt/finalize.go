@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decrypt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/ecies"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func (round *finalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+	round.number = 2
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+
+	// verify each decryption share against the party's public share W_j and sum them to x*R
+	var shared *crypto.ECPoint
+	for j, Pj := range Ps {
+		round.ok[j] = true
+		r1msg := round.temp.decryptRound1Messages[j].Content().(*DecryptRound1Message)
+		pointDj, err := r1msg.UnmarshalDecryptionShare(round.EC())
+		if err != nil {
+			culprits = append(culprits, Pj)
+			continue
+		}
+		if j != round.PartyID().Index {
+			proof, err := r1msg.UnmarshalProof(round.EC())
+			if err != nil {
+				culprits = append(culprits, Pj)
+				continue
+			}
+			st := zkp.ECDDHStatement{
+				Curve: round.EC(),
+				G2:    round.temp.pointR,
+				H1:    round.temp.bigWs[j],
+				H2:    pointDj,
+			}
+			ok := proof.Verify(st)
+			round.ObserveProof(tss.ProofECDDH, ok)
+			if !ok {
+				culprits = append(culprits, Pj)
+				continue
+			}
+		}
+		if shared == nil {
+			shared = pointDj
+			continue
+		}
+		if shared, err = shared.Add(pointDj); err != nil {
+			return round.WrapError(fmt.Errorf("failed to sum the decryption shares: %v", err))
+		}
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("failed to verify the decryption shares")), culprits...)
+	}
+
+	plaintext, err := ecies.DecryptWithSharedPoint(round.EC(), shared, round.temp.ciphertext)
+	if err != nil {
+		return round.WrapError(err)
+	}
+	round.end <- plaintext
+
+	return nil
+}
+
+func (round *finalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *finalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *finalization) NextRound() tss.Round {
+	return nil // finished!
+}
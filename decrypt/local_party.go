@@ -0,0 +1,203 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package decrypt implements threshold decryption of ECIES ciphertexts encrypted to the public key of a committee.
+// Each of t+1 parties contributes a decryption share w_i*R of the ciphertext's ephemeral key R, along with a proof
+// that it used the same secret as its public share W_i = w_i*G. The shares sum to x*R, the point that the holder of
+// the private key x would compute, so the private key is never reconstructed.
+package decrypt
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	ecdsaKeygen "github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var _ tss.Party = (*LocalParty)(nil)
+var _ fmt.Stringer = (*LocalParty)(nil)
+
+type (
+	LocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		key  Key
+		temp localTempData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- []byte
+	}
+
+	// Key is the part of a party's keygen save data that decryption uses. It may hold an ECDSA or an EdDSA key.
+	Key struct {
+		Xi     *big.Int
+		Ks     []*big.Int
+		BigXj  []*crypto.ECPoint
+		PubKey *crypto.ECPoint
+	}
+
+	localMessageStore struct {
+		decryptRound1Messages []tss.ParsedMessage
+	}
+
+	localTempData struct {
+		localMessageStore
+
+		ciphertext []byte
+
+		// round 1
+		pointR *crypto.ECPoint
+		wi     *big.Int
+		bigWs  []*crypto.ECPoint
+	}
+)
+
+// KeyFromECDSA returns the decryption Key of an ECDSA keygen save data
+func KeyFromECDSA(key ecdsaKeygen.LocalPartySaveData) Key {
+	return Key{
+		Xi:     key.Xi,
+		Ks:     key.Ks,
+		BigXj:  key.BigXj,
+		PubKey: key.ECDSAPub,
+	}
+}
+
+// KeyFromEdDSA returns the decryption Key of an EdDSA keygen save data
+func KeyFromEdDSA(key eddsaKeygen.LocalPartySaveData) Key {
+	return Key{
+		Xi:     key.Xi,
+		Ks:     key.Ks,
+		BigXj:  key.BigXj,
+		PubKey: key.EDDSAPub,
+	}
+}
+
+// NewLocalParty constructs a party that decrypts `ciphertext`, which was produced by ecies.Encrypt with the key's public key.
+// The plaintext is sent on `end` once the decryption shares of all the parties in `params` have been combined.
+func NewLocalParty(
+	ciphertext []byte,
+	params *tss.Parameters,
+	key Key,
+	out chan<- tss.Message,
+	end chan<- []byte,
+) tss.Party {
+	partyCount := len(params.Parties().IDs())
+	p := &LocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		key:       buildKeySubset(key, params.Parties().IDs()),
+		temp:      localTempData{},
+		out:       out,
+		end:       end,
+	}
+	// msgs init
+	p.temp.decryptRound1Messages = make([]tss.ParsedMessage, partyCount)
+
+	// temp data init
+	p.temp.ciphertext = ciphertext
+	return p
+}
+
+func (p *LocalParty) FirstRound() tss.Round {
+	return newRound1(p.params, &p.key, &p.temp, p.out, p.end)
+}
+
+func (p *LocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
+		round1, ok := round.(*round1)
+		if !ok {
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		}
+		if err := round1.prepare(); err != nil {
+			return round.WrapError(err)
+		}
+		return nil
+	})
+}
+
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
+		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	}
+	return p.BaseParty.ValidateMessage(msg)
+}
+
+func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	fromPIdx := msg.GetFrom().Index
+
+	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	switch msg.Content().(type) {
+	case *DecryptRound1Message:
+		p.temp.decryptRound1Messages[fromPIdx] = msg
+
+	default: // unrecognised message, just ignore!
+		common.Logger.Warnf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
+
+// buildKeySubset re-indexes the key to the parties taking part, as keygen.BuildLocalSaveDataSubset does
+func buildKeySubset(key Key, sortedIDs tss.SortedPartyIDs) Key {
+	keysToIndices := make(map[string]int, len(key.Ks))
+	for j, kj := range key.Ks {
+		keysToIndices[hex.EncodeToString(kj.Bytes())] = j
+	}
+	newKey := Key{
+		Xi:     key.Xi,
+		Ks:     make([]*big.Int, sortedIDs.Len()),
+		BigXj:  make([]*crypto.ECPoint, sortedIDs.Len()),
+		PubKey: key.PubKey,
+	}
+	for j, id := range sortedIDs {
+		savedIdx, ok := keysToIndices[hex.EncodeToString(id.Key)]
+		if !ok {
+			panic(errors.New("buildKeySubset: unable to find a decrypting party in the key"))
+		}
+		newKey.Ks[j] = key.Ks[savedIdx]
+		newKey.BigXj[j] = key.BigXj[savedIdx]
+	}
+	return newKey
+}
@@ -0,0 +1,141 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decrypt
+
+import (
+	"crypto/elliptic"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/ecies"
+	ecdsaKeygen "github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrentECDSA(t *testing.T) {
+	setUp("info")
+
+	// PHASE: load keygen fixtures
+	keys, pIDs, err := ecdsaKeygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	decryptKeys := make([]Key, len(keys))
+	for i, key := range keys {
+		decryptKeys[i] = KeyFromECDSA(key)
+	}
+
+	// PHASE: encrypt to the committee key
+	plaintext := []byte("hello committee")
+	ciphertext, err := ecies.Encrypt(keys[0].ECDSAPub, plaintext)
+	assert.NoError(t, err)
+
+	// PHASE: decrypt
+	runDecrypt(t, btcec.S256(), ciphertext, plaintext, decryptKeys, pIDs)
+}
+
+func TestE2EConcurrentEdDSA(t *testing.T) {
+	setUp("info")
+
+	// PHASE: load keygen fixtures
+	keys, pIDs, err := eddsaKeygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	decryptKeys := make([]Key, len(keys))
+	for i, key := range keys {
+		decryptKeys[i] = KeyFromEdDSA(key)
+	}
+
+	// PHASE: encrypt to the committee key
+	plaintext := []byte("hello committee")
+	ciphertext, err := ecies.Encrypt(keys[0].EDDSAPub, plaintext)
+	assert.NoError(t, err)
+
+	// PHASE: decrypt
+	runDecrypt(t, edwards.Edwards(), ciphertext, plaintext, decryptKeys, pIDs)
+}
+
+func runDecrypt(t *testing.T, ec elliptic.Curve, ciphertext, plaintext []byte, keys []Key, pIDs tss.SortedPartyIDs) {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan []byte, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// init the parties
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetCurve(ec)
+
+		P := NewLocalParty(ciphertext, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+decrypt:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break decrypt
+
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go updater(P, msg, errCh)
+			}
+
+		case got := <-endCh:
+			assert.Equal(t, plaintext, got)
+			ended++
+			if ended == len(pIDs) {
+				t.Logf("Done. Received the plaintext from %d participants", ended)
+				break decrypt
+			}
+		}
+	}
+}
+
+func TestPrepareRejectsCurveMismatch(t *testing.T) {
+	keys, pIDs, err := ecdsaKeygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	ciphertext, err := ecies.Encrypt(keys[0].ECDSAPub, []byte("hello committee"))
+	assert.NoError(t, err)
+
+	params := tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), testThreshold)
+	params.SetCurve(edwards.Edwards())
+	P := NewLocalParty(ciphertext, params, KeyFromECDSA(keys[0]), make(chan tss.Message, len(pIDs)), nil)
+	if err := P.Start(); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "curve")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decrypt
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into decrypt.pb.go
+
+var (
+	// Ensure that decrypt messages implement ValidateBasic
+	_ = []tss.MessageContent{
+		(*DecryptRound1Message)(nil),
+	}
+)
+
+// ----- //
+
+func NewDecryptRound1Message(
+	from *tss.PartyID,
+	decryptionShare *crypto.ECPoint,
+	proof *zkp.ECDDHProof,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	content := &DecryptRound1Message{
+		DecryptionShare: decryptionShare.ToProtobufPoint(),
+		ProofA1:         proof.A1.ToProtobufPoint(),
+		ProofA2:         proof.A2.ToProtobufPoint(),
+		ProofZ:          proof.Z.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *DecryptRound1Message) ValidateBasic() bool {
+	return m != nil &&
+		m.DecryptionShare != nil &&
+		m.DecryptionShare.ValidateBasic() &&
+		m.ProofA1 != nil &&
+		m.ProofA1.ValidateBasic() &&
+		m.ProofA2 != nil &&
+		m.ProofA2.ValidateBasic() &&
+		common.NonEmptyBytes(m.ProofZ)
+}
+
+func (m *DecryptRound1Message) UnmarshalDecryptionShare(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	point, err := crypto.NewECPointFromProtobuf(ec, m.GetDecryptionShare())
+	if err != nil {
+		return nil, err
+	}
+	if !point.IsInPrimeOrderSubgroup() {
+		return nil, errors.New("the decryption share is not in the prime-order subgroup")
+	}
+	return point, nil
+}
+
+func (m *DecryptRound1Message) UnmarshalProof(ec elliptic.Curve) (*zkp.ECDDHProof, error) {
+	a1, err := crypto.NewECPointFromProtobuf(ec, m.GetProofA1())
+	if err != nil {
+		return nil, err
+	}
+	a2, err := crypto.NewECPointFromProtobuf(ec, m.GetProofA2())
+	if err != nil {
+		return nil, err
+	}
+	return &zkp.ECDDHProof{
+		A1: a1,
+		A2: a2,
+		Z:  new(big.Int).SetBytes(m.GetProofZ()),
+	}, nil
+}
@@ -0,0 +1,113 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package decrypt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto/ecies"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/signing"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// round 1 broadcasts this party's decryption share along with a proof that it is well-formed
+func newRound1(params *tss.Parameters, key *Key, temp *localTempData, out chan<- tss.Message, end chan<- []byte) tss.Round {
+	return &round1{
+		&base{params, key, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1}}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started"))
+	}
+
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	i := round.PartyID().Index
+
+	// 1. compute the decryption share D_i = w_i*R
+	pointDi := round.temp.pointR.ScalarMult(round.temp.wi)
+
+	// 2. prove that log_G(W_i) == log_R(D_i)
+	st := zkp.ECDDHStatement{
+		Curve: round.EC(),
+		G2:    round.temp.pointR,
+		H1:    round.temp.bigWs[i],
+		H2:    pointDi,
+	}
+	proof := zkp.NewECDDHProof(zkp.ECDDHWitness{X: round.temp.wi}, st)
+
+	// 3. broadcast the share and proof
+	r1msg := NewDecryptRound1Message(round.PartyID(), pointDi, &proof)
+	round.temp.decryptRound1Messages[i] = r1msg
+	round.out <- round.UseCodec(r1msg)
+
+	return nil
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	ret := true
+	for j, msg := range round.temp.decryptRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*DecryptRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &finalization{round}
+}
+
+// ----- //
+
+// prepare parses the ciphertext and computes this party's additive share of the key and the public shares of all parties
+func (round *round1) prepare() error {
+	i := round.PartyID().Index
+
+	key := round.key
+	if key.Xi == nil || key.PubKey == nil {
+		return errors.New("the key is incomplete")
+	}
+	keyCurve, _ := tss.GetCurveName(key.PubKey.Curve())
+	if paramsCurve, _ := tss.GetCurveName(round.EC()); keyCurve != paramsCurve {
+		return fmt.Errorf("the key is on curve %q but the parameters are on curve %q", keyCurve, paramsCurve)
+	}
+	if round.Threshold()+1 > len(key.Ks) {
+		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(key.Ks))
+	}
+	pointR, err := ecies.EphemeralKey(round.EC(), round.temp.ciphertext)
+	if err != nil {
+		return err
+	}
+	wi, bigWs, err := signing.PrepareForSigning(round.EC(), i, len(key.Ks), key.Xi, key.Ks, key.BigXj)
+	if err != nil {
+		return err
+	}
+
+	round.temp.pointR = pointR
+	round.temp.wi = wi
+	round.temp.bigWs = bigWs
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/ecies"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
+)
+
+type (
+	// RecoveryCommittee is the set of recovery parties among which the share backups are split. Any Threshold+1 of
+	// them can together recover a party's share, while fewer learn nothing about it. A committee of several recovery
+	// parties must have a Threshold of at least 1, so that none of them can recover a share alone. A committee of a
+	// single break-glass key has a Threshold of 0, and the share is encrypted to that key whole.
+	RecoveryCommittee struct {
+		Threshold int
+		PKs       []*crypto.ECPoint
+	}
+
+	// ShareBackup is a party's secret share split among the parties of a RecoveryCommittee, with the piece of each
+	// recovery party encrypted to its public key. Recovering the key takes the pieces of RecoveryThreshold+1 recovery
+	// parties for each of the backups of threshold+1 parties.
+	ShareBackup struct {
+		Threshold         int
+		RecoveryThreshold int
+		ECDSAPub          *crypto.ECPoint
+		ShareID           *big.Int
+		// ECIES(len(ShareID) || ShareID || piece) for each recovery party in the order of the committee; see
+		// backupPlaintext
+		Ciphertexts [][]byte
+	}
+)
+
+// Validate checks that the committee has at least Threshold+1 public keys and that Threshold is at least 1, unless it
+// has a single public key and a Threshold of 0
+func (committee RecoveryCommittee) Validate() error {
+	if committee.Threshold < 0 {
+		return errors.New("the recovery threshold must not be negative")
+	}
+	if committee.Threshold == 0 && len(committee.PKs) != 1 {
+		return fmt.Errorf("a recovery threshold of 0 needs a single public key but the committee has %d", len(committee.PKs))
+	}
+	if len(committee.PKs) <= committee.Threshold {
+		return fmt.Errorf("the recovery committee needs more than %d public keys but has %d", committee.Threshold, len(committee.PKs))
+	}
+	for i, pk := range committee.PKs {
+		if pk == nil || !pk.ValidateBasic() {
+			return fmt.Errorf("the public key of recovery party %d is invalid", i)
+		}
+	}
+	return nil
+}
+
+// NewShareBackup splits the share in `key` among the recovery parties of `committee` and encrypts the piece of each
+// recovery party to its public key. With a Threshold of 0, the piece of the single recovery party is the share itself.
+func NewShareBackup(key LocalPartySaveData, threshold int, committee RecoveryCommittee) (*ShareBackup, error) {
+	if key.Xi == nil || key.ShareID == nil || key.ECDSAPub == nil {
+		return nil, errors.New("NewShareBackup() received incomplete save data")
+	}
+	if err := committee.Validate(); err != nil {
+		return nil, err
+	}
+	ec := key.ECDSAPub.Curve()
+	pieces := vss.Shares{{Threshold: 0, ID: big.NewInt(1), Share: key.Xi}}
+	if 0 < committee.Threshold {
+		var err error
+		if _, pieces, err = vss.Create(ec, committee.Threshold, key.Xi, recoveryIDs(len(committee.PKs))); err != nil {
+			return nil, err
+		}
+	}
+	ciphertexts := make([][]byte, len(committee.PKs))
+	for k, pk := range committee.PKs {
+		plaintext, err := backupPlaintext(ec, key.ShareID, pieces[k].Share)
+		if err != nil {
+			return nil, err
+		}
+		if ciphertexts[k], err = ecies.Encrypt(pk, plaintext); err != nil {
+			return nil, err
+		}
+	}
+	return &ShareBackup{
+		Threshold:         threshold,
+		RecoveryThreshold: committee.Threshold,
+		ECDSAPub:          key.ECDSAPub,
+		ShareID:           key.ShareID,
+		Ciphertexts:       ciphertexts,
+	}, nil
+}
+
+// Decrypt recovers the piece of the share in the backup that belongs to the recovery party at `recoveryIndex` in the
+// committee, using its private key. The piece reveals nothing about the share on its own.
+func (backup *ShareBackup) Decrypt(recoveryIndex int, recoverySK *big.Int) (*vss.Share, error) {
+	if recoveryIndex < 0 || len(backup.Ciphertexts) <= recoveryIndex {
+		return nil, fmt.Errorf("the backup has no piece for recovery party %d", recoveryIndex)
+	}
+	ec := backup.ECDSAPub.Curve()
+	plaintext, err := ecies.Decrypt(ec, recoverySK, backup.Ciphertexts[recoveryIndex])
+	if err != nil {
+		return nil, err
+	}
+	shareID, piece, err := parseBackupPlaintext(ec, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if shareID.Cmp(backup.ShareID) != 0 {
+		return nil, errors.New("the piece belongs to the share of another party")
+	}
+	return &vss.Share{
+		Threshold: backup.RecoveryThreshold,
+		ID:        big.NewInt(int64(recoveryIndex + 1)),
+		Share:     piece,
+	}, nil
+}
+
+// RecoverKey reconstructs the private key from the backups of at least threshold+1 parties, checking it against the
+// public key recorded in the backups. pieces[i] holds the pieces of backups[i] that at least RecoveryThreshold+1
+// recovery parties decrypted with Decrypt. Whoever runs RecoverKey learns the key.
+func RecoverKey(backups []*ShareBackup, pieces []vss.Shares) (*big.Int, error) {
+	if len(backups) == 0 {
+		return nil, errors.New("RecoverKey() received no backups")
+	}
+	if len(pieces) != len(backups) {
+		return nil, fmt.Errorf("RecoverKey() received pieces for %d backups but %d backups", len(pieces), len(backups))
+	}
+	threshold, ecdsaPub := backups[0].Threshold, backups[0].ECDSAPub
+	if len(backups) <= threshold {
+		return nil, fmt.Errorf("RecoverKey() needs at least %d backups but received %d", threshold+1, len(backups))
+	}
+	ec := ecdsaPub.Curve()
+	shares := make(vss.Shares, len(backups))
+	for i, backup := range backups {
+		if backup.Threshold != threshold || !backup.ECDSAPub.Equals(ecdsaPub) {
+			return nil, fmt.Errorf("backup %d does not belong to the same key", i)
+		}
+		if len(pieces[i]) <= backup.RecoveryThreshold {
+			return nil, fmt.Errorf("backup %d needs the pieces of at least %d recovery parties but has %d",
+				i, backup.RecoveryThreshold+1, len(pieces[i]))
+		}
+		xi, err := pieces[i].ReConstruct(ec)
+		if err != nil {
+			return nil, fmt.Errorf("the share of backup %d could not be recovered: %v", i, err)
+		}
+		shares[i] = &vss.Share{Threshold: threshold, ID: backup.ShareID, Share: xi}
+	}
+	sk, err := shares.ReConstruct(ec)
+	if err != nil {
+		return nil, err
+	}
+	if !crypto.ScalarBaseMult(ec, sk).Equals(ecdsaPub) {
+		return nil, errors.New("the recovered key does not match the public key")
+	}
+	return sk, nil
+}
+
+// backupPlaintext encodes the ShareID, which is a party key of any size, with a 2-byte big-endian length prefix,
+// followed by the piece padded to the byte size of the curve
+func backupPlaintext(ec elliptic.Curve, shareID, piece *big.Int) ([]byte, error) {
+	idBytes := shareID.Bytes()
+	if math.MaxUint16 < len(idBytes) {
+		return nil, errors.New("the ShareID is too long to back up")
+	}
+	byteSize := (ec.Params().BitSize + 7) / 8
+	plaintext := make([]byte, 2+len(idBytes)+byteSize)
+	binary.BigEndian.PutUint16(plaintext, uint16(len(idBytes)))
+	copy(plaintext[2:], idBytes)
+	piece.FillBytes(plaintext[2+len(idBytes):])
+	return plaintext, nil
+}
+
+// parseBackupPlaintext decodes a plaintext encoded by backupPlaintext
+func parseBackupPlaintext(ec elliptic.Curve, plaintext []byte) (shareID, piece *big.Int, err error) {
+	byteSize := (ec.Params().BitSize + 7) / 8
+	if len(plaintext) < 2 {
+		return nil, nil, errors.New("share backup has an unexpected length")
+	}
+	idLen := int(binary.BigEndian.Uint16(plaintext))
+	if len(plaintext) != 2+idLen+byteSize {
+		return nil, nil, errors.New("share backup has an unexpected length")
+	}
+	return new(big.Int).SetBytes(plaintext[2 : 2+idLen]), new(big.Int).SetBytes(plaintext[2+idLen:]), nil
+}
+
+// recoveryIDs returns the evaluation points of the pieces of `count` recovery parties, 1 to count; see Decrypt
+func recoveryIDs(count int) []*big.Int {
+	ids := make([]*big.Int, count)
+	for k := range ids {
+		ids[k] = big.NewInt(int64(k + 1))
+	}
+	return ids
+}
@@ -0,0 +1,294 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// Implements Party
+var _ tss.Party = (*DurableParty)(nil)
+
+type (
+	// DurableParty runs a keygen party over a durable, append-only log so that it can crash and be resumed.
+	// Each message received is appended to the log before it is processed. When the party moves to a new round, a
+	// snapshot of its state is appended together with the messages of that round before any of them is sent.
+	// After a crash, ResumeDurableParty restores the party from its last snapshot, sends that round's messages again
+	// and replays the messages received since.
+	//
+	// The log holds the party's secrets, so it must be stored as securely as the save data.
+	DurableParty struct {
+		*LocalParty
+		mtx       sync.Mutex
+		log       *json.Encoder
+		partyOut  chan tss.Message
+		transport chan<- tss.Message
+
+		// set when the party was restored from the log and has yet to be started
+		resumeSent,
+		resumeReceived []tss.ParsedMessage
+	}
+
+	// logRecord is one line of the durable log: either a round's snapshot and the messages sent in it, or a message received
+	logRecord struct {
+		Snapshot *Snapshot       `json:",omitempty"`
+		Sent     []loggedMessage `json:",omitempty"`
+		Received *loggedMessage  `json:",omitempty"`
+	}
+
+	loggedMessage struct {
+		From        int
+		To          []int `json:",omitempty"`
+		IsBroadcast bool
+		WireBytes   []byte
+	}
+)
+
+// NewDurableParty constructs a keygen party that appends to the durable log `log` as it runs.
+// `out` should be consumed without calling back into the party from the same goroutine, as for NewLocalParty.
+func NewDurableParty(
+	params *tss.Parameters,
+	log io.Writer,
+	out chan<- tss.Message,
+	end chan<- LocalPartySaveData,
+	optionalPreParams ...LocalPreParams,
+) *DurableParty {
+	partyOut := make(chan tss.Message, 2*params.PartyCount())
+	return &DurableParty{
+		LocalParty: NewLocalParty(params, partyOut, end, optionalPreParams...).(*LocalParty),
+		log:        json.NewEncoder(log),
+		partyOut:   partyOut,
+		transport:  out,
+	}
+}
+
+// ResumeDurableParty restores a party that crashed from the log it wrote, read from `logged`. It continues to append
+// to `log`, which should be the same log opened for appending. Call Start on the returned party to resume it.
+func ResumeDurableParty(
+	params *tss.Parameters,
+	logged io.Reader,
+	log io.Writer,
+	out chan<- tss.Message,
+	end chan<- LocalPartySaveData,
+) (*DurableParty, error) {
+	records, torn, err := readLog(logged)
+	if err != nil {
+		return nil, err
+	}
+	last := -1
+	for j, record := range records {
+		if record.Snapshot != nil {
+			last = j
+		}
+	}
+	if last < 0 {
+		return nil, errors.New("the log holds no snapshot, so no message was sent; start a new party instead")
+	}
+
+	partyOut := make(chan tss.Message, 2*params.PartyCount())
+	party, err := NewLocalPartyFromSnapshot(params, records[last].Snapshot, partyOut, end)
+	if err != nil {
+		return nil, err
+	}
+	p := &DurableParty{
+		LocalParty: party,
+		log:        json.NewEncoder(log),
+		partyOut:   partyOut,
+		transport:  out,
+	}
+	for _, record := range records[last:] {
+		for _, lm := range record.Sent {
+			msg, err := lm.parse(params)
+			if err != nil {
+				return nil, err
+			}
+			p.resumeSent = append(p.resumeSent, msg)
+		}
+		if record.Received != nil {
+			msg, err := record.Received.parse(params)
+			if err != nil {
+				return nil, err
+			}
+			p.resumeReceived = append(p.resumeReceived, msg)
+		}
+	}
+	if torn {
+		// terminate the record torn by the crash so that the next one starts on a line of its own
+		if _, err = log.Write([]byte{'\n'}); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Start starts a new party, or resumes a party returned by ResumeDurableParty
+func (p *DurableParty) Start() *tss.Error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.resumeRound < 1 {
+		if err := p.LocalParty.Start(); err != nil {
+			return err
+		}
+		return p.persistAndSend()
+	}
+	if err := p.LocalParty.Resume(); err != nil {
+		return err
+	}
+	// the messages of the snapshot's round may not all have been sent before the crash
+	for _, msg := range p.resumeSent {
+		p.transport <- msg
+	}
+	for _, msg := range p.resumeReceived {
+		if _, err := p.LocalParty.Update(msg); err != nil {
+			return err
+		}
+	}
+	p.resumeSent, p.resumeReceived = nil, nil
+	return p.persistAndSend()
+}
+
+func (p *DurableParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	lm, err2 := newLoggedMessage(msg)
+	if err2 != nil {
+		return false, p.WrapError(err2)
+	}
+	if err2 = p.log.Encode(logRecord{Received: lm}); err2 != nil {
+		return false, p.WrapError(fmt.Errorf("failed to append a received message to the log: %v", err2))
+	}
+	if ok, err = p.LocalParty.Update(msg); !ok || err != nil {
+		return ok, err
+	}
+	return true, p.persistAndSend()
+}
+
+func (p *DurableParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+// persistAndSend appends the state of a new round and its messages to the log, then sends the messages
+func (p *DurableParty) persistAndSend() *tss.Error {
+	var msgs []tss.Message
+	for drained := false; !drained; {
+		select {
+		case msg := <-p.partyOut:
+			msgs = append(msgs, msg)
+		default:
+			drained = true
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	record := logRecord{Sent: make([]loggedMessage, 0, len(msgs))}
+	// a party that finished in this update has no state left to snapshot; its messages are still logged
+	if p.Running() {
+		snap, err := p.LocalParty.Snapshot()
+		if err != nil {
+			return p.WrapError(err)
+		}
+		record.Snapshot = snap
+	}
+	for _, msg := range msgs {
+		lm, err := newLoggedMessage(msg)
+		if err != nil {
+			return p.WrapError(err)
+		}
+		record.Sent = append(record.Sent, *lm)
+	}
+	if err := p.log.Encode(record); err != nil {
+		return p.WrapError(fmt.Errorf("failed to append a snapshot to the log: %v", err))
+	}
+	for _, msg := range msgs {
+		p.transport <- msg
+	}
+	return nil
+}
+
+// ----- //
+
+func newLoggedMessage(msg tss.Message) (*loggedMessage, error) {
+	bz, _, err := msg.WireBytes()
+	if err != nil {
+		return nil, err
+	}
+	lm := &loggedMessage{
+		From:        msg.GetFrom().Index,
+		IsBroadcast: msg.IsBroadcast(),
+		WireBytes:   bz,
+	}
+	for _, to := range msg.GetTo() {
+		lm.To = append(lm.To, to.Index)
+	}
+	return lm, nil
+}
+
+func (lm *loggedMessage) parse(params *tss.Parameters) (tss.ParsedMessage, error) {
+	Ps := params.Parties().IDs()
+	if lm.From < 0 || len(Ps) <= lm.From {
+		return nil, fmt.Errorf("the log holds a message from an unknown party %d", lm.From)
+	}
+	msg, err := params.ParseWireMessage(lm.WireBytes, Ps[lm.From], lm.IsBroadcast)
+	if err != nil || len(lm.To) == 0 {
+		return msg, err
+	}
+	meta := tss.MessageRouting{
+		From:        Ps[lm.From],
+		To:          make([]*tss.PartyID, 0, len(lm.To)),
+		IsBroadcast: lm.IsBroadcast,
+	}
+	for _, to := range lm.To {
+		if to < 0 || len(Ps) <= to {
+			return nil, fmt.Errorf("the log holds a message to an unknown party %d", to)
+		}
+		meta.To = append(meta.To, Ps[to])
+	}
+	return params.UseCodec(tss.NewMessage(meta, msg.Content(), msg.WireMsg())), nil
+}
+
+// readLog reads the records of a log. A record torn by a crash while it was being appended is skipped, and `torn`
+// reports whether the log ends without the newline that completes a record.
+func readLog(logged io.Reader) (records []logRecord, torn bool, err error) {
+	r := bufio.NewReader(logged)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, false, err
+		}
+		if err == io.EOF {
+			torn = 0 < len(line)
+		}
+		if line = bytes.TrimSpace(line); 0 < len(line) {
+			var record logRecord
+			if err := json.Unmarshal(line, &record); err != nil {
+				common.Logger.Warnf("skipping a torn record in the keygen log: %v", err)
+			} else {
+				records = append(records, record)
+			}
+		}
+		if err == io.EOF {
+			return records, torn, nil
+		}
+	}
+}
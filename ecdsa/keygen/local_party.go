@@ -33,6 +33,9 @@ type (
 		// outbound messaging
 		out chan<- tss.Message
 		end chan<- LocalPartySaveData
+
+		// the round of the snapshot that the party was restored from, if any
+		resumeRound int
 	}
 
 	localMessageStore struct {
@@ -51,6 +54,10 @@ type (
 		vs            vss.Vs
 		shares        vss.Shares
 		deCommitPolyG cmt.HashDeCommitment
+
+		// backup mode: the share is split among this committee at the end of keygen
+		shareBackupCommittee *RecoveryCommittee
+		shareBackup          *ShareBackup
 	}
 )
 
@@ -68,7 +75,7 @@ func NewLocalParty(
 		if 1 < len(optionalPreParams) {
 			panic(errors.New("keygen.NewLocalParty expected 0 or 1 item in `optionalPreParams`"))
 		}
-		if !optionalPreParams[0].ValidateWithProof() {
+		if !optionalPreParams[0].ValidateWithProof() && !optionalPreParams[0].ValidateWithTrustedSetup() {
 			panic(errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
 		}
 		data.LocalPreParams = optionalPreParams[0]
@@ -104,7 +111,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -165,6 +172,22 @@ func (save LocalPartySaveData) OriginalIndex() (int, error) {
 	return index, nil
 }
 
+// SetShareBackupCommittee enables backup mode: at the end of keygen the party's share is split among the recovery
+// parties of `committee` and each piece is encrypted to its recovery party; see NewShareBackup. It must be called
+// before Start.
+func (p *LocalParty) SetShareBackupCommittee(committee RecoveryCommittee) error {
+	if err := committee.Validate(); err != nil {
+		return err
+	}
+	p.temp.shareBackupCommittee = &committee
+	return nil
+}
+
+// ShareBackup returns the encrypted backup of this party's share once keygen has finished in backup mode, or nil otherwise.
+func (p *LocalParty) ShareBackup() *ShareBackup {
+	return p.temp.shareBackup
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
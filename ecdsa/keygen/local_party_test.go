@@ -7,21 +7,30 @@
 package keygen
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
+	"reflect"
 	"runtime"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
+	cmts "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
 	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
 	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
 	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
@@ -80,6 +89,117 @@ func TestStartRound1Paillier(t *testing.T) {
 	assert.Equal(t, 2048/8, len2)
 }
 
+func TestGeneratePreParamsWithModulusBitsRejectsUnsupportedSizes(t *testing.T) {
+	for _, bits := range []int{2049, 3071, 4104, 8192} {
+		_, err := GeneratePreParamsWithModulusBits(time.Minute, bits)
+		if assert.Error(t, err, "%d bits should be rejected", bits) {
+			assert.Contains(t, err.Error(), "is not supported")
+		}
+	}
+	for _, bits := range []int{0, 1024, 1536, 2040} {
+		_, err := GeneratePreParamsWithModulusBits(time.Minute, bits)
+		if assert.Error(t, err, "%d bits should be rejected", bits) {
+			assert.Contains(t, err.Error(), "too short for a curve with a 256-bit order")
+		}
+	}
+	assert.NoError(t, checkPaillierModulusBits(tss.EC(), 3072))
+	assert.NoError(t, checkPaillierModulusBits(elliptic.P224(), 1792))
+	assert.NoError(t, checkPaillierModulusBits(elliptic.P224(), 4096))
+
+	// a curve whose order is longer than 512 bits needs a longer modulus than 4096 bits, and accepts it
+	minBits, maxBits := PaillierModulusBitsRange(elliptic.P521())
+	assert.Equal(t, 8*521, minBits)
+	assert.Equal(t, minBits, maxBits)
+	assert.NoError(t, checkPaillierModulusBits(elliptic.P521(), minBits))
+	assert.Equal(t, minBits, paillierModulusBitLen(elliptic.P521()))
+}
+
+// TestE2EConcurrentCurveOver512Bits runs keygen over P-521, whose parties need a Paillier modulus of 4168 bits
+func TestE2EConcurrentCurveOver512Bits(t *testing.T) {
+	setUp("info")
+	ec := elliptic.P521()
+	bits := paillierModulusBitLen(ec)
+
+	fixtures, _, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	pIDs := tss.GenerateTestPartyIDs(len(fixtures))
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		params[i].SetCurve(ec)
+		// the Ring-Pedersen parameters of the fixture do not depend on the curve, but its Paillier modulus is too short;
+		// the primes of the test modulus are not safe primes, which would take minutes to find at this length
+		fixtures[i].PaillierSK = testPaillierKey(t, bits)
+	}
+	saves, tErr := runKeygen(params, fixtures)
+	if !assert.Nil(t, tErr) {
+		return
+	}
+	for i, save := range saves {
+		assert.Equal(t, bits, save.PaillierSK.N.BitLen())
+		assert.True(t, ec.IsOnCurve(save.ECDSAPub.X(), save.ECDSAPub.Y()))
+		assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub), "party %d should agree on the public key", i)
+	}
+}
+
+// testPaillierKey returns a Paillier key of `bits` bits whose primes are not safe primes
+func testPaillierKey(t *testing.T, bits int) *paillier.PrivateKey {
+	for {
+		P, err := rand.Prime(rand.Reader, bits/2)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		Q, err := rand.Prime(rand.Reader, bits/2)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		N := new(big.Int).Mul(P, Q)
+		if N.BitLen() != bits {
+			continue
+		}
+		PMinus1, QMinus1 := new(big.Int).Sub(P, big.NewInt(1)), new(big.Int).Sub(Q, big.NewInt(1))
+		phiN := new(big.Int).Mul(PMinus1, QMinus1)
+		lambdaN := new(big.Int).Div(phiN, new(big.Int).GCD(nil, nil, PMinus1, QMinus1))
+		return &paillier.PrivateKey{PublicKey: paillier.PublicKey{N: N}, LambdaN: lambdaN, PhiN: phiN}
+	}
+}
+
+func TestGeneratePreParamsWithContextCancelled(t *testing.T) {
+	limiter, err := common.NewGoroutineLimiter(2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = GeneratePreParamsWithContext(ctx, 10*time.Minute, limiter, 3)
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second), "the generation should end soon after the cancellation")
+	assert.Less(t, 0, limiter.Peak(), "the searches should have run within the budget")
+
+	// the searches for the primes have returned, so the whole budget can be taken again; a search left running would
+	// hold its place and this would not return
+	release := make(chan struct{})
+	for i := 0; i < limiter.Max(); i++ {
+		limiter.Go(func() { <-release })
+	}
+	close(release)
+}
+
+func TestGeneratePreParamsPastDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	params := tss.NewParametersWithContext(ctx, nil, nil, 2, 1, time.Minute)
+	assert.Equal(t, time.Duration(0), params.SafePrimeGenTimeout(), "the timeout should not be negative")
+	_, err := GeneratePreParamsWithContext(ctx, params.SafePrimeGenTimeout(), nil, 3)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+	_, err = GeneratePreParams(0)
+	assert.Error(t, err, "a timeout of 0 should be rejected")
+}
+
 func TestFinishAndSaveH1H2(t *testing.T) {
 	setUp("debug")
 
@@ -131,9 +251,9 @@ func TestFinishAndSaveH1H2(t *testing.T) {
 func TestBadMessageCulprits(t *testing.T) {
 	setUp("debug")
 
-	pIDs := tss.GenerateTestPartyIDs(2)
-	p2pCtx := tss.NewPeerContext(pIDs)
-	params := tss.NewParameters(p2pCtx, pIDs[0], len(pIDs), 1)
+	committee := tss.GenerateTestPartyIDs(2)
+	p2pCtx := tss.NewPeerContext(committee)
+	params := tss.NewParameters(p2pCtx, committee[0], len(committee), 1)
 
 	fixtures, pIDs, err := LoadKeygenTestFixtures(testParticipants)
 	if err != nil {
@@ -152,7 +272,7 @@ func TestBadMessageCulprits(t *testing.T) {
 		assert.FailNow(t, err.Error())
 	}
 
-	badMsg, _ := NewKGRound1Message(pIDs[1], zero, &paillier.PublicKey{N: zero}, zero, zero, zero, new(dlnp.Proof), new(dlnp.Proof))
+	badMsg, _ := NewKGRound1Message(committee[1], zero, &paillier.PublicKey{N: zero}, zero, zero, zero, new(dlnp.Proof), new(dlnp.Proof))
 	ok, err2 := lp.Update(badMsg)
 	t.Log(err2)
 	assert.False(t, ok)
@@ -160,12 +280,290 @@ func TestBadMessageCulprits(t *testing.T) {
 		return
 	}
 	assert.Equal(t, 1, len(err2.Culprits()))
-	assert.Equal(t, pIDs[1], err2.Culprits()[0])
+	assert.Equal(t, committee[1], err2.Culprits()[0])
 	assert.Equal(t,
-		"task ecdsa-keygen, party {0,P[1]}, round 1, culprits [{1,2}]: message failed ValidateBasic: Type: KGRound1Message, From: {1,2}",
+		"task ecdsa-keygen, party {0,P[1]}, round 1, culprits [{1,P[2]}]: message failed ValidateBasic: Type: KGRound1Message, From: {1,P[2]}",
 		err2.Error())
 }
 
+func TestValidateCommitteePreParams(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	committee := make([]*PublicPreParams, len(fixtures))
+	for j, fixture := range fixtures {
+		committee[j], err = fixture.LocalPreParams.PublicPreParams(pIDs[j])
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, ValidateCommitteePreParams(committee), "honest pre-params should pass")
+	assert.Empty(t, ValidateCommitteePreParamsForCurve(elliptic.P256(), committee), "P-256 needs no longer a modulus")
+	// the 2048-bit Paillier keys of the fixtures are too short for the MtA values of P-521
+	assert.Equal(t, len(committee), len(ValidateCommitteePreParamsForCurve(elliptic.P521(), committee)))
+
+	// give one peer an h2 that is not in the group generated by its h1
+	committee[1].H2 = new(big.Int).Add(committee[1].H2, big.NewInt(1))
+	culprits := ValidateCommitteePreParams(committee)
+	if assert.Equal(t, 1, len(culprits)) {
+		assert.Equal(t, pIDs[1], culprits[0])
+	}
+
+	// with a trusted setup every party has the same h1, h2; the first party stands in for the dealer
+	setup, err := UNSAFE_NewTrustedSetup(fixtures[0].LocalPreParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for j, fixture := range fixtures[1:] {
+		committee[j], err = setup.PreParams(fixture.PaillierSK).PublicPreParams(pIDs[j+1])
+		assert.NoError(t, err)
+	}
+	committee = committee[:len(fixtures)-1]
+	assert.Empty(t, ValidateCommitteePreParamsWithTrustedSetup(tss.EC(), setup, committee), "a shared setup should pass")
+	assert.Equal(t, len(committee), len(ValidateCommitteePreParams(committee)), "h1, h2 are reused without a setup")
+	// a party that brings its own NTilde
+	own, err := fixtures[1].LocalPreParams.PublicPreParams(pIDs[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	culprits = ValidateCommitteePreParamsWithTrustedSetup(tss.EC(), setup, append(committee, own))
+	if assert.Equal(t, 1, len(culprits)) {
+		assert.Equal(t, pIDs[0], culprits[0], "a party without the setup should fail")
+	}
+}
+
+func TestSaveDataSplitRoundTrip(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	pub, secret := keys[0].Split()
+	pubBz, err := json.Marshal(&pub)
+	assert.NoError(t, err)
+	secretBz, err := json.Marshal(&secret)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(pubBz), `"Xi"`, "the public part should hold no secret")
+	assert.NotContains(t, string(pubBz), `"PaillierSK"`, "the public part should hold no secret")
+
+	var pub2 PublicSaveData
+	var secret2 SecretSaveData
+	assert.NoError(t, json.Unmarshal(pubBz, &pub2))
+	assert.NoError(t, json.Unmarshal(secretBz, &secret2))
+	save, err := NewLocalPartySaveDataFromParts(pub2, secret2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, save.Validate())
+	assert.True(t, save.ValidateWithProof())
+	saveBz, err := json.Marshal(&save)
+	assert.NoError(t, err)
+	originalBz, err := json.Marshal(&keys[0])
+	assert.NoError(t, err)
+	assert.Equal(t, string(originalBz), string(saveBz))
+
+	// the secret part of another party of the same key fits the public part too
+	_, secret1 := keys[1].Split()
+	_, err = NewLocalPartySaveDataFromParts(pub, secret1)
+	assert.NoError(t, err)
+
+	// but parts that do not belong together are rejected
+	mixed := secret1
+	mixed.LocalSecrets = secret.LocalSecrets
+	_, err = NewLocalPartySaveDataFromParts(pub, mixed)
+	assert.Error(t, err, "the pre-params of party 1 with the share of party 0")
+	tampered := secret
+	tampered.Xi = new(big.Int).Add(secret.Xi, big.NewInt(1))
+	_, err = NewLocalPartySaveDataFromParts(pub, tampered)
+	assert.Error(t, err, "a share that does not match g^xi")
+	_, err = NewLocalPartySaveDataFromParts(PublicSaveData{}, secret)
+	assert.Error(t, err)
+}
+
+func TestSaveDataZeroize(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	save := keys[0]
+	secrets := secretWords(reflect.ValueOf(save), map[string]bool{
+		"Xi": true, "LambdaN": true, "PhiN": true, "Alpha": true, "Beta": true, "P": true, "Q": true,
+	})
+	if !assert.Len(t, secrets, 7, "every secret should be found") {
+		return
+	}
+	save.Zeroize()
+	for _, words := range secrets {
+		for _, w := range words {
+			assert.Zero(t, w, "no secret word should remain")
+		}
+	}
+	assert.True(t, reflect.ValueOf(save).IsZero(), "every field should be cleared")
+}
+
+// secretWords returns the words backing each *big.Int field of v named in `names`, including those of embedded and
+// nested structs
+func secretWords(v reflect.Value, names map[string]bool) (secrets [][]big.Word) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			secrets = secretWords(v.Elem(), names)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f, field := v.Field(i), v.Type().Field(i)
+			if field.PkgPath != "" { // unexported
+				continue
+			}
+			if x, ok := f.Interface().(*big.Int); ok {
+				if names[field.Name] && x != nil {
+					words := x.Bits()
+					secrets = append(secrets, words[:cap(words)])
+				}
+				continue
+			}
+			secrets = append(secrets, secretWords(f, names)...)
+		}
+	}
+	return
+}
+
+func TestShareBackup(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+	keys, _, err := LoadKeygenTestFixtures(threshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	recoverySKs := make([]*big.Int, 3)
+	committee := RecoveryCommittee{Threshold: 1, PKs: make([]*crypto.ECPoint, len(recoverySKs))}
+	for k := range recoverySKs {
+		recoverySKs[k] = common.GetRandomPositiveInt(tss.EC().Params().N)
+		committee.PKs[k] = crypto.ScalarBaseMult(tss.EC(), recoverySKs[k])
+	}
+
+	backups := make([]*ShareBackup, len(keys))
+	// the pieces that recovery parties 0 and 2 decrypt from each backup
+	pieces, piecesOf0 := make([]vss.Shares, len(keys)), make([]vss.Shares, len(keys))
+	for i, key := range keys {
+		backups[i], err = NewShareBackup(key, threshold, committee)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, k := range []int{0, 2} {
+			piece, err := backups[i].Decrypt(k, recoverySKs[k])
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NotEqual(t, key.Xi, piece.Share, "a piece should not be the share")
+			pieces[i] = append(pieces[i], piece)
+		}
+		piecesOf0[i] = pieces[i][:1]
+		_, err = backups[i].Decrypt(1, recoverySKs[0])
+		assert.Error(t, err, "a recovery party should not decrypt the piece of another")
+	}
+
+	sk, err := RecoverKey(backups, pieces)
+	if assert.NoError(t, err, "threshold+1 backups with the pieces of two recovery parties should recover the key") {
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), sk).Equals(keys[0].ECDSAPub))
+	}
+	_, err = RecoverKey(backups, piecesOf0)
+	assert.Error(t, err, "the pieces of one recovery party should not be enough")
+	_, err = RecoverKey(backups[:threshold], pieces[:threshold])
+	assert.Error(t, err, "threshold backups should not be enough")
+
+	// a party key may be longer than the byte size of the curve, e.g. a 33-byte compressed public key
+	longKey := keys[0]
+	longKey.ShareID = new(big.Int).SetBytes(append([]byte{0x02}, make([]byte, 32)...))
+	longKey.ShareID.SetBit(longKey.ShareID, 0, 1)
+	backup, err := NewShareBackup(longKey, threshold, committee)
+	if assert.NoError(t, err, "a backup should take a party key of any size") {
+		var longPieces vss.Shares
+		for k := 0; k < 2; k++ {
+			piece, err := backup.Decrypt(k, recoverySKs[k])
+			if !assert.NoError(t, err) {
+				return
+			}
+			longPieces = append(longPieces, piece)
+		}
+		xi, err := longPieces.ReConstruct(tss.EC())
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, xi.Cmp(longKey.Xi), "the pieces should rebuild the share")
+		}
+	}
+
+	_, err = NewShareBackup(keys[0], threshold, RecoveryCommittee{Threshold: 0, PKs: committee.PKs})
+	assert.Error(t, err, "no one of several recovery parties should be able to recover a share alone")
+	_, err = NewShareBackup(keys[0], threshold, RecoveryCommittee{Threshold: -1, PKs: committee.PKs[:1]})
+	assert.Error(t, err, "a negative recovery threshold should be rejected")
+	_, err = NewShareBackup(keys[0], threshold, RecoveryCommittee{Threshold: 1, PKs: committee.PKs[:1]})
+	assert.Error(t, err, "a committee should have more than threshold recovery parties")
+}
+
+func TestShareBackupToSingleKey(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+	keys, _, err := LoadKeygenTestFixtures(threshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	// a single break-glass key decrypts each share whole
+	recoverySK := common.GetRandomPositiveInt(tss.EC().Params().N)
+	committee := RecoveryCommittee{Threshold: 0, PKs: []*crypto.ECPoint{crypto.ScalarBaseMult(tss.EC(), recoverySK)}}
+	backups, pieces := make([]*ShareBackup, len(keys)), make([]vss.Shares, len(keys))
+	for i, key := range keys {
+		backups[i], err = NewShareBackup(key, threshold, committee)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Len(t, backups[i].Ciphertexts, 1)
+		piece, err := backups[i].Decrypt(0, recoverySK)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 0, piece.Share.Cmp(key.Xi), "the piece should be the share")
+		pieces[i] = vss.Shares{piece}
+
+		_, err = backups[i].Decrypt(0, common.GetRandomPositiveInt(tss.EC().Params().N))
+		assert.Error(t, err, "another key should not decrypt the share")
+	}
+
+	sk, err := RecoverKey(backups, pieces)
+	if assert.NoError(t, err, "the break-glass key should recover the key from threshold+1 backups") {
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), sk).Equals(keys[0].ECDSAPub))
+	}
+	_, err = RecoverKey(backups[:threshold], pieces[:threshold])
+	assert.Error(t, err, "threshold backups should not be enough")
+}
+
+func TestVerifySharing(t *testing.T) {
+	setUp("info")
+
+	keys, _, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	for _, key := range keys {
+		assert.NoError(t, key.VerifySharing(testThreshold), "honest keygen output should pass")
+	}
+
+	// corrupt this party's own share
+	key := keys[0]
+	key.LocalSecrets.Xi = new(big.Int).Add(key.Xi, big.NewInt(1))
+	assert.Error(t, key.VerifySharing(testThreshold), "a corrupted share should fail")
+
+	// corrupt another party's public point, keeping it consistent with nothing else
+	key = keys[1]
+	key.BigXj = append([]*crypto.ECPoint{}, key.BigXj...)
+	key.BigXj[len(key.BigXj)-1] = crypto.ScalarBaseMult(tss.EC(), big.NewInt(42))
+	assert.Error(t, key.VerifySharing(testThreshold), "an inconsistent public point should fail")
+
+	// the sharing is not of a lower degree
+	assert.Error(t, keys[1].VerifySharing(testThreshold-1))
+}
+
 func TestE2EConcurrentAndSaveFixtures(t *testing.T) {
 	setUp("info")
 
@@ -262,7 +660,7 @@ keygen:
 						}
 						pShares = append(pShares, shareStruct)
 					}
-					uj, err := pShares[:threshold+1].ReConstruct()
+					uj, err := pShares[:threshold+1].ReConstruct(tss.EC())
 					assert.NoError(t, err, "vss.ReConstruct should not throw error")
 
 					// uG test: u*G[j] == V[0]
@@ -280,7 +678,7 @@ keygen:
 					{
 						badShares := pShares[:threshold]
 						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
-						uj, err := pShares[:threshold].ReConstruct()
+						uj, err := pShares[:threshold].ReConstruct(tss.EC())
 						assert.NoError(t, err)
 						assert.NotEqual(t, parties[j].temp.ui, uj)
 						BigXjX, BigXjY := tss.EC().ScalarBaseMult(uj.Bytes())
@@ -337,6 +735,887 @@ keygen:
 	}
 }
 
+func TestE2EConcurrentCompressedCommitments(t *testing.T) {
+	setUp("info")
+
+	threshold := 2
+	fixtures, pIDs, err := LoadKeygenTestFixtures(5)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), threshold)
+		params.SetCompressKGCommitments(true)
+		P := NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	// every party decompressed exactly the de-commitments that were committed to in round 1
+	for _, P := range parties {
+		for j, Pj := range parties {
+			r2msg2 := P.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
+			assert.NotEmpty(t, r2msg2.GetCompressedPolyG(), "commitment points should be sent compressed")
+			assert.Equal(t, []*big.Int(Pj.temp.deCommitPolyG), r2msg2.UnmarshalDeCommitment(tss.EC()))
+		}
+	}
+	// .. and so derived the same public data
+	for _, save := range saves[1:] {
+		assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub))
+		for j, BigXj := range save.BigXj {
+			assert.True(t, BigXj.Equals(saves[0].BigXj[j]))
+		}
+	}
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), save.Xi).Equals(save.BigXj[index]), "ensure BigX_j == g^x_j")
+	}
+
+	// an external verifier, given only the exported commitments, validates each party's share
+	bz, err := json.Marshal(saves[0].VSSCommitments)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var exported []vss.Vs
+	if !assert.NoError(t, json.Unmarshal(bz, &exported)) {
+		return
+	}
+	external := LocalPartySaveData{Ks: saves[0].Ks, VSSCommitments: exported}
+	combined, err := external.CombinedVSSCommitment()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, combined[0].Equals(saves[0].ECDSAPub), "the commitments should commit to the public key")
+	for _, save := range saves {
+		assert.Equal(t, len(exported), len(save.VSSCommitments), "every party should export the same commitments")
+		assert.True(t, vss.VerifyVSSShare(tss.EC(), threshold, combined, save.ShareID, save.Xi))
+		assert.False(t, vss.VerifyVSSShare(tss.EC(), threshold, combined, save.ShareID, new(big.Int).Add(save.Xi, big.NewInt(1))))
+	}
+
+	// a subset of the parties does not hold the commitments of all of them
+	subset := BuildLocalSaveDataSubset(saves[0], pIDs[:threshold+1])
+	assert.Nil(t, subset.VSSCommitments)
+	_, err = subset.CombinedVSSCommitment()
+	assert.Error(t, err)
+}
+
+func TestE2EConcurrentGoroutineBudget(t *testing.T) {
+	setUp("info")
+
+	threshold := 2
+	fixtures, pIDs, err := LoadKeygenTestFixtures(5)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	// a tight budget shared by all of the parties
+	const maxGoroutines = 2
+	limiter, err := common.NewGoroutineLimiter(maxGoroutines)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), threshold)
+		params.SetGoroutineLimiter(limiter)
+		P := NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	assert.Equal(t, maxGoroutines, parties[0].params.MaxGoroutines())
+	assert.LessOrEqual(t, limiter.Peak(), maxGoroutines, "the parties should never exceed their goroutine budget")
+	assert.Less(t, 0, limiter.Peak(), "the parties should do their work within the budget")
+	for _, save := range saves[1:] {
+		assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub))
+	}
+}
+
+func TestE2EConcurrentCustomGroup(t *testing.T) {
+	setUp("info")
+
+	threshold := 1
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	group := &countingGroup{curve: btcec.S256()}
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), threshold)
+		params.SetGroup(group)
+		P := NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	assert.NotZero(t, atomic.LoadInt64(&group.calls), "the curve operations should be done by the group")
+	for _, save := range saves[1:] {
+		assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub))
+	}
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		assert.True(t, crypto.ScalarBaseMult(btcec.S256(), save.Xi).Equals(save.BigXj[index]), "ensure BigX_j == g^x_j")
+	}
+}
+
+// countingGroup is a Group that implements a standard curve and counts the operations done with it
+type countingGroup struct {
+	curve elliptic.Curve
+	calls int64
+}
+
+func (g *countingGroup) Order() *big.Int            { return g.curve.Params().N }
+func (g *countingGroup) Cofactor() *big.Int         { return big.NewInt(1) }
+func (g *countingGroup) Generator() (x, y *big.Int) { return g.curve.Params().Gx, g.curve.Params().Gy }
+func (g *countingGroup) FieldOrder() *big.Int       { return g.curve.Params().P }
+
+func (g *countingGroup) IsOnCurve(x, y *big.Int) bool {
+	atomic.AddInt64(&g.calls, 1)
+	return g.curve.IsOnCurve(x, y)
+}
+
+func (g *countingGroup) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	atomic.AddInt64(&g.calls, 1)
+	return g.curve.Add(x1, y1, x2, y2)
+}
+
+func (g *countingGroup) ScalarMult(x, y *big.Int, k []byte) (kx, ky *big.Int) {
+	atomic.AddInt64(&g.calls, 1)
+	return g.curve.ScalarMult(x, y, k)
+}
+
+func (g *countingGroup) ScalarBaseMult(k []byte) (kx, ky *big.Int) {
+	atomic.AddInt64(&g.calls, 1)
+	return g.curve.ScalarBaseMult(k)
+}
+
+func TestE2EConcurrentMetadata(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	for i, pID := range pIDs {
+		pID.Metadata = map[string]string{"region": fmt.Sprintf("region-%d", i), "endpoint": fmt.Sprintf("10.0.0.%d:8080", i)}
+	}
+	defer func() {
+		for _, pID := range pIDs {
+			pID.Metadata = nil
+		}
+	}()
+
+	t.Run("persisted", func(t *testing.T) {
+		p2pCtx := tss.NewPeerContext(pIDs)
+		params := make([]*tss.Parameters, len(pIDs))
+		for i := range pIDs {
+			params[i] = tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		}
+		saves, err := runKeygen(params, fixtures)
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, save := range saves {
+			bz, err := json.Marshal(&save)
+			assert.NoError(t, err)
+			var loaded LocalPartySaveData
+			assert.NoError(t, json.Unmarshal(bz, &loaded))
+			for j, pID := range pIDs {
+				assert.Equal(t, pID.Metadata, loaded.Metadata[j])
+				metadata, ok := loaded.PartyMetadata(pID.KeyInt())
+				assert.True(t, ok)
+				assert.Equal(t, pID.Metadata, metadata)
+			}
+		}
+	})
+
+	t.Run("only its own", func(t *testing.T) {
+		// party 1 sends metadata other than what the others registered it with
+		liar := tss.NewPartyID(pIDs[1].Id, pIDs[1].Moniker, pIDs[1].KeyInt())
+		liar.Index, liar.Metadata = pIDs[1].Index, map[string]string{"region": "region-0", "endpoint": "10.0.0.0:8080"}
+		liarIDs := append(tss.SortedPartyIDs{}, pIDs...)
+		liarIDs[1] = liar
+
+		params := make([]*tss.Parameters, len(pIDs))
+		for i := range pIDs {
+			if i == 1 {
+				params[i] = tss.NewParameters(tss.NewPeerContext(liarIDs), liar, len(pIDs), 1)
+				continue
+			}
+			params[i] = tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[i], len(pIDs), 1)
+		}
+		_, err := runKeygen(params, fixtures)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, 2, err.Round())
+			if assert.Len(t, err.Culprits(), 1) {
+				assert.Equal(t, 1, err.Culprits()[0].Index)
+			}
+		}
+	})
+
+	// the others registered party 1 with the metadata `registered`, party 1 has its own metadata and the relay
+	// rewrites the metadata of its round 1 message to `relayed`
+	runWithParty1Metadata := func(registered, relayed map[string]string) ([]LocalPartySaveData, *tss.Error) {
+		other := tss.NewPartyID(pIDs[1].Id, pIDs[1].Moniker, pIDs[1].KeyInt())
+		other.Index, other.Metadata = pIDs[1].Index, registered
+		otherIDs := append(tss.SortedPartyIDs{}, pIDs...)
+		otherIDs[1] = other
+
+		params := make([]*tss.Parameters, len(pIDs))
+		for i := range pIDs {
+			if i == 1 {
+				params[i] = tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[i], len(pIDs), 1)
+				continue
+			}
+			params[i] = tss.NewParameters(tss.NewPeerContext(otherIDs), otherIDs[i], len(pIDs), 1)
+		}
+		relay := func(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+			if party.PartyID() == msg.GetFrom() {
+				return
+			}
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				errCh <- party.WrapError(err)
+				return
+			}
+			pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+			if err != nil {
+				errCh <- party.WrapError(err)
+				return
+			}
+			if r1msg, ok := pMsg.Content().(*KGRound1Message); ok && msg.GetFrom().Index == 1 {
+				r1msg.Metadata = relayed
+			}
+			if _, err := party.Update(pMsg); err != nil {
+				errCh <- err
+			}
+		}
+		return runKeygen(params, fixtures, relay)
+	}
+
+	t.Run("registered without metadata", func(t *testing.T) {
+		// party 1 declares its own metadata, which the others were not configured with
+		keys, err := runWithParty1Metadata(nil, pIDs[1].Metadata)
+		if assert.Nil(t, err) {
+			for _, key := range keys {
+				metadata, found := key.PartyMetadata(pIDs[1].KeyInt())
+				assert.True(t, found)
+				assert.Equal(t, pIDs[1].Metadata, metadata, "the metadata that party 1 declared should be saved")
+			}
+		}
+	})
+
+	t.Run("registered with other metadata", func(t *testing.T) {
+		// party 1 sends metadata other than what the others were configured with
+		_, err := runWithParty1Metadata(map[string]string{"region": "region-0"}, pIDs[1].Metadata)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, 2, err.Round())
+			if assert.Len(t, err.Culprits(), 1) {
+				assert.Equal(t, 1, err.Culprits()[0].Index)
+			}
+		}
+	})
+
+	t.Run("bound to the commitment", func(t *testing.T) {
+		// the relay swaps party 1's metadata for the metadata the others registered it with, which passes round 2 but
+		// not the de-commitment of round 3
+		rewritten := map[string]string{"region": "region-0", "endpoint": "10.0.0.0:8080"}
+		_, err := runWithParty1Metadata(rewritten, rewritten)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, 3, err.Round())
+			if assert.Len(t, err.Culprits(), 1) {
+				assert.Equal(t, 1, err.Culprits()[0].Index)
+			}
+		}
+	})
+}
+
+func runKeygen(params []*tss.Parameters, fixtures []LocalPartySaveData, optionalUpdater ...func(tss.Party, tss.Message, chan<- *tss.Error)) ([]LocalPartySaveData, *tss.Error) {
+	parties := make([]*LocalParty, 0, len(params))
+	errCh := make(chan *tss.Error, len(params))
+	outCh := make(chan tss.Message, len(params))
+	endCh := make(chan LocalPartySaveData, len(params))
+
+	updater := test.SharedPartyUpdater
+	if 0 < len(optionalUpdater) {
+		updater = optionalUpdater[0]
+	}
+
+	for i := range params {
+		P := NewLocalParty(params[i], outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]LocalPartySaveData, 0, len(params))
+	for {
+		select {
+		case err := <-errCh:
+			return nil, err
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(params) {
+				return saves, nil
+			}
+		}
+	}
+}
+
+func TestBytePartyProcessesWhileStarting(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	params := tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	// unbuffered, so that the party blocks in Start until its message is read
+	outCh := make(chan tss.Message)
+	P := tss.NewByteParty(NewLocalParty(params, outCh, make(chan LocalPartySaveData, 1), fixtures[0].LocalPreParams), pIDs, outCh)
+	started := make(chan error, 1)
+	go func() {
+		_, err := P.Start()
+		started <- err
+	}()
+
+	// a message that fails its checks is rejected while the party is still starting
+	invalid := tss.NewMessageWrapper(tss.MessageRouting{From: pIDs[1], IsBroadcast: true}, &KGRound1Message{})
+	bz, err := proto.Marshal(invalid)
+	if !assert.NoError(t, err) {
+		return
+	}
+	processed := make(chan error, 1)
+	go func() {
+		_, err := P.ProcessBytes(bz)
+		processed <- err
+	}()
+	select {
+	case err := <-processed:
+		assert.Error(t, err)
+	case <-time.After(time.Minute):
+		assert.FailNow(t, "ProcessBytes waited for Start")
+	}
+
+	<-outCh
+	assert.NoError(t, <-started)
+}
+
+// taggedCodec is protobuf behind a tag byte, which a party on the default codec cannot parse
+type taggedCodec struct{}
+
+const codecTag = 0x7a
+
+func (taggedCodec) Name() string {
+	return "tagged-protobuf"
+}
+
+func (taggedCodec) Marshal(msg proto.Message) ([]byte, error) {
+	bz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codecTag}, bz...), nil
+}
+
+func (taggedCodec) Unmarshal(bz []byte, into proto.Message) error {
+	if len(bz) == 0 || bz[0] != codecTag {
+		return errors.New("the bytes do not start with the codec's tag")
+	}
+	return proto.Unmarshal(bz[1:], into)
+}
+
+func TestE2EByteParty(t *testing.T) {
+	t.Run("default codec", func(t *testing.T) {
+		runE2EByteParty(t, nil)
+	})
+	t.Run("another codec", func(t *testing.T) {
+		runE2EByteParty(t, taggedCodec{})
+	})
+}
+
+// runE2EByteParty runs keygen with every party wrapped in a ByteParty, with the session's codec set to `codec` unless
+// it is nil
+func runE2EByteParty(t *testing.T, codec tss.Codec) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	parties := make(map[string]*tss.ByteParty, len(pIDs))
+	endChs := make([]chan LocalPartySaveData, len(pIDs))
+	var pending [][]byte
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		if codec != nil {
+			params.SetCodec(codec)
+		}
+		outCh := make(chan tss.Message, 2*len(pIDs))
+		endChs[i] = make(chan LocalPartySaveData, 1)
+		P := tss.NewByteParty(NewLocalParty(params, outCh, endChs[i], fixtures[i].LocalPreParams), pIDs, outCh)
+		parties[pIDs[i].Id] = P
+		out, err := P.Start()
+		if !assert.NoError(t, err) {
+			return
+		}
+		pending = append(pending, out...)
+	}
+
+	if codec != nil {
+		// the content is encoded with the codec, so a party on the default codec cannot read it
+		wire := new(tss.MessageWrapper)
+		assert.Error(t, proto.Unmarshal(pending[0], wire), "the content should not be plain protobuf")
+	}
+
+	// deliver the messages one at a time, each to its recipients, until the parties stop sending
+	for len(pending) > 0 {
+		bz := pending[0]
+		pending = pending[1:]
+		from, to, isBroadcast, err := tss.ParseWireRouting(bz)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if isBroadcast {
+			to = nil
+			for _, pID := range pIDs {
+				if pID.Id != from.Id {
+					to = append(to, pID.MessageWrapper_PartyID)
+				}
+			}
+		}
+		for _, dest := range to {
+			out, err := parties[dest.Id].ProcessBytes(bz)
+			if !assert.NoError(t, err) {
+				return
+			}
+			pending = append(pending, out...)
+		}
+	}
+
+	saves := make([]LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		select {
+		case saves[i] = <-endChs[i]:
+		default:
+			assert.FailNow(t, "the party should have finished", "party %d", i)
+		}
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), saves[i].Xi).Equals(saves[i].BigXj[i]), "ensure BigX_j == g^x_j")
+	}
+	assert.True(t, saves[0].ECDSAPub.Equals(saves[1].ECDSAPub))
+
+	_, err = parties[pIDs[0].Id].ProcessBytes([]byte{0xff})
+	assert.Error(t, err, "malformed bytes should be rejected")
+}
+
+func TestE2EBytePartyWithPlainParties(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	// party 0 is wrapped in a ByteParty and the others take their messages with UpdateFromBytes, all on a codec other
+	// than the default
+	var byteParty *tss.ByteParty
+	plain := make(map[string]tss.Party, len(pIDs)-1)
+	outChs := make(map[string]chan tss.Message, len(pIDs)-1)
+	endChs := make([]chan LocalPartySaveData, len(pIDs))
+	var pending [][]byte
+	// collect serializes the messages the plain parties have sent so far as a ByteParty would
+	collect := func() bool {
+		for _, outCh := range outChs {
+			for len(outCh) > 0 {
+				bz, err := tss.WireEnvelope(<-outCh)
+				if !assert.NoError(t, err) {
+					return false
+				}
+				pending = append(pending, bz)
+			}
+		}
+		return true
+	}
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		params.SetCodec(taggedCodec{})
+		outCh := make(chan tss.Message, 2*len(pIDs))
+		endChs[i] = make(chan LocalPartySaveData, 1)
+		P := NewLocalParty(params, outCh, endChs[i], fixtures[i].LocalPreParams)
+		if i == 0 {
+			byteParty = tss.NewByteParty(P, pIDs, outCh)
+			out, err := byteParty.Start()
+			if !assert.NoError(t, err) {
+				return
+			}
+			pending = append(pending, out...)
+			continue
+		}
+		plain[pIDs[i].Id], outChs[pIDs[i].Id] = P, outCh
+		if err := P.Start(); !assert.Nil(t, err) {
+			return
+		}
+	}
+	if !collect() {
+		return
+	}
+
+	for len(pending) > 0 {
+		bz := pending[0]
+		pending = pending[1:]
+		from, to, isBroadcast, err := tss.ParseWireRouting(bz)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if isBroadcast {
+			to = nil
+			for _, pID := range pIDs {
+				if pID.Id != from.Id {
+					to = append(to, pID.MessageWrapper_PartyID)
+				}
+			}
+		}
+		for _, dest := range to {
+			if dest.Id == pIDs[0].Id {
+				out, err := byteParty.ProcessBytes(bz)
+				if !assert.NoError(t, err) {
+					return
+				}
+				pending = append(pending, out...)
+				continue
+			}
+			wireBytes, err := tss.ParseWireContent(bz)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if _, err := plain[dest.Id].UpdateFromBytes(wireBytes, pIDs.FindByKey(from.KeyInt()), isBroadcast); !assert.Nil(t, err) {
+				return
+			}
+			if !collect() {
+				return
+			}
+		}
+	}
+
+	saves := make([]LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		select {
+		case saves[i] = <-endChs[i]:
+		default:
+			assert.FailNow(t, "the party should have finished", "party %d", i)
+		}
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), saves[i].Xi).Equals(saves[i].BigXj[i]), "ensure BigX_j == g^x_j")
+		assert.True(t, saves[i].ECDSAPub.Equals(saves[0].ECDSAPub))
+	}
+}
+
+func TestE2EDurableCrashAtEachRound(t *testing.T) {
+	setUp("info")
+
+	fixtures, pIDs, err := LoadKeygenTestFixtures(3)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	for crashRound := 1; crashRound <= 3; crashRound++ {
+		t.Run(fmt.Sprintf("round %d", crashRound), func(t *testing.T) {
+			runDurableKeygen(t, fixtures, pIDs, crashRound)
+		})
+	}
+}
+
+// runDurableKeygen runs keygen with party 0 over a durable log. Party 0 crashes once it has logged the messages of
+// `crashRound` but before any of them is sent, and is resumed from its log.
+func runDurableKeygen(t *testing.T, fixtures []LocalPartySaveData, pIDs tss.SortedPartyIDs, crashRound int) {
+	threshold := 1
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, 10*len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	// party 0 is driven from this goroutine so that it can be crashed and resumed between any two messages
+	var durableLog bytes.Buffer
+	durableOut := make(chan tss.Message, 10*len(pIDs))
+	params0 := tss.NewParameters(p2pCtx, pIDs[0], len(pIDs), threshold)
+	parties[0] = NewDurableParty(params0, &durableLog, durableOut, endCh, fixtures[0].LocalPreParams)
+	for i := 1; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), threshold)
+		parties[i] = NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(parties[i])
+	}
+	if err := parties[0].Start(); !assert.Nil(t, err) {
+		return
+	}
+
+	deliver := func(msg tss.Message) {
+		dest := msg.GetTo()
+		for _, P := range parties {
+			if P.PartyID().Index == msg.GetFrom().Index || (dest != nil && dest[0].Index != P.PartyID().Index) {
+				continue
+			}
+			if P == parties[0] {
+				if _, err := P.(*DurableParty).Update(msg.(tss.ParsedMessage)); err != nil {
+					errCh <- err
+				}
+				continue
+			}
+			go updater(P, msg, errCh)
+		}
+	}
+
+	crashed := false
+	saves := make([]LocalPartySaveData, 0, len(pIDs))
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			deliver(msg)
+
+		case msg := <-durableOut:
+			if crashed || durableRoundOf(msg) != crashRound {
+				deliver(msg)
+				continue
+			}
+			// crash: the messages of this round are in the log but are never sent, and the last record is torn
+			crashed = true
+			durableLog.WriteString(`{"Received":{"From":1,"WireB`)
+			logged := bytes.NewReader(append([]byte{}, durableLog.Bytes()...))
+			durableOut = make(chan tss.Message, 10*len(pIDs))
+			P, err := ResumeDurableParty(params0, logged, &durableLog, durableOut, endCh)
+			if !assert.NoError(t, err) {
+				return
+			}
+			parties[0] = P
+			if err := P.Start(); !assert.Nil(t, err) {
+				return
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+			if len(saves) == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	assert.True(t, crashed, "party 0 should have crashed")
+	for _, save := range saves[1:] {
+		assert.True(t, save.ECDSAPub.Equals(saves[0].ECDSAPub))
+	}
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoError(t, err)
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), save.Xi).Equals(save.BigXj[index]), "ensure BigX_j == g^x_j")
+	}
+}
+
+func durableRoundOf(msg tss.Message) int {
+	switch msg.(tss.ParsedMessage).Content().(type) {
+	case *KGRound1Message:
+		return 1
+	case *KGRound2Message1, *KGRound2Message2:
+		return 2
+	case *KGRound3Message:
+		return 3
+	}
+	return 0
+}
+
+func BenchmarkKGRound2Message2Size(b *testing.B) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	ids := make([]*big.Int, len(pIDs))
+	for i, pID := range pIDs {
+		ids[i] = pID.KeyInt()
+	}
+	vs, _, err := vss.Create(tss.EC(), testThreshold, common.GetRandomPositiveInt(tss.EC().Params().N), ids)
+	if err != nil {
+		b.Fatal(err)
+	}
+	flatVs, _ := crypto.FlattenECPoints(vs)
+	cmt := cmts.NewHashCommitment(flatVs...)
+
+	b.Run("uncompressed", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			msg := NewKGRound2Message2(pIDs[0], cmt.D)
+			size = proto.Size(msg.Content().(*KGRound2Message2))
+		}
+		b.ReportMetric(float64(size), "bytes/msg")
+	})
+	b.Run("compressed", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			msg, err := NewKGRound2Message2Compressed(tss.EC(), pIDs[0], cmt.D)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = proto.Size(msg.Content().(*KGRound2Message2))
+		}
+		b.ReportMetric(float64(size), "bytes/msg")
+	})
+}
+
 func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	fixtureFileName := makeTestFixtureFilePath(index)
 
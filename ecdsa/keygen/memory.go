@@ -0,0 +1,77 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// EstimateMemory estimates the peak heap memory in bytes that a keygen party with these parameters holds, so that a
+// scheduler can admit a session only if it fits. It builds the structures of the session from values of the size that
+// they have for the curve and the Paillier modulus, and counts them with common.MemSize: the party's pre-parameters,
+// polynomial and shares, and for each peer its messages, held both as received and decoded, and its part of the save
+// data. It grows linearly with the committee size. It does not count the search for safe primes, which should be run
+// in advance with GeneratePreParams. The estimate is not a bound: garbage that is not yet collected comes on top of it.
+func EstimateMemory(params *tss.Parameters) int {
+	n, t, ec := params.PartyCount(), params.Threshold(), params.EC()
+	pBits := paillierModulusBitLen(ec)
+	N, prime := common.IntOfBitLen(pBits), common.IntOfBitLen(pBits/2)
+	q, hash := common.IntOfBitLen(ec.Params().N.BitLen()), common.IntOfBitLen(cmt.HashLength)
+	coord := common.IntOfBitLen(ec.Params().BitSize)
+	point := crypto.NewECPointNoCurveCheck(ec, coord, coord)
+
+	paillierPK := &paillier.PublicKey{N: N}
+	preParams := LocalPreParams{
+		PaillierSK: &paillier.PrivateKey{PublicKey: *paillierPK, LambdaN: N, PhiN: N},
+		NTildei:    N, H1i: N, H2i: N,
+		Alpha: N, Beta: N,
+		P: prime, Q: prime,
+	}
+	dlnProof := new(dlnp.Proof)
+	for i := range dlnProof.Alpha {
+		dlnProof.Alpha[i], dlnProof.T[i] = N, N
+	}
+	var paillierProof paillier.Proof
+	for i := range paillierProof {
+		paillierProof[i] = N
+	}
+	vs := make(vss.Vs, t+1)
+	for i := range vs {
+		vs[i] = point
+	}
+	share := &vss.Share{Threshold: t, ID: q, Share: q}
+	deCommitment := make(cmt.HashDeCommitment, 1+2*len(vs))
+	deCommitment[0] = hash
+	for i := 1; i < len(deCommitment); i++ {
+		deCommitment[i] = coord
+	}
+
+	// the messages of a peer; the templates are complete, so that marshalling them does not fail
+	from := params.PartyID()
+	r1msg, _ := NewKGRound1Message(from, hash, paillierPK, N, N, N, dlnProof, dlnProof)
+	messages := common.MemSize(
+		r1msg,
+		NewKGRound2Message1(from, from, share),
+		NewKGRound2Message2(from, deCommitment),
+		NewKGRound3Message(from, paillierProof),
+	)
+	// and the same messages decoded
+	decoded := common.MemSize(hash, paillierPK, N, N, N, dlnProof, dlnProof, q, deCommitment, paillierProof)
+	// the peer's part of the save data: its index, ring-Pedersen parameters, public share, Paillier key and VSS
+	// commitments
+	saved := common.MemSize(q, N, N, N, point, paillierPK, vs)
+
+	// the polynomial, its VSS commitments and a share for each party
+	own := common.MemSize(preParams, vs) + len(vs)*common.MemSize(q) + n*common.MemSize(share)
+	return own + n*(messages+decoded+saved)
+}
@@ -0,0 +1,110 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestEstimateMemoryGrowsWithCommittee(t *testing.T) {
+	last := 0
+	for n := 2; n <= 10; n++ {
+		pIDs := tss.GenerateTestPartyIDs(n)
+		estimate := EstimateMemory(tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[0], n, n/2))
+		assert.Greater(t, estimate, last)
+		last = estimate
+	}
+}
+
+func TestEstimateMemoryMatchesSession(t *testing.T) {
+	setUp("info")
+
+	threshold := 2
+	fixtures, pIDs, err := LoadKeygenTestFixtures(5)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), threshold)
+		parties = append(parties, NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*LocalParty))
+	}
+
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	ended := 0
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break keygen
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case <-endCh:
+			if ended++; ended == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+	// count what the first party holds at the end of the session: its save data, polynomial and shares, and the
+	// messages of its peers, both as received and decoded
+	P := parties[0]
+	measured := common.MemSize(P.data, P.temp.vs, P.temp.shares)
+	for j := range pIDs {
+		for _, msg := range []tss.ParsedMessage{
+			P.temp.kgRound1Messages[j], P.temp.kgRound2Message1s[j], P.temp.kgRound2Message2s[j], P.temp.kgRound3Messages[j],
+		} {
+			if msg != nil {
+				measured += common.MemSize(msg)
+			}
+		}
+		r1msg := P.temp.kgRound1Messages[j].Content().(*KGRound1Message)
+		dlnProof1, err := r1msg.UnmarshalDLNProof1()
+		assert.NoError(t, err)
+		dlnProof2, err := r1msg.UnmarshalDLNProof2()
+		assert.NoError(t, err)
+		measured += common.MemSize(r1msg.UnmarshalCommitment(), r1msg.UnmarshalPaillierPK(), r1msg.UnmarshalNTilde(),
+			r1msg.UnmarshalH1(), r1msg.UnmarshalH2(), dlnProof1, dlnProof2,
+			P.temp.kgRound2Message2s[j].Content().(*KGRound2Message2).UnmarshalDeCommitment(P.params.EC()),
+			P.temp.kgRound3Messages[j].Content().(*KGRound3Message).UnmarshalProofInts())
+		if r2msg1 := P.temp.kgRound2Message1s[j]; r2msg1 != nil {
+			measured += common.MemSize(r2msg1.Content().(*KGRound2Message1).UnmarshalShare())
+		}
+	}
+	estimate := EstimateMemory(P.params)
+	t.Logf("estimated %d bytes, measured %d bytes", estimate, measured)
+	assert.LessOrEqual(t, measured, estimate, "the estimate should cover what the party holds")
+	assert.LessOrEqual(t, estimate-measured, measured/20, "the estimate should be within 5% of what the party holds")
+}
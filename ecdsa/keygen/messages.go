@@ -7,9 +7,14 @@
 package keygen
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
 	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
 	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
 	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
 	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
@@ -29,6 +34,17 @@ var (
 	}
 )
 
+// ExpectedMessages returns the messages that a party receives from each of the other parties in a keygen, to check
+// a stored stream of them with tss.ValidateMessageStream
+func ExpectedMessages() []tss.ExpectedMessage {
+	return []tss.ExpectedMessage{
+		{Round: 1, Type: proto.MessageName(&KGRound1Message{}), Broadcast: true},
+		{Round: 2, Type: proto.MessageName(&KGRound2Message1{}), Broadcast: false},
+		{Round: 2, Type: proto.MessageName(&KGRound2Message2{}), Broadcast: true},
+		{Round: 3, Type: proto.MessageName(&KGRound3Message{}), Broadcast: true},
+	}
+}
+
 // ----- //
 
 func NewKGRound1Message(
@@ -58,6 +74,7 @@ func NewKGRound1Message(
 		H2:         h2I.Bytes(),
 		Dlnproof_1: dlnProof1Bz,
 		Dlnproof_2: dlnProof2Bz,
+		Metadata:   from.GetMetadata(),
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg), nil
@@ -148,14 +165,67 @@ func NewKGRound2Message2(
 	return tss.NewMessage(meta, content, msg)
 }
 
+// NewKGRound2Message2Compressed is like NewKGRound2Message2 but sends the committed points in compressed form.
+// The de-commitment is expected to be a salt followed by the flattened points, as produced in round 1.
+func NewKGRound2Message2Compressed(
+	ec elliptic.Curve,
+	from *tss.PartyID,
+	deCommitment cmt.HashDeCommitment,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	if len(deCommitment) < 1 {
+		return nil, errors.New("NewKGRound2Message2Compressed() received an empty de-commitment")
+	}
+	polyG, err := crypto.UnFlattenECPoints(ec, deCommitment[1:])
+	if err != nil {
+		return nil, err
+	}
+	polyGBzs := make([][]byte, len(polyG))
+	for i, point := range polyG {
+		polyGBzs[i] = point.BytesCompressed()
+	}
+	content := &KGRound2Message2{
+		DeCommitment:    common.BigIntsToBytes(deCommitment[:1]),
+		CompressedPolyG: polyGBzs,
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
 func (m *KGRound2Message2) ValidateBasic() bool {
-	return m != nil &&
-		common.NonEmptyMultiBytes(m.GetDeCommitment())
+	if m == nil || !common.NonEmptyMultiBytes(m.GetDeCommitment()) {
+		return false
+	}
+	if len(m.GetCompressedPolyG()) == 0 {
+		return true
+	}
+	return len(m.GetDeCommitment()) == 1 &&
+		common.NonEmptyMultiBytes(m.GetCompressedPolyG())
 }
 
-func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
+// UnmarshalDeCommitment returns the de-commitment in its flat form, decompressing the points if they were sent compressed.
+// It returns nil if a compressed point could not be decoded, which will fail the de-commitment.
+func (m *KGRound2Message2) UnmarshalDeCommitment(ec elliptic.Curve) []*big.Int {
 	deComBzs := m.GetDeCommitment()
-	return cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	if len(m.GetCompressedPolyG()) == 0 {
+		return cmt.NewHashDeCommitmentFromBytes(deComBzs)
+	}
+	polyG := make([]*crypto.ECPoint, len(m.GetCompressedPolyG()))
+	for i, bz := range m.GetCompressedPolyG() {
+		point, err := crypto.NewECPointFromBytesCompressed(ec, bz)
+		if err != nil {
+			return nil
+		}
+		polyG[i] = point
+	}
+	flatPolyG, err := crypto.FlattenECPoints(polyG)
+	if err != nil {
+		return nil
+	}
+	return append(cmt.NewHashDeCommitmentFromBytes(deComBzs), flatPolyG...)
 }
 
 // ----- //
@@ -7,18 +7,25 @@
 package keygen
 
 import (
+	"context"
+	"crypto/elliptic"
 	"errors"
+	"fmt"
 	"math/big"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
 const (
-	// Using a modulus length of 2048 is recommended in the GG18 spec
+	// Using a modulus length of 2048 is recommended in the GG18 spec; curves with a larger order need a longer one
 	paillierModulusLen = 2048
+	// The longest Paillier modulus that keygen accepts from a peer, unless the curve needs a longer one
+	maxPaillierModulusLen = 4096
 	// Two 1024-bit safe primes to produce NTilde
 	safePrimeBitLen = 1024
 	// Ticker for printing log statements while generating primes/modulus
@@ -28,7 +35,49 @@ const (
 // GeneratePreParams finds two safe primes and computes the Paillier secret required for the protocol.
 // This can be a time consuming process so it is recommended to do it out-of-band.
 // If not specified, a concurrency value equal to the number of available CPU cores will be used.
+// The Paillier modulus is long enough for the default curve set with tss.SetCurve.
 func GeneratePreParams(timeout time.Duration, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return GeneratePreParamsWithLimiter(timeout, nil, optionalConcurrency...)
+}
+
+// GeneratePreParamsWithLimiter is GeneratePreParams within the budget of a goroutine limiter. With a limiter, the
+// Paillier modulus and the safe primes are generated one after the other rather than at the same time.
+func GeneratePreParamsWithLimiter(timeout time.Duration, limiter *common.GoroutineLimiter, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return generatePreParams(context.Background(), timeout, limiter, paillierModulusBitLen(tss.EC()), optionalConcurrency...)
+}
+
+// GeneratePreParamsForCurve is GeneratePreParams with a Paillier modulus long enough for the curve `ec` rather than
+// for the default curve, for a process that runs keygen sessions over several curves set with Parameters.SetCurve.
+func GeneratePreParamsForCurve(ec elliptic.Curve, timeout time.Duration, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return generatePreParams(context.Background(), timeout, nil, paillierModulusBitLen(ec), optionalConcurrency...)
+}
+
+// GeneratePreParamsWithContext is GeneratePreParamsWithLimiter that stops once `ctx` is done, e.g. when a coordinator
+// aborts the keygen session, returning the error of the context after the searches for the primes have stopped. The
+// search also ends at the deadline of `ctx` if that comes before `timeout`. The limiter may be nil.
+func GeneratePreParamsWithContext(ctx context.Context, timeout time.Duration, limiter *common.GoroutineLimiter, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return generatePreParams(ctx, timeout, limiter, paillierModulusBitLen(tss.EC()), optionalConcurrency...)
+}
+
+// GeneratePreParamsWithModulusBits is GeneratePreParams with a Paillier modulus of `paillierModulusBits` bits, in the
+// range given by PaillierModulusBitsRange for the curve set with tss.SetCurve, rather than the default of 2048. A shorter
+// modulus is quicker to generate and to compute with, and a longer one gives a long-lived key a larger security margin.
+// The MtA proofs take their bounds from the modulus, so they hold for any length in the range.
+func GeneratePreParamsWithModulusBits(timeout time.Duration, paillierModulusBits int, optionalConcurrency ...int) (*LocalPreParams, error) {
+	if err := checkPaillierModulusBits(tss.EC(), paillierModulusBits); err != nil {
+		return nil, err
+	}
+	return generatePreParams(context.Background(), timeout, nil, paillierModulusBits, optionalConcurrency...)
+}
+
+// generatePreParams stops generating once `ctx` is done, returning its error
+func generatePreParams(ctx context.Context, timeout time.Duration, limiter *common.GoroutineLimiter, modulusBitLen int, optionalConcurrency ...int) (*LocalPreParams, error) {
+	if timeout <= 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("GeneratePreParams: the timeout must be positive but is %s", timeout)
+	}
 	var concurrency int
 	if 0 < len(optionalConcurrency) {
 		if 1 < len(optionalConcurrency) {
@@ -46,33 +95,48 @@ func GeneratePreParams(timeout time.Duration, optionalConcurrency ...int) (*Loca
 	paiCh := make(chan *paillier.PrivateKey, 1)
 	sgpCh := make(chan []*common.GermainSafePrime, 1)
 
+	// the searches of a cancelled generation are waited for, so that none outlives it
+	searches := &sync.WaitGroup{}
+	searches.Add(2)
+
 	// 4. generate Paillier public key E_i, private key and proof
-	go func(ch chan<- *paillier.PrivateKey) {
+	generatePaillier := func() {
+		defer searches.Done()
 		common.Logger.Info("generating the Paillier modulus, please wait...")
 		start := time.Now()
 		// more concurrency weight is assigned here because the paillier primes have a requirement of having "large" P-Q
-		PiPaillierSk, _, err := paillier.GenerateKeyPair(paillierModulusLen, timeout, concurrency*2)
+		PiPaillierSk, _, err := paillier.GenerateKeyPairWithContext(ctx, modulusBitLen, timeout, limiter, concurrency*2)
 		if err != nil {
-			ch <- nil
+			paiCh <- nil
 			return
 		}
 		common.Logger.Infof("paillier modulus generated. took %s\n", time.Since(start))
-		ch <- PiPaillierSk
-	}(paiCh)
+		paiCh <- PiPaillierSk
+	}
 
 	// 5-7. generate safe primes for ZKPs used later on
-	go func(ch chan<- []*common.GermainSafePrime) {
+	generateSafePrimes := func() {
+		defer searches.Done()
 		var err error
 		common.Logger.Info("generating the safe primes for the signing proofs, please wait...")
 		start := time.Now()
-		sgps, err := common.GetRandomSafePrimesConcurrent(safePrimeBitLen, 2, timeout, concurrency)
+		sgps, err := common.GetRandomSafePrimesConcurrentWithContext(ctx, safePrimeBitLen, 2, timeout, concurrency, limiter)
 		if err != nil {
-			ch <- nil
+			sgpCh <- nil
 			return
 		}
 		common.Logger.Infof("safe primes generated. took %s\n", time.Since(start))
-		ch <- sgps
-	}(sgpCh)
+		sgpCh <- sgps
+	}
+
+	if limiter == nil {
+		go generatePaillier()
+		go generateSafePrimes()
+	} else {
+		// the searches take the places of the budget themselves; running them here does not hold one while they wait
+		generatePaillier()
+		generateSafePrimes()
+	}
 
 	// this ticker will print a log statement while the generating is still in progress
 	logProgressTicker := time.NewTicker(logProgressTickInterval)
@@ -85,11 +149,20 @@ consumer:
 		select {
 		case <-logProgressTicker.C:
 			common.Logger.Info("still generating primes...")
+		case <-ctx.Done():
+			// the searches stop on their own and send to the buffered channels
+			logProgressTicker.Stop()
+			searches.Wait()
+			return nil, ctx.Err()
 		case sgps = <-sgpCh:
 			if sgps == nil ||
 				sgps[0] == nil || sgps[1] == nil ||
 				!sgps[0].Prime().ProbablyPrime(30) || !sgps[1].Prime().ProbablyPrime(30) ||
 				!sgps[0].SafePrime().ProbablyPrime(30) || !sgps[1].SafePrime().ProbablyPrime(30) {
+				if err := ctx.Err(); err != nil {
+					searches.Wait()
+					return nil, err
+				}
 				return nil, errors.New("timeout or error while generating the safe primes")
 			}
 			if paiSK != nil {
@@ -97,6 +170,10 @@ consumer:
 			}
 		case paiSK = <-paiCh:
 			if paiSK == nil {
+				if err := ctx.Err(); err != nil {
+					searches.Wait()
+					return nil, err
+				}
 				return nil, errors.New("timeout or error while generating the Paillier secret key")
 			}
 			if sgps != nil {
@@ -106,6 +183,48 @@ consumer:
 	}
 	logProgressTicker.Stop()
 
+	preParams := ringPedersenParams(sgps)
+	preParams.PaillierSK = paiSK
+	return preParams, nil
+}
+
+// paillierModulusBitLen is the length of a Paillier modulus that is long enough for the curve `ec`
+func paillierModulusBitLen(ec elliptic.Curve) int {
+	if minBitLen := paillier.MinPaillierBits(ec); paillierModulusLen < minBitLen {
+		return minBitLen
+	}
+	return paillierModulusLen
+}
+
+// PaillierModulusBitsRange returns the shortest and the longest Paillier modulus, in bits, that keygen accepts from a
+// peer and that GeneratePreParamsWithModulusBits generates for the curve `ec`. The shortest is paillier.MinPaillierBits,
+// e.g. 2048 bits for a 256-bit curve, and the longest is 4096 bits, or the shortest for a curve whose order is longer
+// than 512 bits. The length must be a multiple of 8 bits.
+func PaillierModulusBitsRange(ec elliptic.Curve) (minBits, maxBits int) {
+	minBits = paillier.MinPaillierBits(ec)
+	if minBits < maxPaillierModulusLen {
+		return minBits, maxPaillierModulusLen
+	}
+	return minBits, minBits
+}
+
+// checkPaillierModulusBits returns an error unless `bits` is a length of a Paillier modulus in the range of
+// PaillierModulusBitsRange for the curve `ec`
+func checkPaillierModulusBits(ec elliptic.Curve, bits int) error {
+	minBits, maxBits := PaillierModulusBitsRange(ec)
+	if bits < minBits {
+		return fmt.Errorf("a Paillier modulus of %d bits is too short for a curve with a %d-bit order, which needs %d bits",
+			bits, ec.Params().N.BitLen(), minBits)
+	}
+	if maxBits < bits || bits%8 != 0 {
+		return fmt.Errorf("a Paillier modulus of %d bits is not supported; use a multiple of 8 bits from %d to %d bits",
+			bits, minBits, maxBits)
+	}
+	return nil
+}
+
+// ringPedersenParams computes NTilde, h1, h2 and their trapdoor from two safe primes
+func ringPedersenParams(sgps []*common.GermainSafePrime) *LocalPreParams {
 	P, Q := sgps[0].SafePrime(), sgps[1].SafePrime()
 	NTildei := new(big.Int).Mul(P, Q)
 	modNTildeI := common.ModInt(NTildei)
@@ -118,15 +237,13 @@ consumer:
 	h1i := modNTildeI.Mul(f1, f1)
 	h2i := modNTildeI.Exp(h1i, alpha)
 
-	preParams := &LocalPreParams{
-		PaillierSK: paiSK,
-		NTildei:    NTildei,
-		H1i:        h1i,
-		H2i:        h2i,
-		Alpha:      alpha,
-		Beta:       beta,
-		P:          p,
-		Q:          q,
+	return &LocalPreParams{
+		NTildei: NTildei,
+		H1i:     h1i,
+		H2i:     h2i,
+		Alpha:   alpha,
+		Beta:    beta,
+		P:       p,
+		Q:       q,
 	}
-	return preParams, nil
 }
@@ -0,0 +1,144 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+type (
+	// PaillierRotation is what a party broadcasts to replace its Paillier key and its NTilde, h1, h2 without a reshare:
+	// its new public pre-params with the proofs that keygen asks of them. Its signing share is unchanged.
+	PaillierRotation struct {
+		PublicPreParams
+		// the share ID of the rotating party, which identifies its place in the save data
+		ShareID       *big.Int
+		PaillierProof paillier.Proof
+		// Signature is a Schnorr signature of the rotation under the rotating party's public share, which also covers
+		// the Paillier modulus that the rotation replaces, so that only that party can rotate its keys and a rotation
+		// cannot be applied again once its keys are replaced
+		Signature *zkp.DLogProof
+	}
+)
+
+// RotatePaillierKey replaces the party's Paillier key and NTilde, h1, h2 in its save data with those of fresh
+// pre-params, e.g. after a suspected compromise of the Paillier key. It returns the updated save data and the rotation
+// to broadcast to the other parties, which apply it with ApplyPaillierRotation. The given save data is not modified.
+func RotatePaillierKey(key LocalPartySaveData, partyID *tss.PartyID, preParams LocalPreParams) (LocalPartySaveData, *PaillierRotation, error) {
+	if key.ShareID == nil || key.ECDSAPub == nil {
+		return LocalPartySaveData{}, nil, errors.New("RotatePaillierKey() received incomplete save data")
+	}
+	i, err := key.shareIndex(key.ShareID)
+	if err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+	if key.Xi == nil || len(key.BigXj) <= i || len(key.PaillierPKs) <= i || key.PaillierPKs[i] == nil {
+		return LocalPartySaveData{}, nil, errors.New("RotatePaillierKey() received incomplete save data")
+	}
+	pub, err := preParams.PublicPreParams(partyID)
+	if err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+	if err = checkPublicPreParams(key.ECDSAPub.Curve(), pub.PaillierPK, pub.NTilde, pub.H1, pub.H2); err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+	rotation := &PaillierRotation{
+		PublicPreParams: *pub,
+		ShareID:         key.ShareID,
+		PaillierProof:   preParams.PaillierSK.Proof(key.ShareID, key.ECDSAPub),
+	}
+	if rotation.Signature, err = zkp.NewDLogProofWithMessage(key.ECDSAPub.Curve(), key.Xi, key.BigXj[i], rotation.message(key.PaillierPKs[i].N)); err != nil {
+		return LocalPartySaveData{}, nil, err
+	}
+	rotated := key.withPublicPreParams(i, pub)
+	rotated.LocalPreParams = preParams
+	return rotated, rotation, nil
+}
+
+// ApplyPaillierRotation checks that another party's PaillierRotation is signed by that party for its current Paillier
+// key and checks its proofs as keygen checks those of its round 1 and round 3 messages, and returns the save data with
+// that party's Paillier key and NTilde, h1, h2 replaced. The given save data is not modified.
+func (key LocalPartySaveData) ApplyPaillierRotation(rotation *PaillierRotation) (LocalPartySaveData, error) {
+	if rotation == nil || rotation.ShareID == nil || rotation.PaillierPK == nil || rotation.NTilde == nil ||
+		rotation.H1 == nil || rotation.H2 == nil || rotation.DLNProof1 == nil || rotation.DLNProof2 == nil ||
+		rotation.Signature == nil {
+		return LocalPartySaveData{}, errors.New("the Paillier rotation is incomplete")
+	}
+	if key.ECDSAPub == nil {
+		return LocalPartySaveData{}, errors.New("ApplyPaillierRotation() received incomplete save data")
+	}
+	if key.ShareID != nil && key.ShareID.Cmp(rotation.ShareID) == 0 {
+		return LocalPartySaveData{}, errors.New("the Paillier rotation is for this party; use RotatePaillierKey")
+	}
+	j, err := key.shareIndex(rotation.ShareID)
+	if err != nil {
+		return LocalPartySaveData{}, err
+	}
+	if len(key.BigXj) <= j || key.BigXj[j] == nil || len(key.PaillierPKs) <= j || key.PaillierPKs[j] == nil {
+		return LocalPartySaveData{}, errors.New("ApplyPaillierRotation() received incomplete save data")
+	}
+	ec := key.ECDSAPub.Curve()
+	if !rotation.Signature.VerifyWithMessage(ec, key.BigXj[j], rotation.message(key.PaillierPKs[j].N)) {
+		return LocalPartySaveData{}, errors.New("the Paillier rotation is not signed by the rotating party for its current Paillier key")
+	}
+	if err = checkPublicPreParams(ec, rotation.PaillierPK, rotation.NTilde, rotation.H1, rotation.H2); err != nil {
+		return LocalPartySaveData{}, err
+	}
+	// with a trusted setup every party shares the dealer's h1, h2, as in keygen round 2
+	ts := key.LocalPreParams.TrustedSetup
+	sharedSetup := ts != nil && ts.usedBy(rotation.NTilde, rotation.H1, rotation.H2) && ts.Verify()
+	for k := range key.H1j {
+		if k == j || sharedSetup {
+			continue
+		}
+		for _, h := range []*big.Int{rotation.H1, rotation.H2} {
+			if h.Cmp(key.H1j[k]) == 0 || h.Cmp(key.H2j[k]) == 0 {
+				return LocalPartySaveData{}, errors.New("the rotated h1 or h2 is already used by another party")
+			}
+		}
+	}
+	if !rotation.DLNProof1.Verify(rotation.H1, rotation.H2, rotation.NTilde) ||
+		!rotation.DLNProof2.Verify(rotation.H2, rotation.H1, rotation.NTilde) {
+		return LocalPartySaveData{}, errors.New("dln proof verification failed")
+	}
+	if ok, err := rotation.PaillierProof.Verify(rotation.PaillierPK.N, rotation.ShareID, key.ECDSAPub); err != nil || !ok {
+		return LocalPartySaveData{}, fmt.Errorf("paillier verify failed: %v", err)
+	}
+	return key.withPublicPreParams(j, &rotation.PublicPreParams), nil
+}
+
+// message is what the rotating party signs: the rotation's public values and the Paillier modulus that it replaces
+func (rotation *PaillierRotation) message(previousN *big.Int) *big.Int {
+	return common.SHA512_256i(rotation.ShareID, previousN, rotation.PaillierPK.N, rotation.NTilde, rotation.H1, rotation.H2)
+}
+
+// shareIndex finds the place of a party in the save data by its share ID
+func (key LocalPartySaveData) shareIndex(shareID *big.Int) (int, error) {
+	for j, kj := range key.Ks {
+		if kj != nil && kj.Cmp(shareID) == 0 {
+			return j, nil
+		}
+	}
+	return -1, errors.New("the share ID is not one of this key's parties")
+}
+
+// withPublicPreParams returns a copy of the save data with the public pre-params of the party at index j replaced
+func (key LocalPartySaveData) withPublicPreParams(j int, pub *PublicPreParams) LocalPartySaveData {
+	key.PaillierPKs = append([]*paillier.PublicKey(nil), key.PaillierPKs...)
+	key.NTildej = append([]*big.Int(nil), key.NTildej...)
+	key.H1j = append([]*big.Int(nil), key.H1j...)
+	key.H2j = append([]*big.Int(nil), key.H2j...)
+	key.PaillierPKs[j], key.NTildej[j], key.H1j[j], key.H2j[j] = pub.PaillierPK, pub.NTilde, pub.H1, pub.H2
+	return key
+}
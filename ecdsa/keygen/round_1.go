@@ -8,6 +8,7 @@ package keygen
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -35,18 +36,19 @@ func (round *round1) Start() *tss.Error {
 	round.number = 1
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	Pi := round.PartyID()
 	i := Pi.Index
 
 	// 1. calculate "partial" key share ui
-	ui := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ui := common.GetRandomPositiveInt(round.EC().Params().N)
 
 	round.temp.ui = ui
 
 	// 2. compute the vss shares
 	ids := round.Parties().IDs().Keys()
-	vs, shares, err := vss.Create(round.Threshold(), ui, ids)
+	vs, shares, err := vss.Create(round.EC(), round.Threshold(), ui, ids)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -61,39 +63,51 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
-	cmt := cmts.NewHashCommitment(pGFlat...)
+	// the commitment binds the party's metadata too. The digest of the metadata is left out of the de-commitment sent
+	// in round 2, as the other parties take it from the round 1 message.
+	cmt := cmts.NewHashCommitment(append(pGFlat, metadataDigest(Pi.GetMetadata()))...)
 
 	// 4. generate Paillier public key E_i, private key and proof
 	// 5-7. generate safe primes for ZKPs used later on
 	// 9-11. compute ntilde, h1, h2 (uses safe primes)
 	// use the pre-params if they were provided to the LocalParty constructor
 	var preParams *LocalPreParams
-	if round.save.LocalPreParams.Validate() && !round.save.LocalPreParams.ValidateWithProof() {
+	if round.save.LocalPreParams.ValidateWithTrustedSetup() {
+		if !round.save.LocalPreParams.TrustedSetup.Verify() {
+			return round.WrapError(errors.New("the trusted setup failed to verify"), Pi)
+		}
+		preParams = &round.save.LocalPreParams
+	} else if round.save.LocalPreParams.Validate() && !round.save.LocalPreParams.ValidateWithProof() {
 		return round.WrapError(
 			errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
 	} else {
-		preParams, err = GeneratePreParams(round.SafePrimeGenTimeout(), 3)
+		preParams, err = generatePreParams(round.Context(), round.SafePrimeGenTimeout(), round.GoroutineLimiter(), paillierModulusBitLen(round.EC()), 3)
 		if err != nil {
-			return round.WrapError(errors.New("pre-params generation failed"), Pi)
+			return round.WrapError(fmt.Errorf("pre-params generation failed: %w", err), Pi)
 		}
 	}
 	round.save.LocalPreParams = *preParams
 	round.save.NTildej[i] = preParams.NTildei
 	round.save.H1j[i], round.save.H2j[i] = preParams.H1i, preParams.H2i
 
-	// generate the dlnproofs for keygen
-	h1i, h2i, alpha, beta, p, q, NTildei :=
-		preParams.H1i,
-		preParams.H2i,
-		preParams.Alpha,
-		preParams.Beta,
-		preParams.P,
-		preParams.Q,
-		preParams.NTildei
-	dlnProof1 := dlnp.NewProof(h1i, h2i, alpha, p, q, NTildei)
-	dlnProof2 := dlnp.NewProof(h2i, h1i, beta, p, q, NTildei)
+	// generate the dlnproofs for keygen; with a trusted setup, the dealer's proofs are sent
+	var dlnProof1, dlnProof2 *dlnp.Proof
+	if ts := preParams.TrustedSetup; ts != nil {
+		dlnProof1, dlnProof2 = ts.DLNProof1, ts.DLNProof2
+	} else {
+		h1i, h2i, alpha, beta, p, q, NTildei :=
+			preParams.H1i,
+			preParams.H2i,
+			preParams.Alpha,
+			preParams.Beta,
+			preParams.P,
+			preParams.Q,
+			preParams.NTildei
+		dlnProof1 = dlnp.NewProof(h1i, h2i, alpha, p, q, NTildei)
+		dlnProof2 = dlnp.NewProof(h2i, h1i, beta, p, q, NTildei)
+	}
 
 	// for this P: SAVE
 	// - shareID
@@ -107,7 +121,8 @@ func (round *round1) Start() *tss.Error {
 	// for this P: SAVE de-commitments, paillier keys for round 2
 	round.save.PaillierSK = preParams.PaillierSK
 	round.save.PaillierPKs[i] = &preParams.PaillierSK.PublicKey
-	round.temp.deCommitPolyG = cmt.D
+	round.save.Metadata[i] = Pi.GetMetadata()
+	round.temp.deCommitPolyG = cmt.D[:len(cmt.D)-1]
 
 	// BROADCAST commitments, paillier pk + proof; round 1 message
 	{
@@ -117,7 +132,7 @@ func (round *round1) Start() *tss.Error {
 			return round.WrapError(err, Pi)
 		}
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.out <- round.UseCodec(msg)
 	}
 	return nil
 }
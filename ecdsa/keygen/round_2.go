@@ -10,13 +10,18 @@ import (
 	"encoding/hex"
 	"errors"
 	"math/big"
+	"sort"
 	"sync"
 
+	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
 const (
-	paillierBitsLen = 2048
+	nTildeBitsLen = 2048
+
+	// separates the digest of a party's metadata from the other hashes of the protocol
+	metadataDomain = "tss-lib ecdsa keygen metadata"
 )
 
 func (round *round2) Start() *tss.Error {
@@ -26,6 +31,7 @@ func (round *round2) Start() *tss.Error {
 	round.number = 2
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	i := round.PartyID().Index
 
@@ -42,19 +48,22 @@ func (round *round2) Start() *tss.Error {
 			r1msg.UnmarshalNTilde(),
 			r1msg.UnmarshalPaillierPK()
 
-		if paillierPubKeyj.N.BitLen() != paillierBitsLen {
-			return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), msg.GetFrom())
+		if err := checkPublicPreParams(round.EC(), paillierPubKeyj, NTildej, H1j, H2j); err != nil {
+			return round.WrapError(err, msg.GetFrom())
 		}
-
-		if NTildej.BitLen() != paillierBitsLen {
-			return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), msg.GetFrom())
+		// a party's metadata is only ever taken from its own round 1 message, which round 3 checks that Pj committed to;
+		// where this party was configured with the metadata of Pj, Pj must have sent the same
+		if registered := round.Parties().IDs()[j].GetMetadata(); j != i && 0 < len(registered) &&
+			!metadataEqual(registered, r1msg.GetMetadata()) {
+			return round.WrapError(errors.New("the metadata sent differs from the metadata this party was registered with"), msg.GetFrom())
 		}
-
-		if H1j.Cmp(H2j) == 0 {
-			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom())
-		}
-		// the H1, H2 dupe check is disabled during some benchmarking scenarios to allow reuse of pre-params
-		if !round.Params().UNSAFE_KGIgnoreH1H2Dupes() {
+		// with a trusted setup every party uses the dealer's NTilde, h1, h2 and no other
+		if ts := round.save.LocalPreParams.TrustedSetup; ts != nil {
+			if !ts.usedBy(NTildej, H1j, H2j) {
+				return round.WrapError(errors.New("this party did not use the trusted setup for NTilde"), msg.GetFrom())
+			}
+		} else if !round.Params().UNSAFE_KGIgnoreH1H2Dupes() {
+			// the H1, H2 dupe check is disabled during some benchmarking scenarios to allow reuse of pre-params
 			h1JHex, h2JHex := hex.EncodeToString(H1j.Bytes()), hex.EncodeToString(H2j.Bytes())
 			if _, found := h1H2Map[h1JHex]; found {
 				return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom())
@@ -65,23 +74,30 @@ func (round *round2) Start() *tss.Error {
 			h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 		}
 		wg.Add(2)
-		go func(j int, msg tss.ParsedMessage, r1msg *KGRound1Message, H1j, H2j, NTildej *big.Int) {
-			if dlnProof1, err := r1msg.UnmarshalDLNProof1(); err != nil || !dlnProof1.Verify(H1j, H2j, NTildej) {
+		j, msg := j, msg
+		round.Go(func() {
+			dlnProof1, err := r1msg.UnmarshalDLNProof1()
+			ok := err == nil && dlnProof1.Verify(H1j, H2j, NTildej)
+			round.ObserveProof(tss.ProofDLN, ok)
+			if !ok {
 				dlnProof1FailCulprits[j] = msg.GetFrom()
 			}
 			wg.Done()
-		}(j, msg, r1msg, H1j, H2j, NTildej)
-		go func(j int, msg tss.ParsedMessage, r1msg *KGRound1Message, H1j, H2j, NTildej *big.Int) {
-			if dlnProof2, err := r1msg.UnmarshalDLNProof2(); err != nil || !dlnProof2.Verify(H2j, H1j, NTildej) {
+		})
+		round.Go(func() {
+			dlnProof2, err := r1msg.UnmarshalDLNProof2()
+			ok := err == nil && dlnProof2.Verify(H2j, H1j, NTildej)
+			round.ObserveProof(tss.ProofDLN, ok)
+			if !ok {
 				dlnProof2FailCulprits[j] = msg.GetFrom()
 			}
 			wg.Done()
-		}(j, msg, r1msg, H1j, H2j, NTildej)
+		})
 	}
 	wg.Wait()
 	for _, culprit := range append(dlnProof1FailCulprits, dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("dln proof verification failed")), culprit)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j, ...
@@ -99,6 +115,7 @@ func (round *round2) Start() *tss.Error {
 		round.save.PaillierPKs[j] = paillierPK // used in round 4
 		round.save.NTildej[j] = NTildej
 		round.save.H1j[j], round.save.H2j[j] = H1j, H2j
+		round.save.Metadata[j] = r1msg.GetMetadata()
 		round.temp.KGCs[j] = KGC
 	}
 
@@ -112,13 +129,21 @@ func (round *round2) Start() *tss.Error {
 			continue
 		}
 		round.temp.kgRound2Message1s[i] = r2msg1
-		round.out <- r2msg1
+		round.out <- round.UseCodec(r2msg1)
 	}
 
 	// 7. BROADCAST de-commitments of Shamir poly*G
-	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG)
+	var r2msg2 tss.ParsedMessage
+	if round.Params().CompressKGCommitments() {
+		var err error
+		if r2msg2, err = NewKGRound2Message2Compressed(round.EC(), round.PartyID(), round.temp.deCommitPolyG); err != nil {
+			return round.WrapError(err, round.PartyID())
+		}
+	} else {
+		r2msg2 = NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG)
+	}
 	round.temp.kgRound2Message2s[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.UseCodec(r2msg2)
 
 	return nil
 }
@@ -158,3 +183,32 @@ func (round *round2) NextRound() tss.Round {
 	round.started = false
 	return &round3{round}
 }
+
+// ----- //
+
+func metadataEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataDigest hashes the metadata of a party in the order of its keys, for the commitment of round 1
+func metadataDigest(metadata map[string]string) *big.Int {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	in := make([][]byte, 0, 1+2*len(keys))
+	in = append(in, []byte(metadataDomain))
+	for _, k := range keys {
+		in = append(in, []byte(k), []byte(metadata[k]))
+	}
+	return new(big.Int).SetBytes(common.SHA512_256(in...))
+}
@@ -27,6 +27,7 @@ func (round *round3) Start() *tss.Error {
 	round.number = 3
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	Ps := round.Parties().IDs()
 	PIdx := round.PartyID().Index
@@ -47,25 +48,28 @@ func (round *round3) Start() *tss.Error {
 		if i == PIdx {
 			continue
 		}
-		chs[i] = make(chan vssOut)
+		chs[i] = make(chan vssOut, 1) // buffered, as a worker may finish before all are started
 	}
 	for j := range Ps {
 		if j == PIdx {
 			continue
 		}
 		// 6-8.
-		go func(j int, ch chan<- vssOut) {
+		j, ch := j, chs[j]
+		round.Go(func() {
 			// 4-9.
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
-			KGDj := r2msg2.UnmarshalDeCommitment()
+			// Pj committed to its VSS commitments and to the metadata that it sent in round 1
+			KGDj := append(r2msg2.UnmarshalDeCommitment(round.EC()), metadataDigest(round.save.Metadata[j]))
 			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
 			ok, flatPolyGs := cmtDeCmt.DeCommit()
-			if !ok || flatPolyGs == nil {
+			if !ok || len(flatPolyGs) == 0 {
 				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
 				return
 			}
-			PjVs, err := crypto.UnFlattenECPoints(tss.EC(), flatPolyGs)
+			flatPolyGs = flatPolyGs[:len(flatPolyGs)-1]
+			PjVs, err := crypto.UnFlattenECPoints(round.EC(), flatPolyGs)
 			if err != nil {
 				ch <- vssOut{err, nil}
 				return
@@ -76,17 +80,17 @@ func (round *round3) Start() *tss.Error {
 				ID:        round.PartyID().KeyInt(),
 				Share:     r2msg1.UnmarshalShare(),
 			}
-			if ok = PjShare.Verify(round.Threshold(), PjVs); !ok {
+			if ok = PjShare.Verify(round.EC(), round.Threshold(), PjVs); !ok {
 				ch <- vssOut{errors.New("vss verify failed"), nil}
 				return
 			}
 			// (9) handled above
 			ch <- vssOut{nil, PjVs}
-		}(j, chs[j])
+		})
 	}
 
 	// 1,9. calculate xi (deferred for performance)
-	modQ := common.ModInt(tss.EC().Params().N)
+	modQ := common.ModInt(round.EC().Params().N)
 	xi := new(big.Int).Set(round.temp.shares[PIdx].Share)
 	for j := range Ps {
 		if j == PIdx {
@@ -98,7 +102,7 @@ func (round *round3) Start() *tss.Error {
 	}
 	round.save.Xi = modQ.Add(xi, zero)
 
-	// consume unbuffered channels (end the goroutines)
+	// collect the results of the goroutines
 	vssResults := make([]vssOut, len(Ps))
 	{
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
@@ -143,6 +147,16 @@ func (round *round3) Start() *tss.Error {
 		}
 	}
 
+	// keep the commitment vector of each party, so that the shares can be verified externally
+	round.save.VSSCommitments = make([]vss.Vs, len(Ps))
+	for j := range Ps {
+		if j == PIdx {
+			round.save.VSSCommitments[j] = round.temp.vs
+			continue
+		}
+		round.save.VSSCommitments[j] = vssResults[j].pjVs
+	}
+
 	// 12-16. compute Xj for each Pj
 	{
 		var err error
@@ -169,7 +183,7 @@ func (round *round3) Start() *tss.Error {
 	}
 
 	// 17. compute and SAVE the ECDSA public key `y`
-	ecdsaPubKey, err := crypto.NewECPoint(tss.EC(), Vc[0].X(), Vc[0].Y())
+	ecdsaPubKey, err := crypto.NewECPoint(round.EC(), Vc[0].X(), Vc[0].Y())
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
 	}
@@ -183,7 +197,7 @@ func (round *round3) Start() *tss.Error {
 	proof := round.save.PaillierSK.Proof(ki, ecdsaPubKey)
 	r3msg := NewKGRound3Message(round.PartyID(), proof)
 	round.temp.kgRound3Messages[PIdx] = r3msg
-	round.out <- r3msg
+	round.out <- round.UseCodec(r3msg)
 	return nil
 }
 
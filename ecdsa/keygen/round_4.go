@@ -10,7 +10,6 @@ import (
 	"errors"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
-	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -21,6 +20,7 @@ func (round *round4) Start() *tss.Error {
 	round.number = 4
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	i := round.PartyID().Index
 	Ps := round.Parties().IDs()
@@ -32,26 +32,27 @@ func (round *round4) Start() *tss.Error {
 	r3msgs := round.temp.kgRound3Messages
 	chs := make([]chan bool, len(r3msgs))
 	for i := range chs {
-		chs[i] = make(chan bool)
+		chs[i] = make(chan bool, 1) // buffered, as a worker may finish before all are started
 	}
 	for j, msg := range round.temp.kgRound3Messages {
 		if j == i {
 			continue
 		}
-		r3msg := msg.Content().(*KGRound3Message)
-		go func(prf paillier.Proof, j int, ch chan<- bool) {
+		prf, j, ch := msg.Content().(*KGRound3Message).UnmarshalProofInts(), j, chs[j]
+		round.Go(func() {
 			ppk := round.save.PaillierPKs[j]
-			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub)
+			ok, err := prf.Verify(ppk.N, PIDs[j], ecdsaPub, round.GoroutineLimiter())
+			round.ObserveProof(tss.ProofPaillier, err == nil && ok)
 			if err != nil {
 				common.Logger.Error(round.WrapError(err, Ps[j]).Error())
 				ch <- false
 				return
 			}
 			ch <- ok
-		}(r3msg.UnmarshalProofInts(), j, chs[j])
+		})
 	}
 
-	// consume unbuffered channels (end the goroutines)
+	// collect the results of the goroutines
 	for j, ch := range chs {
 		if j == i {
 			round.ok[j] = true
@@ -70,7 +71,15 @@ func (round *round4) Start() *tss.Error {
 
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("paillier verify failed"), culprits...)
+		return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("paillier verify failed")), culprits...)
+	}
+
+	if round.temp.shareBackupCommittee != nil {
+		backup, err := NewShareBackup(*round.save, round.Threshold(), *round.temp.shareBackupCommittee)
+		if err != nil {
+			return round.WrapError(err)
+		}
+		round.temp.shareBackup = backup
 	}
 
 	round.end <- *round.save
@@ -12,6 +12,9 @@ import (
 
 const (
 	TaskName = "ecdsa-keygen"
+
+	// the number of rounds of keygen, reported to the progress callback
+	totalRounds = 4
 )
 
 type (
@@ -9,10 +9,13 @@ package keygen
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -23,6 +26,9 @@ type (
 		H1i, H2i,
 		Alpha, Beta,
 		P, Q *big.Int
+
+		// the dealer's setup that NTildei, H1i, H2i are taken from, in place of Alpha, Beta, P, Q; see TrustedSetup
+		TrustedSetup *TrustedSetup `json:",omitempty"`
 	}
 
 	LocalSecrets struct {
@@ -47,6 +53,32 @@ type (
 
 		// the ECDSA public key
 		ECDSAPub *crypto.ECPoint // y
+
+		// the public metadata of each party, as sent by that party in round 1; it has no part in the cryptography
+		Metadata []map[string]string `json:",omitempty"`
+
+		// the VSS commitments to each party's polynomial, with which anyone can verify the shares; they are kept by
+		// keygen but not by a re-sharing or BuildLocalSaveDataSubset
+		VSSCommitments []vss.Vs `json:",omitempty"`
+	}
+
+	// PublicSaveData is the part of LocalPartySaveData that holds no secret: the public keys, points and NTilde, h1, h2
+	// of every party. It is the bulk of the save data and can be stored apart from SecretSaveData.
+	PublicSaveData struct {
+		Ks                []*big.Int
+		NTildej, H1j, H2j []*big.Int
+		BigXj             []*crypto.ECPoint
+		PaillierPKs       []*paillier.PublicKey
+		ECDSAPub          *crypto.ECPoint
+		Metadata          []map[string]string `json:",omitempty"`
+		VSSCommitments    []vss.Vs            `json:",omitempty"`
+	}
+
+	// SecretSaveData is the part of LocalPartySaveData that must be kept secret: this party's share and its
+	// pre-params, which include its Paillier secret key
+	SecretSaveData struct {
+		LocalPreParams
+		LocalSecrets
 	}
 )
 
@@ -56,6 +88,7 @@ func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
 	saveData.H1j, saveData.H2j = make([]*big.Int, partyCount), make([]*big.Int, partyCount)
 	saveData.BigXj = make([]*crypto.ECPoint, partyCount)
 	saveData.PaillierPKs = make([]*paillier.PublicKey, partyCount)
+	saveData.Metadata = make([]map[string]string, partyCount)
 	return
 }
 
@@ -74,7 +107,77 @@ func (preParams LocalPreParams) ValidateWithProof() bool {
 		preParams.Q != nil
 }
 
+// ValidateWithTrustedSetup reports whether the pre-params use a dealer's TrustedSetup in place of their own NTilde
+func (preParams LocalPreParams) ValidateWithTrustedSetup() bool {
+	ts := preParams.TrustedSetup
+	return preParams.Validate() && ts != nil &&
+		ts.NTilde != nil && ts.H1 != nil && ts.H2 != nil &&
+		preParams.NTildei.Cmp(ts.NTilde) == 0 &&
+		preParams.H1i.Cmp(ts.H1) == 0 &&
+		preParams.H2i.Cmp(ts.H2) == 0
+}
+
+// Split splits the save data into its public and secret parts, which can be serialized and stored independently and
+// joined again with NewLocalPartySaveDataFromParts. The parts share the slices of the save data.
+func (save LocalPartySaveData) Split() (pub PublicSaveData, secret SecretSaveData) {
+	pub = PublicSaveData{
+		Ks:             save.Ks,
+		NTildej:        save.NTildej,
+		H1j:            save.H1j,
+		H2j:            save.H2j,
+		BigXj:          save.BigXj,
+		PaillierPKs:    save.PaillierPKs,
+		ECDSAPub:       save.ECDSAPub,
+		Metadata:       save.Metadata,
+		VSSCommitments: save.VSSCommitments,
+	}
+	secret = SecretSaveData{LocalPreParams: save.LocalPreParams, LocalSecrets: save.LocalSecrets}
+	return
+}
+
+// NewLocalPartySaveDataFromParts joins the parts made by Split. It returns an error unless the secret part belongs to
+// one of the parties of the public part: its share ID must be one of Ks, and its Paillier key, NTilde, h1, h2 and the
+// point g^xi must be the ones that the public part holds for that party.
+func NewLocalPartySaveDataFromParts(pub PublicSaveData, secret SecretSaveData) (LocalPartySaveData, error) {
+	save := LocalPartySaveData{
+		LocalPreParams: secret.LocalPreParams,
+		LocalSecrets:   secret.LocalSecrets,
+		Ks:             pub.Ks,
+		NTildej:        pub.NTildej,
+		H1j:            pub.H1j,
+		H2j:            pub.H2j,
+		BigXj:          pub.BigXj,
+		PaillierPKs:    pub.PaillierPKs,
+		ECDSAPub:       pub.ECDSAPub,
+		Metadata:       pub.Metadata,
+		VSSCommitments: pub.VSSCommitments,
+	}
+	if !save.Validate() || save.Xi == nil || save.ShareID == nil || save.ECDSAPub == nil {
+		return LocalPartySaveData{}, errors.New("NewLocalPartySaveDataFromParts() received incomplete parts")
+	}
+	n := len(save.Ks)
+	if len(save.NTildej) != n || len(save.H1j) != n || len(save.H2j) != n || len(save.BigXj) != n || len(save.PaillierPKs) != n {
+		return LocalPartySaveData{}, fmt.Errorf("the public part should hold the data of each of its %d parties", n)
+	}
+	i, err := save.shareIndex(save.ShareID)
+	if err != nil {
+		return LocalPartySaveData{}, err
+	}
+	if save.PaillierPKs[i] == nil || save.PaillierPKs[i].N.Cmp(save.PaillierSK.N) != 0 ||
+		save.NTildej[i] == nil || save.NTildej[i].Cmp(save.NTildei) != 0 ||
+		save.H1j[i] == nil || save.H1j[i].Cmp(save.H1i) != 0 ||
+		save.H2j[i] == nil || save.H2j[i].Cmp(save.H2i) != 0 {
+		return LocalPartySaveData{}, errors.New("the pre-params of the secret part do not match the public part")
+	}
+	if save.BigXj[i] == nil || !crypto.ScalarBaseMult(save.ECDSAPub.Curve(), save.Xi).Equals(save.BigXj[i]) {
+		return LocalPartySaveData{}, errors.New("the share of the secret part does not match the public part")
+	}
+	return save, nil
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
+// It leaves out the VSSCommitments: the combined commitment is the sum of the commitments of all the parties of
+// keygen, so it cannot be taken over a subset of them.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
 	for j, kj := range sourceData.Ks {
@@ -95,6 +198,105 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 		newData.H2j[j] = sourceData.H2j[savedIdx]
 		newData.BigXj[j] = sourceData.BigXj[savedIdx]
 		newData.PaillierPKs[j] = sourceData.PaillierPKs[savedIdx]
+		if savedIdx < len(sourceData.Metadata) { // absent from data saved before metadata was kept
+			newData.Metadata[j] = sourceData.Metadata[savedIdx]
+		}
 	}
 	return newData
 }
+
+// PartyMetadata returns the public metadata that the party with the given key sent during keygen
+func (save LocalPartySaveData) PartyMetadata(key *big.Int) (map[string]string, bool) {
+	for j, kj := range save.Ks {
+		if kj.Cmp(key) == 0 && j < len(save.Metadata) {
+			return save.Metadata[j], true
+		}
+	}
+	return nil, false
+}
+
+// VerifySharing is a self-consistency check that a party can run after keygen. It checks that the public points BigXj
+// interpolate (in the exponent) to the ECDSA public key, that this party's own point g^xi is the one interpolated from
+// the other parties' points at its index, and that every BigXj lies on the same degree-`threshold` polynomial.
+func (save LocalPartySaveData) VerifySharing(threshold int) error {
+	i, err := save.OriginalIndex()
+	if err != nil {
+		return err
+	}
+	n := len(save.Ks)
+	if len(save.BigXj) != n || n <= threshold {
+		return fmt.Errorf("expected more than %d public points, one for each of the %d parties", threshold, n)
+	}
+	if save.ECDSAPub == nil {
+		return errors.New("the ECDSA public key is missing")
+	}
+	if !crypto.ScalarBaseMult(save.ECDSAPub.Curve(), save.Xi).Equals(save.BigXj[i]) {
+		return errors.New("g^xi does not match this party's public point")
+	}
+	// interpolate from the first threshold+1 parties other than this one, where there are enough of them
+	others := make([]int, 0, threshold+1)
+	for j := 0; j < n && len(others) <= threshold; j++ {
+		if j != i || n == threshold+1 {
+			others = append(others, j)
+		}
+	}
+	xs, Xs := make([]*big.Int, len(others)), make([]*crypto.ECPoint, len(others))
+	for k, j := range others {
+		xs[k], Xs[k] = save.Ks[j], save.BigXj[j]
+	}
+	if pub, err := vss.InterpolateInExponent(xs, Xs, big.NewInt(0)); err != nil || !pub.Equals(save.ECDSAPub) {
+		return errors.New("the public points do not interpolate to the ECDSA public key")
+	}
+	for j := 0; j < n; j++ {
+		Xj, err := vss.InterpolateInExponent(xs, Xs, save.Ks[j])
+		if err != nil || !Xj.Equals(save.BigXj[j]) {
+			if j == i {
+				return errors.New("g^xi does not match the point interpolated from the other parties")
+			}
+			return fmt.Errorf("the public point of party %d is not consistent with the sharing", j)
+		}
+	}
+	return nil
+}
+
+// CombinedVSSCommitment returns the sum of the parties' VSS commitments, which commits to the polynomial whose
+// evaluation at Ks[j] is the final share of party j, so that vss.VerifyVSSShare can check a party's share Xi
+func (save LocalPartySaveData) CombinedVSSCommitment() (vss.Vs, error) {
+	if len(save.VSSCommitments) == 0 || len(save.VSSCommitments) != len(save.Ks) {
+		return nil, errors.New("the save data does not hold the VSS commitments of every party")
+	}
+	combined := make(vss.Vs, len(save.VSSCommitments[0]))
+	for j, vs := range save.VSSCommitments {
+		if len(vs) != len(combined) {
+			return nil, fmt.Errorf("the VSS commitment of party %d has %d points but %d are expected", j, len(vs), len(combined))
+		}
+		for c, v := range vs {
+			if v == nil {
+				return nil, fmt.Errorf("the VSS commitment of party %d is missing a point", j)
+			}
+			if j == 0 {
+				combined[c] = v
+				continue
+			}
+			var err error
+			if combined[c], err = combined[c].Add(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return combined, nil
+}
+
+// Zeroize overwrites the secrets of the save data with zeros: the share Xi, the Paillier secret key and the factors of
+// NTildei with their discrete logs. It then clears every field, so the save data must not be used afterwards. Copies of
+// the save data, such as those made by BuildLocalSaveDataSubset, share its secrets and are wiped with it.
+func (save *LocalPartySaveData) Zeroize() {
+	if sk := save.PaillierSK; sk != nil {
+		common.ZeroInt(sk.LambdaN)
+		common.ZeroInt(sk.PhiN)
+	}
+	for _, x := range []*big.Int{save.Xi, save.Alpha, save.Beta, save.P, save.Q} {
+		common.ZeroInt(x)
+	}
+	*save = LocalPartySaveData{}
+}
@@ -0,0 +1,149 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+type (
+	// Snapshot is the state of a keygen party in one of its rounds, from which the party can be resumed after a crash.
+	// It holds the party's secrets, including its Paillier key and Shamir shares, so it must be stored as securely as
+	// the save data.
+	Snapshot struct {
+		Round                int
+		Data                 LocalPartySaveData
+		KGCs                 []*big.Int
+		Vs                   vss.Vs
+		Shares               vss.Shares
+		DeCommitPolyG        []*big.Int
+		ShareBackupCommittee *RecoveryCommittee `json:",omitempty"`
+		Messages             []SnapshotMessage
+	}
+
+	// SnapshotMessage is a message held by a party, in wire form
+	SnapshotMessage struct {
+		From        int
+		IsBroadcast bool
+		WireBytes   []byte
+	}
+)
+
+// Snapshot returns the state of the party in its current round. It fails if the party has not started or has finished.
+// The snapshot shares memory with the party, so it should be encoded before the party is updated again.
+func (p *LocalParty) Snapshot() (*Snapshot, error) {
+	var snap *Snapshot
+	err := tss.BaseSnapshot(p, func(round tss.Round) error {
+		if round == nil {
+			return errors.New("the party is not running")
+		}
+		snap = &Snapshot{
+			Round:                round.RoundNumber(),
+			Data:                 p.data,
+			KGCs:                 p.temp.KGCs,
+			Vs:                   p.temp.vs,
+			Shares:               p.temp.shares,
+			DeCommitPolyG:        p.temp.deCommitPolyG,
+			ShareBackupCommittee: p.temp.shareBackupCommittee,
+		}
+		for _, msgs := range [][]tss.ParsedMessage{
+			p.temp.kgRound1Messages,
+			p.temp.kgRound2Message1s,
+			p.temp.kgRound2Message2s,
+			p.temp.kgRound3Messages,
+		} {
+			for _, msg := range msgs {
+				if msg == nil {
+					continue
+				}
+				bz, _, err := msg.WireBytes()
+				if err != nil {
+					return err
+				}
+				snap.Messages = append(snap.Messages, SnapshotMessage{
+					From:        msg.GetFrom().Index,
+					IsBroadcast: msg.IsBroadcast(),
+					WireBytes:   bz,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// NewLocalPartyFromSnapshot restores a party from a snapshot taken with Snapshot. `params` must describe the same
+// session as when the snapshot was taken. Call Resume rather than Start on the returned party.
+func NewLocalPartyFromSnapshot(
+	params *tss.Parameters,
+	snap *Snapshot,
+	out chan<- tss.Message,
+	end chan<- LocalPartySaveData,
+) (*LocalParty, error) {
+	if snap == nil || snap.Round < 1 {
+		return nil, errors.New("NewLocalPartyFromSnapshot() received an invalid snapshot")
+	}
+	partyCount := params.PartyCount()
+	if len(snap.Data.Ks) != partyCount || len(snap.KGCs) != partyCount || len(snap.Shares) != partyCount {
+		return nil, fmt.Errorf("the snapshot is of a session with a different number of parties than %d", partyCount)
+	}
+	p := NewLocalParty(params, out, end).(*LocalParty)
+	p.data = snap.Data
+	p.temp.KGCs = snap.KGCs
+	p.temp.vs = snap.Vs
+	p.temp.shares = snap.Shares
+	p.temp.deCommitPolyG = snap.DeCommitPolyG
+	p.temp.shareBackupCommittee = snap.ShareBackupCommittee
+	p.resumeRound = snap.Round
+
+	Ps := params.Parties().IDs()
+	for _, sm := range snap.Messages {
+		if sm.From < 0 || partyCount <= sm.From {
+			return nil, fmt.Errorf("the snapshot holds a message from an unknown party %d", sm.From)
+		}
+		msg, err := params.ParseWireMessage(sm.WireBytes, Ps[sm.From], sm.IsBroadcast)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := p.StoreMessage(msg); !ok || err != nil {
+			return nil, fmt.Errorf("the snapshot holds a message that could not be stored: %s", msg)
+		}
+	}
+	return p, nil
+}
+
+// Resume puts a party restored with NewLocalPartyFromSnapshot back into the round of the snapshot and proceeds with
+// the messages it holds. The messages that the party sent in that round are not sent again.
+func (p *LocalParty) Resume() *tss.Error {
+	if p.resumeRound < 1 {
+		return p.WrapError(errors.New("the party was not restored from a snapshot"))
+	}
+	round := p.FirstRound()
+	for number := 1; number < p.resumeRound && round != nil; number++ {
+		round = round.NextRound()
+	}
+	if round == nil {
+		return p.WrapError(fmt.Errorf("the snapshot is of an unknown round %d", p.resumeRound))
+	}
+	round.(interface{ resumeAt(int) }).resumeAt(p.resumeRound)
+	return tss.BaseResume(p, TaskName, round)
+}
+
+// resumeAt marks the round as started without starting it again; the rounds share this base
+func (round *base) resumeAt(number int) {
+	round.number = number
+	round.started = true
+	round.resetOK()
+}
@@ -0,0 +1,128 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"runtime"
+	"time"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+type (
+	// TrustedSetup is an NTilde, h1, h2 generated once by a trusted dealer and used by every party of a keygen in place
+	// of its own, so that no party has to generate safe primes. It holds only the public values and the dealer's proofs
+	// that h1, h2 generate the same group mod NTilde; the dealer keeps the factors of NTilde.
+	//
+	// ⚠️ UNSAFE: whoever knows the factors of NTilde can forge the range proofs of signing, so the security of a key
+	// made with a trusted setup reduces to the honesty of the dealer. Use it only among parties that all trust the dealer,
+	// and make sure that the dealer discards the factors.
+	TrustedSetup struct {
+		NTilde,
+		H1, H2 *big.Int
+		DLNProof1,
+		DLNProof2 *dlnp.Proof
+	}
+)
+
+// UNSAFE_GenerateTrustedSetup is run by the dealer to generate a TrustedSetup. The factors of NTilde are discarded.
+// If not specified, a concurrency value equal to the number of available CPU cores will be used.
+func UNSAFE_GenerateTrustedSetup(timeout time.Duration, optionalConcurrency ...int) (*TrustedSetup, error) {
+	concurrency := runtime.NumCPU()
+	if 0 < len(optionalConcurrency) {
+		if 1 < len(optionalConcurrency) {
+			panic(errors.New("UNSAFE_GenerateTrustedSetup: expected 0 or 1 item in `optionalConcurrency`"))
+		}
+		concurrency = optionalConcurrency[0]
+	}
+	sgps, err := common.GetRandomSafePrimesConcurrent(safePrimeBitLen, 2, timeout, concurrency)
+	if err != nil {
+		return nil, err
+	}
+	return UNSAFE_NewTrustedSetup(*ringPedersenParams(sgps))
+}
+
+// UNSAFE_NewTrustedSetup makes a TrustedSetup of the NTilde, h1, h2 of the dealer's pre-params, which must hold their
+// factors. The dealer should not take part in a keygen with these pre-params.
+func UNSAFE_NewTrustedSetup(dealerPreParams LocalPreParams) (*TrustedSetup, error) {
+	pp := dealerPreParams
+	if pp.NTildei == nil || pp.H1i == nil || pp.H2i == nil ||
+		pp.Alpha == nil || pp.Beta == nil || pp.P == nil || pp.Q == nil {
+		return nil, errors.New("UNSAFE_NewTrustedSetup: the pre-params do not hold NTilde with its factors")
+	}
+	common.Logger.Warn("a trusted setup for NTilde has been made; the security of its keys reduces to the dealer's.")
+	return &TrustedSetup{
+		NTilde:    pp.NTildei,
+		H1:        pp.H1i,
+		H2:        pp.H2i,
+		DLNProof1: dlnp.NewProof(pp.H1i, pp.H2i, pp.Alpha, pp.P, pp.Q, pp.NTildei),
+		DLNProof2: dlnp.NewProof(pp.H2i, pp.H1i, pp.Beta, pp.P, pp.Q, pp.NTildei),
+	}, nil
+}
+
+// Verify checks the sizes of the setup and the dealer's proofs that h1, h2 generate the same group mod NTilde
+func (setup *TrustedSetup) Verify() bool {
+	if setup == nil || setup.NTilde == nil || setup.H1 == nil || setup.H2 == nil {
+		return false
+	}
+	return setup.NTilde.BitLen() == nTildeBitsLen &&
+		setup.H1.Cmp(setup.H2) != 0 &&
+		setup.DLNProof1.Verify(setup.H1, setup.H2, setup.NTilde) &&
+		setup.DLNProof2.Verify(setup.H2, setup.H1, setup.NTilde)
+}
+
+// usedBy reports whether NTilde, h1, h2 are those of this setup
+func (setup *TrustedSetup) usedBy(NTilde, h1, h2 *big.Int) bool {
+	return NTilde.Cmp(setup.NTilde) == 0 && h1.Cmp(setup.H1) == 0 && h2.Cmp(setup.H2) == 0
+}
+
+// PreParams returns a party's pre-params that use this setup with the party's own Paillier key
+func (setup *TrustedSetup) PreParams(paillierSK *paillier.PrivateKey) LocalPreParams {
+	return LocalPreParams{
+		PaillierSK:   paillierSK,
+		NTildei:      setup.NTilde,
+		H1i:          setup.H1,
+		H2i:          setup.H2,
+		TrustedSetup: setup,
+	}
+}
+
+// UNSAFE_GeneratePreParamsWithTrustedSetup generates a party's Paillier key and returns pre-params that use the
+// dealer's setup, which it checks first. Every party of the keygen must use the same setup.
+// If not specified, a concurrency value equal to the number of available CPU cores will be used.
+// The Paillier modulus is long enough for the default curve set with tss.SetCurve.
+func UNSAFE_GeneratePreParamsWithTrustedSetup(timeout time.Duration, setup *TrustedSetup, optionalConcurrency ...int) (*LocalPreParams, error) {
+	return UNSAFE_GeneratePreParamsWithTrustedSetupForCurve(tss.EC(), timeout, setup, optionalConcurrency...)
+}
+
+// UNSAFE_GeneratePreParamsWithTrustedSetupForCurve is UNSAFE_GeneratePreParamsWithTrustedSetup with a Paillier modulus
+// long enough for the curve `ec` rather than for the default curve, for a keygen whose curve is set with
+// Parameters.SetCurve.
+func UNSAFE_GeneratePreParamsWithTrustedSetupForCurve(ec elliptic.Curve, timeout time.Duration, setup *TrustedSetup, optionalConcurrency ...int) (*LocalPreParams, error) {
+	if !setup.Verify() {
+		return nil, errors.New("the trusted setup failed to verify")
+	}
+	concurrency := runtime.NumCPU()
+	if 0 < len(optionalConcurrency) {
+		if 1 < len(optionalConcurrency) {
+			panic(errors.New("UNSAFE_GeneratePreParamsWithTrustedSetup: expected 0 or 1 item in `optionalConcurrency`"))
+		}
+		concurrency = optionalConcurrency[0]
+	}
+	paillierSK, _, err := paillier.GenerateKeyPair(paillierModulusBitLen(ec), timeout, concurrency)
+	if err != nil {
+		return nil, errors.New("timeout or error while generating the Paillier secret key")
+	}
+	preParams := setup.PreParams(paillierSK)
+	return &preParams, nil
+}
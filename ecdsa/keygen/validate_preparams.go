@@ -0,0 +1,157 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+type (
+	// PublicPreParams is the public portion of a party's LocalPreParams, as it would be broadcast in keygen round 1.
+	PublicPreParams struct {
+		PartyID    *tss.PartyID
+		PaillierPK *paillier.PublicKey
+		NTilde,
+		H1, H2 *big.Int
+		DLNProof1,
+		DLNProof2 *dlnp.Proof
+	}
+)
+
+// PublicPreParams extracts the public portion of the pre-params and proves that h1, h2 generate the same group mod NTilde.
+// With a trusted setup, the dealer's proofs are used.
+func (preParams LocalPreParams) PublicPreParams(partyID *tss.PartyID) (*PublicPreParams, error) {
+	if ts := preParams.TrustedSetup; preParams.ValidateWithTrustedSetup() {
+		return &PublicPreParams{
+			PartyID:    partyID,
+			PaillierPK: &preParams.PaillierSK.PublicKey,
+			NTilde:     ts.NTilde,
+			H1:         ts.H1,
+			H2:         ts.H2,
+			DLNProof1:  ts.DLNProof1,
+			DLNProof2:  ts.DLNProof2,
+		}, nil
+	}
+	if !preParams.ValidateWithProof() {
+		return nil, errors.New("PublicPreParams: the pre-params are incomplete")
+	}
+	return &PublicPreParams{
+		PartyID:    partyID,
+		PaillierPK: &preParams.PaillierSK.PublicKey,
+		NTilde:     preParams.NTildei,
+		H1:         preParams.H1i,
+		H2:         preParams.H2i,
+		DLNProof1:  dlnp.NewProof(preParams.H1i, preParams.H2i, preParams.Alpha, preParams.P, preParams.Q, preParams.NTildei),
+		DLNProof2:  dlnp.NewProof(preParams.H2i, preParams.H1i, preParams.Beta, preParams.P, preParams.Q, preParams.NTildei),
+	}, nil
+}
+
+// ValidateCommitteePreParams runs the same Paillier, NTilde and h1, h2 checks that keygen round 2 applies to the round 1
+// messages, but ahead of time over the whole committee. It returns the parties whose pre-params would make keygen fail.
+func ValidateCommitteePreParams(committee []*PublicPreParams) []*tss.PartyID {
+	return ValidateCommitteePreParamsForCurve(tss.EC(), committee)
+}
+
+// ValidateCommitteePreParamsForCurve is ValidateCommitteePreParams for a keygen over the curve `ec` rather than the
+// default curve, which sets the minimum length of the Paillier moduli.
+func ValidateCommitteePreParamsForCurve(ec elliptic.Curve, committee []*PublicPreParams) []*tss.PartyID {
+	return ValidateCommitteePreParamsWithTrustedSetup(ec, nil, committee)
+}
+
+// ValidateCommitteePreParamsWithTrustedSetup is ValidateCommitteePreParamsForCurve for a keygen with a trusted setup,
+// which may be nil for none. As in keygen round 2, every party must use the setup's NTilde, h1, h2, which all parties
+// share, so they are not checked for reuse.
+func ValidateCommitteePreParamsWithTrustedSetup(ec elliptic.Curve, setup *TrustedSetup, committee []*PublicPreParams) []*tss.PartyID {
+	faulty := make([]bool, len(committee))
+	if setup != nil && !setup.Verify() {
+		common.Logger.Warn("the trusted setup failed to verify")
+		setup = nil
+		for j := range faulty {
+			faulty[j] = true
+		}
+	}
+	h1H2Owners := make(map[string]int, len(committee)*2)
+	for j, pp := range committee {
+		if pp == nil || pp.PaillierPK == nil || pp.NTilde == nil || pp.H1 == nil || pp.H2 == nil {
+			faulty[j] = true
+			continue
+		}
+		if err := checkPublicPreParams(ec, pp.PaillierPK, pp.NTilde, pp.H1, pp.H2); err != nil {
+			common.Logger.Warnf("party %v: %v", pp.PartyID, err)
+			faulty[j] = true
+			continue
+		}
+		if setup != nil {
+			if !setup.usedBy(pp.NTilde, pp.H1, pp.H2) {
+				common.Logger.Warnf("party %v: did not use the trusted setup for NTilde", pp.PartyID)
+				faulty[j] = true
+			}
+			continue
+		}
+		for _, h := range []*big.Int{pp.H1, pp.H2} {
+			hHex := hex.EncodeToString(h.Bytes())
+			if owner, found := h1H2Owners[hHex]; found {
+				common.Logger.Warnf("party %v: h1 or h2 is also used by party %v", pp.PartyID, committee[owner].PartyID)
+				faulty[owner], faulty[j] = true, true
+				continue
+			}
+			h1H2Owners[hHex] = j
+		}
+	}
+	wg := new(sync.WaitGroup)
+	for j, pp := range committee {
+		if faulty[j] {
+			continue
+		}
+		wg.Add(1)
+		go func(j int, pp *PublicPreParams) {
+			defer wg.Done()
+			if !pp.DLNProof1.Verify(pp.H1, pp.H2, pp.NTilde) || !pp.DLNProof2.Verify(pp.H2, pp.H1, pp.NTilde) {
+				common.Logger.Warnf("party %v: dln proof verification failed", pp.PartyID)
+				faulty[j] = true
+			}
+		}(j, pp)
+	}
+	wg.Wait()
+
+	culprits := make([]*tss.PartyID, 0, len(committee))
+	for j, isFaulty := range faulty {
+		if !isFaulty {
+			continue
+		}
+		var culprit *tss.PartyID
+		if committee[j] != nil {
+			culprit = committee[j].PartyID
+		}
+		culprits = append(culprits, culprit)
+	}
+	return culprits
+}
+
+// checkPublicPreParams performs the size and h1, h2 sanity checks for a single party's public pre-params
+func checkPublicPreParams(ec elliptic.Curve, paillierPK *paillier.PublicKey, NTildej, H1j, H2j *big.Int) error {
+	if err := checkPaillierModulusBits(ec, paillierPK.N.BitLen()); err != nil {
+		return fmt.Errorf("got paillier modulus with insufficient bits for this party: %w", err)
+	}
+	if NTildej.BitLen() != nTildeBitsLen {
+		return errors.New("got NTildej with insufficient bits for this party")
+	}
+	if H1j.Cmp(H2j) == 0 {
+		return errors.New("h1j and h2j were equal for this party")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@
 package resharing
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 
@@ -57,9 +58,16 @@ type (
 		newXi     *big.Int
 		newKs     []*big.Int
 		newBigXjs []*crypto.ECPoint // Xj to save in round 5
+
+		// see SessionID
+		sessionID []byte
 	}
 )
 
+const (
+	sessionIDDomain = "tss-lib ecdsa resharing session"
+)
+
 // Exported, used in `tss` client
 // The `key` is read from and/or written to depending on whether this party is part of the old or the new committee.
 // You may optionally generate and set the LocalPreParams if you would like to use pre-generated safe primes and Paillier secret.
@@ -95,9 +103,18 @@ func NewLocalParty(
 	if key.LocalPreParams.ValidateWithProof() {
 		p.save.LocalPreParams = key.LocalPreParams
 	}
+	p.temp.sessionID = SessionID(params)
 	return p
 }
 
+// SessionID derives the canonical ID of a re-sharing session from the old and the new committees, their thresholds,
+// the curve and the session nonce (see tss.ReSharingParameters.ReSharingSessionID), so that the parties of both
+// committees agree on it. Every message carries it, and a message with another ID is dropped, so that a message relayed
+// from another re-sharing is not accepted.
+func SessionID(params *tss.ReSharingParameters) []byte {
+	return common.SHA512_256([]byte(sessionIDDomain), params.ReSharingSessionID())
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
 	return newRound1(p.params, &p.input, &p.save, &p.temp, p.out, p.end)
 }
@@ -111,7 +128,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -122,17 +139,10 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
-	// check that the message's "from index" will fit into the array
-	var maxFromIdx int
-	switch msg.Content().(type) {
-	case *DGRound2Message1, *DGRound2Message2, *DGRound4Message:
-		maxFromIdx = len(p.params.NewParties().IDs()) - 1
-	default:
-		maxFromIdx = len(p.params.OldParties().IDs()) - 1
-	}
-	if maxFromIdx < msg.GetFrom().Index {
-		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
-			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+	// check that the sender is in the committee that sends this message, so that its index fits into the array
+	if senderIndex(p.params, msg) < 0 {
+		return false, p.WrapError(fmt.Errorf("received msg %s from a party that is not in the committee that sends it",
+			msg.Type()), msg.GetFrom())
 	}
 	return true, nil
 }
@@ -142,10 +152,17 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
 		return ok, err
 	}
-	fromPIdx := msg.GetFrom().Index
+	fromPIdx := senderIndex(p.params, msg)
+
+	// a message of another session, e.g. one replayed by a relay, is dropped and does not take the sender's slot; it
+	// need not come from the sender, so the sender is not blamed
+	if content, ok := msg.Content().(sessionMessage); ok && !bytes.Equal(content.GetSessionId(), p.temp.sessionID) {
+		common.Logger.Warnf("dropped a message of another session: %v", msg)
+		return false, nil
+	}
 
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// this does not handle message replays within a session. we expect the caller to apply spoofing protection.
 	switch msg.Content().(type) {
 	case *DGRound1Message:
 		p.temp.dgRound1Messages[fromPIdx] = msg
@@ -166,6 +183,28 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	return true, nil
 }
 
+// senderIndex returns the index of the sender of msg in the committee that sends it: the new committee for the messages
+// of rounds 2 and 4 and the old committee otherwise. It is -1 if the sender is not in that committee. A party in both
+// committees has an index in each, so its index is found by its key rather than taken from the Index of its PartyID.
+func senderIndex(params *tss.ReSharingParameters, msg tss.ParsedMessage) int {
+	switch msg.Content().(type) {
+	case *DGRound2Message1, *DGRound2Message2, *DGRound4Message:
+		return indexOf(params.NewParties().IDs(), msg.GetFrom())
+	default:
+		return indexOf(params.OldParties().IDs(), msg.GetFrom())
+	}
+}
+
+// indexOf returns the index of the key of pID in `ids`, or -1 if it is not there
+func indexOf(ids tss.SortedPartyIDs, pID *tss.PartyID) int {
+	for j, Pj := range ids {
+		if Pj.KeyInt().Cmp(pID.KeyInt()) == 0 {
+			return j
+		}
+	}
+	return -1
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
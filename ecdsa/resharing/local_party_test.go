@@ -13,12 +13,14 @@ import (
 	"runtime"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
 	. "github.com/ordinox/thorchain-tss-lib/ecdsa/resharing"
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/signing"
@@ -231,3 +233,463 @@ signing:
 		}
 	}
 }
+
+func TestE2EConcurrentThresholdIncrease(t *testing.T) {
+	setUp("info")
+
+	threshold, newThreshold := testThreshold, testParticipants-1
+
+	// PHASE: load keygen fixtures
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(threshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: resharing to the same number of parties with a higher threshold
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	paramsFor := func(oldCtx, newCtx *tss.PeerContext, pID *tss.PartyID) *tss.ReSharingParameters {
+		params, err := tss.NewThresholdIncreaseReSharingParameters(oldCtx, newCtx, pID, testParticipants, threshold, newThreshold)
+		assert.NoError(t, err)
+		return params
+	}
+	newKeys := reShare(t, oldKeys, oldPIDs, newPIDs, paramsFor)
+	if newKeys == nil {
+		return
+	}
+	assert.True(t, newKeys[0].ECDSAPub.Equals(oldKeys[0].ECDSAPub), "the public key must not change")
+
+	// t new shares no longer reconstruct the secret; t+1 of them do
+	shares := make(vss.Shares, len(newKeys))
+	for j, key := range newKeys {
+		shares[j] = &vss.Share{Threshold: newThreshold, ID: key.ShareID, Share: key.Xi}
+	}
+	secret, err := shares[:newThreshold].ReConstruct(tss.EC())
+	if assert.NoError(t, err) {
+		assert.False(t, crypto.ScalarBaseMult(tss.EC(), secret).Equals(newKeys[0].ECDSAPub), "t shares must not reconstruct the key")
+	}
+	secret, err = shares[:newThreshold+1].ReConstruct(tss.EC())
+	if assert.NoError(t, err) {
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), secret).Equals(newKeys[0].ECDSAPub), "t+1 shares must reconstruct the key")
+	}
+
+	// PHASE: signing with the new threshold
+	signAndVerify(t, newKeys, newPIDs, newThreshold)
+
+	// an unachievable threshold is rejected
+	_, err = tss.NewThresholdIncreaseReSharingParameters(
+		tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs), oldPIDs[0], testParticipants, threshold, testParticipants)
+	assert.Error(t, err, "t=n must be rejected")
+}
+
+func TestE2ERetainedParties(t *testing.T) {
+	setUp("info")
+
+	// PHASE: re-share the fixture key to a 3-of-5 committee
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	const partyCount, threshold = 5, 2
+	firstPIDs := tss.GenerateTestPartyIDs(partyCount)
+	firstKeys := reShare(t, oldKeys, oldPIDs, firstPIDs, func(oldCtx, newCtx *tss.PeerContext, pID *tss.PartyID) *tss.ReSharingParameters {
+		return tss.NewReSharingParameters(oldCtx, newCtx, pID, testParticipants, testThreshold, partyCount, threshold)
+	})
+	if firstKeys == nil {
+		return
+	}
+
+	// PHASE: re-share from 3 of them to a new 3-of-5 committee that retains two of them under the same keys. A
+	// retained member runs a single party, which contributes its old share and receives a new one.
+	contributors := firstPIDs[:threshold+1]
+	retained := contributors[:2]
+	unsorted := make(tss.UnSortedPartyIDs, 0, partyCount)
+	for _, pID := range retained {
+		unsorted = append(unsorted, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	for j := len(retained); j < partyCount; j++ {
+		unsorted = append(unsorted, tss.NewPartyID(fmt.Sprintf("new-%d", j), fmt.Sprintf("N[%d]", j), common.MustGetRandomInt(256)))
+	}
+	newPIDs := tss.SortPartyIDs(unsorted)
+	oldXis := make(map[string]*big.Int, len(retained))
+	for j, pID := range retained {
+		oldXis[pID.Id] = new(big.Int).Set(firstKeys[j].Xi)
+	}
+	newKeys := reShare(t, firstKeys[:threshold+1], contributors, newPIDs, func(oldCtx, newCtx *tss.PeerContext, pID *tss.PartyID) *tss.ReSharingParameters {
+		return tss.NewReSharingParameters(oldCtx, newCtx, pID, partyCount, threshold, partyCount, threshold)
+	})
+	if newKeys == nil {
+		return
+	}
+	assert.True(t, newKeys[0].ECDSAPub.Equals(oldKeys[0].ECDSAPub), "the public key must not change")
+
+	// every retained member received a fresh share under its key and discarded the old one
+	shares := make(vss.Shares, 0, threshold+1)
+	for j, pID := range newPIDs {
+		oldXi, ok := oldXis[pID.Id]
+		if !ok {
+			continue
+		}
+		assert.Equal(t, pID.KeyInt(), newKeys[j].ShareID)
+		assert.NotEqual(t, 0, oldXi.Cmp(newKeys[j].Xi), "a retained member must receive a fresh share")
+		shares = append(shares, &vss.Share{Threshold: threshold, ID: newKeys[j].ShareID, Share: newKeys[j].Xi})
+	}
+	assert.Len(t, shares, len(retained))
+	for j := range retained {
+		assert.Zero(t, firstKeys[j].Xi.Sign(), "a retained member must discard its old share")
+	}
+	// the shares of the retained members and of a newcomer reconstruct the key
+	for j, pID := range newPIDs {
+		if _, ok := oldXis[pID.Id]; !ok {
+			shares = append(shares, &vss.Share{Threshold: threshold, ID: newKeys[j].ShareID, Share: newKeys[j].Xi})
+			break
+		}
+	}
+	secret, err := shares.ReConstruct(tss.EC())
+	if assert.NoError(t, err) {
+		assert.True(t, crypto.ScalarBaseMult(tss.EC(), secret).Equals(oldKeys[0].ECDSAPub), "t+1 new shares must reconstruct the key")
+	}
+
+	// PHASE: signing with the new committee
+	signAndVerify(t, newKeys, newPIDs, threshold)
+
+	// a key in both committees is rejected by every party when the policy requires a new key
+	oldCtx, newCtx := tss.NewPeerContext(contributors), tss.NewPeerContext(newPIDs)
+	params := tss.NewReSharingParameters(oldCtx, newCtx, contributors[2], partyCount, threshold, partyCount, threshold)
+	assert.Equal(t, tss.RetainedPartyUnchecked, params.RetainedPartyPolicy())
+	assert.NoError(t, params.CheckRetainedParties())
+	params.SetRetainedPartyPolicy(tss.RetainedPartyRequireNewKey)
+	assert.Error(t, params.CheckRetainedParties())
+	P := NewLocalParty(params, firstKeys[2], make(chan tss.Message, 2*partyCount), make(chan keygen.LocalPartySaveData, 1))
+	if err := P.Start(); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "in both the old and the new committee")
+	}
+}
+
+func TestCorruptedOldShareCaughtInRoundOne(t *testing.T) {
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldCtx, newCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+
+	const corrupted = 1
+	start := func(j int, key keygen.LocalPartySaveData) *tss.Error {
+		params := tss.NewReSharingParameters(oldCtx, newCtx, oldPIDs[j], testParticipants, testThreshold, len(newPIDs), testThreshold)
+		P := NewLocalParty(params, key, make(chan tss.Message, len(newPIDs)), make(chan keygen.LocalPartySaveData, 1))
+		return P.Start()
+	}
+	assert.Nil(t, start(0, oldKeys[0]), "an intact old share must pass")
+
+	key := oldKeys[corrupted]
+	key.Xi = new(big.Int).Add(key.Xi, big.NewInt(1))
+	tErr := start(corrupted, key)
+	if assert.NotNil(t, tErr) {
+		assert.Equal(t, 1, tErr.Round())
+		assert.Contains(t, tErr.Error(), "g^w_i == W_i")
+		assert.Equal(t, []*tss.PartyID{oldPIDs[corrupted]}, tErr.Culprits())
+	}
+
+	// a stale copy of another party's public share is caught too
+	key = oldKeys[0]
+	key.BigXj = append([]*crypto.ECPoint(nil), key.BigXj...)
+	key.BigXj[corrupted] = crypto.ScalarBaseMult(tss.EC(), big.NewInt(1))
+	tErr = start(0, key)
+	if assert.NotNil(t, tErr) {
+		assert.Equal(t, 1, tErr.Round())
+		assert.Contains(t, tErr.Error(), "do not reconstruct the public key")
+		assert.Equal(t, []*tss.PartyID{oldPIDs[0]}, tErr.Culprits(), "the party holding the stale data is named")
+	}
+}
+
+func TestReShareAbortsBeforeOldShareDiscard(t *testing.T) {
+	setUp("info")
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldCtx, newCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+
+	errCh := make(chan *tss.Error, len(oldPIDs)+len(newPIDs))
+	outCh := make(chan tss.Message, len(oldPIDs)+len(newPIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(oldPIDs)+len(newPIDs))
+	oldXis := make([]*big.Int, len(oldPIDs))
+	oldCommittee, newCommittee := make([]*LocalParty, 0, len(oldPIDs)), make([]*LocalParty, 0, len(newPIDs))
+	for j, pID := range oldPIDs {
+		oldXis[j] = new(big.Int).Set(oldKeys[j].Xi)
+		params := tss.NewReSharingParameters(oldCtx, newCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	for j, pID := range newPIDs {
+		params := tss.NewReSharingParameters(oldCtx, newCtx, pID, testParticipants, testThreshold, len(newPIDs), testThreshold)
+		save := keygen.NewLocalPartySaveData(len(newPIDs))
+		save.LocalPreParams = fixtures[j].LocalPreParams
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range append(append([]*LocalParty(nil), newCommittee...), oldCommittee...) {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// the first member of the new committee reports a public share that the new shares do not reconstruct
+	const faulty = 0
+	oldErrs := 0
+	for oldErrs < len(oldCommittee) {
+		select {
+		case err := <-errCh:
+			for _, Pj := range oldPIDs {
+				if err.Victim() == Pj {
+					assert.Equal(t, 5, err.Round())
+					oldErrs++
+				}
+			}
+		case msg := <-outCh:
+			if pMsg, ok := msg.(tss.ParsedMessage); ok && msg.GetFrom().Index == faulty {
+				if content, ok := pMsg.Content().(*DGRound4Message); ok {
+					msg = NewDGRound4Message(msg.GetTo(), msg.GetFrom(), content.GetSessionId(), crypto.ScalarBaseMult(tss.EC(), big.NewInt(1)))
+				}
+			}
+			dest := msg.GetTo()
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go test.SharedPartyUpdater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go test.SharedPartyUpdater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+		case save := <-endCh:
+			if save.Xi == nil {
+				assert.Fail(t, "a member of the old committee ended the re-sharing")
+			}
+		case <-time.After(5 * time.Minute):
+			assert.FailNow(t, "timed out waiting for the old committee to abort")
+		}
+	}
+	for j, key := range oldKeys {
+		assert.Equal(t, oldXis[j], key.Xi, "the old share must not be discarded")
+	}
+}
+
+// reShare runs a re-sharing from the old committee to the new committee and returns the save data of the new committee,
+// indexed as in `newPIDs`. A key in both committees is run by a single party, the one of the old committee. It returns
+// nil if the test has failed.
+func reShare(
+	t *testing.T,
+	oldKeys []keygen.LocalPartySaveData,
+	oldPIDs, newPIDs tss.SortedPartyIDs,
+	paramsFor func(oldCtx, newCtx *tss.PeerContext, pID *tss.PartyID) *tss.ReSharingParameters,
+) []keygen.LocalPartySaveData {
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	// init the new parties; re-use the fixture pre-params for speed
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if err != nil {
+		common.Logger.Info("No test fixtures were found, so the safe primes will be generated from scratch. This may take a while...")
+	}
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	newPCount := len(newPIDs)
+
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	newCommittee := make([]*LocalParty, 0, newPCount)
+	bothCommitteesPax := len(oldCommittee) + len(newCommittee)
+
+	errCh := make(chan *tss.Error, bothCommitteesPax)
+	outCh := make(chan tss.Message, bothCommitteesPax)
+	endCh := make(chan keygen.LocalPartySaveData, bothCommitteesPax)
+
+	updater := test.SharedPartyUpdater
+
+	// init the old parties first
+	for j, pID := range oldPIDs {
+		params := paramsFor(oldP2PCtx, newP2PCtx, pID)
+		P := NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty) // discard old key data
+		oldCommittee = append(oldCommittee, P)
+	}
+	// init and start the new parties; a retained party is the one of the old committee, whose pre-params a newcomer
+	// must not reuse
+	newCommittee = newCommittee[:newPCount]
+	retained, usedNTildes := 0, make(map[string]bool, len(oldPIDs))
+	for j, pID := range newPIDs {
+		if k := indexOfKey(oldPIDs, pID); k >= 0 {
+			newCommittee[j] = oldCommittee[k]
+			usedNTildes[oldKeys[k].NTildei.String()] = true
+			retained++
+		}
+	}
+	nextFixture := 0
+	for j, pID := range newPIDs {
+		if newCommittee[j] != nil {
+			continue
+		}
+		params := paramsFor(oldP2PCtx, newP2PCtx, pID)
+		save := keygen.NewLocalPartySaveData(newPCount)
+		for ; len(newPIDs) <= len(fixtures) && nextFixture < len(fixtures); nextFixture++ {
+			if !usedNTildes[fixtures[nextFixture].NTildei.String()] {
+				save.LocalPreParams = fixtures[nextFixture].LocalPreParams
+				nextFixture++
+				break
+			}
+		}
+		P := NewLocalParty(params, save, outCh, endCh).(*LocalParty)
+		newCommittee[j] = P
+		go func(P *LocalParty) { // they will wait for messages
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	// start the old parties; they will send messages
+	for _, P := range oldCommittee {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, len(newCommittee))
+	endedOldCommittee := 0
+	var reSharingEnded int32
+	for {
+		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			return nil
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				t.Fatal("did not expect a msg to have a nil destination during resharing")
+			}
+			// route by key, once per party, as a retained party is in both committees
+			routed := make(map[*LocalParty]bool, len(dest))
+			for _, destP := range dest {
+				if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+					if j := indexOfKey(oldPIDs, destP); j >= 0 {
+						routed[oldCommittee[j]] = true
+					}
+				}
+				if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+					if j := indexOfKey(newPIDs, destP); j >= 0 {
+						routed[newCommittee[j]] = true
+					}
+				}
+			}
+			for P := range routed {
+				go updater(P, msg, errCh)
+			}
+
+		case save := <-endCh:
+			// old committee members that aren't receiving a share have their Xi zeroed
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+				newKeys[index] = save
+			} else {
+				endedOldCommittee++
+			}
+			atomic.AddInt32(&reSharingEnded, 1)
+			if atomic.LoadInt32(&reSharingEnded) == int32(len(oldCommittee)+len(newCommittee)-retained) {
+				assert.Equal(t, len(oldCommittee)-retained, endedOldCommittee)
+				t.Logf("Resharing done. Reshared %d participants", reSharingEnded)
+
+				// xj tests: BigXj == xj*G
+				for j, key := range newKeys {
+					// xj test: BigXj == xj*G
+					xj := key.Xi
+					gXj := crypto.ScalarBaseMult(tss.EC(), xj)
+					BigXj := key.BigXj[j]
+					assert.True(t, BigXj.Equals(gXj), "ensure BigX_j == g^x_j")
+				}
+
+				// more verification of signing is implemented within local_party_test.go of keygen package
+				return newKeys
+			}
+		}
+	}
+}
+
+// indexOfKey returns the position in `ids` of the party with the key of `pID`, or -1
+func indexOfKey(ids tss.SortedPartyIDs, pID *tss.PartyID) int {
+	for j, id := range ids {
+		if id.KeyInt().Cmp(pID.KeyInt()) == 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+// signAndVerify signs with all of the given keys and verifies the resulting signature
+func signAndVerify(t *testing.T, signKeys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, threshold int) {
+	signP2pCtx := tss.NewPeerContext(signPIDs)
+	signParties := make([]*signing.LocalParty, 0, len(signPIDs))
+
+	signErrCh := make(chan *tss.Error, len(signPIDs))
+	signOutCh := make(chan tss.Message, len(signPIDs))
+	signEndCh := make(chan *signing.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for j, signPID := range signPIDs {
+		params := tss.NewParameters(signP2pCtx, signPID, len(signPIDs), threshold)
+		P := signing.NewLocalParty(big.NewInt(42), params, signKeys[j], signOutCh, signEndCh).(*signing.LocalParty)
+		signParties = append(signParties, P)
+		go func(P *signing.LocalParty) {
+			if err := P.Start(); err != nil {
+				signErrCh <- err
+			}
+		}(P)
+	}
+
+	var signEnded int32
+	for {
+		fmt.Printf("ACTIVE GOROUTINES: %d\n", runtime.NumGoroutine())
+		select {
+		case err := <-signErrCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			return
+
+		case msg := <-signOutCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range signParties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, signErrCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(signParties[dest[0].Index], msg, signErrCh)
+			}
+
+		case signData := <-signEndCh:
+			atomic.AddInt32(&signEnded, 1)
+			if atomic.LoadInt32(&signEnded) == int32(len(signPIDs)) {
+				t.Logf("Signing done. Received sign data from %d participants", signEnded)
+
+				// BEGIN ECDSA verify
+				pkX, pkY := signKeys[0].ECDSAPub.X(), signKeys[0].ECDSAPub.Y()
+				pk := ecdsa.PublicKey{
+					Curve: tss.EC(),
+					X:     pkX,
+					Y:     pkY,
+				}
+				ok := ecdsa.Verify(&pk, big.NewInt(42).Bytes(),
+					new(big.Int).SetBytes(signData.Signature.R),
+					new(big.Int).SetBytes(signData.Signature.S))
+
+				assert.True(t, ok, "ecdsa verify must pass")
+				t.Log("ECDSA signing test done.")
+				// END ECDSA verify
+
+				return
+			}
+		}
+	}
+}
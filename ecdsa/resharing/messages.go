@@ -7,6 +7,7 @@
 package resharing
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -36,6 +37,7 @@ var (
 func NewDGRound1Message(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	ecdsaPub *crypto.ECPoint,
 	vct cmt.HashCommitment,
 ) tss.ParsedMessage {
@@ -48,6 +50,7 @@ func NewDGRound1Message(
 	content := &DGRound1Message{
 		EcdsaPub:    ecdsaPub.ToProtobufPoint(),
 		VCommitment: vct.Bytes(),
+		SessionId:   sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -60,8 +63,8 @@ func (m *DGRound1Message) ValidateBasic() bool {
 		common.NonEmptyBytes(m.VCommitment)
 }
 
-func (m *DGRound1Message) UnmarshalECDSAPub() (*crypto.ECPoint, error) {
-	return crypto.NewECPointFromProtobuf(m.GetEcdsaPub())
+func (m *DGRound1Message) UnmarshalECDSAPub(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetEcdsaPub())
 }
 
 func (m *DGRound1Message) UnmarshalVCommitment() *big.Int {
@@ -73,6 +76,7 @@ func (m *DGRound1Message) UnmarshalVCommitment() *big.Int {
 func NewDGRound2Message1(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	paillierPK *paillier.PublicKey,
 	paillierPf paillier.Proof,
 	NTildei, H1i, H2i *big.Int,
@@ -101,6 +105,7 @@ func NewDGRound2Message1(
 		H2:            H2i.Bytes(),
 		Dlnproof_1:    dlnProof1Bz,
 		Dlnproof_2:    dlnProof2Bz,
+		SessionId:     sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg), nil
@@ -156,6 +161,7 @@ func (m *DGRound2Message1) UnmarshalDLNProof2() (*dlnp.Proof, error) {
 func NewDGRound2Message2(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:             from,
@@ -163,7 +169,9 @@ func NewDGRound2Message2(
 		IsBroadcast:      true,
 		IsToOldCommittee: true,
 	}
-	content := &DGRound2Message2{}
+	content := &DGRound2Message2{
+		SessionId: sessionID,
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
@@ -177,6 +185,7 @@ func (m *DGRound2Message2) ValidateBasic() bool {
 func NewDGRound3Message1(
 	to *tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	share *vss.Share,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -186,7 +195,8 @@ func NewDGRound3Message1(
 		IsToOldCommittee: false,
 	}
 	content := &DGRound3Message1{
-		Share: share.Share.Bytes(),
+		Share:     share.Share.Bytes(),
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -202,6 +212,7 @@ func (m *DGRound3Message1) ValidateBasic() bool {
 func NewDGRound3Message2(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	vdct cmt.HashDeCommitment,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -213,6 +224,7 @@ func NewDGRound3Message2(
 	vDctBzs := common.BigIntsToBytes(vdct)
 	content := &DGRound3Message2{
 		VDecommitment: vDctBzs,
+		SessionId:     sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -233,6 +245,8 @@ func (m *DGRound3Message2) UnmarshalVDeCommitment() cmt.HashDeCommitment {
 func NewDGRound4Message(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
+	bigXi *crypto.ECPoint,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:                    from,
@@ -240,11 +254,20 @@ func NewDGRound4Message(
 		IsBroadcast:             true,
 		IsToOldAndNewCommittees: true,
 	}
-	content := &DGRound4Message{}
+	content := &DGRound4Message{
+		BigXI:     bigXi.ToProtobufPoint(),
+		SessionId: sessionID,
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
 
 func (m *DGRound4Message) ValidateBasic() bool {
-	return true
+	return m != nil &&
+		m.BigXI != nil &&
+		m.BigXI.ValidateBasic()
+}
+
+func (m *DGRound4Message) UnmarshalBigXi(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetBigXI())
 }
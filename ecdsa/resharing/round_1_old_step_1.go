@@ -33,13 +33,18 @@ func (round *round1) Start() *tss.Error {
 	round.resetOK() // resets both round.oldOK and round.newOK
 	round.allNewOK()
 
+	if err := round.ReSharingParams().CheckRetainedParties(); err != nil {
+		return round.WrapError(err)
+	}
 	if !round.ReSharingParams().IsOldCommittee() {
 		return nil
 	}
-	round.allOldOK()
+	// a party that is also in the new committee receives the messages of the rest of the old committee
+	if !round.ReSharingParams().IsNewCommittee() {
+		round.allOldOK()
+	}
 
-	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.oldIndex()
 
 	// 1. PrepareForSigning() -> w_i
 	xi, ks, bigXj := round.input.Xi, round.input.Ks, round.input.BigXj
@@ -47,13 +52,27 @@ func (round *round1) Start() *tss.Error {
 		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID())
 	}
 	newKs := round.NewParties().IDs().Keys()
-	wi, _, err := signing.PrepareForSigning(i, len(round.OldParties().IDs()), xi, ks, bigXj)
+	if round.NewThreshold()+1 > len(newKs) {
+		return round.WrapError(fmt.Errorf("new t+1=%d is not satisfied by the new committee count of %d", round.NewThreshold()+1, len(newKs)), round.PartyID())
+	}
+	wi, bigWs, err := signing.PrepareForSigning(round.EC(), i, len(round.OldParties().IDs()), xi, ks, bigXj)
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
+	// PrepareForSigning has checked this party's share against its public share. Catch stale or corrupted old key
+	// data before the re-sharing effort: the public shares of the old committee must also interpolate to the public key.
+	bigY := bigWs[0]
+	for _, bigWj := range bigWs[1:] {
+		if bigY, err = bigY.Add(bigWj); err != nil {
+			return round.WrapError(err, round.PartyID())
+		}
+	}
+	if !bigY.Equals(round.input.ECDSAPub) {
+		return round.WrapError(errors.New("the old committee's public shares do not reconstruct the public key"), round.PartyID())
+	}
 
 	// 2.
-	vi, shares, err := vss.Create(round.NewThreshold(), wi, newKs)
+	vi, shares, err := vss.Create(round.EC(), round.NewThreshold(), wi, newKs)
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
@@ -71,18 +90,18 @@ func (round *round1) Start() *tss.Error {
 
 	// 5. "broadcast" C_i to members of the NEW committee
 	r1msg := NewDGRound1Message(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(), round.temp.sessionID,
 		round.input.ECDSAPub, vCmt.C)
 	round.temp.dgRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.out <- round.UseCodec(r1msg)
 
 	return nil
 }
 
 func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
 	// accept messages from old -> new committee
-	if _, ok := msg.Content().(*DGRound1Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound1Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -110,7 +129,7 @@ func (round *round1) Update() (bool, *tss.Error) {
 			continue
 		}
 		r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
-		candidate, err := r1msg.UnmarshalECDSAPub()
+		candidate, err := r1msg.UnmarshalECDSAPub(round.EC())
 		if err != nil {
 			return false, round.WrapError(errors.New("unable to unmarshal the ecdsa pub key"), msg.GetFrom())
 		}
@@ -8,6 +8,7 @@ package resharing
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ordinox/thorchain-tss-lib/crypto/dlnp"
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
@@ -28,13 +29,13 @@ func (round *round2) Start() *tss.Error {
 	}
 
 	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.newIndex()
 
 	// 2. "broadcast" "ACK" members of the OLD committee
 	r2msg1 := NewDGRound2Message2(
-		round.OldParties().IDs().Exclude(round.PartyID()), round.PartyID())
+		round.OldParties().IDs().Exclude(round.PartyID()), round.PartyID(), round.temp.sessionID)
 	round.temp.dgRound2Message2s[i] = r2msg1
-	round.out <- r2msg1
+	round.out <- round.UseCodec(r2msg1)
 
 	// 1.
 	// generate Paillier public key E_i, private key and proof
@@ -49,9 +50,9 @@ func (round *round2) Start() *tss.Error {
 		preParams = &round.save.LocalPreParams
 	} else {
 		var err error
-		preParams, err = keygen.GeneratePreParams(round.SafePrimeGenTimeout())
+		preParams, err = keygen.GeneratePreParamsWithContext(round.Context(), round.SafePrimeGenTimeout(), round.GoroutineLimiter())
 		if err != nil {
-			return round.WrapError(errors.New("pre-params generation failed"), Pi)
+			return round.WrapError(fmt.Errorf("pre-params generation failed: %w", err), Pi)
 		}
 	}
 	round.save.LocalPreParams = *preParams
@@ -72,13 +73,13 @@ func (round *round2) Start() *tss.Error {
 
 	paillierPf := preParams.PaillierSK.Proof(Pi.KeyInt(), round.save.ECDSAPub)
 	r2msg2, err := NewDGRound2Message1(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(), round.temp.sessionID,
 		&preParams.PaillierSK.PublicKey, paillierPf, preParams.NTildei, preParams.H1i, preParams.H2i, dlnProof1, dlnProof2)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	round.temp.dgRound2Message1s[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.UseCodec(r2msg2)
 
 	// for this P: SAVE de-commitments, paillier keys for round 2
 	round.save.PaillierSK = preParams.PaillierSK
@@ -91,13 +92,13 @@ func (round *round2) Start() *tss.Error {
 
 func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
 	if round.ReSharingParams().IsNewCommittee() {
-		if _, ok := msg.Content().(*DGRound2Message1); ok {
-			return msg.IsBroadcast()
+		if content, ok := msg.Content().(*DGRound2Message1); ok {
+			return msg.IsBroadcast() && round.inSession(content)
 		}
 	}
 	if round.ReSharingParams().IsOldCommittee() {
-		if _, ok := msg.Content().(*DGRound2Message2); ok {
-			return msg.IsBroadcast()
+		if content, ok := msg.Content().(*DGRound2Message2); ok {
+			return msg.IsBroadcast() && round.inSession(content)
 		}
 	}
 	return false
@@ -24,35 +24,41 @@ func (round *round3) Start() *tss.Error {
 	if !round.ReSharingParams().IsOldCommittee() {
 		return nil
 	}
-	round.allOldOK()
+	// a party that is also in the new committee receives the shares of the rest of the old committee
+	if !round.ReSharingParams().IsNewCommittee() {
+		round.allOldOK()
+	}
 
 	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.oldIndex()
 
-	// 2. send share to Pj from the new committee
+	// 2. send share to Pj from the new committee; a party that is also in the new committee keeps its own
 	for j, Pj := range round.NewParties().IDs() {
 		share := round.temp.NewShares[j]
-		r3msg1 := NewDGRound3Message1(Pj, round.PartyID(), share)
-		round.temp.dgRound3Message1s[i] = r3msg1
-		round.out <- r3msg1
+		r3msg1 := NewDGRound3Message1(Pj, round.PartyID(), round.temp.sessionID, share)
+		if Pj.KeyInt().Cmp(Pi.KeyInt()) == 0 {
+			round.temp.dgRound3Message1s[i] = r3msg1
+			continue
+		}
+		round.out <- round.UseCodec(r3msg1)
 	}
 
 	vDeCmt := round.temp.VD
 	r3msg2 := NewDGRound3Message2(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(), round.temp.sessionID,
 		vDeCmt)
 	round.temp.dgRound3Message2s[i] = r3msg2
-	round.out <- r3msg2
+	round.out <- round.UseCodec(r3msg2)
 
 	return nil
 }
 
 func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*DGRound3Message1); ok {
-		return !msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound3Message1); ok {
+		return !msg.IsBroadcast() && round.inSession(content)
 	}
-	if _, ok := msg.Content().(*DGRound3Message2); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound3Message2); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
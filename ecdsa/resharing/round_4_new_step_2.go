@@ -37,7 +37,7 @@ func (round *round4) Start() *tss.Error {
 	}
 
 	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.newIndex()
 
 	// 1-3. verify paillier & dln proofs, store message pieces, ensure uniqueness of h1j, h2j
 	h1H2Map := make(map[string]struct{}, len(round.temp.dgRound2Message1s)*2)
@@ -64,32 +64,41 @@ func (round *round4) Start() *tss.Error {
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 		wg.Add(3)
-		go func(j int, msg tss.ParsedMessage, r2msg1 *DGRound2Message1) {
-			if ok, err := r2msg1.UnmarshalPaillierProof().Verify(paiPK.N, msg.GetFrom().KeyInt(), round.save.ECDSAPub); err != nil || !ok {
+		j, msg := j, msg
+		round.Go(func() {
+			ok, err := r2msg1.UnmarshalPaillierProof().Verify(paiPK.N, msg.GetFrom().KeyInt(), round.save.ECDSAPub, round.GoroutineLimiter())
+			round.ObserveProof(tss.ProofPaillier, err == nil && ok)
+			if err != nil || !ok {
 				paiProofCulprits[j] = msg.GetFrom()
 				common.Logger.Warnf("paillier verify failed for party %s", msg.GetFrom(), err)
 			}
 			wg.Done()
-		}(j, msg, r2msg1)
-		go func(j int, msg tss.ParsedMessage, r2msg1 *DGRound2Message1, H1j, H2j, NTildej *big.Int) {
-			if dlnProof1, err := r2msg1.UnmarshalDLNProof1(); err != nil || !dlnProof1.Verify(H1j, H2j, NTildej) {
+		})
+		round.Go(func() {
+			dlnProof1, err := r2msg1.UnmarshalDLNProof1()
+			ok := err == nil && dlnProof1.Verify(H1j, H2j, NTildej)
+			round.ObserveProof(tss.ProofDLN, ok)
+			if !ok {
 				dlnProof1FailCulprits[j] = msg.GetFrom()
 				common.Logger.Warnf("dln proof 1 verify failed for party %s", msg.GetFrom(), err)
 			}
 			wg.Done()
-		}(j, msg, r2msg1, H1j, H2j, NTildej)
-		go func(j int, msg tss.ParsedMessage, r2msg1 *DGRound2Message1, H1j, H2j, NTildej *big.Int) {
-			if dlnProof2, err := r2msg1.UnmarshalDLNProof2(); err != nil || !dlnProof2.Verify(H2j, H1j, NTildej) {
+		})
+		round.Go(func() {
+			dlnProof2, err := r2msg1.UnmarshalDLNProof2()
+			ok := err == nil && dlnProof2.Verify(H2j, H1j, NTildej)
+			round.ObserveProof(tss.ProofDLN, ok)
+			if !ok {
 				dlnProof2FailCulprits[j] = msg.GetFrom()
 				common.Logger.Warnf("dln proof 2 verify failed for party %s", msg.GetFrom(), err)
 			}
 			wg.Done()
-		}(j, msg, r2msg1, H1j, H2j, NTildej)
+		})
 	}
 	wg.Wait()
 	for _, culprit := range append(append(paiProofCulprits, dlnProof1FailCulprits...), dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("dln proof verification failed")), culprit)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j received in NewCommitteeStep1 here
@@ -107,7 +116,7 @@ func (round *round4) Start() *tss.Error {
 	newXi := big.NewInt(0)
 
 	// 5-9.
-	modQ := common.ModInt(tss.EC().Params().N)
+	modQ := common.ModInt(round.EC().Params().N)
 	vjc := make([][]*crypto.ECPoint, len(round.OldParties().IDs()))
 	for j := 0; j <= len(vjc)-1; j++ { // P1..P_t+1. Ps are indexed from 0 here
 		// 6-7.
@@ -123,7 +132,7 @@ func (round *round4) Start() *tss.Error {
 			// TODO collect culprits and return a list of them as per convention
 			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
 		}
-		vj, err := crypto.UnFlattenECPoints(tss.EC(), flatVs)
+		vj, err := crypto.UnFlattenECPoints(round.EC(), flatVs)
 		if err != nil {
 			return round.WrapError(err, round.Parties().IDs()[j])
 		}
@@ -136,7 +145,7 @@ func (round *round4) Start() *tss.Error {
 			ID:        round.PartyID().KeyInt(),
 			Share:     new(big.Int).SetBytes(r3msg1.Share),
 		}
-		if ok := sharej.Verify(round.NewThreshold(), vj); !ok {
+		if ok := sharej.Verify(round.EC(), round.NewThreshold(), vj); !ok {
 			// TODO collect culprits and return a list of them as per convention
 			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
 		}
@@ -190,17 +199,22 @@ func (round *round4) Start() *tss.Error {
 	round.temp.newKs = newKs
 	round.temp.newBigXjs = newBigXjs
 
-	// Send an "ACK" message to both committees to signal that we're ready to save our data
-	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi)
+	// Send an "ACK" message to both committees to signal that we're ready to save our data, with our public
+	// share so that both committees can confirm that the new shares reconstruct the public key
+	bigXi := crypto.ScalarBaseMult(round.EC(), new(big.Int).Mod(newXi, round.EC().Params().N))
+	if !bigXi.Equals(newBigXjs[i]) {
+		return round.WrapError(errors.New("g^xi does not match the public share derived from the commitments"), Pi)
+	}
+	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi, round.temp.sessionID, bigXi)
 	round.temp.dgRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.out <- round.UseCodec(r4msg)
 
 	return nil
 }
 
 func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*DGRound4Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound4Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
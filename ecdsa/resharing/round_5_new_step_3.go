@@ -8,7 +8,10 @@ package resharing
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -22,8 +25,12 @@ func (round *round5) Start() *tss.Error {
 	round.allOldOK()
 	round.allNewOK()
 
-	Pi := round.PartyID()
-	i := Pi.Index
+	// the new shares must reconstruct the public key before they are saved and the old shares are discarded
+	if err := round.checkNewPublicShares(); err != nil {
+		return err
+	}
+
+	i := round.newIndex()
 
 	if round.IsNewCommittee() {
 		// 21.
@@ -41,7 +48,9 @@ func (round *round5) Start() *tss.Error {
 			r2msg1 := msg.Content().(*DGRound2Message1)
 			round.save.PaillierPKs[j] = r2msg1.UnmarshalPaillierPK()
 		}
-	} else if round.IsOldCommittee() {
+	}
+	// the old share is discarded, also by a party that received a new one
+	if round.IsOldCommittee() {
 		round.input.Xi.SetInt64(0)
 	}
 
@@ -49,6 +58,41 @@ func (round *round5) Start() *tss.Error {
 	return nil
 }
 
+// checkNewPublicShares checks the public shares that the new committee sent with its round 4 ACKs. A member of the new
+// committee compares each one to the share that it derived from the commitments of the old committee. A member of the
+// old committee, which does not see those commitments, checks that they lie on one polynomial that interpolates to the
+// public key.
+func (round *round5) checkNewPublicShares() *tss.Error {
+	newIDs := round.NewParties().IDs()
+	bigXjs := make([]*crypto.ECPoint, len(newIDs))
+	var culprits []*tss.PartyID
+	for j, msg := range round.temp.dgRound4Messages {
+		bigXj, err := msg.Content().(*DGRound4Message).UnmarshalBigXi(round.EC())
+		if err != nil || (round.IsNewCommittee() && !bigXj.Equals(round.temp.newBigXjs[j])) {
+			culprits = append(culprits, newIDs[j])
+			continue
+		}
+		bigXjs[j] = bigXj
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("the public shares of the new committee do not match the commitments"), culprits...)
+	}
+	if round.IsNewCommittee() {
+		return nil // V_0 = y was checked in round 4
+	}
+	inconsistent, err := vss.CheckPublicShares(newIDs.Keys(), bigXjs, round.NewThreshold(), round.input.ECDSAPub)
+	if err != nil {
+		return round.WrapError(fmt.Errorf("the new committee cannot reconstruct the key, aborting: %v", err))
+	}
+	for _, j := range inconsistent {
+		culprits = append(culprits, newIDs[j])
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("the public shares of the new committee are not consistent with the key"), culprits...)
+	}
+	return nil
+}
+
 func (round *round5) CanAccept(msg tss.ParsedMessage) bool {
 	return false
 }
@@ -7,6 +7,8 @@
 package resharing
 
 import (
+	"bytes"
+
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -42,6 +44,11 @@ type (
 	round5 struct {
 		*round4
 	}
+
+	// sessionMessage is a message of any round, each of which carries the session ID
+	sessionMessage interface {
+		GetSessionId() []byte
+	}
 )
 
 var (
@@ -66,6 +73,12 @@ func (round *base) RoundNumber() int {
 	return round.number
 }
 
+// inSession reports whether a message carries the ID of this session, so that one relayed from another session is not
+// accepted
+func (round *base) inSession(content sessionMessage) bool {
+	return bytes.Equal(content.GetSessionId(), round.temp.sessionID)
+}
+
 // CanProceed is inherited by other rounds
 func (round *base) CanProceed() bool {
 	if !round.started {
@@ -110,6 +123,17 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 
 // ----- //
 
+// oldIndex returns the index of this party in the old committee. A party in both committees has an index in each, which
+// need not be the Index of its PartyID.
+func (round *base) oldIndex() int {
+	return indexOf(round.OldParties().IDs(), round.PartyID())
+}
+
+// newIndex returns the index of this party in the new committee; see oldIndex
+func (round *base) newIndex() int {
+	return indexOf(round.NewParties().IDs(), round.PartyID())
+}
+
 // `oldOK` tracks parties which have been verified by Update()
 func (round *base) resetOK() {
 	for j := range round.oldOK {
@@ -0,0 +1,43 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// MtAArtifacts are the raw outputs of a party's MtA exchanges, completed in round 3. Summed over the signers,
+// the k_i give the nonce k, the delta_i give k*gamma and the sigma_i give k*x, where x is the private key; R is
+// g^(k^-1). They are secret: whoever holds them and R can finish a signature with the party's share, so they must be
+// stored as carefully as the key data, and used for at most one signature.
+type MtAArtifacts struct {
+	KI, DeltaI, SigmaI *big.Int
+}
+
+// MtAArtifacts exports the outputs of the party's MtA exchanges, for integrations that persist them to split signing
+// into a pre-signing and an online phase. They are available once the party has finished round 3.
+func (p *LocalParty) MtAArtifacts() (*MtAArtifacts, error) {
+	var artifacts *MtAArtifacts
+	err := tss.BaseSnapshot(p, func(tss.Round) error {
+		if p.temp.mtaArtifacts == nil {
+			return errors.New("the party has not finished the MtA exchanges of round 3")
+		}
+		artifacts = &MtAArtifacts{
+			KI:     new(big.Int).Set(p.temp.mtaArtifacts.KI),
+			DeltaI: new(big.Int).Set(p.temp.mtaArtifacts.DeltaI),
+			SigmaI: new(big.Int).Set(p.temp.mtaArtifacts.SigmaI),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
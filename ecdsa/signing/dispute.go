@@ -0,0 +1,125 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// SessionView is the public part of a signing session's transcript from which R is derived: the commitments to
+// Gamma_j = gamma_j*G broadcast in round 1, the delta_j broadcast in round 3 and the de-commitments broadcast in round 4.
+// Each slice is indexed like Parties.
+type SessionView struct {
+	Curve           elliptic.Curve
+	Parties         tss.SortedPartyIDs
+	Round1Message2s []*SignRound1Message2
+	Round3Messages  []*SignRound3Message
+	Round4Messages  []*SignRound4Message
+}
+
+// SessionView exports the transcript of the session that the party took part in. It is complete once the party
+// has passed round 4.
+func (p *LocalParty) SessionView() (SessionView, error) {
+	partyCount := len(p.params.Parties().IDs())
+	view := SessionView{
+		Curve:           p.params.EC(),
+		Parties:         p.params.Parties().IDs(),
+		Round1Message2s: make([]*SignRound1Message2, partyCount),
+		Round3Messages:  make([]*SignRound3Message, partyCount),
+		Round4Messages:  make([]*SignRound4Message, partyCount),
+	}
+	err := tss.BaseSnapshot(p, func(tss.Round) error {
+		for j := 0; j < partyCount; j++ {
+			r1msg2, r3msg, r4msg := p.temp.signRound1Message2s[j], p.temp.signRound3Messages[j], p.temp.signRound4Messages[j]
+			if r1msg2 == nil || r3msg == nil || r4msg == nil {
+				return fmt.Errorf("the party holds no transcript of rounds 1 to 4 from %s", view.Parties[j])
+			}
+			view.Round1Message2s[j] = r1msg2.Content().(*SignRound1Message2)
+			view.Round3Messages[j] = r3msg.Content().(*SignRound3Message)
+			view.Round4Messages[j] = r4msg.Content().(*SignRound4Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return SessionView{}, err
+	}
+	return view, nil
+}
+
+// VerifyRConsistency recomputes R = (sum_j Gamma_j)*delta^-1 from the nonce contributions committed in the transcript
+// and checks that the signature was made with it. It lets an arbiter confirm that a signature was not forged with a
+// substituted R. A party whose de-commitment does not open its commitment is named as a culprit of round 5.
+func VerifyRConsistency(transcript SessionView, sig *SignatureData) error {
+	ec, Ps := transcript.Curve, transcript.Parties
+	if ec == nil || len(Ps) == 0 {
+		return errors.New("the transcript names no curve or parties")
+	}
+	if len(transcript.Round1Message2s) != len(Ps) || len(transcript.Round3Messages) != len(Ps) ||
+		len(transcript.Round4Messages) != len(Ps) {
+		return fmt.Errorf("the transcript does not hold one message per round from each of the %d parties", len(Ps))
+	}
+	modN := common.ModInt(ec.Params().N)
+
+	var bigR *crypto.ECPoint
+	deltaSum := big.NewInt(0)
+	for j, Pj := range Ps {
+		r1msg2, r3msg, r4msg := transcript.Round1Message2s[j], transcript.Round3Messages[j], transcript.Round4Messages[j]
+		if r1msg2 == nil || r3msg == nil || r4msg == nil || !r1msg2.ValidateBasic() || !r4msg.ValidateBasic() {
+			return tss.NewError(errors.New("the transcript holds a missing or malformed message"), TaskName, 5, nil, Pj)
+		}
+		cmtDeCmt := commitments.HashCommitDecommit{C: r1msg2.UnmarshalCommitment(), D: r4msg.UnmarshalDeCommitment()}
+		ok, bigGammaJ := cmtDeCmt.DeCommit()
+		if !ok || len(bigGammaJ) != 2 {
+			return tss.NewError(errors.New("the de-commitment of Gamma_j does not open its commitment"), TaskName, 5, nil, Pj)
+		}
+		bigGammaJPoint, err := crypto.NewECPoint(ec, bigGammaJ[0], bigGammaJ[1])
+		if err != nil {
+			return tss.NewError(fmt.Errorf("the committed Gamma_j is invalid: %v", err), TaskName, 5, nil, Pj)
+		}
+		if bigR == nil {
+			bigR = bigGammaJPoint
+		} else if bigR, err = bigR.Add(bigGammaJPoint); err != nil {
+			return tss.NewError(fmt.Errorf("bigR.Add(Gamma_j): %v", err), TaskName, 5, nil, Pj)
+		}
+		deltaSum = modN.Add(deltaSum, new(big.Int).SetBytes(r3msg.GetDeltaI()))
+	}
+	if deltaSum.Sign() == 0 {
+		return errors.New("the delta_j of the transcript sum to zero")
+	}
+	bigR = bigR.ScalarMult(modN.Inverse(deltaSum))
+
+	if sig == nil {
+		return errors.New("the signature data is nil")
+	}
+	checked := false
+	if sigR := sig.GetOneRoundData().GetBigR(); sigR != nil {
+		if new(big.Int).SetBytes(sigR.GetX()).Cmp(bigR.X()) != 0 || new(big.Int).SetBytes(sigR.GetY()).Cmp(bigR.Y()) != 0 {
+			return errors.New("the R of the one-round signature data does not derive from the committed nonces")
+		}
+		checked = true
+	}
+	if sigma := sig.GetSignature(); sigma != nil {
+		// the signature holds r = R.x, which FinalizeGetAndVerifyFinalSig does not reduce mod q
+		r := new(big.Int).SetBytes(sigma.GetR())
+		if r.Cmp(bigR.X()) != 0 && r.Cmp(new(big.Int).Mod(bigR.X(), ec.Params().N)) != 0 {
+			return errors.New("the r of the signature does not derive from the committed nonces")
+		}
+		checked = true
+	}
+	if !checked {
+		return errors.New("the signature data holds neither a signature nor R")
+	}
+	return nil
+}
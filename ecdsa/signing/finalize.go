@@ -9,9 +9,11 @@ package signing
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
@@ -34,10 +36,11 @@ const (
 // -----
 
 // FinalizeGetOurSigShare is called in one-round signing mode after the online rounds have finished to compute s_i.
-func FinalizeGetOurSigShare(state *SignatureData, msg *big.Int) (sI *big.Int) {
+// It does not check the expiry of the session; FinalizeGetOurSigShareUnexpired does.
+func FinalizeGetOurSigShare(ec elliptic.Curve, state *SignatureData, msg *big.Int) (sI *big.Int) {
 	data := state.GetOneRoundData()
 
-	N := tss.EC().Params().N
+	N := ec.Params().N
 	modN := common.ModInt(N)
 
 	kI, rSigmaI := new(big.Int).SetBytes(data.GetKI()), new(big.Int).SetBytes(data.GetRSigmaI())
@@ -45,6 +48,18 @@ func FinalizeGetOurSigShare(state *SignatureData, msg *big.Int) (sI *big.Int) {
 	return
 }
 
+// FinalizeGetOurSigShareUnexpired is FinalizeGetOurSigShare for a state that may have been made in a session with an
+// expiry: it refuses with ErrPreSignatureExpired to compute s_i once the expiry is past.
+func FinalizeGetOurSigShareUnexpired(ec elliptic.Curve, state *SignatureData, msg *big.Int) (*big.Int, error) {
+	if state.GetOneRoundData() == nil {
+		return nil, errors.New("the signature data holds no one-round data")
+	}
+	if PreSignatureExpired(state, time.Now()) {
+		return nil, ErrPreSignatureExpired
+	}
+	return FinalizeGetOurSigShare(ec, state, msg), nil
+}
+
 // ConvertBigIntToModNScalar converts a big.Int to a ModNScalar
 func ConvertBigIntToFieldVal(bi *big.Int) *secp256k1.FieldVal {
 	var fieldVal secp256k1.FieldVal
@@ -61,7 +76,9 @@ func ConvertBigIntToModNScalar(bi *big.Int) *secp256k1.ModNScalar {
 
 // FinalizeGetOurSigShare is called in one-round signing mode to build a final signature given others' s_i shares and a msg.
 // Note: each P in otherPs should correspond with that P's s_i at the same index in otherSIs.
+// The btcec signature that it also returns is nil unless the curve `ec` is secp256k1.
 func FinalizeGetAndVerifyFinalSig(
+	ec elliptic.Curve,
 	state *SignatureData,
 	pk *ecdsa.PublicKey,
 	msg *big.Int,
@@ -72,15 +89,18 @@ func FinalizeGetAndVerifyFinalSig(
 	if len(otherSIs) == 0 {
 		return nil, nil, FinalizeWrapError(errors.New("len(otherSIs) == 0"), ourP)
 	}
+	if PreSignatureExpired(state, time.Now()) {
+		return nil, nil, FinalizeWrapError(ErrPreSignatureExpired, ourP)
+	}
 	data := state.GetOneRoundData()
 	if data.GetT() != int32(len(otherSIs)) {
 		return nil, nil, FinalizeWrapError(errors.New("len(otherSIs) != T"), ourP)
 	}
 
-	N := tss.EC().Params().N
+	N := ec.Params().N
 	modN := common.ModInt(N)
 
-	bigR, err := crypto.NewECPoint(tss.EC(),
+	bigR, err := crypto.NewECPoint(ec,
 		new(big.Int).SetBytes(data.GetBigR().GetX()),
 		new(big.Int).SetBytes(data.GetBigR().GetY()))
 	if err != nil {
@@ -97,14 +117,14 @@ func FinalizeGetAndVerifyFinalSig(
 		}
 
 		// prep for identify aborts in phase 7
-		bigRBarJ, err := crypto.NewECPoint(tss.EC(),
+		bigRBarJ, err := crypto.NewECPoint(ec,
 			new(big.Int).SetBytes(bigRBarJBz.GetX()),
 			new(big.Int).SetBytes(bigRBarJBz.GetY()))
 		if err != nil {
 			culprits = append(culprits, Pj)
 			continue
 		}
-		bigSI, err := crypto.NewECPoint(tss.EC(),
+		bigSI, err := crypto.NewECPoint(ec,
 			new(big.Int).SetBytes(bigSJBz.GetX()),
 			new(big.Int).SetBytes(bigSJBz.GetY()))
 		if err != nil {
@@ -155,18 +175,24 @@ func FinalizeGetAndVerifyFinalSig(
 
 	// save the signature for final output
 	signature := new(common.ECSignature)
-	signature.R, signature.S = r.Bytes(), s.Bytes()
-	signature.Signature = append(r.Bytes(), s.Bytes()...)
+	// r and s are padded to the width of the curve order, e.g. 32 bytes for secp256k1
+	orderLen := common.ByteLen(N)
+	signature.R, signature.S = common.PadToLen(r, orderLen), common.PadToLen(s, orderLen)
+	signature.Signature = append(append([]byte(nil), signature.R...), signature.S...)
 	signature.SignatureRecovery = []byte{byte(recId)}
 	signature.M = msg.Bytes()
 	state.Signature = signature
 
-	mR := ConvertBigIntToModNScalar(r)
-	mS := ConvertBigIntToModNScalar(s)
-	btcecSig := btcecdsa.NewSignature(mR, mS)
-	nPubKey := btcec.NewPublicKey(ConvertBigIntToFieldVal(pk.X), ConvertBigIntToFieldVal(pk.Y))
-	if ok = btcecSig.Verify(msg.Bytes(), nPubKey); !ok {
-		return nil, nil, FinalizeWrapError(fmt.Errorf("signature verification 2 failed"), ourP)
+	// the btcec signature and its check are only for secp256k1; on another curve, e.g. P-256, there is none
+	var btcecSig *btcecdsa.Signature
+	if name, _ := tss.GetCurveName(ec); name == tss.Secp256k1 {
+		mR := ConvertBigIntToModNScalar(r)
+		mS := ConvertBigIntToModNScalar(s)
+		btcecSig = btcecdsa.NewSignature(mR, mS)
+		nPubKey := btcec.NewPublicKey(ConvertBigIntToFieldVal(pk.X), ConvertBigIntToFieldVal(pk.Y))
+		if ok = btcecSig.Verify(msg.Bytes(), nPubKey); !ok {
+			return nil, nil, FinalizeWrapError(fmt.Errorf("signature verification 2 failed"), ourP)
+		}
 	}
 
 	// SECURITY: to be safe the oneRoundData is no longer needed here and reuse of `r` can compromise the key
@@ -201,7 +227,7 @@ func (round *finalization) Start() *tss.Error {
 	// Identifiable Abort Type 7 triggered during Phase 6 (GG20)
 	if round.abortingT7 {
 		common.Logger.Infof("round 8: Abort Type 7 code path triggered")
-		q := tss.EC().Params().N
+		q := round.EC().Params().N
 		kIs := make([][]byte, len(Ps))
 		gMus := make([][]*crypto.ECPoint, len(Ps))
 		gNus := make([][]*crypto.ECPoint, len(Ps))
@@ -233,7 +259,7 @@ func (round *finalization) Start() *tss.Error {
 
 			// keep k_i and the g^sigma_i proof for later
 			kIs[j] = r7msg.GetKI()
-			if gSigmaIPfs[j], err = r7msg.UnmarshalSigmaIProof(); err != nil {
+			if gSigmaIPfs[j], err = r7msg.UnmarshalSigmaIProof(round.EC()); err != nil {
 				culprits = append(culprits, Pj)
 				continue
 			}
@@ -271,7 +297,7 @@ func (round *finalization) Start() *tss.Error {
 				if k == j {
 					continue
 				}
-				gMus[j][k] = crypto.ScalarBaseMult(tss.EC(), mu.Mod(mu, q))
+				gMus[j][k] = crypto.ScalarBaseMult(round.EC(), mu.Mod(mu, q))
 			}
 		}
 		bigR := round.temp.rI
@@ -301,8 +327,8 @@ func (round *finalization) Start() *tss.Error {
 				gSigmaI, _ = gSigmaI.Add(gMuIJ)
 				gSigmaI, _ = gSigmaI.Add(gNuJI)
 			}
-			bigSI, _ := crypto.NewECPointFromProtobuf(round.temp.BigSJ[P.Id])
-			if !gSigmaIPfs[i].VerifySigmaI(tss.EC(), gSigmaI, bigR, bigSI) {
+			bigSI, _ := crypto.NewECPointFromProtobuf(round.EC(), round.temp.BigSJ[P.Id])
+			if !gSigmaIPfs[i].VerifySigmaI(round.EC(), gSigmaI, bigR, bigSI) {
 				culprits = append(culprits, P)
 				continue
 			}
@@ -333,14 +359,19 @@ func (round *finalization) Start() *tss.Error {
 	}
 
 	pk := &ecdsa.PublicKey{
-		Curve: tss.EC(),
+		Curve: round.EC(),
 		X:     round.key.ECDSAPub.X(),
 		Y:     round.key.ECDSAPub.Y(),
 	}
-	data, _, err := FinalizeGetAndVerifyFinalSig(round.data, pk, round.temp.m, round.PartyID(), ourSI, otherSIs)
+	data, _, err := FinalizeGetAndVerifyFinalSig(round.EC(), round.data, pk, round.temp.m, round.PartyID(), ourSI, otherSIs)
 	if err != nil {
 		return err
 	}
+	transcriptHash, tErr := round.transcriptHash()
+	if tErr != nil {
+		return round.WrapError(tErr)
+	}
+	data.TranscriptHash = transcriptHash
 	round.data = data
 	round.end <- round.data
 	return nil
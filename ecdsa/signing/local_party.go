@@ -7,9 +7,11 @@
 package signing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
@@ -74,7 +76,8 @@ type (
 		pI2JIs []*mta.ProofBobWC
 
 		// round 3
-		lI *big.Int
+		lI           *big.Int
+		mtaArtifacts *MtAArtifacts
 
 		// round 5
 		bigGammaJs  []*crypto.ECPoint
@@ -88,9 +91,16 @@ type (
 		rI,
 		TI *crypto.ECPoint
 		r7AbortData SignRound7Message_AbortData
+
+		// see SessionID
+		sessionID []byte
 	}
 )
 
+const (
+	sessionIDDomain = "tss-lib ecdsa signing session"
+)
+
 // Constructs a new ECDSA signing party. Note: msg may be left nil for one-round signing mode to only do the pre-processing steps.
 func NewLocalParty(
 	msg *big.Int,
@@ -131,9 +141,24 @@ func NewLocalParty(
 	p.temp.bigGammaJs = make([]*crypto.ECPoint, partyCount)
 	p.temp.r5AbortData.AlphaIJ = make([][]byte, partyCount)
 	p.temp.r5AbortData.BetaJI = make([][]byte, partyCount)
+	p.temp.sessionID = SessionID(params, key)
 	return p
 }
 
+// SessionID derives the canonical ID of a signing session from the committee, threshold, curve, session expiry and
+// session nonce (see tss.Parameters.SessionID) and from the public key of the keygen output, so that all parties of the
+// session agree on it. Every message carries it, and a message with another ID is dropped, so that a message relayed
+// from another session is not accepted. The message digest is left out, as round 1 commits to it and a party signing
+// another message is to be caught there (see MessageCommitment); sessions with the same key and committee thus share
+// an ID unless they set different session nonces or expiries, so a retry sets a fresh nonce.
+func SessionID(params *tss.Parameters, key keygen.LocalPartySaveData) []byte {
+	parts := [][]byte{[]byte(sessionIDDomain), params.SessionID(nil)}
+	if pub := key.ECDSAPub; pub != nil {
+		parts = append(parts, pub.X().Bytes(), pub.Y().Bytes())
+	}
+	return common.SHA512_256(parts...)
+}
+
 // Constructs a new ECDSA signing party for one-round signing. The final SignatureData struct will be a partial struct containing only the data for a final signing round (see the readme).
 func NewLocalPartyWithOneRoundSign(
 	params *tss.Parameters,
@@ -166,7 +191,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -182,6 +207,11 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
 			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
 	}
+	// a stalled session is not completed on stale assumptions; the sender is not at fault
+	if p.params.SessionExpired(time.Now()) {
+		return false, p.WrapError(fmt.Errorf("session %x expired at %s; rejected the message from %s",
+			p.params.SessionID(p.temp.m), p.params.SessionExpiry(), msg.GetFrom()))
+	}
 	return true, nil
 }
 
@@ -192,8 +222,15 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	}
 	fromPIdx := msg.GetFrom().Index
 
+	// a message of another session, e.g. one replayed by a relay, is dropped and does not take the sender's slot; it
+	// need not come from the sender, so the sender is not blamed
+	if content, ok := msg.Content().(sessionMessage); ok && !bytes.Equal(content.GetSessionId(), p.temp.sessionID) {
+		common.Logger.Warnf("dropped a message of another session: %v", msg)
+		return false, nil
+	}
+
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// this does not handle message replays within a session. we expect the caller to apply spoofing protection.
 	switch msg.Content().(type) {
 	case *SignRound1Message1:
 		p.temp.signRound1Message1s[fromPIdx] = msg
@@ -225,3 +262,39 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
+
+// Zeroize overwrites the party's secret signing state with zeros: its nonce shares, its MtA outputs and shares and the
+// abort data that holds them. The key data is not wiped, as it is shared with the caller; see
+// keygen.LocalPartySaveData.Zeroize. In one-round mode the state sent to the end channel holds the nonce shares too,
+// so the party must not be zeroized before that state is finalized. The party must not be used afterwards.
+func (p *LocalParty) Zeroize() {
+	_ = tss.BaseSnapshot(p, func(tss.Round) error {
+		p.temp.Zeroize()
+		return nil
+	})
+}
+
+// Zeroize overwrites the secrets of the temp data with zeros and clears every field
+func (temp *localTempData) Zeroize() {
+	for _, x := range []*big.Int{temp.wI, temp.rAKI, temp.deltaI, temp.sigmaI, temp.gammaI, temp.lI, temp.sI} {
+		common.ZeroInt(x)
+	}
+	for _, xs := range [][]*big.Int{temp.betas, temp.vJIs} {
+		for _, x := range xs {
+			common.ZeroInt(x)
+		}
+	}
+	if a := temp.mtaArtifacts; a != nil {
+		common.ZeroInt(a.KI)
+		common.ZeroInt(a.DeltaI)
+		common.ZeroInt(a.SigmaI)
+	}
+	common.ZeroBytes(temp.SignatureData_OneRoundData.KI, temp.SignatureData_OneRoundData.RSigmaI)
+	common.ZeroBytes(temp.r5AbortData.KI, temp.r5AbortData.GammaI)
+	common.ZeroBytes(temp.r5AbortData.AlphaIJ...)
+	common.ZeroBytes(temp.r5AbortData.BetaJI...)
+	common.ZeroBytes(temp.r7AbortData.KI, temp.r7AbortData.KRandI)
+	common.ZeroBytes(temp.r7AbortData.MuIJ...)
+	common.ZeroBytes(temp.r7AbortData.MuRandIJ...)
+	*temp = localTempData{}
+}
@@ -7,21 +7,39 @@
 package signing
 
 import (
+	"bytes"
+	"compress/flate"
 	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"reflect"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
+	cmts "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/mta"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	eddsaKeygen "github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	eddsaSigning "github.com/ordinox/thorchain-tss-lib/eddsa/signing"
 	"github.com/ordinox/thorchain-tss-lib/test"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -139,8 +157,1611 @@ signing:
 				t.Log("ECDSA signing test done.")
 				// END ECDSA verify
 
+				// BEGIN check R against the transcript
+				view, err := parties[1].SessionView()
+				if assert.NoError(t, err) {
+					assert.NoError(t, VerifyRConsistency(view, data), "R must derive from the committed nonces")
+
+					tampered := &SignatureData{Signature: &common.ECSignature{
+						R: new(big.Int).Add(r, big.NewInt(1)).Bytes(),
+						S: data.GetSignature().GetS(),
+					}}
+					assert.Error(t, VerifyRConsistency(view, tampered), "a substituted R must be detected")
+
+					view.Round4Messages[1] = view.Round4Messages[0]
+					err := VerifyRConsistency(view, data)
+					if assert.IsType(t, &tss.Error{}, err) {
+						assert.Equal(t, []*tss.PartyID{signPIDs[1]}, err.(*tss.Error).Culprits())
+					}
+				}
+				// END check R against the transcript
+
 				break signing
 			}
 		}
 	}
 }
+
+// TestE2EConcurrentSessionsOnDifferentCurves runs an ECDSA session on secp256k1 and an EdDSA session on ed25519
+// side by side. Each session carries its curve in its own Parameters, so neither may touch the default tss.EC().
+// Run it with -race.
+func TestE2EConcurrentSessionsOnDifferentCurves(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures for two unrelated keys
+	ecdsaKeys, ecdsaPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(threshold+1, testParticipants)
+	assert.NoError(t, err, "should load ecdsa keygen fixtures")
+	eddsaKeys, eddsaPIDs, err := eddsaKeygen.LoadKeygenTestFixturesRandomSet(threshold+1, testParticipants)
+	assert.NoError(t, err, "should load eddsa keygen fixtures")
+
+	msg := common.GetRandomPrimeInt(256)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// PHASE: ECDSA signing on secp256k1
+	go func() {
+		defer wg.Done()
+		p2pCtx := tss.NewPeerContext(ecdsaPIDs)
+		parties := make([]tss.Party, 0, len(ecdsaPIDs))
+		errCh := make(chan *tss.Error, len(ecdsaPIDs))
+		outCh := make(chan tss.Message, len(ecdsaPIDs))
+		endCh := make(chan *SignatureData, len(ecdsaPIDs))
+		for i := 0; i < len(ecdsaPIDs); i++ {
+			params := tss.NewParameters(p2pCtx, ecdsaPIDs[i], len(ecdsaPIDs), threshold)
+			params.SetCurve(btcec.S256())
+			// also exercise the rounds' concurrent proof checks under -race
+			params.SetConcurrentVerification(true)
+			parties = append(parties, NewLocalParty(msg, params, ecdsaKeys[i], outCh, endCh))
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var data *SignatureData
+			for range ecdsaPIDs {
+				data = <-endCh
+			}
+			pk := ecdsa.PublicKey{
+				Curve: btcec.S256(),
+				X:     ecdsaKeys[0].ECDSAPub.X(),
+				Y:     ecdsaKeys[0].ECDSAPub.Y(),
+			}
+			r, s := new(big.Int).SetBytes(data.GetSignature().GetR()), new(big.Int).SetBytes(data.GetSignature().GetS())
+			assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+		}()
+		if err := runSession(parties, outCh, errCh, done); err != nil {
+			assert.Fail(t, err.Error())
+		}
+	}()
+
+	// PHASE: EdDSA signing on ed25519
+	go func() {
+		defer wg.Done()
+		p2pCtx := tss.NewPeerContext(eddsaPIDs)
+		parties := make([]tss.Party, 0, len(eddsaPIDs))
+		errCh := make(chan *tss.Error, len(eddsaPIDs))
+		outCh := make(chan tss.Message, len(eddsaPIDs))
+		endCh := make(chan *eddsaSigning.SignatureData, len(eddsaPIDs))
+		for i := 0; i < len(eddsaPIDs); i++ {
+			params := tss.NewParameters(p2pCtx, eddsaPIDs[i], len(eddsaPIDs), threshold)
+			params.SetCurve(edwards.Edwards())
+			parties = append(parties, eddsaSigning.NewLocalParty(msg, params, eddsaKeys[i], outCh, endCh))
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var data *eddsaSigning.SignatureData
+			for range eddsaPIDs {
+				data = <-endCh
+			}
+			pk := edwards.PublicKey{
+				Curve: edwards.Edwards(),
+				X:     eddsaKeys[0].EDDSAPub.X(),
+				Y:     eddsaKeys[0].EDDSAPub.Y(),
+			}
+			sig, err := edwards.ParseSignature(data.GetSignature().GetSignature())
+			if assert.NoError(t, err) {
+				assert.True(t, edwards.Verify(&pk, msg.Bytes(), sig.R, sig.S), "eddsa verify must pass")
+			}
+		}()
+		if err := runSessionWith(parties, outCh, errCh, done, parsedPartyUpdater); err != nil {
+			assert.Fail(t, err.Error())
+		}
+	}()
+
+	wg.Wait()
+}
+
+// runSession starts the parties and routes their messages until `done` is closed or a party fails
+func runSession(parties []tss.Party, outCh <-chan tss.Message, errCh chan *tss.Error, done <-chan struct{}) *tss.Error {
+	return runSessionWith(parties, outCh, errCh, done, test.SharedPartyUpdater)
+}
+
+// runSessionWith is runSession, delivering each message to a party with `updater`
+func runSessionWith(
+	parties []tss.Party, outCh <-chan tss.Message, errCh chan *tss.Error, done <-chan struct{},
+	updater func(tss.Party, tss.Message, chan<- *tss.Error),
+) *tss.Error {
+	for _, P := range parties {
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	for {
+		select {
+		case err := <-errCh:
+			return err
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// parsedPartyUpdater delivers a message to a party as it was sent, without encoding it to wire bytes. The keygen and
+// EdDSA sessions of these tests use it: their messages share protobuf names with those of other packages linked into
+// this binary, so their wire bytes may parse into the other package's types. A transport carrying the messages of
+// several protocols tells them apart, as multicurve does.
+func parsedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	if party.PartyID() == msg.GetFrom() {
+		return
+	}
+	if _, err := party.Update(msg.(tss.ParsedMessage)); err != nil {
+		errCh <- err
+	}
+}
+
+func TestE2EPreSignatureCommitment(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: one-round signing without a message
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(nil, params, keys[i], outCh, endCh))
+	}
+	states := make(map[*tss.PartyID]*SignatureData, len(signPIDs))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+	for _, P := range parties {
+		states[P.PartyID()] = &P.(*LocalParty).data
+	}
+
+	// PHASE: commit to the presignature; every party commits to the same one
+	pre, err := NewPreSignature(tss.EC(), states[signPIDs[0]])
+	if !assert.NoError(t, err) {
+		return
+	}
+	commitment := pre.Commitment()
+	for _, Pj := range signPIDs[1:] {
+		preJ, err := NewPreSignature(tss.EC(), states[Pj])
+		if assert.NoError(t, err) {
+			assert.Equal(t, commitment, preJ.Commitment())
+		}
+	}
+
+	// PHASE: finalize with a message
+	msg := common.GetRandomPrimeInt(256)
+	sIs := make(map[*tss.PartyID]*big.Int, len(signPIDs))
+	for Pj, state := range states {
+		sIs[Pj] = FinalizeGetOurSigShare(tss.EC(), state, msg)
+	}
+	ourP := signPIDs[0]
+	otherSIs := make(map[*tss.PartyID]*big.Int, len(signPIDs)-1)
+	for Pj, sJ := range sIs {
+		if Pj != ourP {
+			otherSIs[Pj] = sJ
+		}
+	}
+	pk := &ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	data, _, tErr := FinalizeGetAndVerifyFinalSig(tss.EC(), states[ourP], pk, msg, ourP, sIs[ourP], otherSIs)
+	if !assert.Nil(t, tErr) {
+		return
+	}
+
+	// PHASE: tie the signature to the commitment
+	assert.NoError(t, VerifyPreSignatureCommitment(commitment, pre, data.GetSignature()))
+
+	other := *pre
+	other.T++
+	assert.Error(t, VerifyPreSignatureCommitment(commitment, &other, data.GetSignature()), "another presignature must not match")
+
+	tampered := &common.ECSignature{R: new(big.Int).Add(pre.BigR.X(), big.NewInt(1)).Bytes(), S: data.GetSignature().GetS()}
+	assert.Error(t, VerifyPreSignatureCommitment(commitment, pre, tampered), "a signature made with another R must not match")
+}
+
+func TestE2EPresign(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// PHASE: presign
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewPresignParty(params, keys[i], outCh, endCh))
+	}
+	presigns := make(map[*tss.PartyID]*PresignData, len(signPIDs))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+	for _, P := range parties {
+		pd, err := NewPresignData(tss.EC(), &P.(*LocalParty).data)
+		if !assert.NoError(t, err) {
+			return
+		}
+		presigns[P.PartyID()] = pd
+	}
+	ourP := signPIDs[0]
+	_, tErr := presigns[ourP].Finalize(nil, ourP, nil)
+	if assert.NotNil(t, tErr) {
+		assert.ErrorIs(t, tErr.Cause(), ErrPresignDataNotSigned)
+	}
+
+	// PHASE: sign online
+	msg := common.GetRandomPrimeInt(256)
+	otherSIs := make(map[*tss.PartyID]*big.Int, len(signPIDs)-1)
+	for Pj, pd := range presigns {
+		sJ, err := pd.Sign(msg)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if Pj != ourP {
+			otherSIs[Pj] = sJ
+		}
+		assert.Empty(t, pd.state.GetOneRoundData().GetKI(), "the nonce share must be wiped")
+		_, err = pd.Sign(new(big.Int).Add(msg, big.NewInt(1)))
+		assert.ErrorIs(t, err, ErrPresignDataConsumed, "a second message must not be signed")
+	}
+	pk := &ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	data, tErr := presigns[ourP].Finalize(pk, ourP, otherSIs)
+	if !assert.Nil(t, tErr) {
+		return
+	}
+	sig := data.GetSignature()
+	ok := ecdsa.Verify(pk, msg.Bytes(), new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS()))
+	assert.True(t, ok, "ecdsa verify must pass")
+
+	_, tErr = presigns[ourP].Finalize(pk, ourP, otherSIs)
+	if assert.NotNil(t, tErr) {
+		assert.ErrorIs(t, tErr.Cause(), ErrPresignDataConsumed)
+	}
+	_, err = presigns[ourP].PreSignature()
+	assert.ErrorIs(t, err, ErrPresignDataConsumed)
+}
+
+func TestE2EZeroize(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+
+	P := parties[0].(*LocalParty)
+	words, bzs := tempSecrets(reflect.ValueOf(&P.temp).Elem(), map[string]bool{
+		"wI": true, "rAKI": true, "deltaI": true, "sigmaI": true, "gammaI": true, "lI": true, "sI": true,
+		"betas": true, "vJIs": true, "mtaArtifacts": true, "KI": true, "RSigmaI": true, "GammaI": true,
+		"AlphaIJ": true, "BetaJI": true, "KRandI": true, "MuIJ": true, "MuRandIJ": true,
+	}, false)
+	if !assert.NotEmpty(t, words) || !assert.NotEmpty(t, bzs) {
+		return
+	}
+	P.Zeroize()
+	for _, ws := range words {
+		for _, w := range ws {
+			assert.Zero(t, w, "no secret word should remain")
+		}
+	}
+	for _, bz := range bzs {
+		assert.Equal(t, make([]byte, len(bz)), bz, "no secret byte should remain")
+	}
+	assert.True(t, reflect.ValueOf(&P.temp).Elem().IsZero(), "every field should be cleared")
+	assert.NotZero(t, keys[0].Xi.Sign(), "the key data should not be wiped")
+}
+
+// tempSecrets returns the words backing each *big.Int and the bytes of each byte slice that v holds in the fields named
+// in `names`, including those of embedded and nested structs. It reads unexported fields too.
+func tempSecrets(v reflect.Value, names map[string]bool, secret bool) (words [][]big.Word, bzs [][]byte) {
+	merge := func(ws [][]big.Word, bs [][]byte) {
+		words, bzs = append(words, ws...), append(bzs, bs...)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || !secret {
+			return
+		}
+		if v.Type() == reflect.TypeOf((*big.Int)(nil)) {
+			x := (*big.Int)(unsafe.Pointer(v.Pointer()))
+			ws := x.Bits()
+			words = append(words, ws[:cap(ws)])
+			return
+		}
+		merge(tempSecrets(v.Elem(), names, secret))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			merge(tempSecrets(v.Field(i), names, secret || names[v.Type().Field(i).Name]))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if secret && 0 < v.Len() {
+				bzs = append(bzs, v.Bytes())
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			merge(tempSecrets(v.Index(i), names, secret))
+		}
+	}
+	return
+}
+
+func TestE2ETranscriptHash(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	sign := func(msg *big.Int) [][]byte {
+		p2pCtx := tss.NewPeerContext(signPIDs)
+		parties := make([]tss.Party, 0, len(signPIDs))
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *SignatureData, len(signPIDs))
+		for i := 0; i < len(signPIDs); i++ {
+			params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+		}
+		hashes := make([][]byte, 0, len(signPIDs))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range signPIDs {
+				hashes = append(hashes, (<-endCh).GetTranscriptHash())
+			}
+		}()
+		if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		return hashes
+	}
+
+	first := sign(common.GetRandomPrimeInt(256))
+	if !assert.Len(t, first, len(signPIDs)) || !assert.NotEmpty(t, first[0]) {
+		return
+	}
+	for _, hash := range first[1:] {
+		assert.Equal(t, first[0], hash, "every party should compute the same transcript hash")
+	}
+	second := sign(common.GetRandomPrimeInt(256))
+	if assert.Len(t, second, len(signPIDs)) {
+		assert.NotEqual(t, first[0], second[0], "sessions signing different messages should have different transcripts")
+	}
+}
+
+func TestE2ESigShareEncryption(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	escrowKeys, _, err := keygen.LoadKeygenTestFixtures(testParticipants+1, testParticipants)
+	if !assert.NoError(t, err, "should load the escrow's key") {
+		return
+	}
+	escrowSK := escrowKeys[0].PaillierSK
+
+	// PHASE: one-round signing without a message
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(nil, params, keys[i], outCh, endCh))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+	states := make(map[*tss.PartyID]*SignatureData, len(signPIDs))
+	for _, P := range parties {
+		states[P.PartyID()] = &P.(*LocalParty).data
+	}
+	pre, err := NewPreSignature(tss.EC(), states[signPIDs[0]])
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// PHASE: the last party hands an encryption of its share to the others instead of the share
+	msg := common.GetRandomPrimeInt(256)
+	ourP, escrowedP := signPIDs[0], signPIDs[len(signPIDs)-1]
+	ve, err := EncryptSigShare(tss.EC(), states[escrowedP], msg, &escrowSK.PublicKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, VerifySigShareEncryption(pre, escrowedP.Id, msg, &escrowSK.PublicKey, ve))
+	assert.Error(t, VerifySigShareEncryption(pre, ourP.Id, msg, &escrowSK.PublicKey, ve), "it is not the share of another party")
+	assert.Error(t, VerifySigShareEncryption(pre, escrowedP.Id, new(big.Int).Add(msg, big.NewInt(1)), &escrowSK.PublicKey, ve), "it is not the share for another message")
+
+	// PHASE: the escrow settles the exchange with the decrypted share
+	sJ, err := DecryptSigShare(pre, escrowedP.Id, msg, escrowSK, ve)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, FinalizeGetOurSigShare(tss.EC(), states[escrowedP], msg).Cmp(sJ))
+	otherSIs := make(map[*tss.PartyID]*big.Int, len(signPIDs)-1)
+	for Pj, state := range states {
+		switch Pj {
+		case ourP:
+		case escrowedP:
+			otherSIs[Pj] = sJ
+		default:
+			otherSIs[Pj] = FinalizeGetOurSigShare(tss.EC(), state, msg)
+		}
+	}
+	pk := &ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	ourSI := FinalizeGetOurSigShare(tss.EC(), states[ourP], msg)
+	_, _, tErr := FinalizeGetAndVerifyFinalSig(tss.EC(), states[ourP], pk, msg, ourP, ourSI, otherSIs)
+	assert.Nil(t, tErr, "the decrypted share should complete the signature")
+}
+
+func TestE2EParticipationProof(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	var sig *common.ECSignature
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			sig = (<-endCh).GetSignature()
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+
+	// PHASE: each signer attests to the signature with its share
+	proof := make(ParticipationProof, 0, len(keys))
+	for _, key := range keys {
+		att, err := NewParticipationAttestation(tss.EC(), key, sig)
+		if !assert.NoError(t, err) {
+			return
+		}
+		proof = append(proof, att)
+	}
+	committeeKs, committeeXj := keys[0].Ks, keys[0].BigXj
+	assert.NoError(t, VerifyParticipationProof(tss.EC(), committeeKs, committeeXj, testThreshold, sig, proof))
+
+	// too few signers
+	assert.Error(t, VerifyParticipationProof(tss.EC(), committeeKs, committeeXj, testThreshold, sig, proof[1:]))
+	// an attestation of another signature
+	other := &common.ECSignature{R: sig.GetR(), S: sig.GetS(), M: new(big.Int).Add(msg, big.NewInt(1)).Bytes()}
+	assert.Error(t, VerifyParticipationProof(tss.EC(), committeeKs, committeeXj, testThreshold, other, proof))
+
+	// an unauthorized signer, with a share of its own
+	rogue := keys[0]
+	rogue.Xi = common.GetRandomPositiveInt(tss.EC().Params().N)
+	rogue.ShareID = common.GetRandomPositiveInt(tss.EC().Params().N)
+	rogue.Ks = append([]*big.Int{rogue.ShareID}, keys[0].Ks[1:]...)
+	rogue.BigXj = append([]*crypto.ECPoint{crypto.ScalarBaseMult(tss.EC(), rogue.Xi)}, keys[0].BigXj[1:]...)
+	rogueAtt, err := NewParticipationAttestation(tss.EC(), rogue, sig)
+	if assert.NoError(t, err) {
+		withRogue := append(ParticipationProof{rogueAtt}, proof[1:]...)
+		err = VerifyParticipationProof(tss.EC(), committeeKs, committeeXj, testThreshold, sig, withRogue)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "not a member of the committee")
+		}
+		// .. or one that claims the place of a member
+		rogueAtt.Ks = keys[0].ShareID
+		assert.Error(t, VerifyParticipationProof(tss.EC(), committeeKs, committeeXj, testThreshold, sig, withRogue))
+	}
+}
+
+func TestE2ESessionExpiry(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	expiry := time.Now().Add(time.Hour)
+
+	// PHASE: one-round signing within the expiry succeeds
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		params.SetSessionExpiry(expiry)
+		parties = append(parties, NewLocalParty(nil, params, keys[i], outCh, endCh))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+	states := make(map[*tss.PartyID]*SignatureData, len(signPIDs))
+	for _, P := range parties {
+		states[P.PartyID()] = &P.(*LocalParty).data
+		assert.Equal(t, expiry.Unix(), states[P.PartyID()].GetOneRoundData().GetExpiry())
+	}
+	ourP := signPIDs[0]
+	assert.False(t, PreSignatureExpired(states[ourP], time.Now()))
+	assert.True(t, PreSignatureExpired(states[ourP], expiry.Add(time.Second)))
+
+	// PHASE: the presignatures are finalized within the expiry, and not past it
+	msg := common.GetRandomPrimeInt(256)
+	otherSIs := make(map[*tss.PartyID]*big.Int, len(signPIDs)-1)
+	for Pj, state := range states {
+		if Pj != ourP {
+			otherSIs[Pj] = FinalizeGetOurSigShare(tss.EC(), state, msg)
+		}
+	}
+	ourSI := FinalizeGetOurSigShare(tss.EC(), states[ourP], msg)
+	pk := &ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+	stale := proto.Clone(states[ourP]).(*SignatureData)
+	stale.OneRoundData.Expiry = time.Now().Add(-time.Minute).Unix()
+	_, _, tErr := FinalizeGetAndVerifyFinalSig(tss.EC(), stale, pk, msg, ourP, ourSI, otherSIs)
+	if assert.NotNil(t, tErr) {
+		assert.ErrorIs(t, tErr.Cause(), ErrPreSignatureExpired)
+	}
+	_, err = FinalizeGetOurSigShareUnexpired(tss.EC(), stale, msg)
+	assert.ErrorIs(t, err, ErrPreSignatureExpired, "s_i must not be released past the expiry")
+	_, err = EncryptSigShare(tss.EC(), stale, msg, &keys[1].PaillierSK.PublicKey)
+	assert.ErrorIs(t, err, ErrPreSignatureExpired, "s_i must not be escrowed past the expiry")
+	sI, err := FinalizeGetOurSigShareUnexpired(tss.EC(), states[ourP], msg)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, ourSI.Cmp(sI))
+	}
+	_, _, tErr = FinalizeGetAndVerifyFinalSig(tss.EC(), states[ourP], pk, msg, ourP, ourSI, otherSIs)
+	assert.Nil(t, tErr)
+
+	// PHASE: a session past its expiry does not start, and rejects messages naming the session and the sender
+	params := tss.NewParameters(p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	params.SetSessionExpiry(time.Now().Add(-time.Minute))
+	P := NewLocalParty(msg, params, keys[0], outCh, endCh)
+	if err := P.Start(); assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "expired")
+	}
+	r1msg2 := NewSignRound1Message2(signPIDs[1], SessionID(params, keys[0]), cmts.HashCommitment(big.NewInt(1)), MessageCommitment(msg), params.SessionExpiry().Unix())
+	_, err2 := P.ValidateMessage(r1msg2)
+	if assert.NotNil(t, err2) {
+		assert.Contains(t, err2.Error(), fmt.Sprintf("%x", params.SessionID(msg)))
+		assert.Contains(t, err2.Error(), signPIDs[1].String())
+		assert.Empty(t, err2.Culprits(), "the sender is not at fault")
+	}
+}
+
+func TestE2EMtAArtifacts(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	_, err = parties[0].(*LocalParty).MtAArtifacts()
+	assert.Error(t, err, "the artifacts are not available before round 3")
+
+	sigs := make(chan *SignatureData, len(signPIDs))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			sigs <- <-endCh
+		}
+	}()
+	if err := runSession(parties, outCh, errCh, done); !assert.Nil(t, err) {
+		return
+	}
+	sig := <-sigs
+
+	modN := common.ModInt(tss.EC().Params().N)
+	k, kx := big.NewInt(0), big.NewInt(0)
+	for _, P := range parties {
+		artifacts, err := P.(*LocalParty).MtAArtifacts()
+		if !assert.NoError(t, err) {
+			return
+		}
+		k, kx = modN.Add(k, artifacts.KI), modN.Add(kx, artifacts.SigmaI)
+	}
+	// R = g^(k^-1)
+	bigR := crypto.ScalarBaseMult(tss.EC(), modN.Inverse(k))
+	assert.Equal(t, 0, bigR.X().Cmp(new(big.Int).SetBytes(sig.GetSignature().GetR())), "the k_i must sum to the nonce of R")
+	// g^(k*x) = Y^k
+	assert.True(t, crypto.ScalarBaseMult(tss.EC(), kx).Equals(keys[0].ECDSAPub.ScalarMult(k)), "the sigma_i must sum to k*x")
+}
+
+// flateCodec compresses the protobuf encoding of messages with DEFLATE
+type flateCodec struct {
+	marshalled int32
+}
+
+func (c *flateCodec) Name() string {
+	return "protobuf+deflate"
+}
+
+func (c *flateCodec) Marshal(msg proto.Message) ([]byte, error) {
+	atomic.AddInt32(&c.marshalled, 1)
+	bz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(bz); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *flateCodec) Unmarshal(bz []byte, into proto.Message) error {
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(bz)))
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(raw, into)
+}
+
+func TestE2ECodec(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	codec := new(flateCodec)
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		assert.Equal(t, params.SessionID(msg), params.With(tss.WithCodec(tss.ProtoCodec{})).SessionID(msg))
+		params.SetCodec(codec)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	assert.NotEqual(t, tss.NewParameters(p2pCtx, signPIDs[0], len(signPIDs), testThreshold).SessionID(msg),
+		parties[0].(*LocalParty).params.SessionID(msg), "the codec must be part of the session ID")
+
+	// the transport only moves bytes, which each party parses with the session's codec
+	var protobufRejected int32
+	updater := func(P tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+		bz, _, err := msg.WireBytes()
+		if err != nil {
+			errCh <- P.WrapError(err)
+			return
+		}
+		if _, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast()); err != nil {
+			atomic.AddInt32(&protobufRejected, 1)
+		}
+		if _, err := P.UpdateFromBytes(bz, msg.GetFrom(), msg.IsBroadcast()); err != nil {
+			errCh <- err
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var data *SignatureData
+		for range signPIDs {
+			data = <-endCh
+		}
+		pk := ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		r, s := new(big.Int).SetBytes(data.GetSignature().GetR()), new(big.Int).SetBytes(data.GetSignature().GetS())
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}()
+	if !assert.Nil(t, runSessionWith(parties, outCh, errCh, done, updater)) {
+		return
+	}
+	assert.NotZero(t, atomic.LoadInt32(&codec.marshalled), "the messages must be encoded with the codec")
+	assert.Equal(t, atomic.LoadInt32(&codec.marshalled), atomic.LoadInt32(&protobufRejected), "no message must be plain protobuf")
+}
+
+func TestE2EPaillierRotation(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	// the pre-params of a party of another committee stand in for freshly generated ones
+	fresh, _, err := keygen.LoadKeygenTestFixtures(testParticipants+3, testParticipants+1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	// PHASE: the first signer rotates its Paillier key and the others apply the rotation
+	rotated, rotation, err := keygen.RotatePaillierKey(keys[0], signPIDs[0], fresh[0].LocalPreParams)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, keys[0].Xi, rotated.Xi, "the signing share must be unchanged")
+	assert.NotEqual(t, keys[0].PaillierSK.N, rotated.PaillierSK.N)
+	_, err = rotated.ApplyPaillierRotation(rotation)
+	assert.Error(t, err, "a party must not apply its own rotation")
+
+	forged := *rotation
+	forged.DLNProof1, forged.DLNProof2 = rotation.DLNProof2, rotation.DLNProof1
+	_, err = keys[1].ApplyPaillierRotation(&forged)
+	assert.Error(t, err, "a rotation with bad proofs must be rejected")
+
+	// anyone can prove pre-params of their own in the name of the rotating party, but only that party can sign them
+	impostor, err := fresh[1].LocalPreParams.PublicPreParams(signPIDs[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	substituted := *rotation
+	substituted.PublicPreParams = *impostor
+	substituted.PaillierProof = fresh[1].PaillierSK.Proof(rotation.ShareID, keys[0].ECDSAPub)
+	_, err = keys[1].ApplyPaillierRotation(&substituted)
+	assert.Error(t, err, "a rotation to keys that the rotating party did not sign must be rejected")
+	substituted.Signature = nil
+	_, err = keys[1].ApplyPaillierRotation(&substituted)
+	assert.Error(t, err, "an unsigned rotation must be rejected")
+
+	keys[0] = rotated
+	for j := 1; j < len(keys); j++ {
+		if keys[j], err = keys[j].ApplyPaillierRotation(rotation); !assert.NoError(t, err) {
+			return
+		}
+		for k, kj := range keys[j].Ks {
+			if kj.Cmp(rotation.ShareID) == 0 {
+				assert.Equal(t, rotation.PaillierPK.N, keys[j].PaillierPKs[k].N)
+			}
+		}
+		_, err = keys[j].ApplyPaillierRotation(rotation)
+		assert.Error(t, err, "a rotation must not be applied again once its keys are replaced")
+	}
+
+	// PHASE: signing with the rotated key
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var data *SignatureData
+		for range signPIDs {
+			data = <-endCh
+		}
+		pk := ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		r, s := new(big.Int).SetBytes(data.GetSignature().GetR()), new(big.Int).SetBytes(data.GetSignature().GetS())
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}()
+	assert.Nil(t, runSession(parties, outCh, errCh, done))
+}
+
+func TestE2ETrustedSetup(t *testing.T) {
+	setUp("info")
+	// the pre-params of a party of another committee stand in for the dealer's
+	dealer, _, err := keygen.LoadKeygenTestFixtures(testParticipants+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	setup, err := keygen.UNSAFE_NewTrustedSetup(dealer[0].LocalPreParams)
+	if !assert.NoError(t, err) || !assert.True(t, setup.Verify()) {
+		return
+	}
+	forged := *setup
+	forged.DLNProof1, forged.DLNProof2 = setup.DLNProof2, setup.DLNProof1
+	assert.False(t, forged.Verify(), "a setup with bad proofs must be rejected")
+
+	// PHASE: keygen, each party with its own Paillier key and the shared NTilde
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	kgParties := make([]tss.Party, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	kgEndCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		preParams := setup.PreParams(fixtures[i].PaillierSK)
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		kgParties = append(kgParties, keygen.NewLocalParty(params, outCh, kgEndCh, preParams))
+	}
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range pIDs {
+			key := <-kgEndCh
+			index, err := key.OriginalIndex()
+			if !assert.NoError(t, err) {
+				return
+			}
+			keys[index] = key
+		}
+	}()
+	if !assert.Nil(t, runSessionWith(kgParties, outCh, errCh, done, parsedPartyUpdater)) {
+		return
+	}
+	for _, key := range keys {
+		for j := range key.NTildej {
+			assert.Equal(t, setup.NTilde, key.NTildej[j])
+		}
+	}
+
+	// PHASE: the first party rotates its Paillier key, keeping the shared NTilde, h1, h2
+	fresh, _, err := keygen.LoadKeygenTestFixtures(testThreshold+2, testThreshold+1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	rotated, rotation, err := keygen.RotatePaillierKey(keys[0], pIDs[0], setup.PreParams(fresh[0].PaillierSK))
+	if !assert.NoError(t, err) {
+		return
+	}
+	keys[0] = rotated
+	for j := 1; j < len(keys); j++ {
+		if keys[j], err = keys[j].ApplyPaillierRotation(rotation); !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	// PHASE: signing with the keys
+	parties := make([]tss.Party, 0, len(pIDs))
+	endCh := make(chan *SignatureData, len(pIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		var data *SignatureData
+		for range pIDs {
+			data = <-endCh
+		}
+		pk := ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		r, s := new(big.Int).SetBytes(data.GetSignature().GetR()), new(big.Int).SetBytes(data.GetSignature().GetS())
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}()
+	assert.Nil(t, runSession(parties, outCh, errCh, done))
+}
+
+func TestE2EMessageStream(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	// the messages received by the first party, as a stateless signer would store them
+	var mtx sync.Mutex
+	var stream []tss.ParsedMessage
+	updater := func(party tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+		if party == parties[0] {
+			mtx.Lock()
+			stream = append(stream, msg.(tss.ParsedMessage))
+			mtx.Unlock()
+		}
+		parsedPartyUpdater(party, msg, errCh)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if !assert.Nil(t, runSessionWith(parties, outCh, errCh, done, updater)) {
+		return
+	}
+	params := tss.NewParameters(p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.NoError(t, tss.ValidateMessageStream(stream, params, ExpectedMessages()), "the stream of a signing should be complete")
+	assert.Error(t, tss.ValidateMessageStream(stream[1:], params, ExpectedMessages()))
+}
+
+func TestE2EMessageMismatchCulprit(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// the party at index `liar` signs a different message than the others
+	const liar = 2
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		m := msg
+		if i == liar {
+			m = new(big.Int).Add(msg, big.NewInt(1))
+		}
+		parties = append(parties, NewLocalParty(m, params, keys[i], outCh, endCh))
+	}
+
+	updater := test.SharedPartyUpdater
+	for _, P := range parties {
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	errs := make(map[int]*tss.Error, len(parties))
+	timeout := time.After(2 * time.Minute)
+	for len(errs) < len(parties) {
+		select {
+		case err := <-errCh:
+			errs[err.Victim().Index] = err
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			assert.FailNow(t, "a session signing different messages must not finish")
+		case <-timeout:
+			assert.FailNow(t, "every party should have caught the mismatch", "%d of %d did", len(errs), len(parties))
+		}
+	}
+	for i, err := range errs {
+		assert.Equal(t, 2, err.Round(), "the mismatch must be caught at the start of round 2")
+		assert.Contains(t, err.Error(), "different message")
+		if i == liar {
+			assert.Len(t, err.Culprits(), len(parties)-1)
+		} else {
+			assert.Equal(t, []*tss.PartyID{signPIDs[liar]}, err.Culprits())
+		}
+	}
+}
+
+func TestMessageCommitment(t *testing.T) {
+	digest := common.SHA512_256([]byte("message"))
+	reversed := make([]byte, len(digest))
+	for i, b := range digest {
+		reversed[len(digest)-1-i] = b
+	}
+	m := new(big.Int).SetBytes(digest)
+
+	assert.Equal(t, MessageCommitment(m), MessageCommitment(new(big.Int).SetBytes(digest)))
+	assert.NotEqual(t, MessageCommitment(m), MessageCommitment(new(big.Int).SetBytes(reversed)), "a digest read with the wrong endianness must be told apart")
+	assert.NotEqual(t, MessageCommitment(nil), MessageCommitment(big.NewInt(0)), "no message must be told apart from a zero digest")
+}
+
+func TestPreSigPoolSingleUse(t *testing.T) {
+	// stand-in sessions: each state holds a distinct R, as a real one-round session's does
+	var generated int64
+	generate := func() (*SignatureData, error) {
+		n := atomic.AddInt64(&generated, 1)
+		time.Sleep(time.Millisecond)
+		bigR := crypto.ScalarBaseMult(tss.EC(), big.NewInt(n))
+		return &SignatureData{OneRoundData: &SignatureData_OneRoundData{BigR: bigR.ToProtobufPoint()}}, nil
+	}
+	pool, err := NewPreSigPool(generate, 4, 8)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer pool.Close()
+
+	const workers, perWorker = 16, 20
+	acquired := make(chan *SignatureData, workers*perWorker)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for got := 0; got < perWorker; {
+				state, err := pool.Acquire()
+				if errors.Is(err, ErrPreSigPoolEmpty) {
+					time.Sleep(time.Millisecond)
+					continue
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				acquired <- state
+				got++
+			}
+		}()
+	}
+	wg.Wait()
+	close(acquired)
+
+	seen := make(map[*SignatureData]bool)
+	seenR := make(map[string]bool)
+	for state := range acquired {
+		r := state.GetOneRoundData().GetBigR()
+		key := string(r.GetX()) + "," + string(r.GetY())
+		assert.False(t, seen[state] || seenR[key], "a presignature was handed out twice")
+		seen[state], seenR[key] = true, true
+	}
+	assert.Len(t, seen, workers*perWorker)
+	assert.NoError(t, pool.Err())
+
+	pool.Close()
+	_, err = pool.Acquire()
+	assert.Equal(t, ErrPreSigPoolClosed, err)
+}
+
+func TestPreSigPoolRejectsRepeatedPreSignature(t *testing.T) {
+	state := &SignatureData{OneRoundData: &SignatureData_OneRoundData{
+		BigR: crypto.ScalarBaseMult(tss.EC(), big.NewInt(7)).ToProtobufPoint(),
+	}}
+	pool, err := NewPreSigPool(func() (*SignatureData, error) { return state, nil }, 1, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer pool.Close()
+	assert.Eventually(t, func() bool { return pool.Err() != nil }, time.Minute, time.Millisecond)
+	assert.Contains(t, pool.Err().Error(), "already pooled")
+	assert.Equal(t, 1, pool.Len())
+
+	got, err := pool.Acquire()
+	assert.NoError(t, err)
+	assert.Equal(t, state, got)
+	_, err = pool.Acquire()
+	assert.True(t, errors.Is(err, ErrPreSigPoolEmpty), "the repeated presignature must not be handed out again")
+}
+
+func TestPreSigPoolForgetsOldNoncePoints(t *testing.T) {
+	pool := &PreSigPool{capacity: 2, pooled: make(map[string]int64)}
+	newState := func(k, expiry int64) *SignatureData {
+		return &SignatureData{OneRoundData: &SignatureData_OneRoundData{
+			BigR:   crypto.ScalarBaseMult(tss.EC(), big.NewInt(k)).ToProtobufPoint(),
+			Expiry: expiry,
+		}}
+	}
+	expired := newState(1, time.Now().Add(-time.Minute).Unix())
+	assert.NoError(t, pool.add(expired))
+	assert.NoError(t, pool.add(newState(2, 0)))
+	assert.NoError(t, pool.add(expired), "the nonce point of an expired presignature should be forgotten")
+
+	for k := int64(3); k < 100; k++ {
+		assert.NoError(t, pool.add(newState(k, 0)))
+	}
+	assert.Len(t, pool.pooled, preSigPoolHistory*pool.capacity)
+	assert.Len(t, pool.pooledOrder, preSigPoolHistory*pool.capacity)
+	assert.Error(t, pool.add(newState(99, 0)), "a recent nonce point should still be remembered")
+}
+
+func TestE2EPreSigPool(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	// every party's pool runs the committee's sessions in the same order: the k-th call of each party's generator
+	// returns its state of the k-th session, which the first of them to ask for runs for the whole committee
+	var mtx sync.Mutex
+	var sessions [][]*SignatureData
+	session := func(k, i int) (*SignatureData, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		for len(sessions) <= k {
+			p2pCtx := tss.NewPeerContext(signPIDs)
+			parties := make([]tss.Party, 0, len(signPIDs))
+			errCh := make(chan *tss.Error, len(signPIDs))
+			outCh := make(chan tss.Message, len(signPIDs))
+			endCh := make(chan *SignatureData, len(signPIDs))
+			for j := range signPIDs {
+				params := tss.NewParameters(p2pCtx, signPIDs[j], len(signPIDs), testThreshold)
+				parties = append(parties, NewLocalParty(nil, params, keys[j], outCh, endCh))
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for range signPIDs {
+					<-endCh
+				}
+			}()
+			if err := runSession(parties, outCh, errCh, done); err != nil {
+				return nil, err
+			}
+			states := make([]*SignatureData, len(parties))
+			for j, P := range parties {
+				states[j] = &P.(*LocalParty).data
+			}
+			sessions = append(sessions, states)
+		}
+		return sessions[k][i], nil
+	}
+	const lowWater, capacity = 1, 2
+	pools := make([]*PreSigPool, len(signPIDs))
+	for i := range signPIDs {
+		i, k := i, 0
+		pools[i], err = NewPreSigPool(func() (*SignatureData, error) {
+			state, err := session(k, i)
+			k++
+			return state, err
+		}, lowWater, capacity)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer pools[i].Close()
+	}
+	full := func() bool {
+		for _, pool := range pools {
+			if pool.Len() < capacity {
+				return false
+			}
+		}
+		return true
+	}
+	if !assert.Eventually(t, full, 5*time.Minute, 10*time.Millisecond, "the pools should fill") {
+		return
+	}
+
+	// each party acquires its presignature of the same session and finalizes it with the message
+	for round := 0; round < capacity; round++ {
+		msg := common.GetRandomPrimeInt(256)
+		sIs := make(map[*tss.PartyID]*big.Int, len(signPIDs))
+		states := make(map[*tss.PartyID]*SignatureData, len(signPIDs))
+		for i, pool := range pools {
+			state, err := pool.Acquire()
+			if !assert.NoError(t, err) {
+				return
+			}
+			states[signPIDs[i]] = state
+			sIs[signPIDs[i]] = FinalizeGetOurSigShare(tss.EC(), state, msg)
+		}
+		ourP := signPIDs[0]
+		otherSIs := make(map[*tss.PartyID]*big.Int, len(signPIDs)-1)
+		for Pj, sJ := range sIs {
+			if Pj != ourP {
+				otherSIs[Pj] = sJ
+			}
+		}
+		pk := &ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		_, _, tErr := FinalizeGetAndVerifyFinalSig(tss.EC(), states[ourP], pk, msg, ourP, sIs[ourP], otherSIs)
+		assert.Nil(t, tErr, "a pooled presignature should finalize to a valid signature")
+	}
+
+	// taking the pools below their low-water mark refilled them in the background
+	assert.Eventually(t, full, 5*time.Minute, 10*time.Millisecond, "the pools should refill")
+	for _, pool := range pools {
+		assert.NoError(t, pool.Err())
+	}
+}
+
+// newTestPaillierKey makes a Paillier key with a modulus of exactly `bits` bits from two primes that are not safe
+// primes, which are much quicker to find; the proofs of keygen and signing do not rely on the primes being safe
+func newTestPaillierKey(bits int) (*paillier.PrivateKey, error) {
+	for {
+		P, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		Q, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		N := new(big.Int).Mul(P, Q)
+		if P.Cmp(Q) == 0 || N.BitLen() != bits {
+			continue
+		}
+		PMinus1, QMinus1 := new(big.Int).Sub(P, big.NewInt(1)), new(big.Int).Sub(Q, big.NewInt(1))
+		phiN := new(big.Int).Mul(PMinus1, QMinus1)
+		lambdaN := new(big.Int).Div(phiN, new(big.Int).GCD(nil, nil, PMinus1, QMinus1))
+		return &paillier.PrivateKey{PublicKey: paillier.PublicKey{N: N}, LambdaN: lambdaN, PhiN: phiN}, nil
+	}
+}
+
+func TestE2EKeygenAndSign3072BitPaillier(t *testing.T) {
+	setUp("info")
+	const paillierModulusBits = 3072
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	// the fixtures' NTilde and a 3072-bit Paillier key for each party
+	preParams := make([]keygen.LocalPreParams, len(pIDs))
+	for i := range pIDs {
+		preParams[i] = fixtures[i].LocalPreParams
+		if preParams[i].PaillierSK, err = newTestPaillierKey(paillierModulusBits); !assert.NoError(t, err) {
+			return
+		}
+	}
+	keys := keygenAndSign(t, tss.EC(), pIDs, preParams)
+	for _, key := range keys {
+		for j := range key.PaillierPKs {
+			assert.Equal(t, paillierModulusBits, key.PaillierPKs[j].N.BitLen())
+		}
+	}
+}
+
+// TestE2EKeygenAndSignP256 runs keygen and signing over NIST P-256 with the curve set on the parameters only, leaving
+// the package default curve alone, and checks the signature with crypto/ecdsa
+func TestE2EKeygenAndSignP256(t *testing.T) {
+	setUp("info")
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	preParams := make([]keygen.LocalPreParams, len(pIDs))
+	for i := range pIDs {
+		preParams[i] = fixtures[i].LocalPreParams
+	}
+	keys := keygenAndSign(t, elliptic.P256(), pIDs, preParams)
+	for _, key := range keys {
+		assert.Equal(t, elliptic.P256(), key.ECDSAPub.Curve())
+	}
+	assert.NotEqual(t, elliptic.P256(), tss.EC(), "the default curve should be untouched")
+}
+
+// keygenAndSign runs keygen over the curve `ec` with the given pre-params and then signs a message with the keys,
+// checking the signature with crypto/ecdsa. It returns the keys.
+func keygenAndSign(t *testing.T, ec elliptic.Curve, pIDs tss.SortedPartyIDs, preParams []keygen.LocalPreParams) []keygen.LocalPartySaveData {
+	// PHASE: keygen
+	p2pCtx := tss.NewPeerContext(pIDs)
+	kgParties := make([]tss.Party, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	kgEndCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetCurve(ec)
+		kgParties = append(kgParties, keygen.NewLocalParty(params, outCh, kgEndCh, preParams[i]))
+	}
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range pIDs {
+			key := <-kgEndCh
+			index, err := key.OriginalIndex()
+			if !assert.NoError(t, err) {
+				return
+			}
+			keys[index] = key
+		}
+	}()
+	if !assert.Nil(t, runSessionWith(kgParties, outCh, errCh, done, parsedPartyUpdater)) {
+		return keys
+	}
+
+	// PHASE: signing with the keys
+	parties := make([]tss.Party, 0, len(pIDs))
+	endCh := make(chan *SignatureData, len(pIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetCurve(ec)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		var data *SignatureData
+		for range pIDs {
+			data = <-endCh
+		}
+		pk := ecdsa.PublicKey{Curve: ec, X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		r, s := new(big.Int).SetBytes(data.GetSignature().GetR()), new(big.Int).SetBytes(data.GetSignature().GetS())
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+	}()
+	assert.Nil(t, runSession(parties, outCh, errCh, done))
+	return keys
+}
+
+func TestStartRejectsDuplicateEvaluationPoint(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	params := tss.NewParameters(tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	P := NewLocalParty(big.NewInt(42), params, keys[0], nil, nil).(*LocalParty)
+	// two signers evaluating the polynomial at the same point leave the Lagrange coefficients undefined
+	P.keys.Ks[1] = P.keys.Ks[0]
+
+	err2 := P.Start()
+	if assert.NotNil(t, err2) {
+		assert.Contains(t, err2.Error(), "the signing subset is malformed")
+	}
+}
+
+func TestSessionIDRejectsReplay(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	newParty := func(i int, msg *big.Int, nonce string) *LocalParty {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold).With(tss.WithSessionNonce([]byte(nonce)))
+		return NewLocalParty(msg, params, keys[i], nil, nil).(*LocalParty)
+	}
+
+	// all parties of a session derive the same ID, even one signing another message, which round 2 catches; a retry
+	// under a fresh nonce gives another ID
+	current := newParty(0, big.NewInt(200), "attempt 2")
+	for i := 1; i < len(signPIDs); i++ {
+		assert.Equal(t, current.temp.sessionID, newParty(i, big.NewInt(200), "attempt 2").temp.sessionID)
+	}
+	assert.Equal(t, current.temp.sessionID, newParty(1, big.NewInt(199), "attempt 2").temp.sessionID)
+	prior := newParty(0, big.NewInt(200), "attempt 1")
+	assert.NotEqual(t, current.temp.sessionID, prior.temp.sessionID)
+
+	// a round 4 message of the prior attempt is neither accepted nor stored
+	sender := signPIDs[1]
+	deCommit := cmts.NewHashCommitment(big.NewInt(1), big.NewInt(2)).D
+	replayed := NewSignRound4Message(sender, prior.temp.sessionID, deCommit)
+	fresh := NewSignRound4Message(sender, current.temp.sessionID, deCommit)
+
+	r4 := &round4{&round3{&round2{&round1{&base{temp: &current.temp}}}}}
+	assert.False(t, r4.CanAccept(replayed), "a message of another session must not be accepted")
+	assert.True(t, r4.CanAccept(fresh))
+
+	ok, tErr := current.StoreMessage(replayed)
+	assert.False(t, ok)
+	assert.Nil(t, tErr, "the sender must not be blamed for a replay")
+	assert.Nil(t, current.temp.signRound4Messages[sender.Index])
+	ok, tErr = current.StoreMessage(fresh)
+	assert.True(t, ok)
+	assert.Nil(t, tErr)
+}
+
+// pdlWSlackCommittee holds a PDL w/ slack proof from each party of a committee, as received in round 5
+type pdlWSlackCommittee struct {
+	pIDs       tss.SortedPartyIDs
+	statements []zkp.PDLwSlackStatement
+	proofs     []zkp.PDLwSlackProof
+}
+
+func newPDLwSlackCommittee(size int) (*pdlWSlackCommittee, error) {
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(size)
+	if err != nil {
+		return nil, err
+	}
+	ec := tss.EC()
+	bigR := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+	c := &pdlWSlackCommittee{
+		pIDs:       pIDs,
+		statements: make([]zkp.PDLwSlackStatement, size),
+		proofs:     make([]zkp.PDLwSlackProof, size),
+	}
+	for j, key := range keys {
+		kJ := common.GetRandomPositiveInt(ec.Params().N)
+		cKJ, rKJ, err := key.PaillierSK.EncryptAndReturnRandomness(kJ)
+		if err != nil {
+			return nil, err
+		}
+		c.statements[j] = zkp.PDLwSlackStatement{
+			PK:         &key.PaillierSK.PublicKey,
+			CipherText: cKJ,
+			Q:          bigR.ScalarMult(kJ),
+			G:          bigR,
+			H1:         key.H1i,
+			H2:         key.H2i,
+			NTilde:     key.NTildei,
+		}
+		c.proofs[j] = zkp.NewPDLwSlackProof(zkp.PDLwSlackWitness{SK: key.PaillierSK, X: kJ, R: rKJ}, c.statements[j])
+	}
+	return c, nil
+}
+
+// verify checks the proofs of the committee as party 0 does in round 6
+func (c *pdlWSlackCommittee) verify(concurrent bool) []*tss.PartyID {
+	params := tss.NewParameters(tss.NewPeerContext(c.pIDs), c.pIDs[0], len(c.pIDs), len(c.pIDs)-1)
+	params.SetConcurrentVerification(concurrent)
+	culprits, _ := params.VerifyPeers(func(j int, Pj *tss.PartyID) error {
+		if !c.proofs[j].Verify(c.statements[j]) {
+			return fmt.Errorf("failed to verify the PDL w/ slack proof of P %d", j)
+		}
+		return nil
+	})
+	return culprits
+}
+
+func TestConcurrentVerificationCulprits(t *testing.T) {
+	c, err := newPDLwSlackCommittee(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	// P 3 replays the proof of P 4
+	c.proofs[3] = c.proofs[4]
+	for _, concurrent := range []bool{false, true} {
+		assert.Equal(t, []*tss.PartyID{c.pIDs[3]}, c.verify(concurrent), "concurrent=%v", concurrent)
+	}
+}
+
+func TestAliceEndErrorCulprit(t *testing.T) {
+	Pj := tss.NewPartyID("2", "P[2]", big.NewInt(2))
+	err := aliceEndError("Alice_end_wc", Pj, &mta.VerifyError{Proof: "ProofBobWC", Check: mta.ProofCheckModNSquare, Err: errors.New("step 7")})
+	var faultErr *tss.FaultError
+	if assert.True(t, errors.As(err, &faultErr)) {
+		assert.Equal(t, tss.FaultBadProof, faultErr.Fault)
+	}
+	var verifyErr *mta.VerifyError
+	if assert.True(t, errors.As(err, &verifyErr)) {
+		assert.Equal(t, Pj, verifyErr.Culprit)
+		assert.Equal(t, mta.ProofCheckModNSquare, verifyErr.Check)
+	}
+	assert.Contains(t, err.Error(), Pj.String())
+
+	// an error other than a failed proof, e.g. of decryption, names the peer but is not a bad proof
+	err = aliceEndError("Alice_end", Pj, errors.New("the message is too large or < 0"))
+	assert.False(t, errors.As(err, &faultErr))
+	assert.Contains(t, err.Error(), Pj.String())
+}
+
+func BenchmarkRound6ProofVerification(b *testing.B) {
+	c, err := newPDLwSlackCommittee(10)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, concurrent := range []bool{false, true} {
+		b.Run(fmt.Sprintf("concurrent=%v", concurrent), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if culprits := c.verify(concurrent); len(culprits) != 0 {
+					b.Fatalf("unexpected culprits: %v", culprits)
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateSignatureSize(t *testing.T) {
+	assert.Equal(t, 64, EstimateSignatureSize(EncodingCompact, true))
+	assert.Equal(t, 65, EstimateSignatureSize(EncodingCompactRecoverable, true))
+	assert.Equal(t, 71, EstimateSignatureSize(EncodingDER, true))
+	assert.Equal(t, 72, EstimateSignatureSize(EncodingDER, false))
+	assert.Zero(t, EstimateSignatureSize(Encoding(-1), true))
+
+	for _, ec := range []elliptic.Curve{tss.EC(), elliptic.P256(), elliptic.P521()} {
+		N := ec.Params().N
+		halfN := new(big.Int).Rsh(N, 1)
+		for i := 0; i < 64; i++ {
+			r, s := common.GetRandomPositiveInt(N), common.GetRandomPositiveInt(halfN)
+			if i == 0 {
+				// the longest low-S signature
+				r, s = new(big.Int).Sub(N, big.NewInt(1)), halfN
+			}
+			sig := &common.ECSignature{R: r.Bytes(), S: s.Bytes(), SignatureRecovery: []byte{1}}
+			for _, encoding := range []Encoding{EncodingCompact, EncodingCompactRecoverable} {
+				bz, err := SerializeSignature(ec, sig, encoding)
+				if assert.NoError(t, err) {
+					assert.Len(t, bz, EstimateSignatureSizeOf(ec, encoding, true))
+				}
+			}
+			der, err := SerializeSignature(ec, sig, EncodingDER)
+			if !assert.NoError(t, err) {
+				continue
+			}
+			assert.LessOrEqual(t, len(der), EstimateSignatureSizeOf(ec, EncodingDER, true))
+			if i == 0 {
+				assert.Len(t, der, EstimateSignatureSizeOf(ec, EncodingDER, true))
+			}
+			if ec == tss.EC() {
+				btcecSig := btcecdsa.NewSignature(ConvertBigIntToModNScalar(r), ConvertBigIntToModNScalar(s))
+				assert.Equal(t, btcecSig.Serialize(), der, "DER must match btcec's encoding")
+			}
+
+			// a high-S signature; N - 1 is the longest
+			highS := &common.ECSignature{R: r.Bytes(), S: new(big.Int).Sub(N, s).Bytes()}
+			if i == 0 {
+				highS.S = r.Bytes()
+			}
+			der, err = SerializeSignature(ec, highS, EncodingDER)
+			if assert.NoError(t, err) {
+				assert.LessOrEqual(t, len(der), EstimateSignatureSizeOf(ec, EncodingDER, false))
+				if i == 0 {
+					assert.Len(t, der, EstimateSignatureSizeOf(ec, EncodingDER, false))
+				}
+			}
+		}
+	}
+
+	_, err := SerializeSignature(tss.EC(), &common.ECSignature{R: []byte{1}, S: []byte{1}}, EncodingCompactRecoverable)
+	assert.Error(t, err, "the recovery id is required")
+}
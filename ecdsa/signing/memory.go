@@ -0,0 +1,77 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/mta"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// EstimateMemory estimates the peak heap memory in bytes that a signing party with these parameters and key holds, so
+// that a scheduler can admit a session only if it fits. It builds the structures of the session from values of the
+// size that they have for the curve and the key's Paillier modulus and NTilde, and counts them with common.MemSize:
+// the party's key data, and for each signer its part of the key data and its messages, held both as received and
+// decoded, and the MtA state kept for it. It grows linearly with the number of signers. The estimate is not a
+// bound: garbage that is not yet collected comes on top of it.
+func EstimateMemory(params *tss.Parameters, key keygen.LocalPartySaveData) int {
+	n, ec := params.PartyCount(), params.EC()
+	pBits, nTildeBits := 2048, 2048
+	if key.PaillierSK != nil && key.PaillierSK.N != nil {
+		pBits = key.PaillierSK.N.BitLen()
+	}
+	if key.NTildei != nil {
+		nTildeBits = key.NTildei.BitLen()
+	}
+	qBits := ec.Params().N.BitLen()
+	N, NSq, NTilde := common.IntOfBitLen(pBits), common.IntOfBitLen(2*pBits), common.IntOfBitLen(nTildeBits)
+	q, q3, q7 := common.IntOfBitLen(qBits), common.IntOfBitLen(3*qBits), common.IntOfBitLen(7*qBits)
+	q3NTilde, hash := common.IntOfBitLen(3*qBits+nTildeBits), common.IntOfBitLen(cmt.HashLength)
+	coord := common.IntOfBitLen(ec.Params().BitSize)
+	point := crypto.NewECPointNoCurveCheck(ec, coord, coord)
+
+	// the proofs, with each value at the bound of its range in GG18Spec (9) Figs. 9 to 11 and GG20 Fig. 14
+	rangeProof := &mta.RangeProofAlice{Z: NTilde, U: NSq, W: NTilde, S: N, S1: q3, S2: q3NTilde}
+	proofBob := &mta.ProofBob{
+		Z: NTilde, ZPrm: NTilde, T: NTilde, V: NSq, W: NTilde, S: N,
+		S1: q3, S2: q3NTilde, T1: q7, T2: q3NTilde,
+	}
+	proofBobWC := &mta.ProofBobWC{ProofBob: proofBob, U: point}
+	tProof := &zkp.TProof{Alpha: point, T: q, U: q}
+	stProof := &zkp.STProof{Alpha: point, Beta: point, T: q, U: q}
+	pdlProof := &zkp.PDLwSlackProof{Z: NTilde, U1: point, U2: NSq, U3: NTilde, S1: q3, S2: N, S3: q3NTilde}
+	deCommitment := cmt.HashDeCommitment{hash, coord, coord}
+
+	// the messages of a signer. The party keeps its own broadcasts and the last of its round 1 messages along with
+	// those of its peers, but not the round 2 messages that it sends.
+	from, sessionID := params.PartyID(), make([]byte, 32)
+	kept := common.MemSize(
+		NewSignRound1Message1(from, from, sessionID, NSq, rangeProof),
+		NewSignRound1Message2(from, sessionID, hash, MessageCommitment(q), 0),
+		NewSignRound3Message(from, sessionID, q, point, tProof),
+		NewSignRound4Message(from, sessionID, deCommitment),
+		NewSignRound5Message(from, sessionID, point, pdlProof),
+		NewSignRound6MessageSuccess(from, sessionID, point, stProof),
+		NewSignRound7MessageSuccess(from, sessionID, q),
+	)
+	received := common.MemSize(NewSignRound2Message(from, from, sessionID, NSq, proofBob, NSq, proofBobWC))
+	// the messages of a peer decoded
+	received += common.MemSize(NSq, rangeProof, hash, NSq, proofBob, NSq, proofBobWC, q, point, tProof, deCommitment,
+		point, pdlProof, point, stProof, q)
+	// the MtA state kept for a peer: the ciphertexts exchanged with it, its proofs and the shares of the MtA outputs
+	received += common.MemSize(NSq, NSq, NSq, proofBob, proofBobWC, q, q)
+	// the signer's part of the key data: its index, ring-Pedersen parameters, public share and Paillier key, and the
+	// points derived from it
+	kept += common.MemSize(q, NTilde, NTilde, NTilde, point, &paillier.PublicKey{N: N}, point, point)
+
+	return common.MemSize(key.LocalPreParams, key.LocalSecrets) + n*kept + (n-1)*received
+}
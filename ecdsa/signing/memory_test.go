@@ -0,0 +1,119 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestEstimateMemoryGrowsWithCommittee(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	last := 0
+	for n := 2; n <= 10; n++ {
+		pIDs := tss.GenerateTestPartyIDs(n)
+		estimate := EstimateMemory(tss.NewParameters(tss.NewPeerContext(pIDs), pIDs[0], n, n-1), keys[0])
+		assert.Greater(t, estimate, last)
+		last = estimate
+	}
+}
+
+func TestEstimateMemoryMatchesSession(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]tss.Party, 0, len(signPIDs))
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := common.GetRandomPrimeInt(256)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh))
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signPIDs {
+			<-endCh
+		}
+	}()
+	if tErr := runSessionWith(parties, outCh, errCh, done, test.SharedPartyUpdater); !assert.Nil(t, tErr) {
+		return
+	}
+
+	// count what the first party holds at the end of the session: its key data, the messages of the signers, both as
+	// received and decoded, and the MtA state kept for each signer; the shares of the MtA outputs are released in round 3
+	P := parties[0].(*LocalParty)
+	ec, store := P.params.EC(), P.temp.localMessageStore
+	measured := common.MemSize(P.keys.LocalPreParams, P.keys.LocalSecrets)
+	for j := range signPIDs {
+		measured += common.MemSize(P.keys.Ks[j], P.keys.NTildej[j], P.keys.H1j[j], P.keys.H2j[j], P.keys.BigXj[j],
+			P.keys.PaillierPKs[j])
+		measured += common.MemSize(P.temp.c1Is[j], P.temp.c1JIs[j], P.temp.c2JIs[j], P.temp.pI1JIs[j], P.temp.pI2JIs[j],
+			P.temp.bigWs[j], P.temp.bigGammaJs[j])
+		for _, msg := range []tss.ParsedMessage{
+			store.signRound1Message1s[j], store.signRound1Message2s[j], store.signRound2Messages[j],
+			store.signRound3Messages[j], store.signRound4Messages[j], store.signRound5Messages[j],
+			store.signRound6Messages[j], store.signRound7Messages[j],
+		} {
+			if msg != nil {
+				measured += common.MemSize(msg)
+			}
+		}
+		if j == P.PartyID().Index {
+			continue
+		}
+		r1msg1 := store.signRound1Message1s[j].Content().(*SignRound1Message1)
+		rangeProof, err := r1msg1.UnmarshalRangeProofAlice()
+		assert.NoError(t, err)
+		r2msg := store.signRound2Messages[j].Content().(*SignRound2Message)
+		proofBob, err := r2msg.UnmarshalProofBob()
+		assert.NoError(t, err)
+		proofBobWC, err := r2msg.UnmarshalProofBobWC(ec)
+		assert.NoError(t, err)
+		r3msg := store.signRound3Messages[j].Content().(*SignRound3Message)
+		TI, err := r3msg.UnmarshalTI(ec)
+		assert.NoError(t, err)
+		tProof, err := r3msg.UnmarshalTProof(ec)
+		assert.NoError(t, err)
+		r5msg := store.signRound5Messages[j].Content().(*SignRound5Message)
+		RI, err := r5msg.UnmarshalRI(ec)
+		assert.NoError(t, err)
+		pdlProof, err := r5msg.UnmarshalPDLwSlackProof(ec)
+		assert.NoError(t, err)
+		r6msg := store.signRound6Messages[j].Content().(*SignRound6Message).GetSuccess()
+		SI, err := r6msg.UnmarshalSI(ec)
+		assert.NoError(t, err)
+		stProof, err := r6msg.UnmarshalSTProof(ec)
+		assert.NoError(t, err)
+		measured += common.MemSize(r1msg1.UnmarshalC(), rangeProof,
+			store.signRound1Message2s[j].Content().(*SignRound1Message2).UnmarshalCommitment(),
+			new(big.Int).SetBytes(r2msg.GetC1()), proofBob, new(big.Int).SetBytes(r2msg.GetC2()), proofBobWC,
+			new(big.Int).SetBytes(r3msg.GetDeltaI()), TI, tProof,
+			store.signRound4Messages[j].Content().(*SignRound4Message).UnmarshalDeCommitment(),
+			RI, pdlProof, SI, stProof,
+			new(big.Int).SetBytes(store.signRound7Messages[j].Content().(*SignRound7Message).GetSI()))
+	}
+	estimate := EstimateMemory(P.params, keys[0])
+	t.Logf("estimated %d bytes, measured %d bytes", estimate, measured)
+	assert.LessOrEqual(t, measured, estimate, "the estimate should cover what the party holds")
+	assert.LessOrEqual(t, estimate-measured, measured/20, "the estimate should be within 5% of what the party holds")
+}
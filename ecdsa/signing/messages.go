@@ -7,9 +7,12 @@
 package signing
 
 import (
+	"crypto/elliptic"
 	"errors"
 	"math/big"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
@@ -34,10 +37,26 @@ var (
 	}
 )
 
+// ExpectedMessages returns the messages that a party receives from each of the other parties in a full signing session, to check
+// a stored stream of them with tss.ValidateMessageStream
+func ExpectedMessages() []tss.ExpectedMessage {
+	return []tss.ExpectedMessage{
+		{Round: 1, Type: proto.MessageName(&SignRound1Message1{}), Broadcast: false},
+		{Round: 1, Type: proto.MessageName(&SignRound1Message2{}), Broadcast: true},
+		{Round: 2, Type: proto.MessageName(&SignRound2Message{}), Broadcast: false},
+		{Round: 3, Type: proto.MessageName(&SignRound3Message{}), Broadcast: true},
+		{Round: 4, Type: proto.MessageName(&SignRound4Message{}), Broadcast: true},
+		{Round: 5, Type: proto.MessageName(&SignRound5Message{}), Broadcast: true},
+		{Round: 6, Type: proto.MessageName(&SignRound6Message{}), Broadcast: true},
+		{Round: 7, Type: proto.MessageName(&SignRound7Message{}), Broadcast: true},
+	}
+}
+
 // ----- //
 
 func NewSignRound1Message1(
 	to, from *tss.PartyID,
+	sessionID []byte,
 	c *big.Int,
 	proof *mta.RangeProofAlice,
 ) tss.ParsedMessage {
@@ -50,6 +69,7 @@ func NewSignRound1Message1(
 	content := &SignRound1Message1{
 		C:               c.Bytes(),
 		RangeProofAlice: pfBz[:],
+		SessionId:       sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -73,14 +93,20 @@ func (m *SignRound1Message1) UnmarshalRangeProofAlice() (*mta.RangeProofAlice, e
 
 func NewSignRound1Message2(
 	from *tss.PartyID,
+	sessionID []byte,
 	commitment cmt.HashCommitment,
+	msgCommitment []byte,
+	expiry int64,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:        from,
 		IsBroadcast: true,
 	}
 	content := &SignRound1Message2{
-		Commitment: commitment.Bytes(),
+		Commitment:        commitment.Bytes(),
+		MessageCommitment: msgCommitment,
+		Expiry:            expiry,
+		SessionId:         sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -88,7 +114,8 @@ func NewSignRound1Message2(
 
 func (m *SignRound1Message2) ValidateBasic() bool {
 	return m.Commitment != nil &&
-		common.NonEmptyBytes(m.GetCommitment())
+		common.NonEmptyBytes(m.GetCommitment()) &&
+		common.NonEmptyBytes(m.GetMessageCommitment())
 }
 
 func (m *SignRound1Message2) UnmarshalCommitment() *big.Int {
@@ -99,6 +126,7 @@ func (m *SignRound1Message2) UnmarshalCommitment() *big.Int {
 
 func NewSignRound2Message(
 	to, from *tss.PartyID,
+	sessionID []byte,
 	c1JI *big.Int,
 	pi1JI *mta.ProofBob,
 	c2JI *big.Int,
@@ -116,6 +144,7 @@ func NewSignRound2Message(
 		C2:         c2JI.Bytes(),
 		ProofBob:   pfBob[:],
 		ProofBobWc: pfBobWC[:],
+		SessionId:  sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -133,14 +162,15 @@ func (m *SignRound2Message) UnmarshalProofBob() (*mta.ProofBob, error) {
 	return mta.ProofBobFromBytes(m.GetProofBob())
 }
 
-func (m *SignRound2Message) UnmarshalProofBobWC() (*mta.ProofBobWC, error) {
-	return mta.ProofBobWCFromBytes(m.GetProofBobWc())
+func (m *SignRound2Message) UnmarshalProofBobWC(ec elliptic.Curve) (*mta.ProofBobWC, error) {
+	return mta.ProofBobWCFromBytes(ec, m.GetProofBobWc())
 }
 
 // ----- //
 
 func NewSignRound3Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	deltaI *big.Int,
 	TI *crypto.ECPoint,
 	tProof *zkp.TProof,
@@ -149,6 +179,7 @@ func NewSignRound3Message(
 		From:        from,
 		IsBroadcast: true,
 	}
+	scalarLen := common.ByteLen(tProof.Alpha.Curve().Params().N)
 	content := &SignRound3Message{
 		DeltaI: deltaI.Bytes(),
 		TI: &common.ECPoint{
@@ -159,8 +190,9 @@ func NewSignRound3Message(
 			X: tProof.Alpha.X().Bytes(),
 			Y: tProof.Alpha.Y().Bytes(),
 		},
-		TProofT: tProof.T.Bytes(),
-		TProofU: tProof.U.Bytes(),
+		TProofT:   common.PadToLen(tProof.T, scalarLen),
+		TProofU:   common.PadToLen(tProof.U, scalarLen),
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -175,31 +207,20 @@ func (m *SignRound3Message) ValidateBasic() bool {
 		!common.NonEmptyBytes(m.GetTProofU()) {
 		return false
 	}
-	TI, err := m.UnmarshalTI()
-	if err != nil {
-		return false
-	}
-	tProof, err := m.UnmarshalTProof()
-	if err != nil {
-		return false
-	}
-	// we have everything we need to validate the TProof here!
-	basePoint2, err := crypto.ECBasePoint2(tss.EC())
-	if err != nil {
-		return false
-	}
-	return TI.ValidateBasic() && tProof.Verify(TI, basePoint2)
+	// the TProof is verified on the session's curve once round 4 starts
+	return m.GetTProofAlpha() != nil &&
+		m.GetTProofAlpha().ValidateBasic()
 }
 
-func (m *SignRound3Message) UnmarshalTI() (*crypto.ECPoint, error) {
+func (m *SignRound3Message) UnmarshalTI(ec elliptic.Curve) (*crypto.ECPoint, error) {
 	if m.GetTI() == nil || !m.GetTI().ValidateBasic() {
 		return nil, errors.New("UnmarshalTI() X or Y coord is nil or did not validate")
 	}
-	return crypto.NewECPointFromProtobuf(m.GetTI())
+	return crypto.NewECPointFromProtobuf(ec, m.GetTI())
 }
 
-func (m *SignRound3Message) UnmarshalTProof() (*zkp.TProof, error) {
-	alpha, err := crypto.NewECPointFromProtobuf(m.GetTProofAlpha())
+func (m *SignRound3Message) UnmarshalTProof(ec elliptic.Curve) (*zkp.TProof, error) {
+	alpha, err := crypto.NewECPointFromProtobuf(ec, m.GetTProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -214,6 +235,7 @@ func (m *SignRound3Message) UnmarshalTProof() (*zkp.TProof, error) {
 
 func NewSignRound4Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	deCommitment cmt.HashDeCommitment,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -223,6 +245,7 @@ func NewSignRound4Message(
 	dcBzs := common.BigIntsToBytes(deCommitment)
 	content := &SignRound4Message{
 		DeCommitment: dcBzs,
+		SessionId:    sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -242,6 +265,7 @@ func (m *SignRound4Message) UnmarshalDeCommitment() []*big.Int {
 
 func NewSignRound5Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	Ri *crypto.ECPoint,
 	pdlwSlackPf *zkp.PDLwSlackProof,
 ) tss.ParsedMessage {
@@ -256,6 +280,7 @@ func NewSignRound5Message(
 	content := &SignRound5Message{
 		RI:             Ri.ToProtobufPoint(),
 		ProofPdlWSlack: pfBzs,
+		SessionId:      sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -268,25 +293,22 @@ func (m *SignRound5Message) ValidateBasic() bool {
 		!common.NonEmptyMultiBytes(m.GetProofPdlWSlack(), zkp.PDLwSlackMarshalledParts) {
 		return false
 	}
-	RI, err := m.UnmarshalRI()
-	if err != nil {
-		return false
-	}
-	return RI.ValidateBasic()
+	return true
 }
 
-func (m *SignRound5Message) UnmarshalRI() (*crypto.ECPoint, error) {
-	return crypto.NewECPointFromProtobuf(m.GetRI())
+func (m *SignRound5Message) UnmarshalRI(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetRI())
 }
 
-func (m *SignRound5Message) UnmarshalPDLwSlackProof() (*zkp.PDLwSlackProof, error) {
-	return zkp.UnmarshalPDLwSlackProof(m.GetProofPdlWSlack())
+func (m *SignRound5Message) UnmarshalPDLwSlackProof(ec elliptic.Curve) (*zkp.PDLwSlackProof, error) {
+	return zkp.UnmarshalPDLwSlackProof(ec, m.GetProofPdlWSlack())
 }
 
 // ----- //
 
 func NewSignRound6MessageSuccess(
 	from *tss.PartyID,
+	sessionID []byte,
 	sI *crypto.ECPoint,
 	proof *zkp.STProof,
 
@@ -295,16 +317,18 @@ func NewSignRound6MessageSuccess(
 		From:        from,
 		IsBroadcast: true,
 	}
+	scalarLen := common.ByteLen(proof.Alpha.Curve().Params().N)
 	content := &SignRound6Message{
 		Content: &SignRound6Message_Success{
 			Success: &SignRound6Message_SuccessData{
 				SI:           sI.ToProtobufPoint(),
 				StProofAlpha: proof.Alpha.ToProtobufPoint(),
 				StProofBeta:  proof.Beta.ToProtobufPoint(),
-				StProofT:     proof.T.Bytes(),
-				StProofU:     proof.U.Bytes(),
+				StProofT:     common.PadToLen(proof.T, scalarLen),
+				StProofU:     common.PadToLen(proof.U, scalarLen),
 			},
 		},
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -312,6 +336,7 @@ func NewSignRound6MessageSuccess(
 
 func NewSignRound6MessageAbort(
 	from *tss.PartyID,
+	sessionID []byte,
 	data *SignRound6Message_AbortData,
 
 ) tss.ParsedMessage {
@@ -323,7 +348,8 @@ func NewSignRound6MessageAbort(
 	data.GetAlphaIJ()[from.Index] = []byte{1}
 	data.GetBetaJI()[from.Index] = []byte{1}
 	content := &SignRound6Message{
-		Content: &SignRound6Message_Abort{Abort: data},
+		Content:   &SignRound6Message_Abort{Abort: data},
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -346,15 +372,7 @@ func (m *SignRound6Message) ValidateBasic() bool {
 			!common.NonEmptyBytes(c.Success.GetStProofU()) {
 			return false
 		}
-		sI, err := c.Success.UnmarshalSI()
-		if err != nil {
-			return false
-		}
-		tProof, err := c.Success.UnmarshalSTProof()
-		if err != nil {
-			return false
-		}
-		return sI.ValidateBasic() && tProof.ValidateBasic()
+		return true
 	case *SignRound6Message_Abort:
 		return c.Abort != nil &&
 			common.NonEmptyBytes(c.Abort.GetKI()) &&
@@ -366,16 +384,16 @@ func (m *SignRound6Message) ValidateBasic() bool {
 	}
 }
 
-func (m *SignRound6Message_SuccessData) UnmarshalSI() (*crypto.ECPoint, error) {
-	return crypto.NewECPointFromProtobuf(m.GetSI())
+func (m *SignRound6Message_SuccessData) UnmarshalSI(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetSI())
 }
 
-func (m *SignRound6Message_SuccessData) UnmarshalSTProof() (*zkp.STProof, error) {
-	alpha, err := crypto.NewECPointFromProtobuf(m.GetStProofAlpha())
+func (m *SignRound6Message_SuccessData) UnmarshalSTProof(ec elliptic.Curve) (*zkp.STProof, error) {
+	alpha, err := crypto.NewECPointFromProtobuf(ec, m.GetStProofAlpha())
 	if err != nil {
 		return nil, err
 	}
-	beta, err := crypto.NewECPointFromProtobuf(m.GetStProofBeta())
+	beta, err := crypto.NewECPointFromProtobuf(ec, m.GetStProofBeta())
 	if err != nil {
 		return nil, err
 	}
@@ -391,6 +409,7 @@ func (m *SignRound6Message_SuccessData) UnmarshalSTProof() (*zkp.STProof, error)
 
 func NewSignRound7MessageSuccess(
 	from *tss.PartyID,
+	sessionID []byte,
 	sI *big.Int,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -398,7 +417,8 @@ func NewSignRound7MessageSuccess(
 		IsBroadcast: true,
 	}
 	content := &SignRound7Message{
-		Content: &SignRound7Message_SI{SI: sI.Bytes()},
+		Content:   &SignRound7Message_SI{SI: sI.Bytes()},
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -406,6 +426,7 @@ func NewSignRound7MessageSuccess(
 
 func NewSignRound7MessageAbort(
 	from *tss.PartyID,
+	sessionID []byte,
 	data *SignRound7Message_AbortData,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -416,7 +437,8 @@ func NewSignRound7MessageAbort(
 	data.GetMuIJ()[from.Index] = []byte{1}
 	data.GetMuRandIJ()[from.Index] = []byte{1}
 	content := &SignRound7Message{
-		Content: &SignRound7Message_Abort{Abort: data},
+		Content:   &SignRound7Message_Abort{Abort: data},
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -445,12 +467,12 @@ func (m *SignRound7Message) ValidateBasic() bool {
 	}
 }
 
-func (m *SignRound7Message_AbortData) UnmarshalSigmaIProof() (*zkp.ECDDHProof, error) {
-	a1, err := crypto.NewECPointFromProtobuf(m.GetEcddhProofA1())
+func (m *SignRound7Message_AbortData) UnmarshalSigmaIProof(ec elliptic.Curve) (*zkp.ECDDHProof, error) {
+	a1, err := crypto.NewECPointFromProtobuf(ec, m.GetEcddhProofA1())
 	if err != nil {
 		return nil, err
 	}
-	a2, err := crypto.NewECPointFromProtobuf(m.GetEcddhProofA2())
+	a2, err := crypto.NewECPointFromProtobuf(ec, m.GetEcddhProofA2())
 	if err != nil {
 		return nil, err
 	}
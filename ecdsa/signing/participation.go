@@ -0,0 +1,103 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+)
+
+const (
+	participationDomain = "tss-lib participation"
+)
+
+type (
+	// ParticipationAttestation is a signer's Schnorr signature of a threshold signature under the public share X_j of
+	// its key share, which attests that the signer took part in making it. The signer is identified by its evaluation
+	// point Ks_j, which is the same in every member's save data.
+	ParticipationAttestation struct {
+		Ks    *big.Int
+		Proof *zkp.DLogProof
+	}
+
+	// ParticipationProof is the attestations of the signers of a threshold signature, to be appended to it so that a
+	// consumer can check that it was made by members of the authorized committee
+	ParticipationProof []*ParticipationAttestation
+)
+
+// NewParticipationAttestation is called by each signer after a signing session to attest with its key share that it
+// took part in making `sig`
+func NewParticipationAttestation(ec elliptic.Curve, key keygen.LocalPartySaveData, sig *common.ECSignature) (*ParticipationAttestation, error) {
+	if key.Xi == nil || key.ShareID == nil {
+		return nil, errors.New("the key data holds no share")
+	}
+	index, err := key.OriginalIndex()
+	if err != nil {
+		return nil, err
+	}
+	proof, err := zkp.NewDLogProofWithMessage(ec, key.Xi, key.BigXj[index], participationDigest(sig))
+	if err != nil {
+		return nil, err
+	}
+	return &ParticipationAttestation{Ks: new(big.Int).Set(key.ShareID), Proof: proof}, nil
+}
+
+// VerifyParticipationProof checks that `proof` holds attestations of `sig` by at least threshold+1 distinct members of
+// the committee whose evaluation points and public shares are `ks` and `bigXj`, as in the save data of any member. An
+// attestation by a party outside the committee or one that does not verify fails the check.
+func VerifyParticipationProof(
+	ec elliptic.Curve,
+	ks []*big.Int,
+	bigXj []*crypto.ECPoint,
+	threshold int,
+	sig *common.ECSignature,
+	proof ParticipationProof,
+) error {
+	if len(ks) != len(bigXj) {
+		return errors.New("the committee must have a public share for each evaluation point")
+	}
+	digest := participationDigest(sig)
+	attested := make(map[int]bool, len(proof))
+	for _, att := range proof {
+		if att == nil || att.Ks == nil {
+			return errors.New("the participation proof holds an incomplete attestation")
+		}
+		j := -1
+		for k, kj := range ks {
+			if kj != nil && kj.Cmp(att.Ks) == 0 {
+				j = k
+				break
+			}
+		}
+		if j < 0 {
+			return fmt.Errorf("the signer with evaluation point %s is not a member of the committee", att.Ks)
+		}
+		if attested[j] {
+			return fmt.Errorf("the signer with evaluation point %s attested more than once", att.Ks)
+		}
+		if bigXj[j] == nil || !att.Proof.VerifyWithMessage(ec, bigXj[j], digest) {
+			return fmt.Errorf("the attestation of the signer with evaluation point %s failed to verify", att.Ks)
+		}
+		attested[j] = true
+	}
+	if len(attested) < threshold+1 {
+		return fmt.Errorf("the participation proof holds %d attestations but %d signers are needed", len(attested), threshold+1)
+	}
+	return nil
+}
+
+// participationDigest is the message that the attestations of `sig` sign: its r, s and message
+func participationDigest(sig *common.ECSignature) *big.Int {
+	return new(big.Int).SetBytes(common.SHA512_256([]byte(participationDomain), sig.GetR(), sig.GetS(), sig.GetM()))
+}
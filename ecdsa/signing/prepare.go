@@ -7,17 +7,18 @@
 package signing
 
 import (
+	"crypto/elliptic"
 	"fmt"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
-	"github.com/ordinox/thorchain-tss-lib/tss"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 )
 
 // PrepareForSigning(), GG18Spec (11) Fig. 14
-func PrepareForSigning(i, pax int, xi *big.Int, ks []*big.Int, bigXs []*crypto.ECPoint) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
-	modQ := common.ModInt(tss.EC().Params().N)
+func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int, bigXs []*crypto.ECPoint) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
+	modQ := common.ModInt(ec.Params().N)
 	if len(ks) != len(bigXs) {
 		panic(fmt.Errorf("PrepareForSigning: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs)))
 	}
@@ -27,6 +28,11 @@ func PrepareForSigning(i, pax int, xi *big.Int, ks []*big.Int, bigXs []*crypto.E
 	if len(ks) <= i {
 		panic(fmt.Errorf("PrepareForSigning: len(ks) <= i (%d <= %d)", len(ks), i))
 	}
+	// the Lagrange coefficients are only defined and non-zero when the indices are distinct and non-zero mod q
+	if _, err = vss.CheckIndexes(ec, ks); err != nil {
+		err = fmt.Errorf("the signing subset is malformed: %v", err)
+		return
+	}
 
 	// 2-4.
 	wi = new(big.Int).Set(xi)
@@ -65,7 +71,7 @@ func PrepareForSigning(i, pax int, xi *big.Int, ks []*big.Int, bigXs []*crypto.E
 	}
 
 	// assertion: g^w_i == W_i
-	if !crypto.ScalarBaseMult(tss.EC(), wi).Equals(bigWs[i]) {
+	if !crypto.ScalarBaseMult(ec, wi).Equals(bigWs[i]) {
 		err = fmt.Errorf("assertion failed: g^w_i == W_i")
 		return
 	}
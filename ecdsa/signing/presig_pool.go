@@ -0,0 +1,221 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPreSigPoolEmpty is returned by Acquire when no presignature is ready
+	ErrPreSigPoolEmpty = errors.New("no presignature is ready in the pool")
+	// ErrPreSigPoolClosed is returned by Acquire once the pool was closed
+	ErrPreSigPoolClosed = errors.New("the presignature pool is closed")
+)
+
+// preSigPoolHistory is the number of pool capacities' worth of presignatures whose nonce points a pool remembers to
+// reject a repeat
+const preSigPoolHistory = 16
+
+type (
+	// PreSigGenerator runs a one-round signing session without a message (see NewLocalParty) with the pool's committee
+	// and returns this party's state from the end channel. The other parties of the committee must run the same
+	// sessions in the same order; coordinating that is left to the generator.
+	PreSigGenerator func() (*SignatureData, error)
+
+	// PreSigPool keeps up to a capacity of presignatures, the states of one-round signing sessions, ready to be
+	// finalized with a message. It generates them in the background, one session at a time, whenever fewer than its
+	// low-water mark are ready. Each presignature is handed out at most once.
+	PreSigPool struct {
+		generate           PreSigGenerator
+		lowWater, capacity int
+
+		mtx   sync.Mutex
+		ready []*SignatureData
+		// the nonce points R of the presignatures that entered the pool, with their expiry, so that one is not pooled
+		// twice, and the order in which they entered. See add.
+		pooled      map[string]int64
+		pooledOrder []string
+		refilling   bool
+		closed      bool
+		err         error
+		wg          sync.WaitGroup
+	}
+)
+
+// NewPreSigPool creates a pool that keeps up to `capacity` presignatures and refills whenever fewer than `lowWater`
+// are ready. It starts filling at once.
+func NewPreSigPool(generate PreSigGenerator, lowWater, capacity int) (*PreSigPool, error) {
+	if generate == nil {
+		return nil, errors.New("NewPreSigPool() received a nil generator")
+	}
+	if lowWater < 1 || capacity < lowWater {
+		return nil, fmt.Errorf("the low-water mark %d must be at least 1 and at most the capacity %d", lowWater, capacity)
+	}
+	pool := &PreSigPool{
+		generate: generate,
+		lowWater: lowWater,
+		capacity: capacity,
+		ready:    make([]*SignatureData, 0, capacity),
+		pooled:   make(map[string]int64),
+	}
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	pool.startRefill()
+	return pool, nil
+}
+
+// Acquire removes a ready presignature from the pool and returns it, to be finalized with FinalizeGetOurSigShareUnexpired.
+// It does not wait for one to be generated: it returns ErrPreSigPoolEmpty when none is ready. It is safe for
+// concurrent use and never returns the same presignature twice.
+func (pool *PreSigPool) Acquire() (*SignatureData, error) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	if pool.closed {
+		return nil, ErrPreSigPoolClosed
+	}
+	defer func() {
+		if len(pool.ready) < pool.lowWater {
+			pool.startRefill()
+		}
+	}()
+	// presignatures whose session has expired are dropped
+	now := time.Now()
+	for 0 < len(pool.ready) && PreSignatureExpired(pool.ready[0], now) {
+		pool.ready[0] = nil
+		pool.ready = pool.ready[1:]
+	}
+	if len(pool.ready) == 0 {
+		if pool.err != nil {
+			return nil, fmt.Errorf("%w; the last refill failed: %v", ErrPreSigPoolEmpty, pool.err)
+		}
+		return nil, ErrPreSigPoolEmpty
+	}
+	state := pool.ready[0]
+	pool.ready[0] = nil
+	pool.ready = pool.ready[1:]
+	return state, nil
+}
+
+// Len returns the number of presignatures ready in the pool
+func (pool *PreSigPool) Len() int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return len(pool.ready)
+}
+
+// Err returns the error of the last refill, or nil if it succeeded. A failed refill is retried by the next Acquire or
+// Refill.
+func (pool *PreSigPool) Err() error {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return pool.err
+}
+
+// Refill starts generating presignatures in the background until the pool is full, unless it is full, closed or
+// already refilling, and returns at once. Acquire calls it whenever fewer than the low-water mark are ready; call it to
+// retry a failed refill without waiting for the next Acquire.
+func (pool *PreSigPool) Refill() {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	pool.startRefill()
+}
+
+// Close stops the pool from refilling, waiting for a session in progress to end, and drops the presignatures it holds
+func (pool *PreSigPool) Close() {
+	pool.mtx.Lock()
+	pool.closed = true
+	pool.ready = nil
+	pool.mtx.Unlock()
+	pool.wg.Wait()
+}
+
+// startRefill starts generating presignatures in the background unless a refill is already running. The lock must be
+// held.
+func (pool *PreSigPool) startRefill() {
+	if pool.closed || pool.refilling || pool.capacity <= len(pool.ready) {
+		return
+	}
+	pool.refilling = true
+	pool.wg.Add(1)
+	go pool.refill()
+}
+
+// refill generates presignatures until the pool is full, it is closed or a session fails
+func (pool *PreSigPool) refill() {
+	defer pool.wg.Done()
+	for {
+		pool.mtx.Lock()
+		if pool.closed || pool.capacity <= len(pool.ready) {
+			pool.refilling = false
+			pool.mtx.Unlock()
+			return
+		}
+		pool.mtx.Unlock()
+
+		state, err := pool.generate()
+
+		pool.mtx.Lock()
+		if err == nil && !pool.closed {
+			err = pool.add(state)
+		}
+		pool.err = err
+		if err != nil {
+			pool.refilling = false
+			pool.mtx.Unlock()
+			return
+		}
+		pool.mtx.Unlock()
+	}
+}
+
+// add puts a generated presignature in the pool, unless its nonce point was pooled before. The lock must be held.
+//
+// So that the memory of the pool stays bounded, it forgets the nonce points of expired presignatures, which it would
+// not hand out anyway, and remembers only those of the last preSigPoolHistory*capacity presignatures. A generator that
+// repeats a presignature without an expiry from before that is not caught.
+func (pool *PreSigPool) add(state *SignatureData) error {
+	bigR := state.GetOneRoundData().GetBigR()
+	if bigR == nil || len(bigR.GetX()) == 0 {
+		return errors.New("the generator returned a state without one-round data")
+	}
+	key := string(bigR.GetX()) + "," + string(bigR.GetY())
+	if _, ok := pool.pooled[key]; ok {
+		return errors.New("the generator returned a presignature that was already pooled")
+	}
+	pool.forget(time.Now())
+	pool.pooled[key] = state.GetOneRoundData().GetExpiry()
+	pool.pooledOrder = append(pool.pooledOrder, key)
+	pool.ready = append(pool.ready, state)
+	return nil
+}
+
+// forget drops the nonce points of the presignatures that expired before `now`, and the oldest ones beyond the history
+// of the pool less one, to make room for the next. The lock must be held.
+func (pool *PreSigPool) forget(now time.Time) {
+	kept := pool.pooledOrder[:0]
+	for _, key := range pool.pooledOrder {
+		if expiry := pool.pooled[key]; expiry != 0 && now.Unix() > expiry {
+			delete(pool.pooled, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	if excess := len(kept) - (preSigPoolHistory*pool.capacity - 1); 0 < excess {
+		for _, key := range kept[:excess] {
+			delete(pool.pooled, key)
+		}
+		kept = append(kept[:0], kept[excess:]...)
+	}
+	for i := len(kept); i < len(pool.pooledOrder); i++ {
+		pool.pooledOrder[i] = ""
+	}
+	pool.pooledOrder = kept
+}
@@ -0,0 +1,130 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+var (
+	// ErrPresignDataConsumed is returned by PresignData.Sign once the presign data has signed a message, and by
+	// PresignData.Finalize once it has produced a signature
+	ErrPresignDataConsumed = errors.New("the presign data has already been consumed")
+	// ErrPresignDataNotSigned is returned by PresignData.Finalize before PresignData.Sign was called
+	ErrPresignDataNotSigned = errors.New("the presign data has not signed a message yet")
+)
+
+// PresignData is this party's state of a presigning session: the nonce shares and the points that the MtA rounds
+// produced before the message was known. It signs exactly one message. Sign consumes it and wipes the nonce shares,
+// since a second signature with the same nonce would reveal the key.
+type PresignData struct {
+	mtx   sync.Mutex
+	ec    elliptic.Curve
+	state *SignatureData
+
+	msg, sI   *big.Int
+	finalized bool
+}
+
+// NewPresignParty constructs a party that runs the presigning rounds of ECDSA signing: the MtA exchanges and the
+// computation of the nonce point R. The state that it sends to the end channel is wrapped with NewPresignData and later
+// signs a single message. It is NewLocalParty with a nil msg.
+func NewPresignParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *SignatureData,
+) tss.Party {
+	return NewLocalParty(nil, params, key, out, end)
+}
+
+// NewPresignData takes the state that a presign party sent to the end channel. The PresignData owns the state from
+// here on; it must not be passed to FinalizeGetOurSigShare or stored elsewhere, or the nonce could be used twice.
+func NewPresignData(ec elliptic.Curve, state *SignatureData) (*PresignData, error) {
+	if ec == nil {
+		return nil, errors.New("NewPresignData() received a nil curve")
+	}
+	data := state.GetOneRoundData()
+	if data == nil {
+		return nil, errors.New("the signature data holds no one-round data")
+	}
+	if len(data.GetKI()) == 0 || len(data.GetRSigmaI()) == 0 {
+		return nil, errors.New("the one-round data holds no nonce shares")
+	}
+	return &PresignData{ec: ec, state: state}, nil
+}
+
+// PreSignature returns the public part of the presign data, e.g. to record its commitment. It is available until
+// Finalize has produced a signature.
+func (pd *PresignData) PreSignature() (*PreSignature, error) {
+	pd.mtx.Lock()
+	defer pd.mtx.Unlock()
+	if pd.finalized {
+		return nil, ErrPresignDataConsumed
+	}
+	return NewPreSignature(pd.ec, pd.state)
+}
+
+// Sign is the online step: it computes this party's share s_i of the signature of `msg` and consumes the presign data.
+// The share is sent to the other signers, and any of them completes the signature with Finalize. A second call fails
+// with ErrPresignDataConsumed, whatever the message.
+func (pd *PresignData) Sign(msg *big.Int) (*big.Int, error) {
+	if msg == nil {
+		return nil, errors.New("Sign() received a nil msg")
+	}
+	pd.mtx.Lock()
+	defer pd.mtx.Unlock()
+	if pd.sI != nil {
+		return nil, ErrPresignDataConsumed
+	}
+	sI, err := FinalizeGetOurSigShareUnexpired(pd.ec, pd.state, msg)
+	if err != nil {
+		return nil, err
+	}
+	pd.msg, pd.sI = new(big.Int).Set(msg), sI
+
+	// wipe the nonce shares, which are not needed to finalize
+	data := pd.state.GetOneRoundData()
+	for _, bz := range [][]byte{data.KI, data.RSigmaI} {
+		for i := range bz {
+			bz[i] = 0
+		}
+	}
+	data.KI, data.RSigmaI = nil, nil
+	return new(big.Int).Set(pd.sI), nil
+}
+
+// Finalize combines this party's share with the shares of the other signers into the signature of the message given
+// to Sign and verifies it under `pk`. A failed call may be retried with corrected shares; once a signature is made, the
+// presign data is spent.
+func (pd *PresignData) Finalize(
+	pk *ecdsa.PublicKey,
+	ourP *tss.PartyID,
+	otherSIs map[*tss.PartyID]*big.Int,
+) (*SignatureData, *tss.Error) {
+	pd.mtx.Lock()
+	defer pd.mtx.Unlock()
+	if pd.finalized {
+		return nil, FinalizeWrapError(ErrPresignDataConsumed, ourP)
+	}
+	if pd.sI == nil {
+		return nil, FinalizeWrapError(ErrPresignDataNotSigned, ourP)
+	}
+	data, _, err := FinalizeGetAndVerifyFinalSig(pd.ec, pd.state, pk, pd.msg, ourP, pd.sI, otherSIs)
+	if err != nil {
+		return nil, err
+	}
+	pd.finalized = true
+	return data, nil
+}
@@ -0,0 +1,128 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+)
+
+const (
+	preSignatureCommitmentDomain = "tss-lib presignature"
+)
+
+// ErrPreSignatureExpired is returned when a presignature is finalized after the expiry of the session that made it
+var ErrPreSignatureExpired = errors.New("the session of the presignature has expired")
+
+// PreSignatureExpired reports whether the state of a one-round signing session was made in a session with an expiry
+// that `now` is past, so that it must not be used to sign
+func PreSignatureExpired(state *SignatureData, now time.Time) bool {
+	expiry := state.GetOneRoundData().GetExpiry()
+	return expiry != 0 && now.Unix() > expiry
+}
+
+// PreSignature is the public part of the state that one-round signing produces before the message is known: the
+// nonce point R and the points R_bar_j and S_j of each party, keyed by party ID. It holds no secrets, so it may be
+// written to an audit log. Every party of the session derives the same PreSignature.
+type PreSignature struct {
+	Curve    elliptic.Curve
+	T        int32
+	BigR     *crypto.ECPoint
+	BigRBarJ map[string]*crypto.ECPoint
+	BigSJ    map[string]*crypto.ECPoint
+}
+
+// NewPreSignature takes the PreSignature from the state that one-round signing sent to the end channel. It must be
+// called before the state is passed to FinalizeGetAndVerifyFinalSig, which discards the one-round data.
+func NewPreSignature(ec elliptic.Curve, state *SignatureData) (*PreSignature, error) {
+	data := state.GetOneRoundData()
+	if data == nil {
+		return nil, errors.New("the signature data holds no one-round data")
+	}
+	bigR, err := crypto.NewECPointFromProtobuf(ec, data.GetBigR())
+	if err != nil {
+		return nil, fmt.Errorf("the one-round data holds an invalid R: %v", err)
+	}
+	pre := &PreSignature{
+		Curve:    ec,
+		T:        data.GetT(),
+		BigR:     bigR,
+		BigRBarJ: make(map[string]*crypto.ECPoint, len(data.GetBigRBarJ())),
+		BigSJ:    make(map[string]*crypto.ECPoint, len(data.GetBigSJ())),
+	}
+	for id, pt := range data.GetBigRBarJ() {
+		if pre.BigRBarJ[id], err = crypto.NewECPointFromProtobuf(ec, pt); err != nil {
+			return nil, fmt.Errorf("the one-round data holds an invalid R_bar_j for %s: %v", id, err)
+		}
+	}
+	for id, pt := range data.GetBigSJ() {
+		if pre.BigSJ[id], err = crypto.NewECPointFromProtobuf(ec, pt); err != nil {
+			return nil, fmt.Errorf("the one-round data holds an invalid S_j for %s: %v", id, err)
+		}
+	}
+	if len(pre.BigRBarJ) != len(pre.BigSJ) {
+		return nil, errors.New("the one-round data does not hold both R_bar_j and S_j for each party")
+	}
+	return pre, nil
+}
+
+// Commitment returns a deterministic hash of the PreSignature and the curve it was made on. It can be recorded before
+// the PreSignature is used and later tied to the signature made with it by VerifyPreSignatureCommitment.
+func (pre *PreSignature) Commitment() [32]byte {
+	curve := pre.Curve.Params()
+	parts := [][]byte{
+		[]byte(preSignatureCommitmentDomain),
+		curve.P.Bytes(), curve.N.Bytes(), curve.Gx.Bytes(), curve.Gy.Bytes(),
+		big.NewInt(int64(pre.T)).Bytes(),
+		pre.BigR.X().Bytes(), pre.BigR.Y().Bytes(),
+	}
+	ids := make([]string, 0, len(pre.BigRBarJ))
+	for id := range pre.BigRBarJ {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		parts = append(parts, []byte(id))
+		for _, pt := range []*crypto.ECPoint{pre.BigRBarJ[id], pre.BigSJ[id]} {
+			if pt == nil {
+				parts = append(parts, nil, nil)
+				continue
+			}
+			parts = append(parts, pt.X().Bytes(), pt.Y().Bytes())
+		}
+	}
+	var commitment [32]byte
+	copy(commitment[:], common.SHA512_256(parts...))
+	return commitment
+}
+
+// VerifyPreSignatureCommitment checks that the PreSignature is the one committed to and that the signature was made
+// with its R, so that an audit log of commitments is bound to the signatures produced.
+func VerifyPreSignatureCommitment(commitment [32]byte, pre *PreSignature, sig *common.ECSignature) error {
+	if pre == nil || pre.Curve == nil || pre.BigR == nil {
+		return errors.New("the presignature is incomplete")
+	}
+	if pre.Commitment() != commitment {
+		return errors.New("the presignature does not match the commitment")
+	}
+	if sig == nil {
+		return errors.New("the signature is nil")
+	}
+	// the signature holds r = R.x, which FinalizeGetAndVerifyFinalSig does not reduce mod q
+	r, bigRX := new(big.Int).SetBytes(sig.GetR()), pre.BigR.X()
+	if r.Cmp(bigRX) != 0 && r.Cmp(new(big.Int).Mod(bigRX, pre.Curve.Params().N)) != 0 {
+		return errors.New("the signature was not made with the committed presignature")
+	}
+	return nil
+}
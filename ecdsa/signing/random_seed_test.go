@@ -0,0 +1,60 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tssdebug
+// +build tssdebug
+
+package signing
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestUnsafeRandomSeed(t *testing.T) {
+	setUp("info")
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	msg := big.NewInt(42)
+	seed, otherSeed := bytes.Repeat([]byte{1}, tss.RandomSeedLen), bytes.Repeat([]byte{2}, tss.RandomSeedLen)
+
+	// round1 starts a fresh party P[0] and returns the wire bytes of its round 1 messages
+	round1 := func(seed []byte) [][]byte {
+		params := tss.NewParameters(tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+		params.UNSAFE_SetRandomSeed(seed)
+		outCh := make(chan tss.Message, len(signPIDs))
+		P := NewLocalParty(msg, params, keys[0], outCh, make(chan *SignatureData, 1))
+		if err := P.Start(); !assert.Nil(t, err) {
+			return nil
+		}
+		wires := make([][]byte, 0, len(signPIDs))
+		for range signPIDs {
+			bz, _, err := (<-outCh).WireBytes()
+			assert.NoError(t, err)
+			wires = append(wires, bz)
+		}
+		return wires
+	}
+	seeded := round1(seed)
+	assert.Len(t, seeded, len(signPIDs))
+	assert.Equal(t, seeded, round1(seed), "the same seed and inputs should give byte-identical round 1 messages")
+	for k, other := range [][][]byte{round1(otherSeed), round1(nil)} {
+		for j := range seeded {
+			assert.NotEqual(t, seeded[j], other[j], "message %d of run %d should differ", j, k)
+		}
+	}
+
+	params := tss.NewParameters(tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	assert.Panics(t, func() { params.UNSAFE_SetRandomSeed(seed[1:]) })
+}
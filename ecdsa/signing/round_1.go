@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
@@ -19,6 +20,8 @@ import (
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
+const messageCommitmentDomain = "tss-lib signing message"
+
 var (
 	zero = big.NewInt(0)
 )
@@ -39,10 +42,27 @@ func (round *round1) Start() *tss.Error {
 	// if this big.Int is not belongs to Zq, the client might not comply with common rule (for ECDSA):
 	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L263
 	if round.temp.m != nil &&
-		round.temp.m.Cmp(tss.EC().Params().N) >= 0 {
+		round.temp.m.Cmp(round.EC().Params().N) >= 0 {
 		return round.WrapError(errors.New("hashed message is not valid"))
 	}
 
+	// a Paillier key too small for the curve would let the MtA values wrap around silently
+	for j, Pj := range round.Parties().IDs() {
+		if err := mta.PaillierCurveCompatible(round.key.PaillierPKs[j], round.EC()); err != nil {
+			return round.WrapError(fmt.Errorf("the Paillier key of %s: %w", Pj, err), Pj)
+		}
+	}
+
+	// the challenges of Bob's proofs are sampled mod q from the digest, which must be at least as long as q
+	if size := round.MtAHash()().Size(); size*8 < round.EC().Params().N.BitLen() {
+		return round.WrapError(fmt.Errorf("the MtA hash must have a digest of at least %d bits, got %d bytes",
+			round.EC().Params().N.BitLen(), size))
+	}
+
+	if round.SessionExpired(time.Now()) {
+		return round.WrapError(fmt.Errorf("session %x expired at %s", round.SessionID(round.temp.m), round.SessionExpiry()))
+	}
+
 	round.number = 1
 	round.started = true
 	round.resetOK()
@@ -51,20 +71,23 @@ func (round *round1) Start() *tss.Error {
 	i := Pi.Index
 	round.ok[i] = true
 
-	gammaI := common.GetRandomPositiveInt(tss.EC().Params().N)
-	kI := common.GetRandomPositiveInt(tss.EC().Params().N)
+	// crypto/rand, unless the session was seeded with UNSAFE_SetRandomSeed
+	reader := round.RandReader(round.SessionID(round.temp.m))
+	gammaI := common.GetRandomPositiveIntFrom(reader, round.EC().Params().N)
+	kI := common.GetRandomPositiveIntFrom(reader, round.EC().Params().N)
 	round.temp.gammaI = gammaI
 	round.temp.r5AbortData.GammaI = gammaI.Bytes()
 
-	gammaIG := crypto.ScalarBaseMult(tss.EC(), gammaI)
+	gammaIG := crypto.ScalarBaseMult(round.EC(), gammaI)
 	round.temp.gammaIG = gammaIG
 
-	cmt := commitments.NewHashCommitment(gammaIG.X(), gammaIG.Y())
+	cmt := commitments.NewHashCommitmentWithRandomness(common.MustGetRandomIntFrom(reader, commitments.HashLength), gammaIG.X(), gammaIG.Y())
 	round.temp.deCommit = cmt.D
 
 	// MtA round 1
 	paiPK := round.key.PaillierPKs[i]
-	cA, rA, err := paiPK.EncryptAndReturnRandomness(kI)
+	rA := common.GetRandomPositiveRelativelyPrimeIntFrom(reader, paiPK.N)
+	cA, err := paiPK.EncryptWithRandomness(kI, rA)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -84,19 +107,19 @@ func (round *round1) Start() *tss.Error {
 		if j == i {
 			continue
 		}
-		pi, err := mta.AliceInit(paiPK, kI, cA, rA, round.key.NTildej[j], round.key.H1j[j], round.key.H2j[j])
+		pi, err := mta.AliceInit(round.EC(), paiPK, kI, cA, rA, round.key.NTildej[j], round.key.H1j[j], round.key.H2j[j], reader)
 		if err != nil {
 			return round.WrapError(fmt.Errorf("failed to init mta: %v", err))
 		}
-		r1msg1 := NewSignRound1Message1(Pj, round.PartyID(), cA, pi)
+		r1msg1 := NewSignRound1Message1(Pj, round.PartyID(), round.temp.sessionID, cA, pi)
 		round.temp.signRound1Message1s[i] = r1msg1
 		round.temp.c1Is[j] = cA
-		round.out <- r1msg1
+		round.out <- round.UseCodec(r1msg1)
 	}
 
-	r1msg2 := NewSignRound1Message2(round.PartyID(), cmt.C)
+	r1msg2 := NewSignRound1Message2(round.PartyID(), round.temp.sessionID, cmt.C, MessageCommitment(round.temp.m), expiryUnix(round.SessionExpiry()))
 	round.temp.signRound1Message2s[i] = r1msg2
-	round.out <- r1msg2
+	round.out <- round.UseCodec(r1msg2)
 	return nil
 }
 
@@ -118,11 +141,11 @@ func (round *round1) Update() (bool, *tss.Error) {
 }
 
 func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound1Message1); ok {
-		return !msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound1Message1); ok {
+		return !msg.IsBroadcast() && round.inSession(content)
 	}
-	if _, ok := msg.Content().(*SignRound1Message2); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound1Message2); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -134,6 +157,25 @@ func (round *round1) NextRound() tss.Round {
 
 // ----- //
 
+// MessageCommitment binds a party to the message digest it signs in the session, so that parties signing different
+// digests, such as one read with the wrong endianness, are caught in round 2 rather than by a signature that fails to
+// verify. Each party broadcasts it in round 1; integrations may also exchange it out of band before a session. A nil
+// message, as in one-round signing, commits to its absence.
+func MessageCommitment(m *big.Int) []byte {
+	if m == nil {
+		return common.SHA512_256([]byte(messageCommitmentDomain), []byte{0})
+	}
+	return common.SHA512_256([]byte(messageCommitmentDomain), []byte{1}, m.Bytes())
+}
+
+// expiryUnix encodes a session expiry for the wire and the one-round data, as 0 if there is none
+func expiryUnix(expiry time.Time) int64 {
+	if expiry.IsZero() {
+		return 0
+	}
+	return expiry.Unix()
+}
+
 // helper to call into PrepareForSigning()
 func (round *round1) prepare() error {
 	i := round.PartyID().Index
@@ -141,7 +183,7 @@ func (round *round1) prepare() error {
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
-	if wI, bigWs, err := PrepareForSigning(i, len(ks), xi, ks, bigXs); err != nil {
+	if wI, bigWs, err := PrepareForSigning(round.EC(), i, len(ks), xi, ks, bigXs); err != nil {
 		return err
 	} else {
 		round.temp.wI = wI
@@ -7,9 +7,12 @@
 package signing
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"sync"
 
+	"github.com/hashicorp/go-multierror"
 	errorspkg "github.com/pkg/errors"
 
 	"github.com/ordinox/thorchain-tss-lib/crypto/mta"
@@ -27,6 +30,28 @@ func (round *round2) Start() *tss.Error {
 	i := round.PartyID().Index
 	round.ok[i] = true
 
+	// every party must have committed to the message that this party signs, and to the same session expiry
+	msgCommitment, expiry := MessageCommitment(round.temp.m), expiryUnix(round.SessionExpiry())
+	var msgCulprits, expiryCulprits []*tss.PartyID
+	for j, Pj := range round.Parties().IDs() {
+		if j == i {
+			continue
+		}
+		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
+		if !bytes.Equal(r1msg2.GetMessageCommitment(), msgCommitment) {
+			msgCulprits = append(msgCulprits, Pj)
+		}
+		if r1msg2.GetExpiry() != expiry {
+			expiryCulprits = append(expiryCulprits, Pj)
+		}
+	}
+	if len(msgCulprits) > 0 {
+		return round.WrapError(errors.New("parties committed to signing a different message than this party"), msgCulprits...)
+	}
+	if len(expiryCulprits) > 0 {
+		return round.WrapError(errors.New("parties committed to a different session expiry than this party"), expiryCulprits...)
+	}
+
 	errChs := make(chan *tss.Error, (len(round.Parties().IDs())-1)*2)
 	wg := sync.WaitGroup{}
 	wg.Add((len(round.Parties().IDs()) - 1) * 2)
@@ -34,8 +59,9 @@ func (round *round2) Start() *tss.Error {
 		if j == i {
 			continue
 		}
+		j, Pj := j, Pj
 		// Bob_mid
-		go func(j int, Pj *tss.PartyID) {
+		round.Go(func() {
 			defer wg.Done()
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
@@ -43,7 +69,9 @@ func (round *round2) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "MtA: UnmarshalRangeProofAlice failed"), Pj)
 				return
 			}
-			betaJI, c1JI, _, pi1JI, err := mta.BobMid(
+			betaJI, c1JI, _, pi1JI, err := mta.BobMidWithHash(
+				round.EC(),
+				round.MtAHash(),
 				round.key.PaillierPKs[j],
 				rangeProofAliceJ,
 				round.temp.gammaI,
@@ -53,7 +81,8 @@ func (round *round2) Start() *tss.Error {
 				round.key.H2j[j],
 				round.key.NTildej[i],
 				round.key.H1j[i],
-				round.key.H2j[i])
+				round.key.H2j[i],
+				round.ModExpBackend())
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
 				return
@@ -63,9 +92,9 @@ func (round *round2) Start() *tss.Error {
 			round.temp.r5AbortData.BetaJI[j] = betaJI.Bytes()
 			round.temp.pI1JIs[j] = pi1JI
 			round.temp.c1JIs[j] = c1JI
-		}(j, Pj)
+		})
 		// Bob_mid_wc
-		go func(j int, Pj *tss.PartyID) {
+		round.Go(func() {
 			defer wg.Done()
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
@@ -73,7 +102,9 @@ func (round *round2) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "MtA: UnmarshalRangeProofAlice failed"), Pj)
 				return
 			}
-			vJI, c2JI, pi2JI, err := mta.BobMidWC(
+			vJI, c2JI, pi2JI, err := mta.BobMidWCWithHash(
+				round.EC(),
+				round.MtAHash(),
 				round.key.PaillierPKs[j],
 				rangeProofAliceJ,
 				round.temp.wI,
@@ -84,7 +115,8 @@ func (round *round2) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.H1j[i],
 				round.key.H2j[i],
-				round.temp.bigWs[i])
+				round.temp.bigWs[i],
+				round.ModExpBackend())
 			if err != nil {
 				errChs <- round.WrapError(err, Pj)
 				return
@@ -92,17 +124,19 @@ func (round *round2) Start() *tss.Error {
 			round.temp.vJIs[j] = vJI
 			round.temp.pI2JIs[j] = pi2JI
 			round.temp.c2JIs[j] = c2JI
-		}(j, Pj)
+		})
 	}
 	// consume error channels; wait for goroutines
 	wg.Wait()
 	close(errChs)
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	var multiErr error
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		multiErr = multierror.Append(multiErr, err.Cause())
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("MtA: failed to verify Bob_mid or Bob_mid_wc"), culprits...)
+		return round.WrapError(fmt.Errorf("MtA: failed to verify Bob_mid or Bob_mid_wc: %w", multiErr), culprits...)
 	}
 	// create and send messages
 	for j, Pj := range round.Parties().IDs() {
@@ -110,12 +144,12 @@ func (round *round2) Start() *tss.Error {
 			continue
 		}
 		r2msg := NewSignRound2Message(
-			Pj, round.PartyID(),
+			Pj, round.PartyID(), round.temp.sessionID,
 			round.temp.c1JIs[j],
 			round.temp.pI1JIs[j],
 			round.temp.c2JIs[j],
 			round.temp.pI2JIs[j])
-		round.out <- r2msg
+		round.out <- round.UseCodec(r2msg)
 	}
 	return nil
 }
@@ -136,8 +170,8 @@ func (round *round2) Update() (bool, *tss.Error) {
 }
 
 func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound2Message); ok {
-		return !msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound2Message); ok {
+		return !msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
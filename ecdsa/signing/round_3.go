@@ -8,9 +8,11 @@ package signing
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 
+	"github.com/hashicorp/go-multierror"
 	errorspkg "github.com/pkg/errors"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -43,8 +45,9 @@ func (round *round3) Start() *tss.Error {
 		if j == i {
 			continue
 		}
+		j, Pj := j, Pj
 		// Alice_end
-		go func(j int, Pj *tss.PartyID) {
+		round.Go(func() {
 			defer wg.Done()
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
 			proofBob, err := r2msg.UnmarshalProofBob()
@@ -52,7 +55,9 @@ func (round *round3) Start() *tss.Error {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "MtA: UnmarshalProofBob failed"), Pj)
 				return
 			}
-			alphaIJ, err := mta.AliceEnd(
+			alphaIJ, err := mta.AliceEndWithHash(
+				round.EC(),
+				round.MtAHash(),
 				round.key.PaillierPKs[i],
 				proofBob,
 				round.key.H1j[i],
@@ -60,24 +65,27 @@ func (round *round3) Start() *tss.Error {
 				round.temp.c1Is[j],
 				new(big.Int).SetBytes(r2msg.GetC1()),
 				round.key.NTildej[i],
-				round.key.PaillierSK)
+				round.key.PaillierSK,
+				round.ModExpBackend())
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(aliceEndError("Alice_end", Pj, err), Pj)
 				return
 			}
 			alphaIJs[j] = alphaIJ
 			round.temp.r5AbortData.AlphaIJ[j] = alphaIJ.Bytes()
-		}(j, Pj)
+		})
 		// Alice_end_wc
-		go func(j int, Pj *tss.PartyID) {
+		round.Go(func() {
 			defer wg.Done()
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
-			proofBobWC, err := r2msg.UnmarshalProofBobWC()
+			proofBobWC, err := r2msg.UnmarshalProofBobWC(round.EC())
 			if err != nil {
 				errChs <- round.WrapError(errorspkg.Wrapf(err, "MtA: UnmarshalProofBobWC failed"), Pj)
 				return
 			}
-			muIJ, muIJRec, muIJRand, err := mta.AliceEndWC(
+			muIJ, muIJRec, muIJRand, err := mta.AliceEndWCWithHash(
+				round.EC(),
+				round.MtAHash(),
 				round.key.PaillierPKs[i],
 				proofBobWC,
 				round.temp.bigWs[j],
@@ -86,32 +94,35 @@ func (round *round3) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.H1j[i],
 				round.key.H2j[i],
-				round.key.PaillierSK)
+				round.key.PaillierSK,
+				round.ModExpBackend())
 			if err != nil {
-				errChs <- round.WrapError(err, Pj)
+				errChs <- round.WrapError(aliceEndError("Alice_end_wc", Pj, err), Pj)
 				return
 			}
 			muIJs[j] = muIJ       // mod q'd
 			muIJRecs[j] = muIJRec // raw recovered
 			muRandIJ[j] = muIJRand
-		}(j, Pj)
+		})
 	}
 
 	// consume error channels; wait for goroutines
 	wg.Wait()
 	close(errChs)
 	culprits := make([]*tss.PartyID, 0, len(round.Parties().IDs()))
+	var multiErr error
 	for err := range errChs {
 		culprits = append(culprits, err.Culprits()...)
+		multiErr = multierror.Append(multiErr, err.Cause())
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...)
+		return round.WrapError(fmt.Errorf("failed to calculate Alice_end or Alice_end_wc: %w", multiErr), culprits...)
 	}
 	// for identifying aborts in round 7: muIJs, revealed during Type 7 identified abort
 	round.temp.r7AbortData.MuIJ = common.BigIntsToBytes(muIJRecs)
 	round.temp.r7AbortData.MuRandIJ = common.BigIntsToBytes(muRandIJ)
 
-	q := tss.EC().Params().N
+	q := round.EC().Params().N
 	modN := common.ModInt(q)
 
 	kI := new(big.Int).SetBytes(round.temp.KI)
@@ -137,18 +148,18 @@ func (round *round3) Start() *tss.Error {
 
 	// gg20: calculate T_i = g^sigma_i h^l_i
 	lI := common.GetRandomPositiveInt(q)
-	h, err := crypto.ECBasePoint2(tss.EC())
+	h, err := crypto.ECBasePoint2(round.EC())
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	hLI := h.ScalarMult(lI)
-	gSigmaI := crypto.ScalarBaseMult(tss.EC(), sigmaI)
+	gSigmaI := crypto.ScalarBaseMult(round.EC(), sigmaI)
 	TI, err := gSigmaI.Add(hLI)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
-	// gg20: generate the ZK proof of T_i, verified in ValidateBasic for the round 3 message
-	tProof, err := zkp.NewTProof(TI, h, sigmaI, lI)
+	// gg20: generate the ZK proof of T_i, which the other parties verify in round 4 on the session's curve
+	tProof, err := zkp.NewTProof(round.EC(), TI, h, sigmaI, lI)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -157,10 +168,12 @@ func (round *round3) Start() *tss.Error {
 	round.temp.lI = lI
 	round.temp.deltaI = deltaI
 	round.temp.sigmaI = sigmaI
+	// copied, as sigma_i is erased in round 6
+	round.temp.mtaArtifacts = &MtAArtifacts{KI: kI, DeltaI: new(big.Int).Set(deltaI), SigmaI: new(big.Int).Set(sigmaI)}
 
-	r3msg := NewSignRound3Message(Pi, deltaI, TI, tProof)
+	r3msg := NewSignRound3Message(Pi, round.temp.sessionID, deltaI, TI, tProof)
 	round.temp.signRound3Messages[i] = r3msg
-	round.out <- r3msg
+	round.out <- round.UseCodec(r3msg)
 	return nil
 }
 
@@ -180,8 +193,8 @@ func (round *round3) Update() (bool, *tss.Error) {
 }
 
 func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound3Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound3Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -190,3 +203,14 @@ func (round *round3) NextRound() tss.Round {
 	round.started = false
 	return &round4{round}
 }
+
+// aliceEndError names the peer of a failed Alice_end or Alice_end_wc and, when Bob's proof failed to verify, sets Pj as
+// the culprit of the *mta.VerifyError and tags the error as a bad proof
+func aliceEndError(step string, Pj *tss.PartyID, err error) error {
+	var verifyErr *mta.VerifyError
+	if errors.As(err, &verifyErr) {
+		verifyErr.Culprit = Pj
+		return tss.NewFaultError(tss.FaultBadProof, fmt.Errorf("MtA: %s failed: %w", step, err))
+	}
+	return fmt.Errorf("MtA: %s with %s failed: %w", step, Pj, err)
+}
@@ -8,7 +8,9 @@ package signing
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/ordinox/thorchain-tss-lib/crypto"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -23,9 +25,35 @@ func (round *round4) Start() *tss.Error {
 	Pi := round.PartyID()
 	i := Pi.Index
 
-	r4msg := NewSignRound4Message(Pi, round.temp.deCommit)
+	// verify the TProofs sent in round 3 on this session's curve
+	h, err := crypto.ECBasePoint2(round.EC())
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	culprits, _ := round.VerifyPeers(func(j int, Pj *tss.PartyID) error {
+		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
+		TI, err := r3msg.UnmarshalTI(round.EC())
+		if err != nil {
+			return err
+		}
+		tProof, err := r3msg.UnmarshalTProof(round.EC())
+		ok := err == nil && tProof.Verify(round.EC(), TI, h)
+		round.ObserveProof(tss.ProofT, ok)
+		if !ok {
+			return errors.New("TProof verification failed")
+		}
+		return nil
+	})
+	if err := round.QuorumError(culprits); err != nil {
+		return round.WrapError(tss.NewFaultError(tss.FaultBadProof, fmt.Errorf("round 3 TProof verification failed: %w", err)), culprits...)
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("round 3 TProof verification failed")), culprits...)
+	}
+
+	r4msg := NewSignRound4Message(Pi, round.temp.sessionID, round.temp.deCommit)
 	round.temp.signRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.out <- round.UseCodec(r4msg)
 	return nil
 }
 
@@ -45,8 +73,8 @@ func (round *round4) Update() (bool, *tss.Error) {
 }
 
 func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound4Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound4Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -30,7 +30,7 @@ func (round *round5) Start() *tss.Error {
 	Pi := round.PartyID()
 	i := Pi.Index
 
-	modN := common.ModInt(tss.EC().Params().N)
+	modN := common.ModInt(round.EC().Params().N)
 
 	bigR := round.temp.gammaIG
 	deltaI := *round.temp.deltaI
@@ -55,7 +55,7 @@ func (round *round5) Start() *tss.Error {
 		if !ok || len(bigGammaJ) != 2 {
 			return round.WrapError(errors.New("commitment verify failed"), Pj)
 		}
-		bigGammaJPoint, err := crypto.NewECPoint(tss.EC(), bigGammaJ[0], bigGammaJ[1])
+		bigGammaJPoint, err := crypto.NewECPoint(round.EC(), bigGammaJ[0], bigGammaJ[1])
 		if err != nil {
 			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj)
 		}
@@ -108,9 +108,9 @@ func (round *round5) Start() *tss.Error {
 	}
 	pdlWSlackPf := zkp.NewPDLwSlackProof(pdlWSlackWitness, pdlWSlackStatement)
 
-	r5msg := NewSignRound5Message(Pi, bigRBarI, &pdlWSlackPf)
+	r5msg := NewSignRound5Message(Pi, round.temp.sessionID, bigRBarI, &pdlWSlackPf)
 	round.temp.signRound5Messages[i] = r5msg
-	round.out <- r5msg
+	round.out <- round.UseCodec(r5msg)
 	return nil
 }
 
@@ -130,8 +130,8 @@ func (round *round5) Update() (bool, *tss.Error) {
 }
 
 func (round *round5) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound5Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound5Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
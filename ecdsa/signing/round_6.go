@@ -30,7 +30,7 @@ func (round *round6) Start() *tss.Error {
 	Pi := round.PartyID()
 	i := Pi.Index
 
-	bigR, _ := crypto.NewECPointFromProtobuf(round.temp.BigR)
+	bigR, _ := crypto.NewECPointFromProtobuf(round.EC(), round.temp.BigR)
 
 	sigmaI := round.temp.sigmaI
 	defer func() {
@@ -40,15 +40,17 @@ func (round *round6) Start() *tss.Error {
 
 	errs := make(map[*tss.PartyID]error)
 	bigRBarJProducts := (*crypto.ECPoint)(nil)
+	bigRBarJs := make([]*crypto.ECPoint, len(round.temp.signRound5Messages))
 	BigRBarJ := make(map[string]*common.ECPoint, len(round.temp.signRound5Messages))
 	for j, msg := range round.temp.signRound5Messages {
 		Pj := round.Parties().IDs()[j]
 		r5msg := msg.Content().(*SignRound5Message)
-		bigRBarJ, err := r5msg.UnmarshalRI()
+		bigRBarJ, err := r5msg.UnmarshalRI(round.EC())
 		if err != nil {
 			errs[Pj] = err
 			continue
 		}
+		bigRBarJs[j] = bigRBarJ
 		BigRBarJ[Pj.Id] = bigRBarJ.ToProtobufPoint()
 
 		// find products of all Rdash_i to ensure it equals the G point of the curve
@@ -60,29 +62,38 @@ func (round *round6) Start() *tss.Error {
 			errs[Pj] = err
 			continue
 		}
-
-		if j == i {
-			continue
+	}
+	// verify ZK proof of consistency between R_i and E_i(k_i)
+	// ported from: https://git.io/Jf69a
+	culprits, culpritErrs := round.VerifyPeers(func(j int, Pj *tss.PartyID) error {
+		if err, found := errs[Pj]; found {
+			return err // already a culprit
 		}
-		// verify ZK proof of consistency between R_i and E_i(k_i)
-		// ported from: https://git.io/Jf69a
-		pdlWSlackPf, err := r5msg.UnmarshalPDLwSlackProof()
+		r5msg := round.temp.signRound5Messages[j].Content().(*SignRound5Message)
+		pdlWSlackPf, err := r5msg.UnmarshalPDLwSlackProof(round.EC())
 		if err != nil {
-			errs[Pj] = err
-			continue
+			return err
 		}
 		r1msg1 := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 		pdlWSlackStatement := zkp.PDLwSlackStatement{
 			PK:         round.key.PaillierPKs[Pj.Index],
 			CipherText: new(big.Int).SetBytes(r1msg1.GetC()),
-			Q:          bigRBarJ,
+			Q:          bigRBarJs[j],
 			G:          bigR,
 			H1:         round.key.H1j[Pj.Index],
 			H2:         round.key.H2j[Pj.Index],
 			NTilde:     round.key.NTildej[Pj.Index], // maybe i
 		}
-		if !pdlWSlackPf.Verify(pdlWSlackStatement) {
-			errs[Pj] = fmt.Errorf("failed to verify ZK proof of consistency between R_i and E_i(k_i) for P %d", j)
+		ok := pdlWSlackPf.Verify(pdlWSlackStatement)
+		round.ObserveProof(tss.ProofPDL, ok)
+		if !ok {
+			return tss.NewFaultError(tss.FaultBadProof, fmt.Errorf("failed to verify ZK proof of consistency between R_i and E_i(k_i) for P %d", j))
+		}
+		return nil
+	})
+	for k, Pj := range culprits {
+		if _, found := errs[Pj]; !found {
+			errs[Pj] = culpritErrs[k]
 		}
 	}
 	if 0 < len(errs) {
@@ -92,18 +103,21 @@ func (round *round6) Start() *tss.Error {
 			multiErr = multierror.Append(multiErr, err)
 			culprits = append(culprits, Pj)
 		}
+		if err := round.QuorumError(culprits); err != nil {
+			multiErr = multierror.Append(multiErr, err)
+		}
 		return round.WrapError(multiErr, culprits...)
 	}
 	{
-		ec := tss.EC()
+		ec := round.EC()
 		gX, gY := ec.Params().Gx, ec.Params().Gy
 		if bigRBarJProducts.X().Cmp(gX) != 0 || bigRBarJProducts.Y().Cmp(gY) != 0 {
 			round.abortingT5 = true
 			common.Logger.Warnf("round 6: consistency check failed: g != R products, entering Type 5 identified abort")
 
-			r6msg := NewSignRound6MessageAbort(Pi, &round.temp.r5AbortData)
+			r6msg := NewSignRound6MessageAbort(Pi, round.temp.sessionID, &round.temp.r5AbortData)
 			round.temp.signRound6Messages[i] = r6msg
-			round.out <- r6msg
+			round.out <- round.UseCodec(r6msg)
 			return nil
 		}
 	}
@@ -115,7 +129,7 @@ func (round *round6) Start() *tss.Error {
 	// R^sigma_i proof used in type 7 aborts
 	bigSI := bigR.ScalarMult(sigmaI)
 	{
-		sigmaPf, err := zkp.NewECSigmaIProof(tss.EC(), sigmaI, bigR, bigSI)
+		sigmaPf, err := zkp.NewECSigmaIProof(round.EC(), sigmaI, bigR, bigSI)
 		if err != nil {
 			return round.WrapError(err, Pi)
 		}
@@ -124,12 +138,12 @@ func (round *round6) Start() *tss.Error {
 		round.temp.r7AbortData.EcddhProofZ = sigmaPf.Z.Bytes()
 	}
 
-	h, err := crypto.ECBasePoint2(tss.EC())
+	h, err := crypto.ECBasePoint2(round.EC())
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	TI, lI := round.temp.TI, round.temp.lI
-	stPf, err := zkp.NewSTProof(TI, bigR, h, sigmaI, lI)
+	stPf, err := zkp.NewSTProof(round.EC(), TI, bigR, h, sigmaI, lI)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -137,9 +151,9 @@ func (round *round6) Start() *tss.Error {
 	round.temp.lI.Set(zero)
 	round.temp.TI, round.temp.lI = nil, nil
 
-	r6msg := NewSignRound6MessageSuccess(Pi, bigSI, stPf)
+	r6msg := NewSignRound6MessageSuccess(Pi, round.temp.sessionID, bigSI, stPf)
 	round.temp.signRound6Messages[i] = r6msg
-	round.out <- r6msg
+	round.out <- round.UseCodec(r6msg)
 	return nil
 }
 
@@ -159,8 +173,8 @@ func (round *round6) Update() (bool, *tss.Error) {
 }
 
 func (round *round6) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound6Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound6Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
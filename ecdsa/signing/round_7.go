@@ -29,7 +29,7 @@ func (round *round7) Start() *tss.Error {
 	Pi := round.PartyID()
 	i := Pi.Index
 
-	N := tss.EC().Params().N
+	N := round.EC().Params().N
 	modN := common.ModInt(N)
 
 	culprits := make([]*tss.PartyID, 0, len(round.temp.signRound6Messages))
@@ -54,7 +54,7 @@ func (round *round7) Start() *tss.Error {
 
 			// Check that value gamma_j (in MtA) is consistent with bigGamma_j that is de-committed in Phase 4
 			gammaJ := new(big.Int).SetBytes(r6msg.GetGammaI())
-			gammaJG := crypto.ScalarBaseMult(tss.EC(), gammaJ)
+			gammaJG := crypto.ScalarBaseMult(round.EC(), gammaJ)
 			if !gammaJG.Equals(round.temp.bigGammaJs[j]) {
 				culprits = append(culprits, Pj)
 				continue
@@ -92,9 +92,9 @@ func (round *round7) Start() *tss.Error {
 
 	// bigR is stored as bytes for the OneRoundData protobuf struct
 	bigRX, bigRY := new(big.Int).SetBytes(round.temp.BigR.GetX()), new(big.Int).SetBytes(round.temp.BigR.GetY())
-	bigR := crypto.NewECPointNoCurveCheck(tss.EC(), bigRX, bigRY)
+	bigR := crypto.NewECPointNoCurveCheck(round.EC(), bigRX, bigRY)
 
-	h, err := crypto.ECBasePoint2(tss.EC())
+	h, err := crypto.ECBasePoint2(round.EC())
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -113,13 +113,13 @@ func (round *round7) Start() *tss.Error {
 		}
 		r6msg := r6msgInner.Success
 
-		TI, err := r3msg.UnmarshalTI()
+		TI, err := r3msg.UnmarshalTI(round.EC())
 		if err != nil {
 			culprits = append(culprits, Pj)
 			multiErr = multierror.Append(multiErr, err)
 			continue
 		}
-		bigSI, err := r6msg.UnmarshalSI()
+		bigSI, err := r6msg.UnmarshalSI(round.EC())
 		if err != nil {
 			culprits = append(culprits, Pj)
 			multiErr = multierror.Append(multiErr, err)
@@ -129,15 +129,17 @@ func (round *round7) Start() *tss.Error {
 
 		// ZK STProof check
 		if j != i {
-			stProof, err := r6msg.UnmarshalSTProof()
+			stProof, err := r6msg.UnmarshalSTProof(round.EC())
 			if err != nil {
 				culprits = append(culprits, Pj)
 				multiErr = multierror.Append(multiErr, err)
 				continue
 			}
-			if ok := stProof.Verify(bigSI, TI, bigR, h); !ok {
+			ok := stProof.Verify(round.EC(), bigSI, TI, bigR, h)
+			round.ObserveProof(tss.ProofST, ok)
+			if !ok {
 				culprits = append(culprits, Pj)
-				multiErr = multierror.Append(multiErr, errors.New("STProof verify failure"))
+				multiErr = multierror.Append(multiErr, tss.NewFaultError(tss.FaultBadProof, errors.New("STProof verify failure")))
 				continue
 			}
 		}
@@ -164,9 +166,9 @@ func (round *round7) Start() *tss.Error {
 		common.Logger.Warnf("round 7: consistency check failed: y != bigSJ products, entering Type 7 identified abort")
 
 		// If we abort here, one-round mode won't matter now - we will proceed to round "8" anyway.
-		r7msg := NewSignRound7MessageAbort(Pi, &round.temp.r7AbortData)
+		r7msg := NewSignRound7MessageAbort(Pi, round.temp.sessionID, &round.temp.r7AbortData)
 		round.temp.signRound7Messages[i] = r7msg
-		round.out <- r7msg
+		round.out <- round.UseCodec(r7msg)
 		return nil
 	}
 	// wipe sensitive data for gc, not used from here
@@ -175,8 +177,14 @@ func (round *round7) Start() *tss.Error {
 	// PRE-PROCESSING FINISHED
 	// If we are in one-round signing mode (msg is nil), we will exit out with the current state here and we are done.
 	round.temp.T = int32(len(round.Parties().IDs()) - 1)
+	round.temp.SignatureData_OneRoundData.Expiry = expiryUnix(round.SessionExpiry())
 	round.data.OneRoundData = &round.temp.SignatureData_OneRoundData
 	if round.temp.m == nil {
+		transcriptHash, err := round.transcriptHash()
+		if err != nil {
+			return round.WrapError(err)
+		}
+		round.data.TranscriptHash = transcriptHash
 		round.end <- round.data
 		for j := range round.ok {
 			round.ok[j] = true
@@ -185,12 +193,15 @@ func (round *round7) Start() *tss.Error {
 	}
 
 	// Continuing the full online protocol.
-	sI := FinalizeGetOurSigShare(round.data, round.temp.m)
+	sI, err := FinalizeGetOurSigShareUnexpired(round.EC(), round.data, round.temp.m)
+	if err != nil {
+		return round.WrapError(err)
+	}
 	round.temp.sI = sI
 
-	r7msg := NewSignRound7MessageSuccess(round.PartyID(), sI)
+	r7msg := NewSignRound7MessageSuccess(round.PartyID(), round.temp.sessionID, sI)
 	round.temp.signRound7Messages[i] = r7msg
-	round.out <- r7msg
+	round.out <- round.UseCodec(r7msg)
 	return nil
 }
 
@@ -212,8 +223,8 @@ func (round *round7) Update() (bool, *tss.Error) {
 
 func (round *round7) CanAccept(msg tss.ParsedMessage) bool {
 	// Collect messages for the full online protocol OR identified abort of type 7.
-	if _, ok := msg.Content().(*SignRound7Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound7Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -7,6 +7,8 @@
 package signing
 
 import (
+	"bytes"
+
 	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -58,6 +60,11 @@ type (
 	finalization struct {
 		*round7
 	}
+
+	// sessionMessage is a message of any round, each of which carries the session ID
+	sessionMessage interface {
+		GetSessionId() []byte
+	}
 )
 
 var (
@@ -81,6 +88,12 @@ func (round *base) RoundNumber() int {
 	return round.number
 }
 
+// inSession reports whether a message carries the ID of this session, so that one relayed from another session is not
+// accepted
+func (round *base) inSession(content sessionMessage) bool {
+	return bytes.Equal(content.GetSessionId(), round.temp.sessionID)
+}
+
 // CanProceed is inherited by other rounds
 func (round *base) CanProceed() bool {
 	if !round.started {
@@ -0,0 +1,71 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+)
+
+// EncryptSigShare is called in one-round signing mode to encrypt the party's signature share s_i for `msg` under an
+// escrow's Paillier key, with a proof that it is the share. It lets a party hand its share to a counterparty in a
+// fair exchange without revealing it: the escrow decrypts it only if the exchange must be settled without the party.
+func EncryptSigShare(ec elliptic.Curve, state *SignatureData, msg *big.Int, escrowPK *paillier.PublicKey) (*zkp.VerifiableEncryption, error) {
+	data := state.GetOneRoundData()
+	if data == nil {
+		return nil, errors.New("the signature data holds no one-round data")
+	}
+	bigR, err := crypto.NewECPointFromProtobuf(ec, data.GetBigR())
+	if err != nil {
+		return nil, fmt.Errorf("the one-round data holds an invalid R: %v", err)
+	}
+	sI, err := FinalizeGetOurSigShareUnexpired(ec, state, msg)
+	if err != nil {
+		return nil, err
+	}
+	return zkp.NewVerifiableEncryption(escrowPK, bigR, bigR.ScalarMult(sI), sI)
+}
+
+// SigShareCheckPoint returns R^s_j = R_bar_j^m * S_j^r, the point that the signature share s_j of the party `id`
+// for `msg` must match
+func (pre *PreSignature) SigShareCheckPoint(id string, msg *big.Int) (*crypto.ECPoint, error) {
+	bigRBarJ, bigSJ := pre.BigRBarJ[id], pre.BigSJ[id]
+	if bigRBarJ == nil || bigSJ == nil {
+		return nil, fmt.Errorf("the presignature holds no R_bar_j and S_j for %s", id)
+	}
+	return bigRBarJ.ScalarMult(msg).Add(bigSJ.ScalarMult(pre.BigR.X()))
+}
+
+// VerifySigShareEncryption checks that `ve` encrypts under the escrow's key the signature share of the party `id`
+// for `msg`, so that it can be accepted in a fair exchange
+func VerifySigShareEncryption(pre *PreSignature, id string, msg *big.Int, escrowPK *paillier.PublicKey, ve *zkp.VerifiableEncryption) error {
+	checkPoint, err := pre.SigShareCheckPoint(id, msg)
+	if err != nil {
+		return err
+	}
+	if !ve.Verify(escrowPK, pre.BigR, checkPoint) {
+		return fmt.Errorf("the verifiable encryption of %s is not of its signature share", id)
+	}
+	return nil
+}
+
+// DecryptSigShare is called by the escrow to recover the signature share of the party `id` for `msg` from a
+// verifiable encryption that VerifySigShareEncryption accepted. The share can then be passed to
+// FinalizeGetAndVerifyFinalSig in place of the party's.
+func DecryptSigShare(pre *PreSignature, id string, msg *big.Int, escrowSK *paillier.PrivateKey, ve *zkp.VerifiableEncryption) (*big.Int, error) {
+	checkPoint, err := pre.SigShareCheckPoint(id, msg)
+	if err != nil {
+		return nil, err
+	}
+	return ve.Decrypt(escrowSK, pre.BigR, checkPoint)
+}
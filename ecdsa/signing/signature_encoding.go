@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// Encoding is a wire format of an ECDSA signature
+type Encoding int
+
+const (
+	// EncodingCompact is r || s, each padded to the byte length of the curve order
+	EncodingCompact Encoding = iota
+	// EncodingCompactRecoverable is EncodingCompact followed by the recovery id byte, as in Ethereum
+	EncodingCompactRecoverable
+	// EncodingDER is the ASN.1 DER SEQUENCE { r INTEGER, s INTEGER }, as in Bitcoin and X.509
+	EncodingDER
+)
+
+// EstimateSignatureSize returns the byte size of a signature on the default curve tss.EC() in the given encoding,
+// so that a fee can be computed before signing. The compact encodings have a fixed size. The size of a DER encoding
+// depends on r and s, so it is the largest size that it can have and a fee estimated with it is never too low; a
+// low-S signature, which this library always produces, is a byte shorter for secp256k1.
+// It returns 0 for an unknown encoding.
+func EstimateSignatureSize(encoding Encoding, lowS bool) int {
+	return EstimateSignatureSizeOf(tss.EC(), encoding, lowS)
+}
+
+// EstimateSignatureSizeOf is EstimateSignatureSize on the given curve
+func EstimateSignatureSizeOf(ec elliptic.Curve, encoding Encoding, lowS bool) int {
+	N := ec.Params().N
+	orderLen := common.ByteLen(N)
+	switch encoding {
+	case EncodingCompact:
+		return 2 * orderLen
+	case EncodingCompactRecoverable:
+		return 2*orderLen + 1
+	case EncodingDER:
+		// a positive INTEGER has a leading 0 byte if its high bit is set; s <= N/2 has one bit fewer than N
+		rLen, sLen := N.BitLen()/8+1, N.BitLen()/8+1
+		if lowS {
+			sLen = (N.BitLen()-1)/8 + 1
+		}
+		seqLen := derTLVLen(rLen) + derTLVLen(sLen)
+		return derTLVLen(seqLen)
+	}
+	return 0
+}
+
+// SerializeSignature encodes a signature made on the curve `ec` in the given encoding
+func SerializeSignature(ec elliptic.Curve, sig *common.ECSignature, encoding Encoding) ([]byte, error) {
+	if sig == nil || len(sig.GetR()) == 0 || len(sig.GetS()) == 0 {
+		return nil, errors.New("SerializeSignature() received a signature without r or s")
+	}
+	orderLen := common.ByteLen(ec.Params().N)
+	r, s := new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())
+	if orderLen < len(r.Bytes()) || orderLen < len(s.Bytes()) {
+		return nil, errors.New("SerializeSignature() received an r or s too large for the curve")
+	}
+	switch encoding {
+	case EncodingCompact, EncodingCompactRecoverable:
+		out := make([]byte, 0, 2*orderLen+1)
+		out = append(append(out, common.PadToLen(r, orderLen)...), common.PadToLen(s, orderLen)...)
+		if encoding == EncodingCompactRecoverable {
+			if len(sig.GetSignatureRecovery()) != 1 {
+				return nil, errors.New("SerializeSignature() received a signature without a recovery id")
+			}
+			out = append(out, sig.GetSignatureRecovery()[0])
+		}
+		return out, nil
+	case EncodingDER:
+		return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	}
+	return nil, fmt.Errorf("unknown signature encoding %d", encoding)
+}
+
+// derTLVLen is the length of a DER element whose content is `contentLen` bytes long
+func derTLVLen(contentLen int) int {
+	switch {
+	case contentLen < 0x80:
+		return 2 + contentLen
+	case contentLen <= 0xff:
+		return 3 + contentLen
+	default:
+		return 4 + contentLen
+	}
+}
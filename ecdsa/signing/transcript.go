@@ -0,0 +1,54 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+const (
+	transcriptDomain = "tss-lib signing transcript"
+)
+
+// transcriptHash hashes the session ID and the broadcast messages that the party holds, in round order and, within a
+// round, in the order of the parties. The messages are hashed in their canonical protobuf encoding, whatever the codec
+// of the session, so every honest party that completes the session computes the same hash.
+func (round *base) transcriptHash() ([]byte, error) {
+	broadcasts := [][]tss.ParsedMessage{
+		round.temp.signRound1Message2s,
+		round.temp.signRound3Messages,
+		round.temp.signRound4Messages,
+		round.temp.signRound5Messages,
+		round.temp.signRound6Messages,
+		round.temp.signRound7Messages,
+	}
+	parts := [][]byte{[]byte(transcriptDomain), round.SessionID(round.temp.m)}
+	for r, msgs := range broadcasts {
+		var position [16]byte
+		binary.BigEndian.PutUint64(position[:8], uint64(r))
+		for j, msg := range msgs {
+			binary.BigEndian.PutUint64(position[8:], uint64(j))
+			// a round that was not run, such as round 7 in one-round mode, is hashed as empty
+			var bz []byte
+			if msg != nil {
+				var err error
+				if bz, err = (protov2.MarshalOptions{Deterministic: true}).Marshal(proto.MessageV2(msg.Content())); err != nil {
+					return nil, fmt.Errorf("failed to encode the message of %s for the transcript: %v", msg.GetFrom(), err)
+				}
+			}
+			parts = append(parts, append(position[:], bz...))
+		}
+	}
+	return common.SHA512_256(parts...), nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+)
+
+// Verifier checks the signatures and participation proofs of a committee from its public data alone: the evaluation
+// points Ks_j and public shares X_j of its members and its public key. It holds no key share and cannot sign, so a
+// monitoring node outside the committee may use it.
+type Verifier struct {
+	ec        elliptic.Curve
+	threshold int
+	ks        []*big.Int
+	bigXj     []*crypto.ECPoint
+	pub       *crypto.ECPoint
+}
+
+// NewVerifier imports the public data of a committee, as found in `Ks`, `BigXj` and `ECDSAPub` of any member's save
+// data. It checks that the public shares lie on one degree-`threshold` polynomial in the exponent that interpolates to
+// `pub`, so that they cannot have been mixed from the data of different keys.
+func NewVerifier(ec elliptic.Curve, ks []*big.Int, bigXj []*crypto.ECPoint, threshold int, pub *crypto.ECPoint) (*Verifier, error) {
+	n := len(ks)
+	if len(bigXj) != n || n <= threshold || threshold < 0 {
+		return nil, fmt.Errorf("expected more than %d public shares, one for each of the %d members", threshold, n)
+	}
+	if pub == nil || !pub.ValidateBasic() {
+		return nil, errors.New("the public key is missing or not on the curve")
+	}
+	v := &Verifier{
+		ec:        ec,
+		threshold: threshold,
+		ks:        make([]*big.Int, n),
+		bigXj:     make([]*crypto.ECPoint, n),
+		pub:       crypto.NewECPointNoCurveCheck(ec, pub.X(), pub.Y()),
+	}
+	for j := range ks {
+		if ks[j] == nil || bigXj[j] == nil || !bigXj[j].ValidateBasic() {
+			return nil, fmt.Errorf("the public data of member %d is missing or not on the curve", j)
+		}
+		v.ks[j] = new(big.Int).Set(ks[j])
+		v.bigXj[j] = crypto.NewECPointNoCurveCheck(ec, bigXj[j].X(), bigXj[j].Y())
+	}
+	xs, Xs := v.ks[:threshold+1], v.bigXj[:threshold+1]
+	if interpolated, err := vss.InterpolateInExponent(xs, Xs, big.NewInt(0)); err != nil || !interpolated.Equals(v.pub) {
+		return nil, errors.New("the public shares do not interpolate to the public key")
+	}
+	for j := threshold + 1; j < n; j++ {
+		if Xj, err := vss.InterpolateInExponent(xs, Xs, v.ks[j]); err != nil || !Xj.Equals(v.bigXj[j]) {
+			return nil, fmt.Errorf("the public share of member %d is not consistent with the sharing", j)
+		}
+	}
+	return v, nil
+}
+
+// NewVerifierFromSaveData imports the public data of the committee from a member's save data, leaving out its share
+func NewVerifierFromSaveData(ec elliptic.Curve, key keygen.LocalPartySaveData, threshold int) (*Verifier, error) {
+	return NewVerifier(ec, key.Ks, key.BigXj, threshold, key.ECDSAPub)
+}
+
+// PublicKey returns the public key of the committee
+func (v *Verifier) PublicKey() *crypto.ECPoint {
+	return v.pub
+}
+
+// VerifySignature checks that `sig` is a signature of its message M under the public key of the committee
+func (v *Verifier) VerifySignature(sig *common.ECSignature) error {
+	if sig == nil || len(sig.GetR()) == 0 || len(sig.GetS()) == 0 {
+		return errors.New("the signature is incomplete")
+	}
+	r, s := new(big.Int).SetBytes(sig.GetR()), new(big.Int).SetBytes(sig.GetS())
+	if !ecdsa.Verify(v.pub.ToECDSAPubKey(), sig.GetM(), r, s) {
+		return errors.New("the signature does not verify under the public key of the committee")
+	}
+	return nil
+}
+
+// VerifyParticipationProof checks that `sig` is a signature by the committee and that `proof` holds attestations of it
+// by at least threshold+1 of its members
+func (v *Verifier) VerifyParticipationProof(sig *common.ECSignature, proof ParticipationProof) error {
+	if err := v.VerifySignature(sig); err != nil {
+		return err
+	}
+	return VerifyParticipationProof(v.ec, v.ks, v.bigXj, v.threshold, sig, proof)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestVerifierFromPublicShares(t *testing.T) {
+	ec := tss.EC()
+	keys, _, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+
+	// the monitoring node imports only the public data of the committee
+	ks, bigXj, pub := keys[0].Ks, keys[0].BigXj, keys[0].ECDSAPub
+	verifier, err := NewVerifier(ec, ks, bigXj, testThreshold, pub)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, verifier.PublicKey().Equals(pub))
+
+	// a signature under the committee's key, here made with the key reconstructed from the signers' shares
+	signerKs, signerXs := make([]*big.Int, len(keys)), make([]*crypto.ECPoint, len(keys))
+	for i, key := range keys {
+		signerKs[i], signerXs[i] = key.ShareID, crypto.ScalarBaseMult(ec, key.Xi)
+	}
+	x := new(big.Int)
+	for i, key := range keys {
+		wi, _, err := PrepareForSigning(ec, i, len(keys), key.Xi, signerKs, signerXs)
+		if !assert.NoError(t, err) {
+			return
+		}
+		x.Add(x, wi)
+	}
+	x.Mod(x, ec.Params().N)
+	digest := common.SHA512_256([]byte("a message"))
+	r, s, err := ecdsa.Sign(rand.Reader, &ecdsa.PrivateKey{PublicKey: *pub.ToECDSAPubKey(), D: x}, digest)
+	if !assert.NoError(t, err) {
+		return
+	}
+	sig := &common.ECSignature{R: r.Bytes(), S: s.Bytes(), M: digest}
+	assert.NoError(t, verifier.VerifySignature(sig))
+
+	proof := make(ParticipationProof, 0, len(keys))
+	for _, key := range keys {
+		att, err := NewParticipationAttestation(ec, key, sig)
+		if !assert.NoError(t, err) {
+			return
+		}
+		proof = append(proof, att)
+	}
+	assert.NoError(t, verifier.VerifyParticipationProof(sig, proof))
+	assert.Error(t, verifier.VerifyParticipationProof(sig, proof[1:]), "too few signers should be rejected")
+
+	// a signature of another message, or by another key, is rejected
+	other := &common.ECSignature{R: sig.GetR(), S: sig.GetS(), M: common.SHA512_256([]byte("another message"))}
+	assert.Error(t, verifier.VerifySignature(other))
+	assert.Error(t, verifier.VerifyParticipationProof(other, proof))
+	otherKey, err := ecdsa.GenerateKey(ec, rand.Reader)
+	if assert.NoError(t, err) {
+		r, s, err := ecdsa.Sign(rand.Reader, otherKey, digest)
+		assert.NoError(t, err)
+		assert.Error(t, verifier.VerifySignature(&common.ECSignature{R: r.Bytes(), S: s.Bytes(), M: digest}))
+	}
+
+	// public data that is not that of one key is rejected on import
+	otherPub := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+	_, err = NewVerifier(ec, ks, bigXj, testThreshold, otherPub)
+	assert.Error(t, err)
+	swapped := append([]*crypto.ECPoint(nil), bigXj...)
+	swapped[len(swapped)-1] = otherPub
+	_, err = NewVerifier(ec, ks, swapped, testThreshold, pub)
+	assert.Error(t, err)
+	_, err = NewVerifier(ec, ks[1:], bigXj, testThreshold, pub)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package signing2p implements two-party ECDSA signing after Lindell (2017), for a key shared by exactly two parties
+// with threshold 1. It needs no MtA: P1 holds a Paillier key and P2, given an encryption of P1's share, does the
+// homomorphic computation of the signature, so a signature takes four messages rather than the rounds of GG18.
+// The keys are taken from the save data of the general keygen run with two parties.
+package signing2p
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/paillier"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/signing"
+)
+
+type (
+	// P1Key is the key of P1, the party that holds the Paillier key
+	P1Key struct {
+		// the additive share w1 of the private key, x = w1 + w2
+		W1         *big.Int
+		PaillierSK *paillier.PrivateKey
+		ECDSAPub   *crypto.ECPoint
+	}
+
+	// P2Key is the key of P2, the party that does the homomorphic computation
+	P2Key struct {
+		// the additive share w2 of the private key, x = w1 + w2
+		W2         *big.Int
+		PaillierPK *paillier.PublicKey
+		// CKey is P1's encryption of its share w1 under its Paillier key
+		CKey     *big.Int
+		ECDSAPub *crypto.ECPoint
+	}
+
+	// KeySetupMessage is sent by P1 to P2 once, to give it the encryption of P1's share with a proof that it encrypts
+	// the share behind P1's public share W1 = w1*G
+	KeySetupMessage struct {
+		CKey  *big.Int
+		Proof zkp.PDLwSlackProof
+	}
+)
+
+// NewP1Key derives P1's key from its save data of a two-party keygen and returns the message that sets up P2's key
+func NewP1Key(key keygen.LocalPartySaveData) (*P1Key, *KeySetupMessage, error) {
+	ec, i, j, err := checkSaveData(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	w1, bigWs, err := signing.PrepareForSigning(ec, i, 2, key.Xi, key.Ks, key.BigXj)
+	if err != nil {
+		return nil, nil, err
+	}
+	cKey, rKey, err := key.PaillierSK.EncryptAndReturnRandomness(w1)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the proof is made with P2's NTilde, h1, h2
+	statement := zkp.PDLwSlackStatement{
+		PK:         &key.PaillierSK.PublicKey,
+		CipherText: cKey,
+		Q:          bigWs[i],
+		G:          crypto.ScalarBaseMult(ec, big.NewInt(1)),
+		H1:         key.H1j[j],
+		H2:         key.H2j[j],
+		NTilde:     key.NTildej[j],
+	}
+	proof := zkp.NewPDLwSlackProof(zkp.PDLwSlackWitness{SK: key.PaillierSK, X: w1, R: rKey}, statement)
+	p1Key := &P1Key{W1: w1, PaillierSK: key.PaillierSK, ECDSAPub: key.ECDSAPub}
+	return p1Key, &KeySetupMessage{CKey: cKey, Proof: proof}, nil
+}
+
+// NewP2Key derives P2's key from its save data of a two-party keygen and P1's setup message, checking that the
+// encryption in the message is of P1's share
+func NewP2Key(key keygen.LocalPartySaveData, msg *KeySetupMessage) (*P2Key, error) {
+	ec, i, j, err := checkSaveData(key)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.CKey == nil || !msg.Proof.ValidateBasic() {
+		return nil, errors.New("the key setup message is incomplete")
+	}
+	w2, bigWs, err := signing.PrepareForSigning(ec, i, 2, key.Xi, key.Ks, key.BigXj)
+	if err != nil {
+		return nil, err
+	}
+	paillierPK := key.PaillierPKs[j]
+	statement := zkp.PDLwSlackStatement{
+		PK:         paillierPK,
+		CipherText: msg.CKey,
+		Q:          bigWs[j],
+		G:          crypto.ScalarBaseMult(ec, big.NewInt(1)),
+		H1:         key.H1i,
+		H2:         key.H2i,
+		NTilde:     key.NTildei,
+	}
+	if !msg.Proof.Verify(statement) {
+		return nil, errors.New("the key setup proof of P1 failed to verify")
+	}
+	return &P2Key{W2: w2, PaillierPK: paillierPK, CKey: msg.CKey, ECDSAPub: key.ECDSAPub}, nil
+}
+
+// checkSaveData returns the curve of the save data of a two-party keygen with the index of this party and the other
+func checkSaveData(key keygen.LocalPartySaveData) (ec elliptic.Curve, i, j int, err error) {
+	if len(key.Ks) != 2 || len(key.BigXj) != 2 || len(key.PaillierPKs) != 2 || len(key.NTildej) != 2 ||
+		len(key.H1j) != 2 || len(key.H2j) != 2 {
+		return nil, 0, 0, fmt.Errorf("two-party signing needs the save data of a two-party keygen, got %d parties", len(key.Ks))
+	}
+	if key.Xi == nil || key.ShareID == nil || key.ECDSAPub == nil || key.PaillierSK == nil {
+		return nil, 0, 0, errors.New("the save data is incomplete")
+	}
+	for i = range key.Ks {
+		if key.Ks[i] != nil && key.Ks[i].Cmp(key.ShareID) == 0 {
+			return key.ECDSAPub.Curve(), i, 1 - i, nil
+		}
+	}
+	return nil, 0, 0, errors.New("the save data does not hold this party's share ID")
+}
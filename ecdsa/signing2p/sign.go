@@ -0,0 +1,223 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing2p
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	cmts "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+)
+
+type (
+	// SignMessage1 is sent by P1 to P2 to commit to its nonce point R1 = k1*G and the proof of k1
+	SignMessage1 struct {
+		Commitment cmts.HashCommitment
+	}
+
+	// SignMessage2 is sent by P2 to P1 with its nonce point R2 = k2*G and a proof of k2
+	SignMessage2 struct {
+		R2    *crypto.ECPoint
+		Proof *zkp.DLogProof
+	}
+
+	// SignMessage3 is sent by P1 to P2 to open its commitment to R1 and the proof of k1
+	SignMessage3 struct {
+		DeCommitment cmts.HashDeCommitment
+	}
+
+	// SignMessage4 is sent by P2 to P1 with the encryption of the signature before P1's nonce is applied
+	SignMessage4 struct {
+		C3 *big.Int
+	}
+
+	// P1Signer is P1's state in a signing session. A session signs a single message and must not be reused.
+	P1Signer struct {
+		key   *P1Key
+		msg   *big.Int
+		k1    *big.Int
+		cmt   *cmts.HashCommitDecommit
+		bigR  *crypto.ECPoint
+		state int
+	}
+
+	// P2Signer is P2's state in a signing session. A session signs a single message and must not be reused.
+	P2Signer struct {
+		key   *P2Key
+		msg   *big.Int
+		k2    *big.Int
+		bigR2 *crypto.ECPoint
+		cmt   cmts.HashCommitment
+		state int
+	}
+)
+
+// NewP1Signer starts P1's side of a session signing the digest `msg` and returns the message for P2
+func NewP1Signer(key *P1Key, msg *big.Int) (*P1Signer, *SignMessage1, error) {
+	if key == nil || key.W1 == nil || key.PaillierSK == nil || key.ECDSAPub == nil || msg == nil {
+		return nil, nil, errors.New("NewP1Signer() received a nil or incomplete argument")
+	}
+	ec := key.ECDSAPub.Curve()
+	k1 := common.GetRandomPositiveInt(ec.Params().N)
+	bigR1 := crypto.ScalarBaseMult(ec, k1)
+	proof, err := zkp.NewDLogProof(ec, k1, bigR1)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmt := cmts.NewHashCommitment(bigR1.X(), bigR1.Y(), proof.Alpha.X(), proof.Alpha.Y(), proof.T)
+	p1 := &P1Signer{key: key, msg: msg, k1: k1, cmt: cmt, state: 1}
+	return p1, &SignMessage1{Commitment: cmt.C}, nil
+}
+
+// NewP2Signer starts P2's side of a session signing the digest `msg` on P1's first message and returns the message
+// for P1
+func NewP2Signer(key *P2Key, msg *big.Int, msg1 *SignMessage1) (*P2Signer, *SignMessage2, error) {
+	if key == nil || key.W2 == nil || key.PaillierPK == nil || key.CKey == nil || key.ECDSAPub == nil || msg == nil {
+		return nil, nil, errors.New("NewP2Signer() received a nil or incomplete argument")
+	}
+	if msg1 == nil || msg1.Commitment == nil {
+		return nil, nil, errors.New("the first message of P1 is incomplete")
+	}
+	ec := key.ECDSAPub.Curve()
+	k2 := common.GetRandomPositiveInt(ec.Params().N)
+	bigR2 := crypto.ScalarBaseMult(ec, k2)
+	proof, err := zkp.NewDLogProof(ec, k2, bigR2)
+	if err != nil {
+		return nil, nil, err
+	}
+	p2 := &P2Signer{key: key, msg: msg, k2: k2, bigR2: bigR2, cmt: msg1.Commitment, state: 2}
+	return p2, &SignMessage2{R2: bigR2, Proof: proof}, nil
+}
+
+// Reveal checks P2's nonce point and opens P1's commitment
+func (p1 *P1Signer) Reveal(msg2 *SignMessage2) (*SignMessage3, error) {
+	if p1.state != 1 {
+		return nil, errors.New("Reveal() was called out of order")
+	}
+	p1.state = -1
+	ec := p1.key.ECDSAPub.Curve()
+	if msg2 == nil || msg2.R2 == nil || !msg2.R2.ValidateBasic() || !msg2.R2.IsOnCurve() {
+		return nil, errors.New("the nonce point R2 of P2 is invalid")
+	}
+	if !msg2.Proof.Verify(ec, msg2.R2) {
+		return nil, errors.New("the proof of P2's nonce failed to verify")
+	}
+	p1.bigR = msg2.R2.ScalarMult(p1.k1)
+	p1.state = 3
+	return &SignMessage3{DeCommitment: p1.cmt.D}, nil
+}
+
+// Sign checks P1's nonce point against its commitment and computes the encryption of the signature for P1
+func (p2 *P2Signer) Sign(msg3 *SignMessage3) (*SignMessage4, error) {
+	if p2.state != 2 {
+		return nil, errors.New("Sign() was called out of order")
+	}
+	p2.state = -1
+	ec := p2.key.ECDSAPub.Curve()
+	q := ec.Params().N
+	if msg3 == nil {
+		return nil, errors.New("the third message of P1 is nil")
+	}
+	cmtDeCmt := cmts.HashCommitDecommit{C: p2.cmt, D: msg3.DeCommitment}
+	ok, values := cmtDeCmt.DeCommit()
+	if !ok || len(values) != 5 {
+		return nil, errors.New("the de-commitment of P1 does not open its commitment")
+	}
+	bigR1, err := crypto.NewECPoint(ec, values[0], values[1])
+	if err != nil {
+		return nil, fmt.Errorf("the nonce point R1 of P1 is invalid: %v", err)
+	}
+	alpha, err := crypto.NewECPoint(ec, values[2], values[3])
+	if err != nil {
+		return nil, fmt.Errorf("the proof of P1's nonce is invalid: %v", err)
+	}
+	if proof := (&zkp.DLogProof{Alpha: alpha, T: values[4]}); !proof.Verify(ec, bigR1) {
+		return nil, errors.New("the proof of P1's nonce failed to verify")
+	}
+
+	// R = k2*R1 = k1*k2*G
+	r := new(big.Int).Mod(bigR1.ScalarMult(p2.k2).X(), q)
+	if r.Sign() == 0 {
+		return nil, errors.New("r is zero; start a new session")
+	}
+	// c3 = Enc(rho*q + k2^-1*(m + r*w2)) + k2^-1*r*Enc(w1), where rho masks the result mod N before P1 reduces it
+	modQ := common.ModInt(q)
+	k2Inv := modQ.Inverse(p2.k2)
+	rho := common.GetRandomPositiveInt(new(big.Int).Mul(q, q))
+	plain := new(big.Int).Mul(rho, q)
+	plain.Add(plain, modQ.Mul(k2Inv, modQ.Add(p2.msg, modQ.Mul(r, p2.key.W2))))
+	c1, err := p2.key.PaillierPK.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	c2, err := p2.key.PaillierPK.HomoMult(modQ.Mul(k2Inv, r), p2.key.CKey)
+	if err != nil {
+		return nil, err
+	}
+	c3, err := p2.key.PaillierPK.HomoAdd(c1, c2)
+	if err != nil {
+		return nil, err
+	}
+	p2.k2 = nil
+	p2.state = 4
+	return &SignMessage4{C3: c3}, nil
+}
+
+// Finalize decrypts P2's message into the signature, which it checks against the public key before returning it
+func (p1 *P1Signer) Finalize(msg4 *SignMessage4) (*common.ECSignature, error) {
+	if p1.state != 3 {
+		return nil, errors.New("Finalize() was called out of order")
+	}
+	p1.state = -1
+	if msg4 == nil || msg4.C3 == nil {
+		return nil, errors.New("the message of P2 is incomplete")
+	}
+	ec := p1.key.ECDSAPub.Curve()
+	N := ec.Params().N
+	modN := common.ModInt(N)
+
+	sPrm, err := p1.key.PaillierSK.Decrypt(msg4.C3)
+	if err != nil {
+		return nil, err
+	}
+	s := modN.Mul(modN.Inverse(p1.k1), sPrm)
+	p1.k1 = nil
+	r := new(big.Int).Mod(p1.bigR.X(), N)
+
+	// the recovery ID lets the public key be recovered from the signature
+	recId := 0
+	if p1.bigR.X().Cmp(N) >= 0 {
+		recId = 2
+	}
+	if p1.bigR.Y().Bit(0) != 0 {
+		recId |= 1
+	}
+	// use the low s form, as signing.FinalizeGetAndVerifyFinalSig does
+	if s.Cmp(new(big.Int).Rsh(N, 1)) > 0 {
+		s.Sub(N, s)
+		recId ^= 1
+	}
+
+	pk := ecdsa.PublicKey{Curve: ec, X: p1.key.ECDSAPub.X(), Y: p1.key.ECDSAPub.Y()}
+	if !ecdsa.Verify(&pk, p1.msg.Bytes(), r, s) {
+		return nil, errors.New("the signature failed to verify; P2 may have sent a bad message")
+	}
+	signature := new(common.ECSignature)
+	// r and s are padded to the width of the curve order, as signing.FinalizeGetAndVerifyFinalSig does
+	orderLen := common.ByteLen(N)
+	signature.R, signature.S = common.PadToLen(r, orderLen), common.PadToLen(s, orderLen)
+	signature.Signature = append(append([]byte(nil), signature.R...), signature.S...)
+	signature.SignatureRecovery = []byte{byte(recId)}
+	signature.M = p1.msg.Bytes()
+	p1.state = 5
+	return signature, nil
+}
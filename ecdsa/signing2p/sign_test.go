@@ -0,0 +1,174 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing2p
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestE2ETwoPartySigning(t *testing.T) {
+	keys := runTwoPartyKeygen(t)
+	if keys == nil {
+		return
+	}
+
+	p1Key, setup, err := NewP1Key(keys[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	p2Key, err := NewP2Key(keys[1], setup)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	digest := sha256.Sum256([]byte("two-party signing"))
+	msg := new(big.Int).SetBytes(digest[:])
+	for i := 0; i < 3; i++ {
+		signature, err := signTwoParty(p1Key, p2Key, msg)
+		if !assert.NoError(t, err) {
+			return
+		}
+		pk := ecdsa.PublicKey{Curve: tss.EC(), X: keys[0].ECDSAPub.X(), Y: keys[0].ECDSAPub.Y()}
+		r, s := new(big.Int).SetBytes(signature.R), new(big.Int).SetBytes(signature.S)
+		assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "the signature should verify under the keygen's public key")
+		// r and s are as wide as the curve order however small they are
+		orderLen := common.ByteLen(tss.EC().Params().N)
+		assert.Len(t, signature.R, orderLen)
+		assert.Len(t, signature.S, orderLen)
+		assert.Equal(t, append(append([]byte(nil), signature.R...), signature.S...), signature.Signature)
+	}
+
+	// a setup message whose ciphertext is not of P1's share is rejected
+	bad := *setup
+	bad.CKey = new(big.Int).Add(setup.CKey, big.NewInt(1))
+	_, err = NewP2Key(keys[1], &bad)
+	assert.Error(t, err)
+
+	// a setup message with any part of its proof missing is rejected before the proof is verified
+	for name, drop := range map[string]func(pf *zkp.PDLwSlackProof){
+		"Z":  func(pf *zkp.PDLwSlackProof) { pf.Z = nil },
+		"U1": func(pf *zkp.PDLwSlackProof) { pf.U1 = nil },
+		"U2": func(pf *zkp.PDLwSlackProof) { pf.U2 = nil },
+		"U3": func(pf *zkp.PDLwSlackProof) { pf.U3 = nil },
+		"S1": func(pf *zkp.PDLwSlackProof) { pf.S1 = nil },
+		"S2": func(pf *zkp.PDLwSlackProof) { pf.S2 = nil },
+		"S3": func(pf *zkp.PDLwSlackProof) { pf.S3 = nil },
+	} {
+		incomplete := *setup
+		drop(&incomplete.Proof)
+		assert.NotPanics(t, func() {
+			_, err = NewP2Key(keys[1], &incomplete)
+		}, "a proof without %s should not panic", name)
+		assert.Error(t, err, "a proof without %s should be rejected", name)
+	}
+
+	// a session with an opening of a different commitment is rejected by P2
+	signer1, msg1, err := NewP1Signer(p1Key, msg)
+	assert.NoError(t, err)
+	other, _, err := NewP1Signer(p1Key, msg)
+	assert.NoError(t, err)
+	signer2, msg2, err := NewP2Signer(p2Key, msg, msg1)
+	assert.NoError(t, err)
+	_, err = other.Reveal(msg2)
+	assert.NoError(t, err)
+	msg3, err := signer1.Reveal(msg2)
+	assert.NoError(t, err)
+	msg3.DeCommitment = other.cmt.D
+	_, err = signer2.Sign(msg3)
+	assert.Error(t, err)
+}
+
+func signTwoParty(p1Key *P1Key, p2Key *P2Key, msg *big.Int) (*common.ECSignature, error) {
+	signer1, msg1, err := NewP1Signer(p1Key, msg)
+	if err != nil {
+		return nil, err
+	}
+	signer2, msg2, err := NewP2Signer(p2Key, msg, msg1)
+	if err != nil {
+		return nil, err
+	}
+	msg3, err := signer1.Reveal(msg2)
+	if err != nil {
+		return nil, err
+	}
+	msg4, err := signer2.Sign(msg3)
+	if err != nil {
+		return nil, err
+	}
+	return signer1.Finalize(msg4)
+}
+
+// runTwoPartyKeygen runs the general keygen with two parties and threshold 1, returning the save data of each in the
+// order of the parties
+func runTwoPartyKeygen(t *testing.T) []keygen.LocalPartySaveData {
+	fixtures, pIDs, err := keygen.LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return nil
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*keygen.LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		P := keygen.NewLocalParty(params, outCh, endCh, fixtures[i].LocalPreParams).(*keygen.LocalParty)
+		parties = append(parties, P)
+		go func(P *keygen.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]keygen.LocalPartySaveData, len(pIDs))
+	ended := 0
+	for ended < len(pIDs) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			return nil
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			if !assert.NoError(t, err) {
+				return nil
+			}
+			saves[index] = save
+			ended++
+		}
+	}
+	return saves
+}
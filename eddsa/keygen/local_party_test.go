@@ -12,6 +12,7 @@ import (
 	"math/big"
 	"os"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -133,7 +134,7 @@ keygen:
 						}
 						pShares = append(pShares, shareStruct)
 					}
-					uj, err := pShares[:threshold+1].ReConstruct()
+					uj, err := pShares[:threshold+1].ReConstruct(tss.EC())
 					assert.NoError(t, err, "vss.ReConstruct should not throw error")
 
 					// uG test: u*G[j] == V[0]
@@ -151,7 +152,7 @@ keygen:
 					{
 						badShares := pShares[:threshold]
 						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
-						uj, err := pShares[:threshold].ReConstruct()
+						uj, err := pShares[:threshold].ReConstruct(tss.EC())
 						assert.NoError(t, err)
 						assert.NotEqual(t, parties[j].temp.ui, uj)
 						BigXjX, BigXjY := tss.EC().ScalarBaseMult(uj.Bytes())
@@ -213,6 +214,82 @@ keygen:
 	}
 }
 
+func TestProgressFunc(t *testing.T) {
+	setUp("info")
+	pIDs := tss.GenerateTestPartyIDs(3)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan LocalPartySaveData, len(pIDs))
+
+	var mtx sync.Mutex
+	progress := make([][]int, len(pIDs))
+	parties := make([]tss.Party, 0, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(p2pCtx, pIDs[i], len(pIDs), 1)
+		params.SetCurve(edwards.Edwards())
+		params.SetProgressFunc(func(round, totalRounds, partyIndex int) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			assert.Equal(t, 3, totalRounds)
+			progress[partyIndex] = append(progress[partyIndex], round)
+			// a faulty callback must not stop the protocol
+			if partyIndex == 0 {
+				panic("a bug in the progress bar")
+			}
+		})
+		parties = append(parties, NewLocalParty(params, outCh, endCh))
+	}
+	for _, P := range parties {
+		if err := P.Start(); !assert.Nil(t, err) {
+			return
+		}
+	}
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index || (msg.GetTo() != nil && msg.GetTo()[0].Index != P.PartyID().Index) {
+					continue
+				}
+				go test.SharedPartyUpdater(P, msg, errCh)
+			}
+		case <-endCh:
+			ended++
+		}
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	for i := range pIDs {
+		assert.Equal(t, []int{1, 2, 3}, progress[i], "party %d should have reported each round once", i)
+	}
+}
+
+func TestSaveDataPointsDefaultToEd25519(t *testing.T) {
+	// the fixtures were written before points carried the name of their curve
+	keys, _, err := LoadKeygenTestFixtures(1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	assert.Equal(t, edwards.Edwards(), keys[0].EDDSAPub.Curve())
+	for _, Xj := range keys[0].BigXj {
+		assert.Equal(t, edwards.Edwards(), Xj.Curve())
+	}
+
+	bz, err := json.Marshal(keys[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	var decoded LocalPartySaveData
+	if assert.NoError(t, json.Unmarshal(bz, &decoded)) {
+		assert.True(t, decoded.EDDSAPub.Equals(keys[0].EDDSAPub))
+		assert.Equal(t, len(keys[0].BigXj), len(decoded.BigXj))
+	}
+	assert.Error(t, json.Unmarshal([]byte(`{"EDDSAPub":{"Coords":[1,2]}}`), &decoded), "a point off Ed25519 must be rejected")
+}
+
 func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	fixtureFileName := makeTestFixtureFilePath(index)
 
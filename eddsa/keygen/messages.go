@@ -7,8 +7,11 @@
 package keygen
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
@@ -28,6 +31,16 @@ var (
 	}
 )
 
+// ExpectedMessages returns the messages that a party receives from each of the other parties in a keygen, to check
+// a stored stream of them with tss.ValidateMessageStream
+func ExpectedMessages() []tss.ExpectedMessage {
+	return []tss.ExpectedMessage{
+		{Round: 1, Type: proto.MessageName(&KGRound1Message{}), Broadcast: true},
+		{Round: 2, Type: proto.MessageName(&KGRound2Message1{}), Broadcast: false},
+		{Round: 2, Type: proto.MessageName(&KGRound2Message2{}), Broadcast: true},
+	}
+}
+
 // ----- //
 
 func NewKGRound1Message(from *tss.PartyID, ct cmt.HashCommitment) tss.ParsedMessage {
@@ -92,7 +105,7 @@ func NewKGRound2Message2(
 	content := &KGRound2Message2{
 		DeCommitment: dcBzs,
 		ProofAlpha:   proof.Alpha.ToProtobufPoint(),
-		ProofT:       proof.T.Bytes(),
+		ProofT:       common.PadToLen(proof.T, common.ByteLen(proof.Alpha.Curve().Params().N)),
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -108,8 +121,8 @@ func (m *KGRound2Message2) UnmarshalDeCommitment() []*big.Int {
 	return cmt.NewHashDeCommitmentFromBytes(deComBzs)
 }
 
-func (m *KGRound2Message2) UnmarshalZKProof() (*zkp.DLogProof, error) {
-	point, err := crypto.NewECPointFromProtobuf(m.GetProofAlpha())
+func (m *KGRound2Message2) UnmarshalZKProof(ec elliptic.Curve) (*zkp.DLogProof, error) {
+	point, err := crypto.NewECPointFromProtobuf(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -34,17 +34,18 @@ func (round *round1) Start() *tss.Error {
 	round.number = 1
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	Pi := round.PartyID()
 	i := Pi.Index
 
 	// 1. calculate "partial" key share ui
-	ui := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ui := common.GetRandomPositiveInt(round.EC().Params().N)
 	round.temp.ui = ui
 
 	// 2. compute the vss shares
 	ids := round.Parties().IDs().Keys()
-	vs, shares, err := vss.Create(round.Threshold(), ui, ids)
+	vs, shares, err := vss.Create(round.EC(), round.Threshold(), ui, ids)
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
@@ -76,7 +77,7 @@ func (round *round1) Start() *tss.Error {
 	{
 		msg := NewKGRound1Message(round.PartyID(), cmt.C)
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.out <- round.UseCodec(msg)
 	}
 	return nil
 }
@@ -22,6 +22,7 @@ func (round *round2) Start() *tss.Error {
 	round.number = 2
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	i := round.PartyID().Index
 
@@ -41,11 +42,11 @@ func (round *round2) Start() *tss.Error {
 			continue
 		}
 		round.temp.kgRound2Message1s[i] = r2msg1
-		round.out <- r2msg1
+		round.out <- round.UseCodec(r2msg1)
 	}
 
 	// 5. compute Schnorr prove
-	pii, err := zkp.NewDLogProof(round.temp.ui, round.temp.vs[0])
+	pii, err := zkp.NewDLogProof(round.EC(), round.temp.ui, round.temp.vs[0])
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewDLogProof(ui, vi0)"))
 	}
@@ -53,7 +54,7 @@ func (round *round2) Start() *tss.Error {
 	// 5. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
 	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, pii)
 	round.temp.kgRound2Message2s[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.UseCodec(r2msg2)
 
 	return nil
 }
@@ -27,6 +27,7 @@ func (round *round3) Start() *tss.Error {
 	round.number = 3
 	round.started = true
 	round.resetOK()
+	round.ReportProgress(round.number, totalRounds)
 
 	Ps := round.Parties().IDs()
 	PIdx := round.PartyID().Index
@@ -41,7 +42,7 @@ func (round *round3) Start() *tss.Error {
 		share := r2msg1.UnmarshalShare()
 		xi = new(big.Int).Add(xi, share)
 	}
-	round.save.Xi = new(big.Int).Mod(xi, tss.EC().Params().N)
+	round.save.Xi = new(big.Int).Mod(xi, round.EC().Params().N)
 
 	// 2-3.
 	Vc := make(vss.Vs, round.Threshold()+1)
@@ -59,14 +60,15 @@ func (round *round3) Start() *tss.Error {
 		if i == PIdx {
 			continue
 		}
-		chs[i] = make(chan vssOut)
+		chs[i] = make(chan vssOut, 1) // buffered, as a worker may finish before all are started
 	}
 	for j := range Ps {
 		if j == PIdx {
 			continue
 		}
 		// 6-9.
-		go func(j int, ch chan<- vssOut) {
+		j, ch := j, chs[j]
+		round.Go(func() {
 			// 4-10.
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
@@ -77,22 +79,26 @@ func (round *round3) Start() *tss.Error {
 				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
 				return
 			}
-			PjVs, err := crypto.UnFlattenECPoints(tss.EC(), flatPolyGs)
-			for i, PjV := range PjVs {
-				PjVs[i] = PjV.EightInvEight()
-			}
+			PjVs, err := crypto.UnFlattenECPoints(round.EC(), flatPolyGs)
 			if err != nil {
 				ch <- vssOut{err, nil}
 				return
 			}
-			proof, err := r2msg2.UnmarshalZKProof()
-			if err != nil {
+			for _, PjV := range PjVs {
+				if !PjV.IsInPrimeOrderSubgroup() {
+					ch <- vssOut{errors.New("vss commitment is not in the prime-order subgroup"), nil}
+					return
+				}
+			}
+			proof, err := r2msg2.UnmarshalZKProof(round.EC())
+			if err != nil || !proof.Alpha.IsInPrimeOrderSubgroup() {
 				ch <- vssOut{errors.New("failed to unmarshal zk proof"), nil}
 				return
 			}
-			ok = proof.Verify(PjVs[0])
+			ok = proof.Verify(round.EC(), PjVs[0])
+			round.ObserveProof(tss.ProofDLog, ok)
 			if !ok {
-				ch <- vssOut{errors.New("failed to prove zk proof"), nil}
+				ch <- vssOut{tss.NewFaultError(tss.FaultBadProof, errors.New("failed to prove zk proof")), nil}
 				return
 			}
 			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
@@ -101,16 +107,16 @@ func (round *round3) Start() *tss.Error {
 				ID:        round.PartyID().KeyInt(),
 				Share:     r2msg1.UnmarshalShare(),
 			}
-			if ok = PjShare.Verify(round.Threshold(), PjVs); !ok {
+			if ok = PjShare.Verify(round.EC(), round.Threshold(), PjVs); !ok {
 				ch <- vssOut{errors.New("vss verify failed"), nil}
 				return
 			}
 			// (9) handled above
 			ch <- vssOut{nil, PjVs}
-		}(j, chs[j])
+		})
 	}
 
-	// consume unbuffered channels (end the goroutines)
+	// collect the results of the goroutines
 	vssResults := make([]vssOut, len(Ps))
 	{
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
@@ -155,10 +161,20 @@ func (round *round3) Start() *tss.Error {
 		}
 	}
 
+	// keep the commitment vector of each party, so that the shares can be verified externally
+	round.save.VSSCommitments = make([]vss.Vs, len(Ps))
+	for j := range Ps {
+		if j == PIdx {
+			round.save.VSSCommitments[j] = round.temp.vs
+			continue
+		}
+		round.save.VSSCommitments[j] = vssResults[j].pjVs
+	}
+
 	// 13-17. compute Xj for each Pj
 	{
 		var err error
-		modQ := common.ModInt(tss.EC().Params().N)
+		modQ := common.ModInt(round.EC().Params().N)
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
 		bigXj := round.save.BigXj
 		for j := 0; j < round.PartyCount(); j++ {
@@ -182,7 +198,7 @@ func (round *round3) Start() *tss.Error {
 	}
 
 	// 18. compute and SAVE the EDDSA public key `y`
-	eddsaPubKey, err := crypto.NewECPoint(tss.EC(), Vc[0].X(), Vc[0].Y())
+	eddsaPubKey, err := crypto.NewECPoint(round.EC(), Vc[0].X(), Vc[0].Y())
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
 	}
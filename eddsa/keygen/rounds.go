@@ -12,6 +12,9 @@ import (
 
 const (
 	TaskName = "eddsa-keygen"
+
+	// the number of rounds of keygen, reported to the progress callback
+	totalRounds = 3
 )
 
 type (
@@ -7,11 +7,17 @@
 package keygen
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
 	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
@@ -33,6 +39,10 @@ type (
 
 		// the EdDSA public key
 		EDDSAPub *crypto.ECPoint // y
+
+		// the VSS commitments to each party's polynomial, with which anyone can verify the shares; they are kept by
+		// keygen but not by a re-sharing or BuildLocalSaveDataSubset
+		VSSCommitments []vss.Vs `json:",omitempty"`
 	}
 )
 
@@ -43,6 +53,8 @@ func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
 }
 
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
+// It leaves out the VSSCommitments: the combined commitment is the sum of the commitments of all the parties of
+// keygen, so it cannot be taken over a subset of them.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
 	for j, kj := range sourceData.Ks {
@@ -61,3 +73,97 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 	}
 	return newData
 }
+
+// CombinedVSSCommitment returns the sum of the parties' VSS commitments, which commits to the polynomial whose
+// evaluation at Ks[j] is the final share of party j, so that vss.VerifyVSSShare can check a party's share Xi
+func (save LocalPartySaveData) CombinedVSSCommitment() (vss.Vs, error) {
+	if len(save.VSSCommitments) == 0 || len(save.VSSCommitments) != len(save.Ks) {
+		return nil, errors.New("the save data does not hold the VSS commitments of every party")
+	}
+	combined := make(vss.Vs, len(save.VSSCommitments[0]))
+	for j, vs := range save.VSSCommitments {
+		if len(vs) != len(combined) {
+			return nil, fmt.Errorf("the VSS commitment of party %d has %d points but %d are expected", j, len(vs), len(combined))
+		}
+		for c, v := range vs {
+			if v == nil {
+				return nil, fmt.Errorf("the VSS commitment of party %d is missing a point", j)
+			}
+			if j == 0 {
+				combined[c] = v
+				continue
+			}
+			var err error
+			if combined[c], err = combined[c].Add(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return combined, nil
+}
+
+// UnmarshalJSON decodes the save data with its points on Ed25519 when their curve is not named, as in save data
+// written before points carried the name of their curve, rather than on the default tss.EC()
+func (save *LocalPartySaveData) UnmarshalJSON(payload []byte) error {
+	type saveData LocalPartySaveData
+	aux := &struct {
+		*saveData
+		BigXj          []*ed25519Point
+		EDDSAPub       *ed25519Point
+		VSSCommitments [][]*ed25519Point `json:",omitempty"`
+	}{saveData: (*saveData)(save)}
+	if err := json.Unmarshal(payload, aux); err != nil {
+		return err
+	}
+	save.BigXj = ed25519Points(aux.BigXj)
+	save.EDDSAPub = nil
+	if aux.EDDSAPub != nil {
+		save.EDDSAPub = aux.EDDSAPub.ECPoint
+	}
+	save.VSSCommitments = nil
+	for _, vs := range aux.VSSCommitments {
+		save.VSSCommitments = append(save.VSSCommitments, ed25519Points(vs))
+	}
+	return nil
+}
+
+// ed25519Point decodes a point on Ed25519 unless the payload names another curve
+type ed25519Point struct {
+	*crypto.ECPoint
+}
+
+func (p *ed25519Point) UnmarshalJSON(payload []byte) error {
+	aux := &struct {
+		Curve  tss.CurveName
+		Coords [2]*big.Int
+	}{}
+	if err := json.Unmarshal(payload, aux); err != nil {
+		return err
+	}
+	var curve elliptic.Curve = edwards.Edwards()
+	if aux.Curve != "" {
+		var ok bool
+		if curve, ok = tss.GetCurveByName(aux.Curve); !ok {
+			return fmt.Errorf("ed25519Point.UnmarshalJSON: unknown curve %q", aux.Curve)
+		}
+	}
+	point, err := crypto.NewECPoint(curve, aux.Coords[0], aux.Coords[1])
+	if err != nil {
+		return err
+	}
+	p.ECPoint = point
+	return nil
+}
+
+func ed25519Points(points []*ed25519Point) []*crypto.ECPoint {
+	if points == nil {
+		return nil
+	}
+	decoded := make([]*crypto.ECPoint, len(points))
+	for i, p := range points {
+		if p != nil {
+			decoded[i] = p.ECPoint
+		}
+	}
+	return decoded
+}
@@ -7,6 +7,7 @@
 package resharing
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -34,6 +35,7 @@ var (
 func NewDGRound1Message(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	eddsaPub *crypto.ECPoint,
 	vct cmt.HashCommitment,
 ) tss.ParsedMessage {
@@ -46,6 +48,7 @@ func NewDGRound1Message(
 	content := &DGRound1Message{
 		EddsaPub:    eddsaPub.ToProtobufPoint(),
 		VCommitment: vct.Bytes(),
+		SessionId:   sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -58,8 +61,8 @@ func (m *DGRound1Message) ValidateBasic() bool {
 		common.NonEmptyBytes(m.VCommitment)
 }
 
-func (m *DGRound1Message) UnmarshalEDDSAPub() (*crypto.ECPoint, error) {
-	return crypto.NewECPointFromProtobuf(m.GetEddsaPub())
+func (m *DGRound1Message) UnmarshalEDDSAPub(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetEddsaPub())
 }
 
 func (m *DGRound1Message) UnmarshalVCommitment() *big.Int {
@@ -71,6 +74,7 @@ func (m *DGRound1Message) UnmarshalVCommitment() *big.Int {
 func NewDGRound2Message(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:             from,
@@ -78,7 +82,9 @@ func NewDGRound2Message(
 		IsBroadcast:      true,
 		IsToOldCommittee: true,
 	}
-	content := &DGRound2Message{}
+	content := &DGRound2Message{
+		SessionId: sessionID,
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
@@ -92,6 +98,7 @@ func (m *DGRound2Message) ValidateBasic() bool {
 func NewDGRound3Message1(
 	to *tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	share *vss.Share,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -101,7 +108,8 @@ func NewDGRound3Message1(
 		IsToOldCommittee: false,
 	}
 	content := &DGRound3Message1{
-		Share: share.Share.Bytes(),
+		Share:     share.Share.Bytes(),
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -117,6 +125,7 @@ func (m *DGRound3Message1) ValidateBasic() bool {
 func NewDGRound3Message2(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
 	vdct cmt.HashDeCommitment,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -128,6 +137,7 @@ func NewDGRound3Message2(
 	vDctBzs := common.BigIntsToBytes(vdct)
 	content := &DGRound3Message2{
 		VDecommitment: vDctBzs,
+		SessionId:     sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -148,6 +158,8 @@ func (m *DGRound3Message2) UnmarshalVDeCommitment() cmt.HashDeCommitment {
 func NewDGRound4Message(
 	to []*tss.PartyID,
 	from *tss.PartyID,
+	sessionID []byte,
+	bigXi *crypto.ECPoint,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
 		From:                    from,
@@ -155,11 +167,20 @@ func NewDGRound4Message(
 		IsBroadcast:             true,
 		IsToOldAndNewCommittees: true,
 	}
-	content := &DGRound4Message{}
+	content := &DGRound4Message{
+		BigXI:     bigXi.ToProtobufPoint(),
+		SessionId: sessionID,
+	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
 }
 
 func (m *DGRound4Message) ValidateBasic() bool {
-	return true
+	return m != nil &&
+		m.BigXI != nil &&
+		m.BigXI.ValidateBasic()
+}
+
+func (m *DGRound4Message) UnmarshalBigXi(ec elliptic.Curve) (*crypto.ECPoint, error) {
+	return crypto.NewECPointFromProtobuf(ec, m.GetBigXI())
 }
@@ -33,13 +33,18 @@ func (round *round1) Start() *tss.Error {
 	round.resetOK() // resets both round.oldOK and round.newOK
 	round.allNewOK()
 
+	if err := round.ReSharingParams().CheckRetainedParties(); err != nil {
+		return round.WrapError(err)
+	}
 	if !round.ReSharingParams().IsOldCommittee() {
 		return nil
 	}
-	round.allOldOK()
+	// a party that is also in the new committee receives the messages of the rest of the old committee
+	if !round.ReSharingParams().IsNewCommittee() {
+		round.allOldOK()
+	}
 
-	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.oldIndex()
 
 	// 1. PrepareForSigning() -> w_i
 	xi, ks := round.input.Xi, round.input.Ks
@@ -47,10 +52,13 @@ func (round *round1) Start() *tss.Error {
 		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID())
 	}
 	newKs := round.NewParties().IDs().Keys()
-	wi := signing.PrepareForSigning(i, len(round.OldParties().IDs()), xi, ks)
+	if round.NewThreshold()+1 > len(newKs) {
+		return round.WrapError(fmt.Errorf("new t+1=%d is not satisfied by the new committee count of %d", round.NewThreshold()+1, len(newKs)), round.PartyID())
+	}
+	wi := signing.PrepareForSigning(round.EC(), i, len(round.OldParties().IDs()), xi, ks)
 
 	// 2.
-	vi, shares, err := vss.Create(round.NewThreshold(), wi, newKs)
+	vi, shares, err := vss.Create(round.EC(), round.NewThreshold(), wi, newKs)
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
@@ -68,18 +76,18 @@ func (round *round1) Start() *tss.Error {
 
 	// 5. "broadcast" C_i to members of the NEW committee
 	r1msg := NewDGRound1Message(
-		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
+		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(), round.temp.sessionID,
 		round.input.EDDSAPub, vCmt.C)
 	round.temp.dgRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.out <- round.UseCodec(r1msg)
 
 	return nil
 }
 
 func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
 	// accept messages from old -> new committee
-	if _, ok := msg.Content().(*DGRound1Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound1Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -107,7 +115,7 @@ func (round *round1) Update() (bool, *tss.Error) {
 		}
 		// save the eddsa pub received from the old committee
 		r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
-		candidate, err := r1msg.UnmarshalEDDSAPub()
+		candidate, err := r1msg.UnmarshalEDDSAPub(round.EC())
 		if err != nil {
 			return false, round.WrapError(errors.New("unable to unmarshal the eddsa pub key"), msg.GetFrom())
 		}
@@ -24,22 +24,25 @@ func (round *round2) Start() *tss.Error {
 	if !round.ReSharingParams().IsNewCommittee() {
 		return nil
 	}
-	round.allNewOK()
+	// a party that is also in the old committee receives the "ACK" messages of the rest of the new committee
+	if !round.ReSharingParams().IsOldCommittee() {
+		round.allNewOK()
+	}
 
 	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.newIndex()
 
 	// 1. "broadcast" "ACK" members of the OLD committee
-	r2msg := NewDGRound2Message(round.OldParties().IDs(), Pi)
+	r2msg := NewDGRound2Message(round.OldParties().IDs(), Pi, round.temp.sessionID)
 	round.temp.dgRound2Messages[i] = r2msg
-	round.out <- r2msg
+	round.out <- round.UseCodec(r2msg)
 
 	return nil
 }
 
 func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*DGRound2Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound2Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
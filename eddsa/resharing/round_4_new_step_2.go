@@ -34,13 +34,13 @@ func (round *round4) Start() *tss.Error {
 	}
 
 	Pi := round.PartyID()
-	i := Pi.Index
+	i := round.newIndex()
 
 	// 1.
 	newXi := big.NewInt(0)
 
 	// 2-8.
-	modQ := common.ModInt(tss.EC().Params().N)
+	modQ := common.ModInt(round.EC().Params().N)
 	vjc := make([][]*crypto.ECPoint, len(round.OldParties().IDs()))
 	for j := 0; j <= len(vjc)-1; j++ { // P1..P_t+1. Ps are indexed from 0 here
 		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
@@ -55,13 +55,15 @@ func (round *round4) Start() *tss.Error {
 			// TODO collect culprits and return a list of them as per convention
 			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
 		}
-		vj, err := crypto.UnFlattenECPoints(tss.EC(), flatVs)
+		vj, err := crypto.UnFlattenECPoints(round.EC(), flatVs)
 		if err != nil {
 			return round.WrapError(err, round.Parties().IDs()[j])
 		}
 
-		for i, v := range vj {
-			vj[i] = v.EightInvEight()
+		for _, v := range vj {
+			if !v.IsInPrimeOrderSubgroup() {
+				return round.WrapError(errors.New("v_j is not in the prime-order subgroup"), round.Parties().IDs()[j])
+			}
 		}
 
 		vjc[j] = vj
@@ -72,7 +74,7 @@ func (round *round4) Start() *tss.Error {
 			ID:        round.PartyID().KeyInt(),
 			Share:     new(big.Int).SetBytes(r3msg1.Share),
 		}
-		if ok := sharej.Verify(round.NewThreshold(), vj); !ok {
+		if ok := sharej.Verify(round.EC(), round.NewThreshold(), vj); !ok {
 			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
 		}
 
@@ -124,17 +126,22 @@ func (round *round4) Start() *tss.Error {
 	round.temp.newKs = newKs
 	round.temp.newBigXjs = newBigXjs
 
-	// 21. Send an "ACK" message to both committees to signal that we're ready to save our data
-	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi)
+	// 21. Send an "ACK" message to both committees to signal that we're ready to save our data, with our public
+	// share so that both committees can confirm that the new shares reconstruct the public key
+	bigXi := crypto.ScalarBaseMult(round.EC(), new(big.Int).Mod(newXi, round.EC().Params().N))
+	if !bigXi.Equals(newBigXjs[i]) {
+		return round.WrapError(errors.New("g^xi does not match the public share derived from the commitments"), Pi)
+	}
+	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi, round.temp.sessionID, bigXi)
 	round.temp.dgRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.out <- round.UseCodec(r4msg)
 
 	return nil
 }
 
 func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*DGRound4Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*DGRound4Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
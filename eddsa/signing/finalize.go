@@ -9,10 +9,6 @@ package signing
 import (
 	"errors"
 	"fmt"
-	"math/big"
-
-	"github.com/agl/ed25519/edwards25519"
-	"github.com/decred/dcrd/dcrec/edwards/v2"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/tss"
@@ -26,35 +22,37 @@ func (round *finalization) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
-	sumS := round.temp.si
-	for j := range round.Parties().IDs() {
+	// each signer's s_i is counted once, whatever the order in which the messages arrived
+	partials := NewPartialSignatures(round.EC(), round.Parties().IDs())
+	if err := partials.Add(round.PartyID(), encodedBytesToBigInt(round.temp.si)); err != nil {
+		return round.WrapError(err)
+	}
+	for j, Pj := range round.Parties().IDs() {
 		round.ok[j] = true
 		if j == round.PartyID().Index {
 			continue
 		}
 		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
-		sjBytes := bigIntToEncodedBytes(r3msg.UnmarshalS())
-		var tmpSumS [32]byte
-		edwards25519.ScMulAdd(&tmpSumS, sumS, bigIntToEncodedBytes(big.NewInt(1)), sjBytes)
-		sumS = &tmpSumS
+		if err := partials.Add(Pj, r3msg.UnmarshalS()); err != nil {
+			return round.WrapError(err, Pj)
+		}
 	}
-	s := encodedBytesToBigInt(sumS)
+	s, err := partials.Sum()
+	if err != nil {
+		return round.WrapError(err, partials.Missing()...)
+	}
+	sumS := bigIntToEncodedBytes(s)
 
 	// save the signature for final output
 	signature := new(common.ECSignature)
 	signature.Signature = append(bigIntToEncodedBytes(round.temp.r)[:], sumS[:]...)
-	signature.R = round.temp.r.Bytes()
-	signature.S = s.Bytes()
+	signature.R = common.PadToLen(round.temp.r, common.ByteLen(round.EC().Params().N))
+	signature.S = common.PadToLen(s, common.ByteLen(round.EC().Params().N))
 	signature.M = round.temp.m.Bytes()
 	round.data.Signature = signature
 
-	pk := edwards.PublicKey{
-		Curve: tss.EC(),
-		X:     round.key.EDDSAPub.X(),
-		Y:     round.key.EDDSAPub.Y(),
-	}
-
-	ok := edwards.Verify(&pk, round.temp.m.Bytes(), round.temp.r, s)
+	encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
+	ok := verifySignature(round.EdDSAHash(), encodedPubKey, round.temp.m.Bytes(), round.temp.r, s)
 	if !ok {
 		return round.WrapError(fmt.Errorf("signature verification failed"))
 	}
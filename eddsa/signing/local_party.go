@@ -7,9 +7,11 @@
 package signing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
@@ -56,12 +58,26 @@ type (
 		// round 2
 		cjs []*big.Int
 		si  *[32]byte
+		// copies of cjs and pointRi taken under the party's lock when round 2 starts, for the accessors of LocalParty
+		// which may be called while the party runs
+		nonces struct {
+			sync.Mutex
+			cjs     []*big.Int
+			pointRi *crypto.ECPoint
+		}
 
 		// round 3
 		r *big.Int
+
+		// see SessionID
+		sessionID []byte
 	}
 )
 
+const (
+	sessionIDDomain = "tss-lib eddsa signing session"
+)
+
 func NewLocalParty(
 	msg *big.Int,
 	params *tss.Parameters,
@@ -87,9 +103,23 @@ func NewLocalParty(
 	// temp data init
 	p.temp.m = msg
 	p.temp.cjs = make([]*big.Int, partyCount)
+	p.temp.sessionID = SessionID(params, key, msg)
 	return p
 }
 
+// SessionID derives the canonical ID of a signing session from the committee, threshold, curve, message digest `msg`,
+// session expiry and session nonce (see tss.Parameters.SessionID) and from the public key of the keygen output, so
+// that all parties of the session agree on it. Every message carries it, and a message with another ID is dropped, so
+// that a message relayed from another session is not accepted. Sessions that sign the same message with the same key
+// and committee share an ID unless they set different session nonces or expiries, so a retry sets a fresh nonce.
+func SessionID(params *tss.Parameters, key keygen.LocalPartySaveData, msg *big.Int) []byte {
+	parts := [][]byte{[]byte(sessionIDDomain), params.SessionID(msg)}
+	if pub := key.EDDSAPub; pub != nil {
+		parts = append(parts, pub.X().Bytes(), pub.Y().Bytes())
+	}
+	return common.SHA512_256(parts...)
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
 	return newRound1(p.params, &p.keys, &p.data, &p.temp, p.out, p.end)
 }
@@ -112,7 +142,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
-	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	msg, err := p.params.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
 		return false, p.WrapError(err)
 	}
@@ -138,8 +168,15 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	}
 	fromPIdx := msg.GetFrom().Index
 
+	// a message of another session, e.g. one replayed by a relay, is dropped and does not take the sender's slot; it
+	// need not come from the sender, so the sender is not blamed
+	if content, ok := msg.Content().(sessionMessage); ok && !bytes.Equal(content.GetSessionId(), p.temp.sessionID) {
+		common.Logger.Warnf("dropped a message of another session: %v", msg)
+		return false, nil
+	}
+
 	// switch/case is necessary to store any messages beyond current round
-	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	// this does not handle message replays within a session. we expect the caller to apply spoofing protection.
 	switch msg.Content().(type) {
 	case *SignRound1Message:
 		p.temp.signRound1Messages[fromPIdx] = msg
@@ -148,6 +185,15 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 		p.temp.signRound2Messages[fromPIdx] = msg
 
 	case *SignRound3Message:
+		// a partial signature is counted once: a copy of one already stored is dropped and a different one is an error
+		if prev := p.temp.signRound3Messages[fromPIdx]; prev != nil {
+			prevS, s := prev.Content().(*SignRound3Message).UnmarshalS(), msg.Content().(*SignRound3Message).UnmarshalS()
+			if prevS.Cmp(s) != 0 {
+				return false, p.WrapError(fmt.Errorf("%w: %s sent two different partial signatures",
+					ErrDuplicatePartialSignature, msg.GetFrom()), msg.GetFrom())
+			}
+			return false, nil
+		}
 		p.temp.signRound3Messages[fromPIdx] = msg
 
 	default: // unrecognised message, just ignore!
@@ -157,6 +203,34 @@ func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	return true, nil
 }
 
+// NonceCommitments returns the hash commitments to each party's nonce point R_j, as broadcast in round 1 and indexed by party.
+// It returns nil before round 2 has started and the commitments from then on, also once the party has finished. It is
+// safe to call while the party runs. The commitments are public; no secret nonce is exposed.
+func (p *LocalParty) NonceCommitments() []*big.Int {
+	p.temp.nonces.Lock()
+	defer p.temp.nonces.Unlock()
+	if p.temp.nonces.cjs == nil {
+		return nil
+	}
+	cjs := make([]*big.Int, len(p.temp.nonces.cjs))
+	for j, cj := range p.temp.nonces.cjs {
+		cjs[j] = new(big.Int).Set(cj)
+	}
+	return cjs
+}
+
+// NoncePoint returns this party's public nonce point R_i = r_i*G, which it committed to in round 1.
+// Like NonceCommitments, it returns nil before round 2 has started. The secret r_i itself is never exposed.
+func (p *LocalParty) NoncePoint() *crypto.ECPoint {
+	p.temp.nonces.Lock()
+	defer p.temp.nonces.Unlock()
+	if p.temp.nonces.pointRi == nil {
+		return nil
+	}
+	Ri := p.temp.nonces.pointRi
+	return crypto.NewECPointNoCurveCheck(p.params.EC(), Ri.X(), Ri.Y())
+}
+
 func (p *LocalParty) PartyID() *tss.PartyID {
 	return p.params.PartyID()
 }
@@ -7,17 +7,24 @@
 package signing
 
 import (
+	"errors"
 	"fmt"
+	"hash"
 	"math/big"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/agl/ed25519/edwards25519"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
 	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/test"
 	"github.com/ordinox/thorchain-tss-lib/tss"
@@ -64,6 +71,8 @@ func TestE2EConcurrent(t *testing.T) {
 		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), threshold)
 
 		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		assert.Nil(t, P.NonceCommitments())
+		assert.Nil(t, P.NoncePoint())
 		parties = append(parties, P)
 		go func(P *LocalParty) {
 			if err := P.Start(); err != nil {
@@ -82,6 +91,10 @@ signing:
 			break signing
 
 		case msg := <-outCh:
+			// the nonce accessors may be called while the parties run
+			if from := parties[msg.GetFrom().Index]; from.NonceCommitments() != nil {
+				assert.NotNil(t, from.NoncePoint())
+			}
 			dest := msg.GetTo()
 			if dest == nil {
 				for _, P := range parties {
@@ -136,8 +149,559 @@ signing:
 				t.Log("EDDSA signing test done.")
 				// END EDDSA verify
 
+				// BEGIN check exposed nonce commitments
+				for _, p := range parties {
+					cjs := p.NonceCommitments()
+					assert.Equal(t, len(signPIDs), len(cjs))
+					for j, cj := range cjs {
+						r1msg := p.temp.signRound1Messages[j].Content().(*SignRound1Message)
+						assert.Equal(t, 0, cj.Cmp(r1msg.UnmarshalCommitment()), "exposed commitment must match the round 1 message")
+					}
+					Ri := p.NoncePoint()
+					r2msg := p.temp.signRound2Messages[p.PartyID().Index].Content().(*SignRound2Message)
+					cmtDeCmt := commitments.HashCommitDecommit{C: cjs[p.PartyID().Index], D: r2msg.UnmarshalDeCommitment()}
+					ok, coordinates := cmtDeCmt.DeCommit()
+					assert.True(t, ok, "exposed commitment must open with the round 2 de-commitment")
+					assert.Equal(t, 0, Ri.X().Cmp(coordinates[0]))
+					assert.Equal(t, 0, Ri.Y().Cmp(coordinates[1]))
+				}
+				// END check exposed nonce commitments
+
 				break signing
 			}
 		}
 	}
 }
+
+func TestForgedDLogProofCulprit(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	ec := edwards.Edwards()
+	culprit := signPIDs[1]
+	for _, tc := range []struct {
+		name string
+		// tamper returns the round 2 message of the culprit that its peers receive
+		tamper func(r2msg *SignRound2Message) tss.ParsedMessage
+	}{{
+		// the culprit opens its commitment to R_1 but proves knowledge of the discrete log of another point
+		name: "forged proof",
+		tamper: func(r2msg *SignRound2Message) tss.ParsedMessage {
+			x := common.GetRandomPositiveInt(ec.Params().N)
+			forged, err := zkp.NewDLogProof(ec, x, crypto.ScalarBaseMult(ec, x))
+			if err != nil {
+				panic(err)
+			}
+			return NewSignRound2Message(culprit, r2msg.GetSessionId(), r2msg.UnmarshalDeCommitment(), forged)
+		},
+	}, {
+		// the culprit reveals R_1 with randomness that does not open its commitment
+		name: "tampered de-commitment",
+		tamper: func(r2msg *SignRound2Message) tss.ParsedMessage {
+			deCommit := r2msg.UnmarshalDeCommitment()
+			deCommit[0] = new(big.Int).Add(deCommit[0], big.NewInt(1))
+			proof, err := r2msg.UnmarshalZKProof(ec)
+			if err != nil {
+				panic(err)
+			}
+			return NewSignRound2Message(culprit, r2msg.GetSessionId(), deCommit, proof)
+		},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			errCh := make(chan *tss.Error, len(signPIDs))
+			outCh := make(chan tss.Message, len(signPIDs))
+			endCh := make(chan *SignatureData, len(signPIDs))
+			parties := newSigningParties(big.NewInt(200), keys, signPIDs, outCh, endCh)
+			for _, P := range parties {
+				go func(P *LocalParty) {
+					if err := P.Start(); err != nil {
+						errCh <- err
+					}
+				}(P)
+			}
+			for {
+				select {
+				case err := <-errCh:
+					assert.Equal(t, 3, err.Round())
+					assert.Equal(t, []*tss.PartyID{culprit}, err.Culprits())
+					return
+				case msg := <-outCh:
+					if r2msg, ok := msg.(tss.ParsedMessage).Content().(*SignRound2Message); ok && msg.GetFrom() == culprit {
+						msg = tc.tamper(r2msg)
+					}
+					deliver(parties, msg, errCh)
+				case <-endCh:
+					assert.FailNow(t, "signing must not finish with a tampered round 2 message")
+				}
+			}
+		})
+	}
+}
+
+func TestStartRejectsDuplicateEvaluationPoint(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	params := tss.NewParameters(tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	params.SetCurve(edwards.Edwards())
+	P := NewLocalParty(big.NewInt(42), params, keys[0], nil, nil).(*LocalParty)
+	// two signers evaluating the polynomial at the same point leave the Lagrange coefficients undefined
+	P.keys.Ks[1] = P.keys.Ks[0]
+
+	err2 := P.Start()
+	if assert.NotNil(t, err2) {
+		assert.Contains(t, err2.Error(), "the signing subset is malformed")
+	}
+}
+
+func TestE2EBlake2bVariant(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	newBlake2b := func() hash.Hash {
+		h, _ := blake2b.New512(nil)
+		return h
+	}
+
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := big.NewInt(200)
+	parties := newSigningParties(msg, keys, signPIDs, outCh, endCh, tss.WithEdDSAHash(newBlake2b))
+	sig := runSession(t, parties, outCh, endCh)
+	if sig == nil {
+		return
+	}
+
+	pk := edwards.PublicKey{Curve: edwards.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	assert.True(t, verifyEd25519Blake2b(pk, msg.Bytes(), sig), "the signature should verify under Ed25519-BLAKE2b")
+	edSig, err := edwards.ParseSignature(sig)
+	if assert.NoError(t, err) {
+		assert.False(t, edwards.Verify(&pk, msg.Bytes(), edSig.R, edSig.S), "the signature should not verify under Ed25519")
+	}
+}
+
+// newSigningParties creates the parties signing `msg` with `keys`, each on the Ed25519 curve of its own parameters
+func newSigningParties(
+	msg *big.Int, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs,
+	outCh chan tss.Message, endCh chan *SignatureData, opts ...tss.ParameterOption,
+) []*LocalParty {
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	for i := range signPIDs {
+		params := tss.NewParameters(p2pCtx, signPIDs[i], len(signPIDs), testThreshold).With(opts...)
+		params.SetCurve(edwards.Edwards())
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty))
+	}
+	return parties
+}
+
+// deliver routes `msg` to its recipients among the parties
+func deliver(parties []*LocalParty, msg tss.Message, errCh chan<- *tss.Error) {
+	if dest := msg.GetTo(); dest != nil {
+		go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+		return
+	}
+	for _, P := range parties {
+		if P.PartyID().Index != msg.GetFrom().Index {
+			go test.SharedPartyUpdater(P, msg, errCh)
+		}
+	}
+}
+
+// runSession starts the parties and routes their messages until all of them end, and returns the signature; it fails
+// the test and returns nil if a party fails
+func runSession(t *testing.T, parties []*LocalParty, outCh <-chan tss.Message, endCh <-chan *SignatureData) []byte {
+	errCh := make(chan *tss.Error, len(parties))
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	var sig []byte
+	for ended := 0; ended < len(parties); {
+		select {
+		case err := <-errCh:
+			assert.Fail(t, err.Error())
+			return nil
+		case msg := <-outCh:
+			deliver(parties, msg, errCh)
+		case data := <-endCh:
+			sig = data.Signature.Signature
+			ended++
+		}
+	}
+	return sig
+}
+
+func TestDeterministicNonce(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	sign := func(msg *big.Int, deterministic bool) []byte {
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *SignatureData, len(signPIDs))
+		parties := newSigningParties(msg, keys, signPIDs, outCh, endCh)
+		for _, P := range parties {
+			P.params.UNSAFE_SetDeterministicNonce(deterministic)
+		}
+		return runSession(t, parties, outCh, endCh)
+	}
+
+	pk := edwards.PublicKey{Curve: edwards.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	msg := big.NewInt(42)
+	sig := sign(msg, true)
+	edSig, err := edwards.ParseSignature(sig)
+	if assert.NoError(t, err) {
+		assert.True(t, edwards.Verify(&pk, msg.Bytes(), edSig.R, edSig.S), "the signature should verify")
+	}
+	assert.Equal(t, sig, sign(msg, true), "a replayed session should reproduce the signature")
+	assert.NotEqual(t, sig, sign(big.NewInt(43), true))
+	assert.NotEqual(t, sign(msg, false), sign(msg, false), "random nonces should differ between sessions")
+}
+
+// verifyEd25519Blake2b checks s*B = R + k*A with k = BLAKE2b-512(R || A || M), the verification of Ed25519-BLAKE2b
+func verifyEd25519Blake2b(pk edwards.PublicKey, m, sig []byte) bool {
+	ec := edwards.Edwards()
+	R, err := edwards.ParsePubKey(sig[:32])
+	if err != nil {
+		return false
+	}
+	littleEndian := func(b []byte) *big.Int {
+		be := make([]byte, len(b))
+		for i := range b {
+			be[len(b)-1-i] = b[i]
+		}
+		return new(big.Int).SetBytes(be)
+	}
+	digest := blake2b.Sum512(append(append(append([]byte{}, sig[:32]...), pk.Serialize()...), m...))
+	k := new(big.Int).Mod(littleEndian(digest[:]), ec.Params().N)
+	s := littleEndian(sig[32:])
+
+	sBx, sBy := ec.ScalarBaseMult(s.Bytes())
+	kAx, kAy := ec.ScalarMult(pk.X, pk.Y, k.Bytes())
+	x, y := ec.Add(R.X, R.Y, kAx, kAy)
+	return sBx.Cmp(x) == 0 && sBy.Cmp(y) == 0
+}
+
+func TestNonceIsIdentityRejected(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	parties := newSigningParties(big.NewInt(200), keys, signPIDs, outCh, endCh)
+	for _, P := range parties {
+		if err := P.Start(); !assert.Nil(t, err) {
+			return
+		}
+	}
+
+	// the last party picks its nonce after seeing the others', so that the nonce points cancel out, and commits to it
+	// in place of the round 1 message it had sent
+	ec := edwards.Edwards()
+	modN := common.ModInt(ec.Params().N)
+	last := parties[len(parties)-1]
+	sum := big.NewInt(0)
+	for _, P := range parties[:len(parties)-1] {
+		sum = modN.Add(sum, P.temp.ri)
+	}
+	ri := modN.Sub(big.NewInt(0), sum)
+	pointRi := crypto.ScalarBaseMult(ec, ri)
+	cmt := commitments.NewHashCommitment(pointRi.X(), pointRi.Y())
+	last.temp.ri, last.temp.pointRi, last.temp.deCommit = ri, pointRi, cmt.D
+	crafted := NewSignRound1Message(last.PartyID(), last.temp.sessionID, cmt.C)
+	last.temp.signRound1Messages[last.PartyID().Index] = crafted
+
+	pending := make([]tss.Message, 0, len(parties))
+	for range parties {
+		m := <-outCh
+		if m.GetFrom().Index == last.PartyID().Index {
+			m = crafted
+		}
+		pending = append(pending, m)
+	}
+	for _, m := range pending {
+		deliver(parties, m, errCh)
+	}
+
+	failed := make(map[int]*tss.Error, len(parties))
+	for len(failed) < len(parties) {
+		select {
+		case err := <-errCh:
+			failed[err.Victim().Index] = err
+		case m := <-outCh:
+			deliver(parties, m, errCh)
+		case <-endCh:
+			assert.FailNow(t, "a session whose nonce points cancel out must not finish")
+		case <-time.After(time.Minute):
+			assert.FailNow(t, "every party should have rejected R", "%d of %d did", len(failed), len(parties))
+		}
+	}
+	for _, err := range failed {
+		assert.Equal(t, 3, err.Round())
+		assert.True(t, errors.Is(err, ErrNonceIsIdentity), err.Error())
+	}
+}
+
+func TestNonceDeCommitmentCulprit(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	parties := newSigningParties(big.NewInt(200), keys, signPIDs, outCh, endCh)
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// the cheater reveals in round 2 another nonce point than the one it committed to, with a valid proof for it
+	ec := edwards.Edwards()
+	cheater := signPIDs[len(signPIDs)-1]
+	ri := common.GetRandomPositiveInt(ec.Params().N)
+	pointRi := crypto.ScalarBaseMult(ec, ri)
+	proof, err := zkp.NewDLogProof(ec, ri, pointRi)
+	if !assert.NoError(t, err) {
+		return
+	}
+	forged := NewSignRound2Message(cheater, parties[0].temp.sessionID, commitments.NewHashCommitment(pointRi.X(), pointRi.Y()).D, proof)
+
+	failed := make(map[int]*tss.Error, len(parties)-1)
+	for len(failed) < len(parties)-1 {
+		select {
+		case err := <-errCh:
+			failed[err.Victim().Index] = err
+		case m := <-outCh:
+			if m.Type() == forged.Type() && m.GetFrom().Index == cheater.Index {
+				m = forged
+			}
+			deliver(parties, m, errCh)
+		case <-endCh:
+			assert.FailNow(t, "a session with a forged nonce point must not finish")
+		case <-time.After(time.Minute):
+			assert.FailNow(t, "every honest party should have caught the forged nonce point", "%d did", len(failed))
+		}
+	}
+	for _, err := range failed {
+		assert.Equal(t, 3, err.Round())
+		assert.True(t, errors.Is(err, ErrNonceDeCommitment), err.Error())
+		assert.Equal(t, []*tss.PartyID{cheater}, err.Culprits())
+	}
+}
+
+func TestSessionIDRejectsReplay(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	msg := big.NewInt(200)
+
+	// a retry of a session signs the same message under a fresh session nonce
+	prior := newSigningParties(msg, keys, signPIDs, nil, nil, tss.WithSessionNonce([]byte("attempt 1")))
+	current := newSigningParties(msg, keys, signPIDs, outCh, endCh, tss.WithSessionNonce([]byte("attempt 2")))
+
+	// all parties of a session derive the same ID, and another message or nonce gives another ID
+	for _, P := range current[1:] {
+		assert.Equal(t, current[0].temp.sessionID, P.temp.sessionID)
+	}
+	assert.NotEqual(t, current[0].temp.sessionID, prior[0].temp.sessionID)
+	other := newSigningParties(big.NewInt(199), keys, signPIDs, nil, nil, tss.WithSessionNonce([]byte("attempt 2")))
+	assert.NotEqual(t, current[0].temp.sessionID, other[0].temp.sessionID)
+
+	// a round 2 message of the prior attempt is neither accepted nor stored
+	sender, ec := signPIDs[1], current[0].params.EC()
+	ri := common.GetRandomPositiveInt(ec.Params().N)
+	pointRi := crypto.ScalarBaseMult(ec, ri)
+	proof, err := zkp.NewDLogProof(ec, ri, pointRi)
+	if !assert.NoError(t, err) {
+		return
+	}
+	deCommit := commitments.NewHashCommitment(pointRi.X(), pointRi.Y()).D
+	replayed := NewSignRound2Message(sender, prior[0].temp.sessionID, deCommit, proof)
+	fresh := NewSignRound2Message(sender, current[0].temp.sessionID, deCommit, proof)
+
+	r2 := &round2{&round1{&base{temp: &current[0].temp}}}
+	assert.False(t, r2.CanAccept(replayed), "a message of another session must not be accepted")
+	assert.True(t, r2.CanAccept(fresh))
+
+	ok, tErr := current[0].StoreMessage(replayed)
+	assert.False(t, ok)
+	assert.Nil(t, tErr, "the sender must not be blamed for a replay")
+	assert.Nil(t, current[0].temp.signRound2Messages[sender.Index])
+
+	// the retry still completes
+	sig := runSession(t, current, outCh, endCh)
+	if sig == nil {
+		return
+	}
+	pk := edwards.PublicKey{Curve: edwards.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	edSig, err := edwards.ParseSignature(sig)
+	if assert.NoError(t, err) {
+		assert.True(t, edwards.Verify(&pk, msg.Bytes(), edSig.R, edSig.S))
+	}
+}
+
+func TestRetryRound2AfterTransientFailure(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	msg := big.NewInt(200)
+	sender := signPIDs[len(signPIDs)-1]
+
+	t.Run("succeeds within the retries", func(t *testing.T) {
+		victim, retries, culprits, final, sig := runRetrySession(t, msg, keys, signPIDs, 1, 1)
+		if !assert.Nil(t, final) {
+			return
+		}
+		assert.Equal(t, 1, retries, "round 2 should have been retried once")
+		assert.Empty(t, culprits, "the culprits of a failure that was retried should not be reported")
+
+		pk := edwards.PublicKey{Curve: edwards.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+		edSig, err := edwards.ParseSignature(sig)
+		if assert.NoError(t, err) {
+			assert.True(t, edwards.Verify(&pk, msg.Bytes(), edSig.R, edSig.S), "eddsa verify must pass")
+		}
+
+		// a failure that is not of the current round cannot be retried
+		assert.True(t, errors.Is(tss.RetryRound(victim, victim.WrapError(errors.New("late"), sender)), tss.ErrNotRetryable))
+	})
+
+	t.Run("reports the culprits once the retries are used up", func(t *testing.T) {
+		_, retries, culprits, final, _ := runRetrySession(t, msg, keys, signPIDs, 1, 2)
+		if !assert.NotNil(t, final, "the session should fail") {
+			return
+		}
+		assert.True(t, errors.Is(final, tss.ErrNotRetryable), final.Error())
+		assert.Equal(t, 1, retries)
+		if assert.Len(t, culprits, 1) {
+			assert.Equal(t, sender, culprits[0].Culprit)
+			assert.Equal(t, 3, culprits[0].Round)
+		}
+	})
+
+	t.Run("is not transient without a sender verifier", func(t *testing.T) {
+		parties := newSigningParties(msg, keys, signPIDs, nil, nil)
+		failure := parties[0].WrapError(errors.New("bad message"), sender)
+		assert.False(t, tss.IsTransient(parties[0].params, failure))
+	})
+}
+
+// runRetrySession runs a session whose parties trust a SenderVerifier and may retry a round `maxRetries` times, in
+// which the round 2 message of the last signer reaches the first signer corrupted `corruptions` times, as if by a
+// faulty link. The first signer retries each failure until RetryRound refuses it. It returns the first signer, its
+// retries and the CulpritEvents reported to it, and either the error that RetryRound refused or the signature.
+func runRetrySession(
+	t *testing.T, msg *big.Int, keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs, maxRetries, corruptions int,
+) (victim *LocalParty, retries int, culprits []tss.CulpritEvent, final *tss.Error, sig []byte) {
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *SignatureData, len(signPIDs))
+	events := make(chan tss.CulpritEvent, len(signPIDs))
+	parties := newSigningParties(msg, keys, signPIDs, outCh, endCh,
+		tss.WithSenderVerifier(func(tss.ParsedMessage) error { return nil }),
+		tss.WithMaxRetries(maxRetries),
+		tss.WithCulpritHandler(func(event tss.CulpritEvent) { events <- event }),
+	)
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+	defer func() {
+		for len(events) > 0 {
+			culprits = append(culprits, <-events)
+		}
+	}()
+
+	sender, victim := signPIDs[len(signPIDs)-1], parties[0]
+	ec := edwards.Edwards()
+	ri := common.GetRandomPositiveInt(ec.Params().N)
+	pointRi := crypto.ScalarBaseMult(ec, ri)
+	proof, err := zkp.NewDLogProof(ec, ri, pointRi)
+	if !assert.NoError(t, err) {
+		return
+	}
+	corrupted := NewSignRound2Message(sender, victim.temp.sessionID, commitments.NewHashCommitment(pointRi.X(), pointRi.Y()).D, proof)
+	var original tss.Message
+	// deliverToVictim delivers the sender's round 2 message to the victim, corrupted while corruptions remain
+	deliverToVictim := func() {
+		if corruptions > 0 {
+			corruptions--
+			go test.SharedPartyUpdater(victim, corrupted, errCh)
+			return
+		}
+		go test.SharedPartyUpdater(victim, original, errCh)
+	}
+	round1Type := NewSignRound1Message(sender, nil, big.NewInt(1)).Type()
+
+	round1Msgs := 0
+	for ended := 0; ended < len(parties); {
+		select {
+		case err := <-errCh:
+			if !assert.Equal(t, victim.PartyID(), err.Victim(), err.Error()) || !assert.Equal(t, 3, err.Round()) {
+				return
+			}
+			assert.Equal(t, []*tss.PartyID{sender}, err.Culprits())
+			assert.True(t, tss.IsTransient(victim.params, err))
+			if final = tss.RetryRound(victim, err); final != nil {
+				return
+			}
+			retries++
+			assert.Equal(t, []*tss.PartyID{sender}, victim.WaitingFor())
+			// the sender's message is delivered again
+			deliverToVictim()
+		case m := <-outCh:
+			if m.Type() == round1Type {
+				round1Msgs++
+			}
+			for _, P := range parties {
+				if P.PartyID().Index == m.GetFrom().Index {
+					continue
+				}
+				if P == victim && m.Type() == corrupted.Type() && m.GetFrom().Index == sender.Index {
+					original = m
+					deliverToVictim()
+					continue
+				}
+				go test.SharedPartyUpdater(P, m, errCh)
+			}
+		case data := <-endCh:
+			ended++
+			sig = data.Signature.Signature
+		case <-time.After(time.Minute):
+			assert.FailNow(t, "the session should end", "%d parties ended", ended)
+		}
+	}
+	assert.Equal(t, len(parties), round1Msgs, "round 1 should not have been run again")
+	return
+}
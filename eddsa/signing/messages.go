@@ -7,8 +7,11 @@
 package signing
 
 import (
+	"crypto/elliptic"
 	"math/big"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	cmt "github.com/ordinox/thorchain-tss-lib/crypto/commitments"
@@ -27,10 +30,21 @@ var (
 	}
 )
 
+// ExpectedMessages returns the messages that a party receives from each of the other parties in a signing, to check
+// a stored stream of them with tss.ValidateMessageStream
+func ExpectedMessages() []tss.ExpectedMessage {
+	return []tss.ExpectedMessage{
+		{Round: 1, Type: proto.MessageName(&SignRound1Message{}), Broadcast: true},
+		{Round: 2, Type: proto.MessageName(&SignRound2Message{}), Broadcast: true},
+		{Round: 3, Type: proto.MessageName(&SignRound3Message{}), Broadcast: true},
+	}
+}
+
 // ----- //
 
 func NewSignRound1Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	commitment cmt.HashCommitment,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -39,6 +53,7 @@ func NewSignRound1Message(
 	}
 	content := &SignRound1Message{
 		Commitment: commitment.Bytes(),
+		SessionId:  sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -57,6 +72,7 @@ func (m *SignRound1Message) UnmarshalCommitment() *big.Int {
 
 func NewSignRound2Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	deCommitment cmt.HashDeCommitment,
 	proof *zkp.DLogProof,
 ) tss.ParsedMessage {
@@ -68,7 +84,8 @@ func NewSignRound2Message(
 	content := &SignRound2Message{
 		DeCommitment: dcBzs,
 		ProofAlpha:   proof.Alpha.ToProtobufPoint(),
-		ProofT:       proof.T.Bytes(),
+		ProofT:       common.PadToLen(proof.T, common.ByteLen(proof.Alpha.Curve().Params().N)),
+		SessionId:    sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
@@ -87,8 +104,8 @@ func (m *SignRound2Message) UnmarshalDeCommitment() []*big.Int {
 	return cmt.NewHashDeCommitmentFromBytes(deComBzs)
 }
 
-func (m *SignRound2Message) UnmarshalZKProof() (*zkp.DLogProof, error) {
-	point, err := crypto.NewECPointFromProtobuf(m.GetProofAlpha())
+func (m *SignRound2Message) UnmarshalZKProof(ec elliptic.Curve) (*zkp.DLogProof, error) {
+	point, err := crypto.NewECPointFromProtobuf(ec, m.GetProofAlpha())
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +119,7 @@ func (m *SignRound2Message) UnmarshalZKProof() (*zkp.DLogProof, error) {
 
 func NewSignRound3Message(
 	from *tss.PartyID,
+	sessionID []byte,
 	si *big.Int,
 ) tss.ParsedMessage {
 	meta := tss.MessageRouting{
@@ -109,7 +127,8 @@ func NewSignRound3Message(
 		IsBroadcast: true,
 	}
 	content := &SignRound3Message{
-		S: si.Bytes(),
+		S:         si.Bytes(),
+		SessionId: sessionID,
 	}
 	msg := tss.NewMessageWrapper(meta, content)
 	return tss.NewMessage(meta, content, msg)
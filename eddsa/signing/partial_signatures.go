@@ -0,0 +1,78 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// ErrDuplicatePartialSignature is returned when a signer's partial signature is added a second time
+var ErrDuplicatePartialSignature = errors.New("the signer's partial signature was already added")
+
+// PartialSignatures combines the partial signatures s_j of the signers of a session into s. Each is kept in the place
+// of its signer, so that it is counted exactly once whatever the order in which they arrive, and a second one from a
+// signer is detected rather than added to the sum.
+type PartialSignatures struct {
+	ec      elliptic.Curve
+	parties tss.SortedPartyIDs
+	sJ      []*big.Int
+}
+
+// NewPartialSignatures creates an empty set for the partial signatures of the signers `parties`
+func NewPartialSignatures(ec elliptic.Curve, parties tss.SortedPartyIDs) *PartialSignatures {
+	return &PartialSignatures{ec: ec, parties: parties, sJ: make([]*big.Int, len(parties))}
+}
+
+// Add adds the partial signature of the signer Pj. It returns ErrDuplicatePartialSignature if Pj's was already added,
+// keeping the first, and an error if Pj is not a signer.
+func (ps *PartialSignatures) Add(Pj *tss.PartyID, sJ *big.Int) error {
+	if Pj == nil || sJ == nil {
+		return errors.New("PartialSignatures.Add() received a nil argument")
+	}
+	j := Pj.Index
+	if j < 0 || len(ps.parties) <= j || ps.parties[j].KeyInt().Cmp(Pj.KeyInt()) != 0 {
+		return fmt.Errorf("%s is not a signer of the session", Pj)
+	}
+	if ps.sJ[j] != nil {
+		if ps.sJ[j].Cmp(sJ) != 0 {
+			return fmt.Errorf("%w: %s sent two different partial signatures", ErrDuplicatePartialSignature, Pj)
+		}
+		return ErrDuplicatePartialSignature
+	}
+	ps.sJ[j] = new(big.Int).Set(sJ)
+	return nil
+}
+
+// Missing returns the signers whose partial signatures have not been added
+func (ps *PartialSignatures) Missing() []*tss.PartyID {
+	missing := make([]*tss.PartyID, 0, len(ps.parties))
+	for j, sJ := range ps.sJ {
+		if sJ == nil {
+			missing = append(missing, ps.parties[j])
+		}
+	}
+	return missing
+}
+
+// Sum returns s, the sum of the partial signatures mod the order of the curve, once every signer's has been added
+func (ps *PartialSignatures) Sum() (*big.Int, error) {
+	if missing := ps.Missing(); 0 < len(missing) {
+		return nil, fmt.Errorf("the partial signatures of %v are missing", missing)
+	}
+	modN := common.ModInt(ps.ec.Params().N)
+	s := new(big.Int)
+	for _, sJ := range ps.sJ {
+		s = modN.Add(s, sJ)
+	}
+	return s, nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestPartialSignaturesOutOfOrder(t *testing.T) {
+	ec := edwards.Edwards()
+	pIDs := tss.GenerateTestPartyIDs(5)
+	sJ := make([]*big.Int, len(pIDs))
+	expected := new(big.Int)
+	for j := range pIDs {
+		sJ[j] = common.GetRandomPositiveInt(ec.Params().N)
+		expected.Add(expected, sJ[j])
+	}
+	expected.Mod(expected, ec.Params().N)
+
+	ps := NewPartialSignatures(ec, pIDs)
+	order := rand.Perm(len(pIDs))
+	for i, j := range order {
+		assert.NoError(t, ps.Add(pIDs[j], sJ[j]))
+		if i == 1 {
+			// a resent copy is detected and not counted again
+			err := ps.Add(pIDs[order[0]], sJ[order[0]])
+			assert.True(t, errors.Is(err, ErrDuplicatePartialSignature))
+		}
+		if i < len(order)-1 {
+			assert.Len(t, ps.Missing(), len(pIDs)-i-1)
+			_, err := ps.Sum()
+			assert.Error(t, err, "the sum should fail while partial signatures are missing")
+		}
+	}
+
+	// a different partial signature from a signer already counted does not replace the first
+	err := ps.Add(pIDs[order[2]], new(big.Int).Add(sJ[order[2]], big.NewInt(1)))
+	assert.True(t, errors.Is(err, ErrDuplicatePartialSignature))
+	assert.Contains(t, err.Error(), "two different partial signatures")
+
+	s, err := ps.Sum()
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, expected.Cmp(s), "s should be the sum of each partial signature counted once")
+	}
+	assert.Empty(t, ps.Missing())
+}
+
+func TestPartialSignaturesRejectsUnknownSigner(t *testing.T) {
+	ec := edwards.Edwards()
+	pIDs := tss.GenerateTestPartyIDs(3)
+	ps := NewPartialSignatures(ec, pIDs[:2])
+	assert.Error(t, ps.Add(pIDs[2], big.NewInt(1)), "a party outside the signers should be rejected")
+	assert.Error(t, ps.Add(pIDs[0], nil))
+}
@@ -7,16 +7,22 @@
 package signing
 
 import (
+	"crypto/hmac"
+	"crypto/sha512"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 	"github.com/ordinox/thorchain-tss-lib/crypto"
 	"github.com/ordinox/thorchain-tss-lib/crypto/commitments"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
 	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
+const deterministicNonceDomain = "tss-lib eddsa deterministic nonce"
+
 // round 1 represents round 1 of the signing part of the EDDSA TSS spec
 func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *SignatureData) tss.Round {
 	return &round1{
@@ -32,13 +38,23 @@ func (round *round1) Start() *tss.Error {
 	round.started = true
 	round.resetOK()
 
+	if size := round.EdDSAHash()().Size(); size != 64 {
+		return round.WrapError(fmt.Errorf("the EdDSA hash must have a 64-byte digest, got %d bytes", size))
+	}
+
 	i := round.PartyID().Index
 
 	// 1. select ri
-	ri := common.GetRandomPositiveInt(tss.EC().Params().N)
+	ri := common.GetRandomPositiveInt(round.EC().Params().N)
+	if round.DeterministicNonce() {
+		if round.temp.m == nil {
+			return round.WrapError(errors.New("a deterministic nonce needs the message to sign"))
+		}
+		ri = deterministicNonce(round.EC().Params().N, round.key.Xi, round.SessionID(round.temp.m), round.temp.m)
+	}
 
 	// 2. make commitment
-	pointRi := crypto.ScalarBaseMult(tss.EC(), ri)
+	pointRi := crypto.ScalarBaseMult(round.EC(), ri)
 	cmt := commitments.NewHashCommitment(pointRi.X(), pointRi.Y())
 
 	// 3. store r1 message pieces
@@ -47,9 +63,9 @@ func (round *round1) Start() *tss.Error {
 	round.temp.deCommit = cmt.D
 
 	// 4. broadcast commitment
-	r1msg2 := NewSignRound1Message(round.PartyID(), cmt.C)
+	r1msg2 := NewSignRound1Message(round.PartyID(), round.temp.sessionID, cmt.C)
 	round.temp.signRound1Messages[i] = r1msg2
-	round.out <- r1msg2
+	round.out <- round.UseCodec(r1msg2)
 
 	return nil
 }
@@ -70,8 +86,8 @@ func (round *round1) Update() (bool, *tss.Error) {
 }
 
 func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound1Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound1Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -93,8 +109,28 @@ func (round *round1) prepare() error {
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
-	wi := PrepareForSigning(i, len(ks), xi, ks)
+	// the Lagrange coefficients are only defined and non-zero when the indices are distinct and non-zero mod q
+	if _, err := vss.CheckIndexes(round.EC(), ks); err != nil {
+		return fmt.Errorf("the signing subset is malformed: %v", err)
+	}
+	wi := PrepareForSigning(round.EC(), i, len(ks), xi, ks)
 
 	round.temp.wi = wi
 	return nil
 }
+
+// deterministicNonce derives r_i as RFC 8032 derives the nonce of a single signer, with a keyed hash of the message:
+// HMAC-SHA-512 keyed with the share x_i over the session ID and the message, reduced mod q. The 512-bit digest makes
+// the bias of the reduction negligible. See Parameters.UNSAFE_SetDeterministicNonce for why this is unsafe.
+func deterministicNonce(q, xi *big.Int, sessionID []byte, m *big.Int) *big.Int {
+	key := append([]byte(deterministicNonceDomain), xi.Bytes()...)
+	for counter := byte(0); ; counter++ {
+		mac := hmac.New(sha512.New, key)
+		mac.Write(sessionID)
+		mac.Write(m.Bytes())
+		mac.Write([]byte{counter})
+		if ri := new(big.Int).Mod(new(big.Int).SetBytes(mac.Sum(nil)), q); ri.Sign() != 0 {
+			return ri
+		}
+	}
+}
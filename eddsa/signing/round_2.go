@@ -8,6 +8,7 @@ package signing
 
 import (
 	"errors"
+	"math/big"
 
 	errors2 "github.com/pkg/errors"
 
@@ -30,24 +31,28 @@ func (round *round2) Start() *tss.Error {
 		r1msg := msg.Content().(*SignRound1Message)
 		round.temp.cjs[j] = r1msg.UnmarshalCommitment()
 	}
+	round.temp.nonces.Lock()
+	round.temp.nonces.cjs = append([]*big.Int(nil), round.temp.cjs...)
+	round.temp.nonces.pointRi = round.temp.pointRi
+	round.temp.nonces.Unlock()
 
 	// 2. compute Schnorr prove
-	pir, err := zkp.NewDLogProof(round.temp.ri, round.temp.pointRi)
+	pir, err := zkp.NewDLogProof(round.EC(), round.temp.ri, round.temp.pointRi)
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewDLogProof(ri, pointRi)"))
 	}
 
 	// 3. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
-	r2msg := NewSignRound2Message(round.PartyID(), round.temp.deCommit, pir)
+	r2msg := NewSignRound2Message(round.PartyID(), round.temp.sessionID, round.temp.deCommit, pir)
 	round.temp.signRound2Messages[i] = r2msg
-	round.out <- r2msg
+	round.out <- round.UseCodec(r2msg)
 
 	return nil
 }
 
 func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound2Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound2Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
@@ -7,8 +7,6 @@
 package signing
 
 import (
-	"crypto/sha512"
-
 	"github.com/agl/ed25519/edwards25519"
 	"github.com/pkg/errors"
 
@@ -17,6 +15,15 @@ import (
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
+// ErrNonceDeCommitment is returned when a signer reveals a nonce point R_j that does not open its commitment of round 1
+var ErrNonceDeCommitment = errors.New("de-commitment verify failed: the revealed R_j does not match the commitment")
+
+// ErrNonceIsIdentity is returned when the sum R of the signers' nonce points is the identity
+var ErrNonceIsIdentity = errors.New("the aggregated nonce point R is the identity")
+
+// encodedIdentity is the encoding of the identity (0, 1)
+var encodedIdentity = [32]byte{1}
+
 func (round *round3) Start() *tss.Error {
 	if round.started {
 		return round.WrapError(errors.New("round already started"))
@@ -42,60 +49,59 @@ func (round *round3) Start() *tss.Error {
 		r2msg := msg.Content().(*SignRound2Message)
 		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
 		ok, coordinates := cmtDeCmt.DeCommit()
+		// R_j is bound to the commitment of round 1, before any nonce point was revealed
 		if !ok {
-			return round.WrapError(errors.New("de-commitment verify failed"))
+			return round.WrapError(ErrNonceDeCommitment, Pj)
 		}
 		if len(coordinates) != 2 {
-			return round.WrapError(errors.New("length of de-commitment should be 2"))
+			return round.WrapError(errors.New("length of de-commitment should be 2"), Pj)
 		}
 
-		Rj, err := crypto.NewECPoint(tss.EC(), coordinates[0], coordinates[1])
-		Rj = Rj.EightInvEight()
+		Rj, err := crypto.NewECPoint(round.EC(), coordinates[0], coordinates[1])
 		if err != nil {
 			return round.WrapError(errors.Wrapf(err, "NewECPoint(Rj)"), Pj)
 		}
-		proof, err := r2msg.UnmarshalZKProof()
-		if err != nil {
+		if !Rj.IsInPrimeOrderSubgroup() {
+			return round.WrapError(errors.New("Rj is not in the prime-order subgroup"), Pj)
+		}
+		proof, err := r2msg.UnmarshalZKProof(round.EC())
+		if err != nil || !proof.Alpha.IsInPrimeOrderSubgroup() {
 			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
 		}
-		ok = proof.Verify(Rj)
+		ok = proof.Verify(round.EC(), Rj)
+		round.ObserveProof(tss.ProofDLog, ok)
 		if !ok {
-			return round.WrapError(errors.New("failed to prove Rj"), Pj)
+			return round.WrapError(tss.NewFaultError(tss.FaultBadProof, errors.New("failed to prove Rj")), Pj)
 		}
 
-		extendedRj := ecPointToExtendedElement(Rj.X(), Rj.Y())
+		extendedRj := ecPointToExtendedElement(round.EC(), Rj.X(), Rj.Y())
 		R = addExtendedElements(R, extendedRj)
 	}
 
 	// 7. compute lambda
 	var encodedR [32]byte
 	R.ToBytes(&encodedR)
+	// nonce points that cancel out would make a signature with the nonce 0, which reveals the key
+	if encodedR == encodedIdentity {
+		return round.WrapError(ErrNonceIsIdentity)
+	}
 	encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
 
-	// h = hash512(k || A || M)
-	h := sha512.New()
-	h.Reset()
-	_, _ = h.Write(encodedR[:])
-	_, _ = h.Write(encodedPubKey[:])
-	_, _ = h.Write(round.temp.m.Bytes())
-
-	var lambda [64]byte
-	h.Sum(lambda[:0])
-	var lambdaReduced [32]byte
-	edwards25519.ScReduce(&lambdaReduced, &lambda)
+	// h = hash512(k || A || M), or the hash of the EdDSA variant set in the parameters
+	lambdaReduced := challengeHash(round.EdDSAHash(), &encodedR, encodedPubKey, round.temp.m.Bytes())
 
 	// 8. compute si
 	var localS [32]byte
-	edwards25519.ScMulAdd(&localS, &lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
+	edwards25519.ScMulAdd(&localS, lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
 
 	// 9. store r3 message pieces
 	round.temp.si = &localS
 	round.temp.r = encodedBytesToBigInt(&encodedR)
 
 	// 10. broadcast si to other parties
-	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS))
+	r3msg := NewSignRound3Message(round.PartyID(), round.temp.sessionID, encodedBytesToBigInt(&localS))
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
-	round.out <- r3msg
+	round.out <- round.UseCodec(r3msg)
 
 	return nil
 }
@@ -116,12 +122,37 @@ func (round *round3) Update() (bool, *tss.Error) {
 }
 
 func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
-	if _, ok := msg.Content().(*SignRound3Message); ok {
-		return msg.IsBroadcast()
+	if content, ok := msg.Content().(*SignRound3Message); ok {
+		return msg.IsBroadcast() && round.inSession(content)
 	}
 	return false
 }
 
+// Retry goes back to round 2 to receive new round 2 messages from `parties`. It is safe, as this round checks every
+// round 2 message before it computes or sends s_i, and a new message must still open the nonce commitment of round 1,
+// so a sender cannot change its R_j. Round 3 cannot be retried once s_i has been sent.
+func (round *round3) Retry(parties []*tss.PartyID) tss.Round {
+	i := round.PartyID().Index
+	if round.temp.signRound3Messages[i] != nil {
+		return nil
+	}
+	for _, Pj := range parties {
+		if Pj == nil || Pj.Index == i || len(round.ok) <= Pj.Index {
+			return nil
+		}
+	}
+	round.number = 2
+	round.started = true
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+	for _, Pj := range parties {
+		round.temp.signRound2Messages[Pj.Index] = nil
+		round.ok[Pj.Index] = false
+	}
+	return round.round2
+}
+
 func (round *round3) NextRound() tss.Round {
 	round.started = false
 	return &finalization{round}
@@ -7,6 +7,8 @@
 package signing
 
 import (
+	"bytes"
+
 	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
 	"github.com/ordinox/thorchain-tss-lib/tss"
 )
@@ -39,6 +41,11 @@ type (
 	finalization struct {
 		*round3
 	}
+
+	// sessionMessage is a message of any round, each of which carries the session ID
+	sessionMessage interface {
+		GetSessionId() []byte
+	}
 )
 
 var (
@@ -46,6 +53,8 @@ var (
 	_ tss.Round = (*round2)(nil)
 	_ tss.Round = (*round3)(nil)
 	_ tss.Round = (*finalization)(nil)
+
+	_ tss.RetryableRound = (*round3)(nil)
 )
 
 // ----- //
@@ -58,6 +67,12 @@ func (round *base) RoundNumber() int {
 	return round.number
 }
 
+// inSession reports whether a message carries the ID of this session, so that one relayed from another session is not
+// accepted
+func (round *base) inSession(content sessionMessage) bool {
+	return bytes.Equal(content.GetSessionId(), round.temp.sessionID)
+}
+
 // CanProceed is inherited by other rounds
 func (round *base) CanProceed() bool {
 	if !round.started {
@@ -7,12 +7,14 @@
 package signing
 
 import (
+	"crypto/elliptic"
+	"crypto/subtle"
+	"hash"
 	"math/big"
 
 	"github.com/agl/ed25519/edwards25519"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
-	"github.com/ordinox/thorchain-tss-lib/tss"
 )
 
 func encodedBytesToBigInt(s *[32]byte) *big.Int {
@@ -100,11 +102,11 @@ func addExtendedElements(p, q edwards25519.ExtendedGroupElement) edwards25519.Ex
 	return result
 }
 
-func ecPointToExtendedElement(x *big.Int, y *big.Int) edwards25519.ExtendedGroupElement {
+func ecPointToExtendedElement(ec elliptic.Curve, x *big.Int, y *big.Int) edwards25519.ExtendedGroupElement {
 	encodedXBytes := bigIntToEncodedBytes(x)
 	encodedYBytes := bigIntToEncodedBytes(y)
 
-	z := common.GetRandomPositiveInt(tss.EC().Params().N)
+	z := common.GetRandomPositiveInt(ec.Params().N)
 	encodedZBytes := bigIntToEncodedBytes(z)
 
 	var fx, fy, fxy edwards25519.FieldElement
@@ -126,3 +128,41 @@ func ecPointToExtendedElement(x *big.Int, y *big.Int) edwards25519.ExtendedGroup
 		T: T,
 	}
 }
+
+// challengeHash computes the challenge H(R || A || M) of an EdDSA signature, reduced mod the order of the curve. The
+// digest of newHash must be 64 bytes long.
+func challengeHash(newHash func() hash.Hash, encodedR, encodedPubKey *[32]byte, m []byte) *[32]byte {
+	h := newHash()
+	_, _ = h.Write(encodedR[:])
+	_, _ = h.Write(encodedPubKey[:])
+	_, _ = h.Write(m)
+
+	var digest [64]byte
+	copy(digest[:], h.Sum(nil))
+	lambdaReduced := new([32]byte)
+	edwards25519.ScReduce(lambdaReduced, &digest)
+	return lambdaReduced
+}
+
+// verifySignature verifies the EdDSA signature (r, s) of m under the encoded public key with the challenge hash
+// newHash. With SHA-512 it is the verification of Ed25519.
+func verifySignature(newHash func() hash.Hash, encodedPubKey *[32]byte, m []byte, r, s *big.Int) bool {
+	encodedR, encodedS := bigIntToEncodedBytes(r), bigIntToEncodedBytes(s)
+	if encodedS[31]&224 != 0 {
+		return false
+	}
+	var A edwards25519.ExtendedGroupElement
+	if !A.FromBytes(encodedPubKey) {
+		return false
+	}
+	edwards25519.FeNeg(&A.X, &A.X)
+	edwards25519.FeNeg(&A.T, &A.T)
+
+	// s*B - k*A must be R
+	k := challengeHash(newHash, encodedR, encodedPubKey, m)
+	var checkR edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&checkR, k, &A, encodedS)
+	var encodedCheckR [32]byte
+	checkR.ToBytes(&encodedCheckR)
+	return subtle.ConstantTimeCompare(encodedR[:], encodedCheckR[:]) == 1
+}
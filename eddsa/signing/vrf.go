@@ -0,0 +1,148 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+)
+
+const (
+	vrfDomain = "tss-lib eddsa VRF"
+)
+
+// ErrVRFShareExportDisabled is returned by VRFShare unless the parameters of the party enable the export
+var ErrVRFShareExportDisabled = errors.New("the export of VRF shares is not enabled in the parameters")
+
+// VRFShare is a signer's contribution Gamma_j = x_j*H(alpha) to the threshold VRF output of the input alpha, with a
+// proof that it uses the same x_j as the signer's public share X_j = x_j*G. The signer is identified by its evaluation
+// point Ks_j, which is the same in every member's save data.
+//
+// The contribution is derived from the key share rather than from the signing nonce r_j: a nonce is fresh in each
+// session, so a value derived from it would not be unique to alpha, and r_j must never be exposed, as anyone who
+// learns it can solve the partial signature s_j = r_j + k*w_j for the key share. Gamma_j reveals neither.
+type VRFShare struct {
+	Ks    *big.Int
+	Gamma *crypto.ECPoint
+	Proof *zkp.ECDDHProof
+}
+
+// VRFShare returns this party's share of the VRF output of `alpha`. It returns ErrVRFShareExportDisabled unless the
+// export was enabled with tss.Parameters.SetVRFShareExport. The share is safe to publish.
+func (p *LocalParty) VRFShare(alpha []byte) (*VRFShare, error) {
+	if !p.params.VRFShareExport() {
+		return nil, ErrVRFShareExportDisabled
+	}
+	if p.keys.Xi == nil {
+		return nil, errors.New("the key data holds no share")
+	}
+	ec := p.params.EC()
+	pointH, err := vrfInputPoint(ec, alpha)
+	if err != nil {
+		return nil, err
+	}
+	i := p.PartyID().Index
+	gamma := pointH.ScalarMult(p.keys.Xi)
+	proof := zkp.NewECDDHProof(zkp.ECDDHWitness{X: p.keys.Xi}, zkp.ECDDHStatement{
+		Curve: ec,
+		G2:    pointH,
+		H1:    p.keys.BigXj[i],
+		H2:    gamma,
+	})
+	return &VRFShare{Ks: new(big.Int).Set(p.keys.Ks[i]), Gamma: gamma, Proof: &proof}, nil
+}
+
+// CombineVRFShares checks the VRF shares of `alpha` of at least threshold+1 distinct members of the committee whose
+// evaluation points and public shares are `ks` and `bigXj`, as in the save data of any member, and combines the first
+// threshold+1 into Gamma = x*H(alpha) and the VRF output, a hash of Gamma. The public shares are checked to interpolate
+// to the public key `pub`, so the output is that of `pub` whichever members contributed. A share by a party outside the
+// committee or one whose proof does not verify fails the combination.
+func CombineVRFShares(
+	ec elliptic.Curve,
+	pub *crypto.ECPoint,
+	ks []*big.Int,
+	bigXj []*crypto.ECPoint,
+	threshold int,
+	alpha []byte,
+	shares []*VRFShare,
+) (output []byte, gamma *crypto.ECPoint, err error) {
+	if len(ks) != len(bigXj) {
+		return nil, nil, errors.New("the committee must have a public share for each evaluation point")
+	}
+	if len(shares) < threshold+1 {
+		return nil, nil, fmt.Errorf("%d VRF shares were given but %d are needed", len(shares), threshold+1)
+	}
+	pointH, err := vrfInputPoint(ec, alpha)
+	if err != nil {
+		return nil, nil, err
+	}
+	members := make([]int, 0, threshold+1)
+	for _, share := range shares[:threshold+1] {
+		if share == nil || share.Ks == nil || share.Gamma == nil || share.Proof == nil {
+			return nil, nil, errors.New("an incomplete VRF share was given")
+		}
+		j := -1
+		for k, kj := range ks {
+			if kj != nil && kj.Cmp(share.Ks) == 0 {
+				j = k
+				break
+			}
+		}
+		if j < 0 {
+			return nil, nil, fmt.Errorf("the signer with evaluation point %s is not a member of the committee", share.Ks)
+		}
+		for _, m := range members {
+			if m == j {
+				return nil, nil, fmt.Errorf("the signer with evaluation point %s gave more than one VRF share", share.Ks)
+			}
+		}
+		st := zkp.ECDDHStatement{Curve: ec, G2: pointH, H1: bigXj[j], H2: share.Gamma}
+		if bigXj[j] == nil || !share.Gamma.ValidateBasic() || !share.Proof.Verify(st) {
+			return nil, nil, fmt.Errorf("the VRF share of the signer with evaluation point %s failed to verify", share.Ks)
+		}
+		members = append(members, j)
+	}
+
+	// interpolate Gamma and the public key in the exponent at 0
+	modQ := common.ModInt(ec.Params().N)
+	var bigX *crypto.ECPoint
+	for c, j := range members {
+		lambda := big.NewInt(1)
+		for _, m := range members {
+			if m == j {
+				continue
+			}
+			lambda = modQ.Mul(lambda, modQ.Mul(ks[m], modQ.Inverse(new(big.Int).Sub(ks[m], ks[j]))))
+		}
+		gammaJ, bigXJ := shares[c].Gamma.ScalarMult(lambda), bigXj[j].ScalarMult(lambda)
+		if gamma == nil {
+			gamma, bigX = gammaJ, bigXJ
+			continue
+		}
+		if gamma, err = gamma.Add(gammaJ); err != nil {
+			return nil, nil, err
+		}
+		if bigX, err = bigX.Add(bigXJ); err != nil {
+			return nil, nil, err
+		}
+	}
+	if pub == nil || !bigX.Equals(pub) {
+		return nil, nil, errors.New("the public shares of the committee do not interpolate to the public key")
+	}
+	return common.SHA512_256([]byte(vrfDomain), gamma.Bytes()), gamma, nil
+}
+
+// vrfInputPoint is H(alpha), the point that the key shares evaluate alpha at; no one knows its discrete log
+func vrfInputPoint(ec elliptic.Curve, alpha []byte) (*crypto.ECPoint, error) {
+	return crypto.HashToCurve(ec, []byte(vrfDomain), alpha)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// vrfShares has the party of each of `keys` export its share of the VRF output of `alpha`
+func vrfShares(keys []keygen.LocalPartySaveData, alpha []byte, export bool) ([]*VRFShare, error) {
+	unsorted := make(tss.UnSortedPartyIDs, 0, len(keys))
+	for _, key := range keys {
+		moniker := key.ShareID.String()
+		unsorted = append(unsorted, tss.NewPartyID(moniker, moniker, key.ShareID))
+	}
+	pIDs := tss.SortPartyIDs(unsorted)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	shares := make([]*VRFShare, 0, len(keys))
+	for _, key := range keys {
+		var pID *tss.PartyID
+		for _, id := range pIDs {
+			if id.KeyInt().Cmp(key.ShareID) == 0 {
+				pID = id
+			}
+		}
+		params := tss.NewParameters(p2pCtx, pID, len(pIDs), testThreshold)
+		params.SetCurve(edwards.Edwards())
+		params.SetVRFShareExport(export)
+		P := NewLocalParty(big.NewInt(1), params, key, nil, nil).(*LocalParty)
+		share, err := P.VRFShare(alpha)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func TestVRFShares(t *testing.T) {
+	ec := edwards.Edwards()
+	alpha := []byte("the VRF input")
+
+	keys, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	committee := keys[0]
+
+	_, err = vrfShares(keys[:testThreshold+1], alpha, false)
+	assert.True(t, errors.Is(err, ErrVRFShareExportDisabled), "the export must be enabled explicitly")
+
+	shares, err := vrfShares(keys[:testThreshold+1], alpha, true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	output, gamma, err := CombineVRFShares(ec, committee.EDDSAPub, committee.Ks, committee.BigXj, testThreshold, alpha, shares)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Gamma is x*H(alpha) for the key x of the public key
+	x := new(big.Int)
+	for i, key := range keys[:testThreshold+1] {
+		x.Add(x, PrepareForSigning(ec, i, testThreshold+1, key.Xi, committee.Ks[:testThreshold+1]))
+	}
+	x.Mod(x, ec.Params().N)
+	assert.True(t, crypto.ScalarBaseMult(ec, x).Equals(committee.EDDSAPub))
+	pointH, err := crypto.HashToCurve(ec, []byte(vrfDomain), alpha)
+	if assert.NoError(t, err) {
+		assert.True(t, pointH.ScalarMult(x).Equals(gamma))
+	}
+
+	// the output is unique to the key and input, whichever members contribute
+	others, err := vrfShares(keys[testParticipants-testThreshold-1:], alpha, true)
+	if assert.NoError(t, err) {
+		otherOutput, _, err := CombineVRFShares(ec, committee.EDDSAPub, committee.Ks, committee.BigXj, testThreshold, alpha, others)
+		assert.NoError(t, err)
+		assert.Equal(t, output, otherOutput)
+	}
+	otherAlpha, err := vrfShares(keys[:testThreshold+1], []byte("another input"), true)
+	if assert.NoError(t, err) {
+		otherOutput, _, err := CombineVRFShares(ec, committee.EDDSAPub, committee.Ks, committee.BigXj, testThreshold, []byte("another input"), otherAlpha)
+		assert.NoError(t, err)
+		assert.NotEqual(t, output, otherOutput)
+	}
+
+	// a share that does not match the signer's public share is rejected
+	forged := *shares[1]
+	forged.Gamma = shares[1].Gamma.ScalarMult(big.NewInt(2))
+	forgedShares := append([]*VRFShare(nil), shares...)
+	forgedShares[1] = &forged
+	_, _, err = CombineVRFShares(ec, committee.EDDSAPub, committee.Ks, committee.BigXj, testThreshold, alpha, forgedShares)
+	assert.Error(t, err)
+
+	// too few shares, or shares for another public key, are rejected
+	_, _, err = CombineVRFShares(ec, committee.EDDSAPub, committee.Ks, committee.BigXj, testThreshold, alpha, shares[:testThreshold])
+	assert.Error(t, err)
+	otherPub := crypto.ScalarBaseMult(ec, common.GetRandomPositiveInt(ec.Params().N))
+	_, _, err = CombineVRFShares(ec, otherPub, committee.Ks, committee.BigXj, testThreshold, alpha, shares)
+	assert.Error(t, err)
+}
@@ -0,0 +1,11 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package metrics exports the events of a tss.Observer as Prometheus metrics.
+//
+// It is a module of its own, github.com/ordinox/thorchain-tss-lib/metrics, so that the rest of the library does not
+// depend on the Prometheus client. Run its tests from this directory.
+package metrics
@@ -0,0 +1,12 @@
+module github.com/ordinox/thorchain-tss-lib/metrics
+
+go 1.17
+
+require (
+	github.com/decred/dcrd/dcrec/edwards/v2 v2.0.0
+	github.com/ordinox/thorchain-tss-lib v0.0.0
+	github.com/prometheus/client_golang v1.11.0
+	github.com/stretchr/testify v1.8.0
+)
+
+replace github.com/ordinox/thorchain-tss-lib => ../
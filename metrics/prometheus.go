@@ -0,0 +1,81 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+const (
+	namespace = "tss"
+)
+
+// Implements tss.Observer
+var _ tss.Observer = (*Observer)(nil)
+
+type (
+	// Observer counts the rounds, proof checks and failures of the sessions it is set on with Parameters.SetObserver.
+	// One Observer may be shared by any number of sessions.
+	Observer struct {
+		roundDuration *prometheus.HistogramVec
+		proofs        *prometheus.CounterVec
+		failures      *prometheus.CounterVec
+	}
+)
+
+// NewObserver creates the collectors and registers them with `reg`
+func NewObserver(reg prometheus.Registerer) (*Observer, error) {
+	o := &Observer{
+		roundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "round_duration_seconds",
+			Help:      "Time from the start of a round until all of its messages were received.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+		}, []string{"task", "round"}),
+		proofs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "proof_verifications_total",
+			Help:      "Number of peer proofs checked, by kind of proof and result.",
+		}, []string{"proof", "result"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures_total",
+			Help:      "Number of errors returned by parties, by task, round and whether a peer was blamed.",
+		}, []string{"task", "round", "cause"}),
+	}
+	for _, c := range []prometheus.Collector{o.roundDuration, o.proofs, o.failures} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *Observer) RoundFinished(task string, round int, duration time.Duration) {
+	o.roundDuration.WithLabelValues(task, strconv.Itoa(round)).Observe(duration.Seconds())
+}
+
+func (o *Observer) ProofVerified(proof string, ok bool) {
+	result := "ok"
+	if !ok {
+		result = "failed"
+	}
+	o.proofs.WithLabelValues(proof, result).Inc()
+}
+
+func (o *Observer) Failed(err *tss.Error) {
+	cause := "peer"
+	if err.SelfCaused() {
+		cause = "self"
+	}
+	o.failures.WithLabelValues(err.Task(), strconv.Itoa(err.Round()), cause).Inc()
+}
@@ -0,0 +1,102 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package metrics
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestObserverReportsSession(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	observer, err := NewObserver(reg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pIDs := tss.GenerateTestPartyIDs(3)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	parties := make([]tss.Party, 0, len(pIDs))
+	for _, pID := range pIDs {
+		params := tss.NewParameters(p2pCtx, pID, len(pIDs), 1)
+		params.SetCurve(edwards.Edwards())
+		params.SetObserver(observer)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID() != msg.GetFrom() {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+
+		case <-endCh:
+			if ended++; ended == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	// a malformed message from a peer is counted as a failure
+	params := tss.NewParameters(p2pCtx, pIDs[0], len(pIDs), 1)
+	params.SetCurve(edwards.Edwards())
+	params.SetObserver(observer)
+	P := keygen.NewLocalParty(params, make(chan tss.Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+	_, _ = P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(0)))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(observer.roundDuration), "rounds 1 and 2 should be observed")
+	assert.Equal(t, float64(6), testutil.ToFloat64(observer.proofs.WithLabelValues(tss.ProofDLog, "ok")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(observer.failures.WithLabelValues(keygen.TaskName, "1", "peer")))
+
+	families, err := reg.Gather()
+	if assert.NoError(t, err) {
+		names := make([]string, 0, len(families))
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		assert.ElementsMatch(t, []string{
+			"tss_round_duration_seconds",
+			"tss_proof_verifications_total",
+			"tss_failures_total",
+		}, names)
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package multicurve coordinates an ECDSA and an EdDSA signing session run by the same set of nodes, so that one
+// logical event can be signed for chains on both curves in a single flow.
+package multicurve
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+
+	ecdsaKeygen "github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	ecdsaSigning "github.com/ordinox/thorchain-tss-lib/ecdsa/signing"
+	eddsaKeygen "github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	eddsaSigning "github.com/ordinox/thorchain-tss-lib/eddsa/signing"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+const (
+	TaskName = "multicurve-signing"
+)
+
+// The messages of ECDSA and EdDSA signing share protobuf names, so the wire bytes of a message start with a tag of the
+// session it belongs to, and are parsed with the message types of that session
+const (
+	ecdsaSession byte = iota
+	eddsaSession
+)
+
+var (
+	ecdsaMessages = newMessageTypes(
+		new(ecdsaSigning.SignRound1Message1), new(ecdsaSigning.SignRound1Message2), new(ecdsaSigning.SignRound2Message),
+		new(ecdsaSigning.SignRound3Message), new(ecdsaSigning.SignRound4Message), new(ecdsaSigning.SignRound5Message),
+		new(ecdsaSigning.SignRound6Message), new(ecdsaSigning.SignRound7Message),
+	)
+	eddsaMessages = newMessageTypes(
+		new(eddsaSigning.SignRound1Message), new(eddsaSigning.SignRound2Message), new(eddsaSigning.SignRound3Message),
+	)
+)
+
+// Implements Stringer
+var _ fmt.Stringer = (*LocalParty)(nil)
+
+type (
+	// LocalParty drives an ECDSA signing party and an EdDSA signing party of the same node side by side.
+	// Both sessions share the outbound message channel and report a single SignatureData once both have finished.
+	// The wire bytes of its messages are tagged with their session, so they must be passed to UpdateFromBytes.
+	// The nodes are matched across the two sessions by their PartyID.Id, so each node must use the same Id in both.
+	LocalParty struct {
+		ecdsaParty,
+		eddsaParty tss.Party
+		ecdsaParams,
+		eddsaParams *tss.Parameters
+
+		ecdsaOut,
+		eddsaOut chan tss.Message
+		ecdsaEnd chan *ecdsaSigning.SignatureData
+		eddsaEnd chan *eddsaSigning.SignatureData
+		out      chan<- tss.Message
+		end      chan<- *SignatureData
+		started  sync.Once
+		quit     chan struct{}
+		stopped  sync.Once
+	}
+
+	// message is a message of one of the sessions, whose wire bytes are tagged with the session
+	message struct {
+		tss.Message
+		session byte
+	}
+
+	// messageTypes are the message types of a session, by their protobuf name and by their Go type
+	messageTypes struct {
+		byName map[string]tss.MessageContent
+		byType map[reflect.Type]bool
+	}
+
+	// SignatureData holds the signatures of both sessions
+	SignatureData struct {
+		ECDSA *ecdsaSigning.SignatureData
+		EdDSA *eddsaSigning.SignatureData
+	}
+)
+
+// NewLocalParty constructs a party that signs ecdsaMsg with the ECDSA key and eddsaMsg with the EdDSA key.
+// The EdDSA session's parameters are set to the ed25519 curve; the ECDSA session keeps the curve of its parameters.
+func NewLocalParty(
+	ecdsaMsg, eddsaMsg *big.Int,
+	ecdsaParams, eddsaParams *tss.Parameters,
+	ecdsaKey ecdsaKeygen.LocalPartySaveData,
+	eddsaKey eddsaKeygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *SignatureData,
+) (*LocalParty, error) {
+	if ecdsaParams == nil || eddsaParams == nil {
+		return nil, errors.New("NewLocalParty() received nil parameters")
+	}
+	if ecdsaParams.PartyID().Id != eddsaParams.PartyID().Id {
+		return nil, fmt.Errorf("the ECDSA and EdDSA sessions belong to different nodes (%s != %s)",
+			ecdsaParams.PartyID().Id, eddsaParams.PartyID().Id)
+	}
+	ecdsaIDs, eddsaIDs := ecdsaParams.Parties().IDs(), eddsaParams.Parties().IDs()
+	if len(ecdsaIDs) != len(eddsaIDs) {
+		return nil, errors.New("the ECDSA and EdDSA sessions must have the same nodes")
+	}
+	for _, pID := range ecdsaIDs {
+		if findByID(eddsaIDs, pID.Id) == nil {
+			return nil, fmt.Errorf("node %s is in the ECDSA session but not in the EdDSA session", pID.Id)
+		}
+	}
+	eddsaParams.SetCurve(edwards.Edwards())
+
+	// a session can send all of its messages without blocking, so that it does not hang once run has returned
+	p := &LocalParty{
+		ecdsaParams: ecdsaParams,
+		eddsaParams: eddsaParams,
+		ecdsaOut:    make(chan tss.Message, ecdsaMessages.perSession(len(ecdsaIDs))),
+		eddsaOut:    make(chan tss.Message, eddsaMessages.perSession(len(eddsaIDs))),
+		ecdsaEnd:    make(chan *ecdsaSigning.SignatureData, 1),
+		eddsaEnd:    make(chan *eddsaSigning.SignatureData, 1),
+		out:         out,
+		end:         end,
+		quit:        make(chan struct{}),
+	}
+	p.ecdsaParty = ecdsaSigning.NewLocalParty(ecdsaMsg, ecdsaParams, ecdsaKey, p.ecdsaOut, p.ecdsaEnd)
+	p.eddsaParty = eddsaSigning.NewLocalParty(eddsaMsg, eddsaParams, eddsaKey, p.eddsaOut, p.eddsaEnd)
+	return p, nil
+}
+
+// Start starts both sessions concurrently and returns the first error of either. The sessions are started outside of
+// the goroutine budget of their parameters, as a party may wait for a place in the budget as it starts, e.g. to generate
+// its pre-parameters, which would never come if its own start held the last place.
+func (p *LocalParty) Start() *tss.Error {
+	p.started.Do(func() {
+		go p.run()
+	})
+	var ecdsaErr, eddsaErr *tss.Error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ecdsaErr = p.ecdsaParty.Start()
+	}()
+	go func() {
+		defer wg.Done()
+		eddsaErr = p.eddsaParty.Start()
+	}()
+	wg.Wait()
+	if ecdsaErr != nil {
+		p.stop()
+		return ecdsaErr
+	}
+	if eddsaErr != nil {
+		p.stop()
+		return eddsaErr
+	}
+	return nil
+}
+
+// Update routes a message to the session it belongs to. Its sender may be given as the node's PartyID in either session.
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	if msg == nil || msg.GetFrom() == nil {
+		return false, p.WrapError(errors.New("received a nil message or sender"))
+	}
+	var party tss.Party
+	var params *tss.Parameters
+	switch contentType := reflect.TypeOf(msg.Content()); {
+	case ecdsaMessages.byType[contentType]:
+		party, params = p.ecdsaParty, p.ecdsaParams
+	case eddsaMessages.byType[contentType]:
+		party, params = p.eddsaParty, p.eddsaParams
+	default:
+		return false, p.WrapError(fmt.Errorf("received a message of an unexpected type: %s", msg.Type()), msg.GetFrom())
+	}
+	from := findByID(params.Parties().IDs(), msg.GetFrom().Id)
+	if from == nil {
+		return false, p.WrapError(fmt.Errorf("received a message from an unknown node: %s", msg.GetFrom().Id), msg.GetFrom())
+	}
+	if from != msg.GetFrom() {
+		meta := tss.MessageRouting{
+			From:        from,
+			To:          msg.GetTo(),
+			IsBroadcast: msg.IsBroadcast(),
+		}
+		msg = tss.NewMessage(meta, msg.Content(), msg.WireMsg())
+	}
+	return party.Update(msg)
+}
+
+// UpdateFromBytes is like Update for the wire bytes of a message of this party's peers. It is parsed with the codec of
+// the ECDSA session, which the EdDSA session must share.
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := parseWireMessage(p.ecdsaParams.Codec(), wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err)
+	}
+	return p.Update(msg)
+}
+
+// Running returns whether either session is still running
+func (p *LocalParty) Running() bool {
+	return p.ecdsaParty.Running() || p.eddsaParty.Running()
+}
+
+// WaitingFor returns the nodes that either session is waiting for, as PartyIDs of the ECDSA session
+func (p *LocalParty) WaitingFor() []*tss.PartyID {
+	ecdsaIDs := p.ecdsaParams.Parties().IDs()
+	waiting := p.ecdsaParty.WaitingFor()
+	for _, pID := range p.eddsaParty.WaitingFor() {
+		if findByID(waiting, pID.Id) == nil {
+			waiting = append(waiting, findByID(ecdsaIDs, pID.Id))
+		}
+	}
+	return waiting
+}
+
+// ECDSAParty returns the party of the ECDSA session
+func (p *LocalParty) ECDSAParty() tss.Party {
+	return p.ecdsaParty
+}
+
+// EdDSAParty returns the party of the EdDSA session
+func (p *LocalParty) EdDSAParty() tss.Party {
+	return p.eddsaParty
+}
+
+func (p *LocalParty) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, -1, p.PartyID(), culprits...)
+}
+
+// PartyID returns this node's PartyID in the ECDSA session
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.ecdsaParams.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, ecdsa: %s, eddsa: %s", p.PartyID(), p.ecdsaParty, p.eddsaParty)
+}
+
+// run forwards the messages of both sessions, tagged with their session, until both have finished, and then reports
+// both signatures. It returns early once the party is stopped.
+func (p *LocalParty) run() {
+	data := &SignatureData{}
+	for data.ECDSA == nil || data.EdDSA == nil {
+		select {
+		case msg := <-p.ecdsaOut:
+			if !p.forward(&message{msg, ecdsaSession}) {
+				return
+			}
+		case msg := <-p.eddsaOut:
+			if !p.forward(&message{msg, eddsaSession}) {
+				return
+			}
+		case data.ECDSA = <-p.ecdsaEnd:
+		case data.EdDSA = <-p.eddsaEnd:
+		case <-p.quit:
+			return
+		}
+	}
+	// a session sends its messages before it finishes, but the last of them may still be buffered
+	for {
+		select {
+		case msg := <-p.ecdsaOut:
+			if !p.forward(&message{msg, ecdsaSession}) {
+				return
+			}
+		case msg := <-p.eddsaOut:
+			if !p.forward(&message{msg, eddsaSession}) {
+				return
+			}
+		default:
+			select {
+			case p.end <- data:
+			case <-p.quit:
+			}
+			return
+		}
+	}
+}
+
+// forward sends a message of either session to the outbound channel, and returns false if the party was stopped first
+func (p *LocalParty) forward(msg *message) bool {
+	select {
+	case p.out <- msg:
+		return true
+	case <-p.quit:
+		return false
+	}
+}
+
+func (p *LocalParty) stop() {
+	p.stopped.Do(func() {
+		close(p.quit)
+	})
+}
+
+// WireBytes returns the wire bytes of the message, after the tag of its session
+func (msg *message) WireBytes() ([]byte, *tss.MessageRouting, error) {
+	bz, routing, err := msg.Message.WireBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]byte{msg.session}, bz...), routing, nil
+}
+
+func newMessageTypes(contents ...tss.MessageContent) messageTypes {
+	types := messageTypes{
+		byName: make(map[string]tss.MessageContent, len(contents)),
+		byType: make(map[reflect.Type]bool, len(contents)),
+	}
+	for _, content := range contents {
+		types.byName[proto.MessageName(content)] = content
+		types.byType[reflect.TypeOf(content)] = true
+	}
+	return types
+}
+
+// perSession returns the most messages that a party of a session of partyCount parties sends, as it sends each type of
+// message at most once to each of its peers
+func (types messageTypes) perSession(partyCount int) int {
+	return len(types.byType) * partyCount
+}
+
+// parseWireMessage parses the tagged wire bytes of a message into a message type of the session of the tag
+func parseWireMessage(codec tss.Codec, wireBytes []byte, from *tss.PartyID, isBroadcast bool) (tss.ParsedMessage, error) {
+	if len(wireBytes) == 0 {
+		return nil, errors.New("received an empty message")
+	}
+	var types messageTypes
+	switch wireBytes[0] {
+	case ecdsaSession:
+		types = ecdsaMessages
+	case eddsaSession:
+		types = eddsaMessages
+	default:
+		return nil, fmt.Errorf("received a message of an unknown session: %d", wireBytes[0])
+	}
+	wire := &tss.MessageWrapper{
+		IsBroadcast: isBroadcast,
+		From:        from.MessageWrapper_PartyID,
+		Message:     new(any.Any),
+	}
+	if err := codec.Unmarshal(wireBytes[1:], wire.Message); err != nil {
+		return nil, err
+	}
+	typeURL := wire.Message.GetTypeUrl()
+	prototype, ok := types.byName[typeURL[strings.LastIndex(typeURL, "/")+1:]]
+	if !ok {
+		return nil, fmt.Errorf("received a message of an unexpected type: %s", typeURL)
+	}
+	content := proto.Clone(prototype).(tss.MessageContent)
+	if err := proto.Unmarshal(wire.Message.GetValue(), content); err != nil {
+		return nil, err
+	}
+	return tss.NewMessage(tss.MessageRouting{From: from, IsBroadcast: isBroadcast}, content, wire), nil
+}
+
+func findByID(pIDs []*tss.PartyID, id string) *tss.PartyID {
+	for _, pID := range pIDs {
+		if pID.Id == id {
+			return pID
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package multicurve
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	ecdsaKeygen "github.com/ordinox/thorchain-tss-lib/ecdsa/keygen"
+	ecdsaSigning "github.com/ordinox/thorchain-tss-lib/ecdsa/signing"
+	eddsaKeygen "github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	"github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+const (
+	testThreshold = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+func TestE2EConcurrent(t *testing.T) {
+	runE2E(t, nil, nil)
+}
+
+func TestE2EConcurrentGoroutineBudgetOfOne(t *testing.T) {
+	limiter, err := common.NewGoroutineLimiter(1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// one of the parties waits for the budget as it starts, which it would never get if its start held the only place
+	var wrapped bool
+	runE2E(t, limiter, func(P *LocalParty) {
+		if wrapped {
+			return
+		}
+		wrapped = true
+		P.ecdsaParty = &preParamsParty{Party: P.ecdsaParty, limiter: limiter}
+	})
+	assert.Equal(t, 1, limiter.Peak(), "both sessions should have run within the budget")
+}
+
+// preParamsParty generates pre-parameters within the goroutine budget before it starts the party it wraps
+type preParamsParty struct {
+	tss.Party
+	limiter *common.GoroutineLimiter
+}
+
+func (p *preParamsParty) Start() *tss.Error {
+	if _, err := ecdsaKeygen.GeneratePreParamsWithLimiter(10*time.Minute, p.limiter, 1); err != nil {
+		return p.WrapError(err)
+	}
+	return p.Party.Start()
+}
+
+// runE2E signs with both keys of the fixtures, with both sessions of every party sharing the given goroutine budget
+// unless it is nil, after wrap, if any, is applied to each party
+func runE2E(t *testing.T, limiter *common.GoroutineLimiter, wrap func(P *LocalParty)) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures; the same nodes hold a share of each key
+	ecdsaKeys, ecdsaPIDs, err := ecdsaKeygen.LoadKeygenTestFixtures(threshold + 1)
+	assert.NoError(t, err, "should load ecdsa keygen fixtures")
+	eddsaKeys, eddsaPIDs, err := eddsaKeygen.LoadKeygenTestFixtures(threshold + 1)
+	assert.NoError(t, err, "should load eddsa keygen fixtures")
+
+	// PHASE: signing
+	ecdsaCtx, eddsaCtx := tss.NewPeerContext(ecdsaPIDs), tss.NewPeerContext(eddsaPIDs)
+	parties := make(map[string]*LocalParty, len(ecdsaPIDs))
+
+	errCh := make(chan *tss.Error, 2*len(ecdsaPIDs))
+	outCh := make(chan tss.Message, 2*len(ecdsaPIDs))
+	endCh := make(chan *SignatureData, len(ecdsaPIDs))
+
+	ecdsaMsg, eddsaMsg := common.GetRandomPrimeInt(256), common.GetRandomPrimeInt(256)
+	for _, pID := range ecdsaPIDs {
+		ecdsaParams := tss.NewParameters(ecdsaCtx, pID, len(ecdsaPIDs), threshold)
+		eddsaParams := tss.NewParameters(eddsaCtx, findByID(eddsaPIDs, pID.Id), len(eddsaPIDs), threshold)
+		if limiter != nil {
+			ecdsaParams.SetGoroutineLimiter(limiter)
+			eddsaParams.SetGoroutineLimiter(limiter)
+		}
+		ecdsaKey, eddsaKey := ecdsaKeys[pID.Index], eddsaKeys[findByID(eddsaPIDs, pID.Id).Index]
+
+		P, err := NewLocalParty(ecdsaMsg, eddsaMsg, ecdsaParams, eddsaParams, ecdsaKey, eddsaKey, outCh, endCh)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if wrap != nil {
+			wrap(P)
+		}
+		parties[pID.Id] = P
+	}
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// the transport only knows the nodes by their Id
+	updater := func(P *LocalParty, msg tss.Message) {
+		bz, _, err := msg.WireBytes()
+		if err != nil {
+			errCh <- P.WrapError(err)
+			return
+		}
+		if _, err := P.UpdateFromBytes(bz, msg.GetFrom(), msg.IsBroadcast()); err != nil {
+			errCh <- err
+		}
+	}
+
+	var ended int
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for id, P := range parties {
+					if id == msg.GetFrom().Id {
+						continue
+					}
+					go updater(P, msg)
+				}
+			} else {
+				go updater(parties[dest[0].Id], msg)
+			}
+
+		case data := <-endCh:
+			ended++
+			ecdsaPK := ecdsa.PublicKey{
+				Curve: btcec.S256(),
+				X:     ecdsaKeys[0].ECDSAPub.X(),
+				Y:     ecdsaKeys[0].ECDSAPub.Y(),
+			}
+			r := new(big.Int).SetBytes(data.ECDSA.GetSignature().GetR())
+			s := new(big.Int).SetBytes(data.ECDSA.GetSignature().GetS())
+			assert.True(t, ecdsa.Verify(&ecdsaPK, ecdsaMsg.Bytes(), r, s), "ecdsa verify must pass")
+
+			eddsaPK := edwards.PublicKey{
+				Curve: edwards.Edwards(),
+				X:     eddsaKeys[0].EDDSAPub.X(),
+				Y:     eddsaKeys[0].EDDSAPub.Y(),
+			}
+			sig, err := edwards.ParseSignature(data.EdDSA.GetSignature().GetSignature())
+			if assert.NoError(t, err) {
+				assert.True(t, edwards.Verify(&eddsaPK, eddsaMsg.Bytes(), sig.R, sig.S), "eddsa verify must pass")
+			}
+
+			if ended == len(parties) {
+				t.Logf("Done. Received both signatures from %d participants", ended)
+				break signing
+			}
+		}
+	}
+}
+
+func TestNewLocalPartyRejectsDifferentNodes(t *testing.T) {
+	ecdsaKeys, ecdsaPIDs, err := ecdsaKeygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load ecdsa keygen fixtures")
+	eddsaKeys, eddsaPIDs, err := eddsaKeygen.LoadKeygenTestFixtures(testThreshold+2, 1)
+	assert.NoError(t, err, "should load eddsa keygen fixtures")
+
+	ecdsaParams := tss.NewParameters(tss.NewPeerContext(ecdsaPIDs), ecdsaPIDs[0], len(ecdsaPIDs), testThreshold)
+	eddsaParams := tss.NewParameters(tss.NewPeerContext(eddsaPIDs), eddsaPIDs[0], len(eddsaPIDs), testThreshold)
+	_, err = NewLocalParty(big.NewInt(1), big.NewInt(1), ecdsaParams, eddsaParams, ecdsaKeys[0], eddsaKeys[0], nil, nil)
+	assert.Error(t, err)
+}
+
+func TestStoppedPartyDoesNotBlockItsSessions(t *testing.T) {
+	ecdsaKeys, ecdsaPIDs, err := ecdsaKeygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load ecdsa keygen fixtures")
+	eddsaKeys, eddsaPIDs, err := eddsaKeygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load eddsa keygen fixtures")
+
+	ecdsaParams := tss.NewParameters(tss.NewPeerContext(ecdsaPIDs), ecdsaPIDs[0], len(ecdsaPIDs), testThreshold)
+	eddsaParams := tss.NewParameters(tss.NewPeerContext(eddsaPIDs), eddsaPIDs[0], len(eddsaPIDs), testThreshold)
+	// nobody reads the outbound channel, so run blocks as it forwards the first message
+	P, err := NewLocalParty(big.NewInt(1), big.NewInt(1), ecdsaParams, eddsaParams, ecdsaKeys[0], eddsaKeys[0],
+		make(chan tss.Message), make(chan *SignatureData))
+	if !assert.NoError(t, err) {
+		return
+	}
+	msg := tss.NewMessage(tss.MessageRouting{From: ecdsaPIDs[0], IsBroadcast: true}, new(ecdsaSigning.SignRound4Message), nil)
+	returned := make(chan struct{})
+	go func() {
+		P.run()
+		close(returned)
+	}()
+	P.ecdsaOut <- msg
+	P.stop()
+	<-returned
+
+	// the sessions can still send the rest of their messages once run has returned
+	for _, out := range []chan tss.Message{P.ecdsaOut, P.eddsaOut} {
+		for free := cap(out) - len(out); 0 < free; free-- {
+			select {
+			case out <- msg:
+			default:
+				assert.FailNow(t, "a session blocked on its outbound channel")
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// ByteParty drives a Party with serialized messages only, for transports that deal in bytes rather than channels.
+// Each message is serialized as its MessageWrapper, so that it carries its sender, recipients and broadcast flag for
+// the transport to route it; ParseWireRouting reads them back. The content of the wrapper is encoded with the codec of
+// the party's Parameters, as WireBytes encodes it.
+type ByteParty struct {
+	mtx    sync.Mutex
+	party  Party
+	params *Parameters
+	peers  SortedPartyIDs
+	out    <-chan Message
+}
+
+// the number of the field of a MessageWrapper that holds the content, which a ByteParty encodes with the session's codec;
+// see protob/message.proto
+const wireMessageField protowire.Number = 10
+
+// NewByteParty wraps a party that was constructed with the `out` channel, which must be buffered to hold all the
+// messages the party sends in a round (2 * len(peers) is enough for every protocol in this library). The `peers` are
+// the parties the messages may come from, including this one; the sender of an incoming message is found among them
+// by its key.
+func NewByteParty(party Party, peers SortedPartyIDs, out <-chan Message) *ByteParty {
+	return &ByteParty{party: party, params: party.FirstRound().Params(), peers: peers, out: out}
+}
+
+// Party returns the wrapped party
+func (bp *ByteParty) Party() Party {
+	return bp.party
+}
+
+// Start starts the party and returns its serialized outgoing messages. The lock is not held while the party starts, so
+// that messages may be processed meanwhile; those sent while starting may then be returned by ProcessBytes instead.
+func (bp *ByteParty) Start() (out [][]byte, err error) {
+	startErr := bp.party.Start()
+	bp.mtx.Lock()
+	defer bp.mtx.Unlock()
+	if startErr != nil {
+		out, _ = bp.drain()
+		return out, startErr
+	}
+	return bp.drain()
+}
+
+// ProcessBytes parses a message serialized by another party's ByteParty, updates the party with it and returns the
+// serialized messages that the party sends as a result. As with UpdateFromBytes, the transport should only deliver a
+// message that claims to be a broadcast if it was received via a reliable broadcast.
+// Calls are serialized so that the messages returned are those sent while processing `in`.
+func (bp *ByteParty) ProcessBytes(in []byte) (out [][]byte, err error) {
+	bp.mtx.Lock()
+	defer bp.mtx.Unlock()
+	wire, wireBytes, err := parseWireEnvelope(in)
+	if err != nil {
+		return nil, err
+	}
+	if wire.From == nil || wireBytes == nil {
+		return nil, errors.New("ProcessBytes: the message has no sender or content")
+	}
+	from := bp.peers.FindByKey(wire.From.KeyInt())
+	if from == nil {
+		return nil, fmt.Errorf("ProcessBytes: the message is from an unknown party %s", wire.From.GetId())
+	}
+	msg, err := bp.params.ParseWireMessage(wireBytes, from, wire.IsBroadcast)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bp.party.Update(msg); err != nil {
+		out, _ = bp.drain()
+		return out, err
+	}
+	return bp.drain()
+}
+
+// ParseWireRouting returns the routing of a message serialized by a ByteParty, so that the transport can deliver it.
+// The recipients are nil for a broadcast.
+func ParseWireRouting(bz []byte) (from *MessageWrapper_PartyID, to []*MessageWrapper_PartyID, isBroadcast bool, err error) {
+	wire, _, err := parseWireEnvelope(bz)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return wire.From, wire.To, wire.IsBroadcast, nil
+}
+
+// ParseWireContent returns the content of a message serialized by a ByteParty, encoded as WireBytes encodes it, so that
+// the transport can pass it to the UpdateFromBytes of a party that is not wrapped in a ByteParty
+func ParseWireContent(bz []byte) ([]byte, error) {
+	_, wireBytes, err := parseWireEnvelope(bz)
+	if err != nil {
+		return nil, err
+	}
+	if wireBytes == nil {
+		return nil, errors.New("ParseWireContent: the message has no content")
+	}
+	return wireBytes, nil
+}
+
+// drain serializes the messages that the party has sent so far
+func (bp *ByteParty) drain() (out [][]byte, err error) {
+	for {
+		select {
+		case msg := <-bp.out:
+			bz, err := WireEnvelope(msg)
+			if err != nil {
+				return out, err
+			}
+			out = append(out, bz)
+		default:
+			return out, nil
+		}
+	}
+}
+
+// WireEnvelope serializes a message as a ByteParty does: its MessageWrapper with the content encoded by WireBytes, i.e.
+// with the codec of the message's session. With the default codec it is the protobuf encoding of the MessageWrapper. A
+// transport uses it to pass the message of a party that is not wrapped in a ByteParty to ByteParty.ProcessBytes.
+func WireEnvelope(msg Message) ([]byte, error) {
+	wireBytes, _, err := msg.WireBytes()
+	if err != nil {
+		return nil, err
+	}
+	routing := proto.Clone(msg.WireMsg()).(*MessageWrapper)
+	routing.Message = nil
+	bz, err := proto.Marshal(routing)
+	if err != nil {
+		return nil, err
+	}
+	bz = protowire.AppendTag(bz, wireMessageField, protowire.BytesType)
+	return protowire.AppendBytes(bz, wireBytes), nil
+}
+
+// parseWireEnvelope splits bytes serialized by WireEnvelope into the routing of the MessageWrapper and the encoded
+// content, which is nil if there is none
+func parseWireEnvelope(bz []byte) (routing *MessageWrapper, wireBytes []byte, err error) {
+	fields := make([]byte, 0, len(bz))
+	for rest := bz; 0 < len(rest); {
+		num, typ, n := protowire.ConsumeTag(rest)
+		if n < 0 {
+			return nil, nil, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, rest[n:])
+		if m < 0 {
+			return nil, nil, protowire.ParseError(m)
+		}
+		if num == wireMessageField && typ == protowire.BytesType {
+			value, k := protowire.ConsumeBytes(rest[n:])
+			if k < 0 {
+				return nil, nil, protowire.ParseError(k)
+			}
+			wireBytes = value
+		} else {
+			fields = append(fields, rest[:n+m]...)
+		}
+		rest = rest[n+m:]
+	}
+	routing = new(MessageWrapper)
+	if err = proto.Unmarshal(fields, routing); err != nil {
+		return nil, nil, err
+	}
+	return routing, wireBytes, nil
+}
@@ -0,0 +1,48 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec serializes the content of wire messages, the protobuf Any that holds a protocol message, so that protobuf can
+// be swapped for a more compact encoding. All parties of a session must use the same codec.
+type Codec interface {
+	// Name identifies the codec. A codec other than the default is mixed into the SessionID, so that parties on
+	// different codecs derive different session IDs and do not take part in the same session.
+	Name() string
+	Marshal(msg proto.Message) ([]byte, error)
+	Unmarshal(bz []byte, into proto.Message) error
+}
+
+// ProtoCodec is the default Codec, which serializes with protobuf
+type ProtoCodec struct{}
+
+var _ Codec = ProtoCodec{}
+
+const protoCodecName = "protobuf"
+
+func (ProtoCodec) Name() string {
+	return protoCodecName
+}
+
+func (ProtoCodec) Marshal(msg proto.Message) ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+func (ProtoCodec) Unmarshal(bz []byte, into proto.Message) error {
+	return proto.Unmarshal(bz, into)
+}
+
+// codecOrDefault returns the codec, or ProtoCodec if it is nil
+func codecOrDefault(codec Codec) Codec {
+	if codec == nil {
+		return ProtoCodec{}
+	}
+	return codec
+}
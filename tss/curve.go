@@ -9,12 +9,41 @@ package tss
 import (
 	"crypto/elliptic"
 	"errors"
+	"math/big"
+	"sync"
 
 	s256k1 "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+type (
+	CurveName string
+
+	// CurveOrderPowers holds the order q of a curve and its powers, which the proofs use as sampling ranges and bounds.
+	CurveOrderPowers struct {
+		Q, Q2, Q3, Q4, Q5, Q6, Q7 *big.Int
+	}
+)
+
+const (
+	Secp256k1 CurveName = "secp256k1"
+	Nist256p1 CurveName = "nist256p1" // a.k.a secp256r1, P-256
+	Ed25519   CurveName = "ed25519"
 )
 
 var (
 	ec elliptic.Curve
+
+	// the curves that serialised points and keys may name; fixed at init, so safe to read concurrently
+	curves = map[CurveName]elliptic.Curve{
+		Secp256k1: s256k1.S256(),
+		Nist256p1: elliptic.P256(),
+		Ed25519:   edwards.Edwards(),
+	}
+
+	// the powers of the order of each curve seen so far, looked up by the order
+	curvePowersMtx sync.RWMutex
+	curvePowers    []*CurveOrderPowers
 )
 
 // Init default curve (secp256k1)
@@ -22,15 +51,95 @@ func init() {
 	ec = s256k1.S256()
 }
 
-// EC returns the current elliptic curve in use. The default is secp256k1
+// EC returns the default elliptic curve, which is given to new Parameters. The default is secp256k1
 func EC() elliptic.Curve {
 	return ec
 }
 
-// SetCurve sets the curve used by TSS. Must be called before Start. The default is secp256k1
+// SetCurve sets the default curve given to new Parameters. The default is secp256k1
+// To run sessions over different curves concurrently, set the curve on each session's Parameters instead.
 func SetCurve(curve elliptic.Curve) {
 	if curve == nil {
 		panic(errors.New("SetCurve received a nil curve"))
 	}
 	ec = curve
 }
+
+// GetCurveByName returns the curve registered under the given name
+func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
+	curve, ok := curves[name]
+	return curve, ok
+}
+
+// GetCurveName returns the name under which the given curve is registered.
+// Curves are matched by their domain parameters, as edwards.Edwards() returns a new instance on each call.
+func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
+	if curve == nil {
+		return "", false
+	}
+	params := curve.Params()
+	for name, c := range curves {
+		if c == curve {
+			return name, true
+		}
+		cp := c.Params()
+		if cp.P.Cmp(params.P) == 0 && cp.N.Cmp(params.N) == 0 && cp.Gx.Cmp(params.Gx) == 0 && cp.Gy.Cmp(params.Gy) == 0 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// CurvePowers returns the order of the default curve and its powers q^2 through q^7.
+// They are computed once per curve, so a later SetCurve is reflected without recomputing the powers of a known curve.
+func CurvePowers() *CurveOrderPowers {
+	return CurvePowersOf(EC())
+}
+
+// CurvePowersOf returns the order of the given curve and its powers q^2 through q^7, computing them once per curve order.
+// The caller gets its own copy of the cached values, so it may modify them without affecting other sessions.
+func CurvePowersOf(curve elliptic.Curve) *CurveOrderPowers {
+	return cachedCurvePowers(curve).copy()
+}
+
+func cachedCurvePowers(curve elliptic.Curve) *CurveOrderPowers {
+	q := curve.Params().N
+	curvePowersMtx.RLock()
+	for _, powers := range curvePowers {
+		if powers.Q.Cmp(q) == 0 {
+			curvePowersMtx.RUnlock()
+			return powers
+		}
+	}
+	curvePowersMtx.RUnlock()
+
+	powers := &CurveOrderPowers{Q: new(big.Int).Set(q)}
+	powers.Q2 = new(big.Int).Mul(powers.Q, powers.Q)
+	powers.Q3 = new(big.Int).Mul(powers.Q2, powers.Q)
+	powers.Q4 = new(big.Int).Mul(powers.Q3, powers.Q)
+	powers.Q5 = new(big.Int).Mul(powers.Q4, powers.Q)
+	powers.Q6 = new(big.Int).Mul(powers.Q5, powers.Q)
+	powers.Q7 = new(big.Int).Mul(powers.Q6, powers.Q)
+
+	curvePowersMtx.Lock()
+	defer curvePowersMtx.Unlock()
+	for _, cached := range curvePowers {
+		if cached.Q.Cmp(q) == 0 { // another caller got here first
+			return cached
+		}
+	}
+	curvePowers = append(curvePowers, powers)
+	return powers
+}
+
+func (powers *CurveOrderPowers) copy() *CurveOrderPowers {
+	return &CurveOrderPowers{
+		Q:  new(big.Int).Set(powers.Q),
+		Q2: new(big.Int).Set(powers.Q2),
+		Q3: new(big.Int).Set(powers.Q3),
+		Q4: new(big.Int).Set(powers.Q4),
+		Q5: new(big.Int).Set(powers.Q5),
+		Q6: new(big.Int).Set(powers.Q6),
+		Q7: new(big.Int).Set(powers.Q7),
+	}
+}
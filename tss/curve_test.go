@@ -0,0 +1,99 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestCurvePowers(t *testing.T) {
+	for _, curve := range []elliptic.Curve{btcec.S256(), elliptic.P256(), edwards.Edwards()} {
+		q := curve.Params().N
+		powers := CurvePowersOf(curve)
+		for k, qk := range []*big.Int{powers.Q, powers.Q2, powers.Q3, powers.Q4, powers.Q5, powers.Q6, powers.Q7} {
+			want := new(big.Int).Exp(q, big.NewInt(int64(k+1)), nil)
+			assert.Equal(t, 0, want.Cmp(qk), "q^%d of %s", k+1, curve.Params().Name)
+		}
+		// a caller modifying its powers in place must not corrupt those of other callers
+		powers.Q.SetInt64(1)
+		powers.Q7.Mod(powers.Q7, big.NewInt(2))
+		again := CurvePowersOf(curve)
+		assert.Equal(t, 0, q.Cmp(again.Q), "%s", curve.Params().Name)
+		assert.Equal(t, 0, new(big.Int).Exp(q, big.NewInt(7), nil).Cmp(again.Q7), "%s", curve.Params().Name)
+	}
+
+	prev := EC()
+	defer SetCurve(prev)
+	SetCurve(edwards.Edwards())
+	assert.Equal(t, 0, CurvePowers().Q.Cmp(edwards.Edwards().Params().N), "SetCurve should change the default curve's powers")
+}
+
+func TestGroupCurve(t *testing.T) {
+	for _, curve := range []elliptic.Curve{btcec.S256(), elliptic.P256(), edwards.Edwards()} {
+		assert.Equal(t, curve, GroupCurve(NewCurveGroup(curve)), "the curve of a curve's group should be the curve itself")
+	}
+
+	// a group that is not a curve is adapted, and is still recognised as the curve it implements
+	curve := GroupCurve(p256Group{elliptic.P256()})
+	name, ok := GetCurveName(curve)
+	assert.True(t, ok)
+	assert.Equal(t, Nist256p1, name)
+	assert.Equal(t, "nist256p1", curve.Params().Name)
+
+	k := big.NewInt(42).Bytes()
+	wantX, wantY := elliptic.P256().ScalarBaseMult(k)
+	x, y := curve.ScalarBaseMult(k)
+	assert.True(t, wantX.Cmp(x) == 0 && wantY.Cmp(y) == 0)
+	x2, y2 := curve.Double(x, y)
+	wantX, wantY = elliptic.P256().Double(wantX, wantY)
+	assert.True(t, wantX.Cmp(x2) == 0 && wantY.Cmp(y2) == 0)
+
+	params := NewParameters(nil, nil, 1, 0)
+	params.SetGroup(Ed25519Group())
+	assert.Equal(t, edwards.Edwards().Params().N, params.Group().Order())
+}
+
+// p256Group is a Group that implements P-256 without being an elliptic.Curve
+type p256Group struct {
+	c elliptic.Curve
+}
+
+func (g p256Group) Order() *big.Int                         { return g.c.Params().N }
+func (g p256Group) Cofactor() *big.Int                      { return big.NewInt(1) }
+func (g p256Group) Generator() (x, y *big.Int)              { return g.c.Params().Gx, g.c.Params().Gy }
+func (g p256Group) FieldOrder() *big.Int                    { return g.c.Params().P }
+func (g p256Group) IsOnCurve(x, y *big.Int) bool            { return g.c.IsOnCurve(x, y) }
+func (g p256Group) ScalarBaseMult(k []byte) (x, y *big.Int) { return g.c.ScalarBaseMult(k) }
+func (g p256Group) ScalarMult(x, y *big.Int, k []byte) (kx, ky *big.Int) {
+	return g.c.ScalarMult(x, y, k)
+}
+func (g p256Group) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) { return g.c.Add(x1, y1, x2, y2) }
+
+func BenchmarkCurvePowers(b *testing.B) {
+	b.Run("computed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q := EC().Params().N
+			q3 := new(big.Int).Mul(q, q)
+			q3.Mul(q3, q)
+			q7 := new(big.Int).Mul(q3, q3)
+			_ = q7.Mul(q7, q)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = CurvePowers().Q7
+		}
+	})
+}
@@ -7,6 +7,7 @@
 package tss
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -17,6 +18,8 @@ type Error struct {
 	round    int
 	victim   *PartyID
 	culprits []*PartyID
+	// whether the culprits were reported to the CulpritHandler, which happens once
+	reported bool
 }
 
 func NewError(err error, task string, round int, victim *PartyID, culprits ...*PartyID) *Error {
@@ -50,3 +53,37 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("task %s, party %v, round %d: %s",
 		err.task, err.victim, err.round, err.cause.Error())
 }
+
+// FaultType returns the kind of fault of the error's culprits: the one tagged by a FaultError in its cause, or
+// FaultProtocol
+func (err *Error) FaultType() string {
+	var fault *FaultError
+	if err != nil && errors.As(err.cause, &fault) {
+		return fault.Fault
+	}
+	return FaultProtocol
+}
+
+// FaultError tags an error with the kind of fault that it reports, one of the Fault* kinds
+type FaultError struct {
+	Fault string
+	Err   error
+}
+
+// NewFaultError tags `err` with the kind of fault that it reports
+func NewFaultError(fault string, err error) error {
+	return &FaultError{Fault: fault, Err: err}
+}
+
+func (err *FaultError) Error() string { return err.Err.Error() }
+
+func (err *FaultError) Unwrap() error { return err.Err }
+
+// withFault tags the cause of a non-nil error with the kind of fault, unless it is already tagged
+func withFault(err *Error, fault string) *Error {
+	var tagged *FaultError
+	if err == nil || errors.As(err.cause, &tagged) {
+		return err
+	}
+	return NewError(NewFaultError(fault, err.cause), err.task, err.round, err.victim, err.culprits...)
+}
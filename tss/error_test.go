@@ -0,0 +1,40 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestErrorRoundAndCulprits(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	cause := errors.New("bad share")
+	err := NewError(NewFaultError(FaultBadProof, cause), "signing", 2, pIDs[0], pIDs[1], pIDs[2])
+
+	assert.Equal(t, 2, err.Round())
+	assert.Equal(t, "signing", err.Task())
+	assert.Equal(t, pIDs[0], err.Victim())
+	assert.Equal(t, []*PartyID{pIDs[1], pIDs[2]}, err.Culprits())
+	assert.False(t, err.SelfCaused())
+	assert.Equal(t, FaultBadProof, err.FaultType())
+	assert.True(t, errors.Is(err, cause), "the cause must be reachable through the error interface")
+
+	var tssErr *Error
+	var wrapped error = err
+	assert.True(t, errors.As(wrapped, &tssErr))
+	assert.Equal(t, 2, tssErr.Round())
+
+	selfCaused := NewError(cause, "signing", 1, pIDs[0])
+	assert.True(t, selfCaused.SelfCaused())
+	assert.Empty(t, selfCaused.Culprits())
+	assert.Equal(t, FaultProtocol, selfCaused.FaultType())
+}
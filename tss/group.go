@@ -0,0 +1,117 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+type (
+	// Group is the set of operations on an elliptic curve group that the protocols use, so that a custom or optimised
+	// implementation of a curve can be plugged in with Parameters.SetGroup. Points are given by their affine coordinates.
+	// IsOnCurve need not check that a point lies in the prime-order subgroup; where the group has a cofactor, as that of
+	// ed25519 does, the points that need it are checked with crypto.ECPoint.IsInPrimeOrderSubgroup, so Cofactor must
+	// report it.
+	Group interface {
+		// Order returns the prime order q of the group
+		Order() *big.Int
+		// Cofactor returns the cofactor h of the curve, the ratio of the number of its points to the order q
+		Cofactor() *big.Int
+		// Generator returns the base point G
+		Generator() (x, y *big.Int)
+		// FieldOrder returns the order p of the field of the coordinates. Together with the order and the generator it
+		// identifies a standard curve, so that points serialised from a custom group can be read back on that curve.
+		FieldOrder() *big.Int
+		IsOnCurve(x, y *big.Int) bool
+		Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int)
+		ScalarMult(x, y *big.Int, k []byte) (kx, ky *big.Int)
+		ScalarBaseMult(k []byte) (kx, ky *big.Int)
+	}
+
+	// curveGroup adapts an elliptic.Curve to a Group
+	curveGroup struct {
+		elliptic.Curve
+	}
+
+	// groupCurve adapts a Group to the elliptic.Curve that the protocols are written against
+	groupCurve struct {
+		Group
+		params *elliptic.CurveParams
+	}
+)
+
+// NewCurveGroup returns the Group of an elliptic.Curve
+func NewCurveGroup(curve elliptic.Curve) Group {
+	if curve == nil {
+		panic(errors.New("NewCurveGroup received a nil curve"))
+	}
+	if g, ok := curve.(*groupCurve); ok {
+		return g.Group
+	}
+	return &curveGroup{curve}
+}
+
+// Ed25519Group returns the Group of ed25519. Like edwards.Edwards it only checks that points are on the curve, not that
+// they lie in the prime-order subgroup.
+func Ed25519Group() Group {
+	return NewCurveGroup(edwards.Edwards())
+}
+
+// GroupCurve returns an elliptic.Curve whose operations are those of the group. The curve of a group returned by
+// NewCurveGroup is returned as is.
+func GroupCurve(g Group) elliptic.Curve {
+	if g == nil {
+		panic(errors.New("GroupCurve received a nil group"))
+	}
+	if cg, ok := g.(*curveGroup); ok {
+		return cg.Curve
+	}
+	gx, gy := g.Generator()
+	params := &elliptic.CurveParams{
+		P:       g.FieldOrder(),
+		N:       g.Order(),
+		Gx:      gx,
+		Gy:      gy,
+		BitSize: g.FieldOrder().BitLen(),
+	}
+	curve := &groupCurve{Group: g, params: params}
+	if name, ok := GetCurveName(curve); ok {
+		params.Name = string(name)
+	}
+	return curve
+}
+
+func (cg *curveGroup) Order() *big.Int {
+	return cg.Params().N
+}
+
+func (cg *curveGroup) Cofactor() *big.Int {
+	if ed, ok := cg.Curve.(*edwards.TwistedEdwardsCurve); ok {
+		return big.NewInt(int64(ed.H))
+	}
+	return big.NewInt(1)
+}
+
+func (cg *curveGroup) Generator() (x, y *big.Int) {
+	return cg.Params().Gx, cg.Params().Gy
+}
+
+func (cg *curveGroup) FieldOrder() *big.Int {
+	return cg.Params().P
+}
+
+func (gc *groupCurve) Params() *elliptic.CurveParams {
+	return gc.params
+}
+
+func (gc *groupCurve) Double(x, y *big.Int) (x2, y2 *big.Int) {
+	return gc.Add(x, y, x, y)
+}
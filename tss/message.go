@@ -11,6 +11,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
 )
 
 type (
@@ -29,6 +31,7 @@ type (
 		// Indicates whether the message is to both committees during re-sharing; used mainly in tests
 		IsToOldAndNewCommittees() bool
 		// Returns the encoded inner message bytes to send over the wire along with metadata about how the message should be delivered
+		// The bytes are encoded with the session's Codec
 		WireBytes() ([]byte, *MessageRouting, error)
 		// Returns the protobuf message wrapper struct
 		// Only its inner content should be sent over the wire, not this struct itself
@@ -68,6 +71,8 @@ type (
 		MessageRouting
 		content MessageContent
 		wire    *MessageWrapper
+		// encodes the content in WireBytes; ProtoCodec if nil
+		codec Codec
 	}
 )
 
@@ -137,7 +142,7 @@ func (mm *MessageImpl) IsToOldAndNewCommittees() bool {
 }
 
 func (mm *MessageImpl) WireBytes() ([]byte, *MessageRouting, error) {
-	bz, err := proto.Marshal(mm.wire.Message)
+	bz, err := codecOrDefault(mm.codec).Marshal(mm.wire.Message)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -148,6 +153,11 @@ func (mm *MessageImpl) WireMsg() *MessageWrapper {
 	return mm.wire
 }
 
+// MemSize estimates the heap memory in bytes held by the content of the message and its wire form; see common.MemSize
+func (mm *MessageImpl) MemSize() int {
+	return common.MemSize(mm.content, mm.wire)
+}
+
 func (mm *MessageImpl) Content() MessageContent {
 	return mm.content
 }
@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+)
+
+// Observer receives events from a party as it runs, e.g. to export metrics. Set one with Parameters.SetObserver.
+// The methods may be called concurrently and from within the party's lock, so they must be quick and must not call
+// back into the party.
+type Observer interface {
+	// RoundFinished is called when a round has received all of its messages, with the time since the round started.
+	// A last round that completes in its Start without waiting for messages is not reported.
+	RoundFinished(task string, round int, duration time.Duration)
+	// ProofVerified is called each time a peer's zero-knowledge proof of the given kind has been checked
+	ProofVerified(proof string, ok bool)
+	// Failed is called with each error that a party returns from Start or Update
+	Failed(err *Error)
+}
+
+// Proof kinds reported to Observer.ProofVerified
+const (
+	ProofDLN      = "dln"
+	ProofPaillier = "paillier"
+	ProofDLog     = "dlog"
+	ProofECDDH    = "ecddh"
+	ProofPDL      = "pdl"
+	ProofST       = "st"
+	ProofT        = "t"
+)
+
+// observerOf returns the observer of the party's current round, or nil
+func observerOf(p Party) Observer {
+	if rnd := p.round(); rnd != nil {
+		return rnd.Params().Observer()
+	}
+	return nil
+}
+
+type (
+	// CulpritEvent is a structured report of a peer that a party has found at fault, e.g. for a SIEM. Set a
+	// CulpritHandler to receive one for each culprit of each error that a party returns.
+	CulpritEvent struct {
+		Task  string
+		Round int
+		// Culprit is the party at fault and PartyIndex its index in the session
+		Culprit    *PartyID
+		PartyIndex int
+		// Reporter is the party that found the fault
+		Reporter  *PartyID
+		FaultType string
+		// EvidenceHash digests the task, round, culprit and cause of the error, so that the reports of one fault by
+		// several parties can be matched
+		EvidenceHash []byte
+	}
+
+	// CulpritHandler receives the CulpritEvents of a party. Set one with Parameters.SetCulpritHandler. Like the
+	// methods of Observer it may be called concurrently and from within the party's lock.
+	CulpritHandler func(event CulpritEvent)
+
+	// ProgressFunc is told when a party starts a round, with the round number, the number of rounds of the protocol and
+	// the index of the party, e.g. to draw a progress bar. Set one with Parameters.SetProgressFunc. Like the methods of
+	// Observer it is called from within the party's lock; a panic in it is recovered and logged.
+	ProgressFunc func(round, totalRounds, partyIndex int)
+)
+
+// Fault kinds reported in CulpritEvent.FaultType
+const (
+	// FaultInvalidMessage is a message that is malformed or unexpected
+	FaultInvalidMessage = "invalid-message"
+	// FaultBadProof is a zero-knowledge proof that failed to verify
+	FaultBadProof = "bad-proof"
+	// FaultProtocol is any other deviation from the protocol, e.g. a failed consistency check
+	FaultProtocol = "protocol"
+	// FaultImpersonation is a message that claims the PartyID of a member but that its SenderVerifier rejected
+	FaultImpersonation = "impersonation"
+	// FaultTimeout is a party that had not sent its messages of a round when the session ran out of time
+	FaultTimeout = "timeout"
+)
+
+// CulpritEvents returns the events of the culprits of an error
+func CulpritEvents(err *Error) []CulpritEvent {
+	if err == nil {
+		return nil
+	}
+	events := make([]CulpritEvent, 0, len(err.culprits))
+	for _, culprit := range err.culprits {
+		if culprit == nil {
+			continue
+		}
+		var round [8]byte
+		binary.BigEndian.PutUint64(round[:], uint64(err.round))
+		var cause []byte
+		if err.cause != nil {
+			cause = []byte(err.cause.Error())
+		}
+		events = append(events, CulpritEvent{
+			Task:         err.task,
+			Round:        err.round,
+			Culprit:      culprit,
+			PartyIndex:   culprit.Index,
+			Reporter:     err.victim,
+			FaultType:    err.FaultType(),
+			EvidenceHash: common.SHA512_256([]byte(err.task), round[:], culprit.GetKey(), cause),
+		})
+	}
+	return events
+}
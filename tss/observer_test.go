@@ -0,0 +1,224 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	"github.com/ordinox/thorchain-tss-lib/test"
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+type recordingObserver struct {
+	mtx      sync.Mutex
+	rounds   map[int]int
+	proofs   map[string]int
+	failures []*Error
+}
+
+func (o *recordingObserver) RoundFinished(task string, round int, duration time.Duration) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.rounds[round]++
+}
+
+func (o *recordingObserver) ProofVerified(proof string, ok bool) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if ok {
+		o.proofs[proof]++
+	}
+}
+
+func (o *recordingObserver) Failed(err *Error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.failures = append(o.failures, err)
+}
+
+func TestObserver(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	observer := &recordingObserver{rounds: make(map[int]int), proofs: make(map[string]int)}
+
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	parties := make([]Party, 0, len(pIDs))
+	for _, pID := range pIDs {
+		params := NewParameters(p2pCtx, pID, len(pIDs), 1)
+		params.SetCurve(edwards.Edwards())
+		params.SetObserver(observer)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			for _, P := range parties {
+				if P.PartyID() != msg.GetFrom() {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+
+		case <-endCh:
+			if ended++; ended == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	observer.mtx.Lock()
+	// the last round completes in Start without waiting for messages
+	assert.Equal(t, map[int]int{1: 3, 2: 3}, observer.rounds, "each party should finish each round once")
+	assert.Equal(t, map[string]int{ProofDLog: 6}, observer.proofs, "each party should verify the proof of each peer")
+	assert.Empty(t, observer.failures)
+	observer.mtx.Unlock()
+
+	// a malformed message is reported as a failure caused by its sender
+	params := NewParameters(p2pCtx, pIDs[0], len(pIDs), 1)
+	params.SetCurve(edwards.Edwards())
+	params.SetObserver(observer)
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+	_, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(0)))
+	assert.NotNil(t, err)
+	if assert.Len(t, observer.failures, 1) {
+		assert.Equal(t, []*PartyID{pIDs[1]}, observer.failures[0].Culprits())
+	}
+}
+
+func TestCulpritHandler(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	var mtx sync.Mutex
+	var events []CulpritEvent
+	handler := func(event CulpritEvent) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		events = append(events, event)
+	}
+
+	errCh := make(chan *Error, len(pIDs))
+	outCh := make(chan Message, len(pIDs))
+	endCh := make(chan keygen.LocalPartySaveData, len(pIDs))
+
+	parties := make([]Party, 0, len(pIDs))
+	for _, pID := range pIDs {
+		params := NewParameters(p2pCtx, pID, len(pIDs), 1)
+		params.SetCurve(edwards.Edwards())
+		params.SetCulpritHandler(handler)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// the first party broadcasts a DLog proof that does not verify in round 2
+	bad := pIDs[0]
+	var errs []*Error
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			if errs = append(errs, err); len(errs) == len(pIDs)-1 {
+				break keygen
+			}
+
+		case msg := <-outCh:
+			if dest := msg.GetTo(); dest != nil {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				continue
+			}
+			if r2msg2, ok := msg.(ParsedMessage).Content().(*keygen.KGRound2Message2); ok && msg.GetFrom() == bad {
+				proof, err := r2msg2.UnmarshalZKProof(edwards.Edwards())
+				if !assert.NoError(t, err) {
+					return
+				}
+				proof = &zkp.DLogProof{Alpha: proof.Alpha, T: new(big.Int).Add(proof.T, big.NewInt(1))}
+				msg = keygen.NewKGRound2Message2(bad, r2msg2.UnmarshalDeCommitment(), proof)
+			}
+			for _, P := range parties {
+				if P.PartyID() != msg.GetFrom() {
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			}
+
+		case <-endCh:
+		}
+	}
+
+	for _, err := range errs {
+		assert.Equal(t, []*PartyID{bad}, err.Culprits())
+		assert.Equal(t, FaultBadProof, err.FaultType())
+	}
+	recorded := func() []CulpritEvent {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]CulpritEvent(nil), events...)
+	}
+	reported := recorded()
+	if !assert.Len(t, reported, len(pIDs)-1, "each honest party should report the culprit once") {
+		return
+	}
+	reporters := make(map[*PartyID]bool)
+	for _, event := range reported {
+		assert.Equal(t, keygen.TaskName, event.Task)
+		assert.Equal(t, 3, event.Round)
+		assert.Equal(t, bad, event.Culprit)
+		assert.Equal(t, bad.Index, event.PartyIndex)
+		assert.Equal(t, FaultBadProof, event.FaultType)
+		assert.Len(t, event.EvidenceHash, 32)
+		reporters[event.Reporter] = true
+	}
+	assert.Equal(t, map[*PartyID]bool{pIDs[1]: true, pIDs[2]: true}, reporters)
+	assert.Equal(t, reported[0].EvidenceHash, reported[1].EvidenceHash, "the reports of one fault should match")
+
+	// a malformed message is reported as an invalid message
+	params := NewParameters(p2pCtx, pIDs[0], len(pIDs), 1)
+	params.SetCurve(edwards.Edwards())
+	params.SetCulpritHandler(handler)
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+	_, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(0)))
+	assert.NotNil(t, err)
+	if reported = recorded(); assert.Len(t, reported, len(pIDs)) {
+		assert.Equal(t, FaultInvalidMessage, reported[len(pIDs)-1].FaultType)
+		assert.Equal(t, pIDs[1], reported[len(pIDs)-1].Culprit)
+	}
+}
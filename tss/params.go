@@ -7,7 +7,14 @@
 package tss
 
 import (
+	"bytes"
+	"context"
+	"crypto/elliptic"
+	"crypto/sha512"
 	"errors"
+	"fmt"
+	"hash"
+	"math/big"
 	"time"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
@@ -15,28 +22,101 @@ import (
 
 type (
 	Parameters struct {
+		context                 context.Context
+		ec                      elliptic.Curve
 		partyID                 *PartyID
 		parties                 *PeerContext
 		partyCount              int
 		threshold               int
 		safePrimeGenTimeout     time.Duration
 		unsafeKGIgnoreH1H2Dupes bool
+		compressKGCommitments   bool
+		concurrentVerification  bool
+		verificationChunkSize   int
+		modExpBackend           common.ModExpBackend
+		observer                Observer
+		culpritHandler          CulpritHandler
+		progressFunc            ProgressFunc
+		goroutineLimiter        *common.GoroutineLimiter
+		codec                   Codec
+		sessionExpiry           time.Time
+		sessionNonce            []byte
+		maxDuration             time.Duration
+		maxRetries              int
+		unknownSenderPolicy     UnknownSenderPolicy
+		eddsaHash               func() hash.Hash
+		mtaHash                 func() hash.Hash
+		senderVerifier          SenderVerifier
+		vrfShareExport          bool
+		deterministicNonce      bool
+		randomSeed              []byte
 	}
 
 	ReSharingParameters struct {
 		*Parameters
-		newParties    *PeerContext
-		newPartyCount int
-		newThreshold  int
+		newParties          *PeerContext
+		newPartyCount       int
+		newThreshold        int
+		retainedPartyPolicy RetainedPartyPolicy
 	}
+
+	// RetainedPartyPolicy is how a re-sharing treats a key that is in both the old and the new committee
+	RetainedPartyPolicy int
+
+	// UnknownSenderPolicy is how a party treats a message from a sender that is not a member of its committee
+	UnknownSenderPolicy int
+
+	// SenderVerifier authenticates the sender of an incoming message. It returns an error unless the transport
+	// authenticated `msg` as sent by msg.GetFrom(), e.g. by the channel it arrived on or a signature over its bytes.
+	SenderVerifier func(msg ParsedMessage) error
+
+	// ParameterOption changes a field of the copy of Parameters made by With
+	ParameterOption func(*Parameters)
+)
+
+const (
+	// RetainedPartyUnchecked accepts a key that is in both committees and is the default. The member with that key runs
+	// a single party, which contributes its old share to the re-sharing and receives a fresh share in its place.
+	RetainedPartyUnchecked RetainedPartyPolicy = iota
+	// RetainedPartyRequireNewKey rejects a key that is in both committees. A member that stays on then runs two parties,
+	// one per committee, and joins the new committee under a new key: its old party contributes its share to the
+	// re-sharing and its new party receives a fresh share.
+	RetainedPartyRequireNewKey
+)
+
+const (
+	// UnknownSenderLogAndDrop logs and drops the message, tolerating noise on an open network
+	UnknownSenderLogAndDrop UnknownSenderPolicy = iota
+	// UnknownSenderDrop drops the message silently
+	UnknownSenderDrop
+	// UnknownSenderAbort fails the update with an error that names the sender as the culprit
+	UnknownSenderAbort
 )
 
 const (
 	defaultSafePrimeGenTimeout = 5 * time.Minute
+	// each goroutine of a concurrent verification checks one peer, the finest granularity
+	defaultVerificationChunkSize = 1
+	defaultMaxRetries            = 3
+
+	sessionIDDomain    = "tss-lib session"
+	sessionNonceDomain = "tss-lib session nonce"
 )
 
 // Exported, used in `tss` client
 func NewParameters(ctx *PeerContext, partyID *PartyID, partyCount, threshold int, optionalSafePrimeGenTimeout ...time.Duration) *Parameters {
+	return NewParametersWithContext(context.Background(), ctx, partyID, partyCount, threshold, optionalSafePrimeGenTimeout...)
+}
+
+// NewParametersWithContext is NewParameters for a session that is abandoned once `ctx` is done. The party then stops
+// any pre-params generation that it runs, and its Start, Update and CheckDeadline return an error that wraps the error
+// of the context, e.g. context.Canceled. If `ctx` has a deadline before the safe prime generation timeout would run
+// out, the timeout is shortened to end at the deadline, and to 0 if the deadline has passed, so that the generation
+// fails at once.
+func NewParametersWithContext(ctx context.Context, peerCtx *PeerContext, partyID *PartyID, partyCount, threshold int, optionalSafePrimeGenTimeout ...time.Duration) *Parameters {
+	if ctx == nil {
+		panic(errors.New("NewParametersWithContext: expected a non-nil `ctx`"))
+	}
 	var safePrimeGenTimeout time.Duration
 	if 0 < len(optionalSafePrimeGenTimeout) {
 		if 1 < len(optionalSafePrimeGenTimeout) {
@@ -46,15 +126,113 @@ func NewParameters(ctx *PeerContext, partyID *PartyID, partyCount, threshold int
 	} else {
 		safePrimeGenTimeout = defaultSafePrimeGenTimeout
 	}
+	// the generation cannot outlast the session
+	if deadline, ok := ctx.Deadline(); ok {
+		if untilDeadline := time.Until(deadline); untilDeadline < safePrimeGenTimeout {
+			safePrimeGenTimeout = untilDeadline
+		}
+		if safePrimeGenTimeout < 0 {
+			safePrimeGenTimeout = 0
+		}
+	}
 	return &Parameters{
-		parties:             ctx,
+		context:             ctx,
+		ec:                  EC(),
+		parties:             peerCtx,
 		partyID:             partyID,
 		partyCount:          partyCount,
 		threshold:           threshold,
 		safePrimeGenTimeout: safePrimeGenTimeout,
+		maxRetries:          defaultMaxRetries,
+	}
+}
+
+// Context returns the context of this session, which is context.Background() unless the Parameters were created with
+// NewParametersWithContext
+func (params *Parameters) Context() context.Context {
+	if params.context == nil {
+		return context.Background()
+	}
+	return params.context
+}
+
+// EC returns the curve of this session. It is the package default curve at the time the Parameters were created
+// unless set with SetCurve.
+func (params *Parameters) EC() elliptic.Curve {
+	return params.ec
+}
+
+// SetCurve sets the curve of this session, so that sessions over different curves can run concurrently. Must be called before Start.
+func (params *Parameters) SetCurve(curve elliptic.Curve) {
+	if curve == nil {
+		panic(errors.New("SetCurve received a nil curve"))
+	}
+	params.ec = curve
+}
+
+// Group returns the group of this session's curve
+func (params *Parameters) Group() Group {
+	return NewCurveGroup(params.ec)
+}
+
+// SetGroup sets the curve of this session to the given group, so that its operations are done by the group's
+// implementation. Must be called before Start.
+func (params *Parameters) SetGroup(g Group) {
+	params.SetCurve(GroupCurve(g))
+}
+
+// Observer returns the observer of this session, or nil if none was set
+func (params *Parameters) Observer() Observer {
+	return params.observer
+}
+
+// SetObserver sets an observer that is told of this session's rounds, proofs and failures. Must be called before Start.
+func (params *Parameters) SetObserver(observer Observer) {
+	params.observer = observer
+}
+
+// CulpritHandler returns the culprit handler of this session, or nil if none was set
+func (params *Parameters) CulpritHandler() CulpritHandler {
+	return params.culpritHandler
+}
+
+// SetCulpritHandler sets a handler that receives a CulpritEvent for each culprit of each error of this session's
+// party. Must be called before Start.
+func (params *Parameters) SetCulpritHandler(handler CulpritHandler) {
+	params.culpritHandler = handler
+}
+
+// ObserveProof reports the outcome of checking a peer's proof to the observer, if one was set
+func (params *Parameters) ObserveProof(proof string, ok bool) {
+	if params.observer != nil {
+		params.observer.ProofVerified(proof, ok)
 	}
 }
 
+// ProgressFunc returns the progress callback of this session, or nil if none was set
+func (params *Parameters) ProgressFunc() ProgressFunc {
+	return params.progressFunc
+}
+
+// SetProgressFunc sets a callback that is told as this session's party starts each round. Must be called before Start.
+func (params *Parameters) SetProgressFunc(progress ProgressFunc) {
+	params.progressFunc = progress
+}
+
+// ReportProgress tells the progress callback, if one was set, that the party has started round `round` of
+// `totalRounds`. A panic in the callback is recovered, so that it cannot stop the protocol.
+func (params *Parameters) ReportProgress(round, totalRounds int) {
+	if params.progressFunc == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			common.Logger.Warnf("party %s: the progress callback panicked in round %d: %v", params.partyID, round, r)
+		}
+	}()
+	params.progressFunc(round, totalRounds, params.partyID.Index)
+}
+
 func (params *Parameters) Parties() *PeerContext {
 	return params.parties
 }
@@ -88,6 +266,459 @@ func (params *Parameters) UNSAFE_setKGIgnoreH1H2Dupes(unsafeKGIgnoreH1H2Dupes bo
 	params.unsafeKGIgnoreH1H2Dupes = unsafeKGIgnoreH1H2Dupes
 }
 
+// Getter. When enabled, keygen sends its VSS commitment points in compressed form, roughly halving that message's size.
+func (params *Parameters) CompressKGCommitments() bool {
+	return params.compressKGCommitments
+}
+
+// Setter. When enabled, keygen sends its VSS commitment points in compressed form, roughly halving that message's size.
+// Receivers accept either form, so parties need not agree on this setting.
+func (params *Parameters) SetCompressKGCommitments(compressKGCommitments bool) {
+	params.compressKGCommitments = compressKGCommitments
+}
+
+// Getter. When enabled, rounds verify the proofs of all peers concurrently rather than one after another.
+func (params *Parameters) ConcurrentVerification() bool {
+	return params.concurrentVerification
+}
+
+// Setter. When enabled, rounds verify the proofs of all peers concurrently rather than one after another.
+// The culprits of a failed round are the same either way. Must be called before Start.
+func (params *Parameters) SetConcurrentVerification(concurrentVerification bool) {
+	params.concurrentVerification = concurrentVerification
+}
+
+// VerificationChunkSize returns the number of peers whose messages each goroutine of a concurrent verification checks
+func (params *Parameters) VerificationChunkSize() int {
+	if params.verificationChunkSize < 1 {
+		return defaultVerificationChunkSize
+	}
+	return params.verificationChunkSize
+}
+
+// SetVerificationChunkSize sets the number of peers whose messages each goroutine of a concurrent verification checks,
+// one after the other. Larger chunks start fewer goroutines, which avoids oversubscribing a shared host in a large
+// session; a size below 1 restores the default of 1. Must be called before Start.
+func (params *Parameters) SetVerificationChunkSize(size int) {
+	params.verificationChunkSize = size
+}
+
+// MaxGoroutines returns the goroutine budget of this session, or 0 if it has none
+func (params *Parameters) MaxGoroutines() int {
+	return params.goroutineLimiter.Max()
+}
+
+// SetMaxGoroutines gives this session a budget of at most `max` goroutines for its internal parallelism, such as
+// checking the messages of its peers and generating safe primes; work beyond it is queued. A `max` below 1 removes the
+// budget. Must be called before Start.
+func (params *Parameters) SetMaxGoroutines(max int) {
+	params.goroutineLimiter, _ = common.NewGoroutineLimiter(max)
+}
+
+// GoroutineLimiter returns the goroutine budget of this session, or nil if it has none
+func (params *Parameters) GoroutineLimiter() *common.GoroutineLimiter {
+	return params.goroutineLimiter
+}
+
+// SetGoroutineLimiter sets the goroutine budget of this session, which may be shared with other sessions for a ceiling
+// on all of them. Must be called before Start.
+func (params *Parameters) SetGoroutineLimiter(limiter *common.GoroutineLimiter) {
+	params.goroutineLimiter = limiter
+}
+
+// Go runs fn in a new goroutine within the goroutine budget of this session, waiting for room in the budget first
+func (params *Parameters) Go(fn func()) {
+	params.goroutineLimiter.Go(fn)
+}
+
+// SessionExpiry returns the time after which this session is abandoned, or the zero time if it does not expire
+func (params *Parameters) SessionExpiry() time.Time {
+	return params.sessionExpiry
+}
+
+// SetSessionExpiry sets a time after which this session is abandoned, so that a stalled session cannot be completed
+// much later on stale assumptions. It is kept to the second. All parties of the session must set the same expiry.
+// Must be called before Start.
+func (params *Parameters) SetSessionExpiry(expiry time.Time) {
+	params.sessionExpiry = expiry.Truncate(time.Second)
+}
+
+// SessionExpired reports whether the session has an expiry that `now` is past
+func (params *Parameters) SessionExpired(now time.Time) bool {
+	return !params.sessionExpiry.IsZero() && now.After(params.sessionExpiry)
+}
+
+// SessionNonce returns the nonce that tells this session apart from others of the same parties, or nil if none was set
+func (params *Parameters) SessionNonce() []byte {
+	return params.sessionNonce
+}
+
+// SetSessionNonce sets a nonce that is mixed into the SessionID, so that the session is told apart from others of the
+// same parties on the same inputs, e.g. from an earlier attempt at it that failed: messages of one are then rejected
+// by the other. All parties of the session must set the same nonce, e.g. one that they agree on with the session's
+// other inputs, and a retry must use a fresh one. Must be called before Start.
+func (params *Parameters) SetSessionNonce(nonce []byte) {
+	params.sessionNonce = append([]byte(nil), nonce...)
+}
+
+// MaxDuration returns the longest that a party may run from Start until it finishes, or 0 if there is no limit
+func (params *Parameters) MaxDuration() time.Duration {
+	return params.maxDuration
+}
+
+// SetMaxDuration sets the longest that a party may run from Start until it finishes. Once it has run longer, Update
+// and CheckDeadline return an ErrSessionTimeout that names the current round and, as culprits, the parties it is
+// still waiting for. A party restored from a snapshot counts from when it resumed. 0, the default, sets no limit.
+func (params *Parameters) SetMaxDuration(maxDuration time.Duration) {
+	params.maxDuration = maxDuration
+}
+
+// MaxRetries returns how many times RetryRound may retry a round of the party
+func (params *Parameters) MaxRetries() int {
+	return params.maxRetries
+}
+
+// SetMaxRetries sets how many times RetryRound may retry a round of the party over the whole session, so that a peer
+// cannot stall it by failing a round again and again. Once they are used up, a transient failure is final. The
+// default is 3, and 0 disables retries.
+func (params *Parameters) SetMaxRetries(maxRetries int) {
+	params.maxRetries = maxRetries
+}
+
+// UnknownSenderPolicy returns how the party treats a message from a sender that is not a member of its committee
+func (params *Parameters) UnknownSenderPolicy() UnknownSenderPolicy {
+	return params.unknownSenderPolicy
+}
+
+// SetUnknownSenderPolicy sets how the party treats a message from a sender that is not a member of its committee.
+// The default is UnknownSenderLogAndDrop.
+func (params *Parameters) SetUnknownSenderPolicy(policy UnknownSenderPolicy) {
+	params.unknownSenderPolicy = policy
+}
+
+// IsCommitteeMember reports whether Pj is a member of the committee under the same key and index
+func (params *Parameters) IsCommitteeMember(Pj *PartyID) bool {
+	return isMemberOf(params.parties, Pj)
+}
+
+// SenderVerifier returns the verifier that authenticates the senders of incoming messages, or nil if they are not
+// authenticated
+func (params *Parameters) SenderVerifier() SenderVerifier {
+	return params.senderVerifier
+}
+
+// SetSenderVerifier sets the verifier that authenticates the sender of each incoming message from a member of the
+// committee, so that a peer that claims another's PartyID is rejected. Must be called before Start.
+func (params *Parameters) SetSenderVerifier(verifier SenderVerifier) {
+	params.senderVerifier = verifier
+}
+
+// VRFShareExport reports whether a signing party may export its share of a threshold VRF output
+func (params *Parameters) VRFShareExport() bool {
+	return params.vrfShareExport
+}
+
+// SetVRFShareExport allows a signing party to export its share of a threshold VRF output, which is disabled by default.
+// An exported share is derived from the party's key share and is safe to publish, but an application should only
+// enable it if it builds a VRF on the key, as every share it publishes evaluates the key on an input of its choosing.
+func (params *Parameters) SetVRFShareExport(vrfShareExport bool) {
+	params.vrfShareExport = vrfShareExport
+}
+
+// DeterministicNonce reports whether an EdDSA signing party derives its nonce r_i from its key share and the message
+// rather than drawing it at random
+func (params *Parameters) DeterministicNonce() bool {
+	return params.deterministicNonce
+}
+
+// UNSAFE_SetDeterministicNonce makes an EdDSA signing party derive its nonce r_i with a keyed hash of its key share over
+// the session and the message, as RFC 8032 derives the nonce of a single signer, so that a session can be replayed
+// to reproduce test vectors. It only changes the party's own contribution to the nonce R.
+//
+// ⚠️ UNSAFE: unlike a single signer, a party does not control R. A co-signer who makes two sessions over the same
+// message run with different nonces of its own gets two s_i with the same r_i under different challenges, from which
+// it solves for the party's key share. Every party setting this option does not prevent that. Only use it with
+// co-signers that are trusted not to deviate, e.g. in tests and audits, never with keys that hold value.
+func (params *Parameters) UNSAFE_SetDeterministicNonce(deterministicNonce bool) {
+	if deterministicNonce {
+		common.Logger.Warn("UNSAFE_SetDeterministicNonce() has been called; do not sign with these keys in production.")
+	}
+	params.deterministicNonce = deterministicNonce
+}
+
+// EdDSAHash returns the constructor of the hash of the EdDSA challenge H(R || A || M); SHA-512 by default, as Ed25519
+// mandates
+func (params *Parameters) EdDSAHash() func() hash.Hash {
+	if params.eddsaHash == nil {
+		return sha512.New
+	}
+	return params.eddsaHash
+}
+
+// SetEdDSAHash sets the hash of the EdDSA challenge, for variants of Ed25519 such as Ed25519-BLAKE2b. Its digest must be
+// 64 bytes long. All parties of a session must use the same hash. Must be called before Start.
+func (params *Parameters) SetEdDSAHash(newHash func() hash.Hash) {
+	params.eddsaHash = newHash
+}
+
+// MtAHash returns the constructor of the hash that derives the Fiat-Shamir challenges of Bob's proofs in the MtA
+// exchanges of ECDSA signing; SHA-512/256 by default
+func (params *Parameters) MtAHash() func() hash.Hash {
+	if params.mtaHash == nil {
+		return sha512.New512_256
+	}
+	return params.mtaHash
+}
+
+// SetMtAHash sets the hash of the challenges of Bob's MtA proofs, e.g. to SHA-256 where only certain hashes are
+// approved. Its digest must be at least as long as the curve order. All parties of a session must use the same hash.
+// Must be called before Start.
+func (params *Parameters) SetMtAHash(newHash func() hash.Hash) {
+	params.mtaHash = newHash
+}
+
+// ModExpBackend returns the backend that runs the modular exponentiations of proof verification, or nil for the
+// default of big.Int
+func (params *Parameters) ModExpBackend() common.ModExpBackend {
+	return params.modExpBackend
+}
+
+// SetModExpBackend sets the backend that runs the modular exponentiations of proof verification, so that they can be
+// offloaded to an accelerated implementation. Must be called before Start.
+func (params *Parameters) SetModExpBackend(backend common.ModExpBackend) {
+	params.modExpBackend = backend
+}
+
+// Codec returns the codec that this session's messages are serialized with; ProtoCodec by default
+func (params *Parameters) Codec() Codec {
+	return codecOrDefault(params.codec)
+}
+
+// SetCodec sets the codec that this session's messages are serialized with by WireBytes and parsed with by
+// UpdateFromBytes. All parties of the session must use the same codec. Must be called before Start.
+func (params *Parameters) SetCodec(codec Codec) {
+	params.codec = codec
+}
+
+// UseCodec makes WireBytes serialize a message that this party sends with the session's codec. Rounds call it on
+// every message they send.
+func (params *Parameters) UseCodec(msg ParsedMessage) ParsedMessage {
+	if mm, ok := msg.(*MessageImpl); ok && params.codec != nil {
+		mm.codec = params.codec
+	}
+	return msg
+}
+
+// ParseWireMessage parses a message received from the wire with the session's codec
+func (params *Parameters) ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	return ParseWireMessage(wireBytes, from, isBroadcast, params.Codec())
+}
+
+// With returns a copy of the Parameters with the options applied, e.g. to derive the configuration of a variant
+// session. The copy has its own PeerContext so that changes to either do not affect the other; the PartyIDs themselves
+// are shared, as they are not modified once sorted.
+func (params *Parameters) With(opts ...ParameterOption) *Parameters {
+	clone := *params
+	clone.parties = NewPeerContext(append(SortedPartyIDs(nil), params.parties.IDs()...))
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	return &clone
+}
+
+// WithThreshold sets the threshold of the copy made by With
+func WithThreshold(threshold int) ParameterOption {
+	return func(params *Parameters) {
+		params.threshold = threshold
+	}
+}
+
+// WithSafePrimeGenTimeout sets the safe prime generation timeout of the copy made by With
+func WithSafePrimeGenTimeout(timeout time.Duration) ParameterOption {
+	return func(params *Parameters) {
+		params.safePrimeGenTimeout = timeout
+	}
+}
+
+// WithCurve sets the curve of the copy made by With, as SetCurve does
+func WithCurve(curve elliptic.Curve) ParameterOption {
+	return func(params *Parameters) {
+		params.SetCurve(curve)
+	}
+}
+
+// WithCompressKGCommitments sets whether the copy made by With compresses keygen's VSS commitments
+func WithCompressKGCommitments(compressKGCommitments bool) ParameterOption {
+	return func(params *Parameters) {
+		params.compressKGCommitments = compressKGCommitments
+	}
+}
+
+// WithConcurrentVerification sets whether the copy made by With verifies the proofs of its peers concurrently
+func WithConcurrentVerification(concurrentVerification bool) ParameterOption {
+	return func(params *Parameters) {
+		params.concurrentVerification = concurrentVerification
+	}
+}
+
+// WithVerificationChunkSize sets the number of peers checked by each goroutine of a concurrent verification of the copy
+// made by With
+func WithVerificationChunkSize(size int) ParameterOption {
+	return func(params *Parameters) {
+		params.verificationChunkSize = size
+	}
+}
+
+// WithModExpBackend sets the ModExp backend of the copy made by With
+func WithModExpBackend(backend common.ModExpBackend) ParameterOption {
+	return func(params *Parameters) {
+		params.modExpBackend = backend
+	}
+}
+
+// WithCodec sets the codec of the copy made by With
+func WithCodec(codec Codec) ParameterOption {
+	return func(params *Parameters) {
+		params.codec = codec
+	}
+}
+
+// WithGoroutineLimiter sets the goroutine budget of the copy made by With
+func WithGoroutineLimiter(limiter *common.GoroutineLimiter) ParameterOption {
+	return func(params *Parameters) {
+		params.goroutineLimiter = limiter
+	}
+}
+
+// WithSessionExpiry sets the session expiry of the copy made by With, as SetSessionExpiry does
+func WithSessionExpiry(expiry time.Time) ParameterOption {
+	return func(params *Parameters) {
+		params.SetSessionExpiry(expiry)
+	}
+}
+
+// WithSessionNonce sets the session nonce of the copy made by With, as SetSessionNonce does
+func WithSessionNonce(nonce []byte) ParameterOption {
+	return func(params *Parameters) {
+		params.SetSessionNonce(nonce)
+	}
+}
+
+// WithMaxDuration sets the maximum duration of the copy made by With
+func WithMaxDuration(maxDuration time.Duration) ParameterOption {
+	return func(params *Parameters) {
+		params.maxDuration = maxDuration
+	}
+}
+
+// WithMaxRetries sets how many times a round of the copy made by With may be retried
+func WithMaxRetries(maxRetries int) ParameterOption {
+	return func(params *Parameters) {
+		params.maxRetries = maxRetries
+	}
+}
+
+// WithUnknownSenderPolicy sets how the copy made by With treats a message from a sender that is not a member of its
+// committee
+func WithUnknownSenderPolicy(policy UnknownSenderPolicy) ParameterOption {
+	return func(params *Parameters) {
+		params.unknownSenderPolicy = policy
+	}
+}
+
+// WithSenderVerifier sets the verifier of the senders of incoming messages of the copy made by With
+func WithSenderVerifier(verifier SenderVerifier) ParameterOption {
+	return func(params *Parameters) {
+		params.senderVerifier = verifier
+	}
+}
+
+// WithVRFShareExport sets whether a signing party of the copy made by With may export its share of a VRF output
+func WithVRFShareExport(vrfShareExport bool) ParameterOption {
+	return func(params *Parameters) {
+		params.vrfShareExport = vrfShareExport
+	}
+}
+
+// WithEdDSAHash sets the hash of the EdDSA challenge of the copy made by With
+func WithEdDSAHash(newHash func() hash.Hash) ParameterOption {
+	return func(params *Parameters) {
+		params.eddsaHash = newHash
+	}
+}
+
+// WithMtAHash sets the hash of the challenges of Bob's MtA proofs of the copy made by With
+func WithMtAHash(newHash func() hash.Hash) ParameterOption {
+	return func(params *Parameters) {
+		params.mtaHash = newHash
+	}
+}
+
+// WithObserver sets the observer of the copy made by With
+func WithObserver(observer Observer) ParameterOption {
+	return func(params *Parameters) {
+		params.observer = observer
+	}
+}
+
+// WithCulpritHandler sets the culprit handler of the copy made by With
+func WithCulpritHandler(handler CulpritHandler) ParameterOption {
+	return func(params *Parameters) {
+		params.culpritHandler = handler
+	}
+}
+
+// WithProgressFunc sets the progress callback of the copy made by With
+func WithProgressFunc(progress ProgressFunc) ParameterOption {
+	return func(params *Parameters) {
+		params.progressFunc = progress
+	}
+}
+
+// SessionID derives an identifier for the session from the sorted list of parties, the threshold and the curve, and,
+// when signing, from the message digest `msg` (pass nil otherwise), from the codec, the MtA hash and the EdDSA hash
+// unless they are the defaults and from the session expiry and the session nonce if they were set.
+// All parties of a session derive the same ID, so parties configured with different hashes see each other's messages
+// as from another session rather than blaming each other for proofs that fail to verify.
+func (params *Parameters) SessionID(msg *big.Int) []byte {
+	curve := params.EC().Params()
+	parts := [][]byte{
+		[]byte(sessionIDDomain),
+		big.NewInt(int64(params.Threshold())).Bytes(),
+		curve.P.Bytes(), curve.N.Bytes(), curve.Gx.Bytes(), curve.Gy.Bytes(),
+	}
+	for _, pID := range params.Parties().IDs() {
+		parts = append(parts, pID.GetKey())
+	}
+	if msg != nil {
+		parts = append(parts, msg.Bytes())
+	}
+	if name := params.Codec().Name(); name != protoCodecName {
+		parts = append(parts, []byte(name))
+	}
+	if id := hashIdentity(params.MtAHash()); !bytes.Equal(id, hashIdentity(sha512.New512_256)) {
+		parts = append(parts, id)
+	}
+	if id := hashIdentity(params.EdDSAHash()); !bytes.Equal(id, hashIdentity(sha512.New)) {
+		parts = append(parts, id)
+	}
+	if !params.sessionExpiry.IsZero() {
+		parts = append(parts, big.NewInt(params.sessionExpiry.Unix()).Bytes())
+	}
+	if len(params.sessionNonce) != 0 {
+		// the domain keeps a nonce from being taken for an expiry of the same bytes
+		parts = append(parts, []byte(sessionNonceDomain), params.sessionNonce)
+	}
+	return common.SHA512_256(parts...)
+}
+
+// hashIdentity tells hash functions apart by their digest of a fixed input
+func hashIdentity(newHash func() hash.Hash) []byte {
+	h := newHash()
+	_, _ = h.Write([]byte(sessionIDDomain))
+	return h.Sum(nil)
+}
+
 // ----- //
 
 // Exported, used in `tss` client
@@ -101,6 +732,23 @@ func NewReSharingParameters(ctx, newCtx *PeerContext, partyID *PartyID, partyCou
 	}
 }
 
+// NewThresholdIncreaseReSharingParameters builds the ReSharingParameters for re-sharing a key among the same n parties
+// under a higher threshold. The public key is unchanged; only the degree of the sharing polynomial grows.
+// Each party joins the new committee `newCtx` under a fresh PartyID, as a PartyID may not take a place in both committees.
+// Exported, used in `tss` client
+func NewThresholdIncreaseReSharingParameters(ctx, newCtx *PeerContext, partyID *PartyID, partyCount, threshold, newThreshold int) (*ReSharingParameters, error) {
+	if newPartyCount := len(newCtx.IDs()); newPartyCount != partyCount {
+		return nil, fmt.Errorf("the new committee has %d parties but the party count must stay at %d", newPartyCount, partyCount)
+	}
+	if newThreshold <= threshold {
+		return nil, fmt.Errorf("the new threshold %d must be greater than the current threshold %d", newThreshold, threshold)
+	}
+	if partyCount <= newThreshold {
+		return nil, fmt.Errorf("the new threshold %d is not achievable by %d parties; it must be at most n-1", newThreshold, partyCount)
+	}
+	return NewReSharingParameters(ctx, newCtx, partyID, partyCount, threshold, partyCount, newThreshold), nil
+}
+
 func (rgParams *ReSharingParameters) OldParties() *PeerContext {
 	return rgParams.Parties() // wr use the original method for old parties
 }
@@ -129,6 +777,46 @@ func (rgParams *ReSharingParameters) OldAndNewPartyCount() int {
 	return rgParams.OldPartyCount() + rgParams.NewPartyCount()
 }
 
+// ReSharingSessionID derives an identifier for the re-sharing from the SessionID of the old committee and from the new
+// committee and its threshold, so that the parties of both committees derive the same ID
+func (rgParams *ReSharingParameters) ReSharingSessionID() []byte {
+	parts := [][]byte{rgParams.SessionID(nil), big.NewInt(int64(rgParams.NewThreshold())).Bytes()}
+	for _, pID := range rgParams.NewParties().IDs() {
+		parts = append(parts, pID.GetKey())
+	}
+	return common.SHA512_256(parts...)
+}
+
+// IsCommitteeMember reports whether Pj is a member of the old or the new committee under the same key and index
+func (rgParams *ReSharingParameters) IsCommitteeMember(Pj *PartyID) bool {
+	return isMemberOf(rgParams.parties, Pj) || isMemberOf(rgParams.newParties, Pj)
+}
+
+// RetainedPartyPolicy returns how keys in both committees are treated; RetainedPartyUnchecked by default
+func (rgParams *ReSharingParameters) RetainedPartyPolicy() RetainedPartyPolicy {
+	return rgParams.retainedPartyPolicy
+}
+
+// SetRetainedPartyPolicy sets how keys in both committees are treated. Must be called before Start.
+func (rgParams *ReSharingParameters) SetRetainedPartyPolicy(policy RetainedPartyPolicy) {
+	rgParams.retainedPartyPolicy = policy
+}
+
+// CheckRetainedParties returns an error if a key is in both the old and the new committee and the policy rejects it.
+// Every party of the re-sharing runs this check, so that they all abort rather than wait on a party that aborted alone.
+func (rgParams *ReSharingParameters) CheckRetainedParties() error {
+	if rgParams.retainedPartyPolicy == RetainedPartyUnchecked {
+		return nil
+	}
+	for _, Pj := range rgParams.NewParties().IDs() {
+		if Pk := rgParams.OldParties().IDs().FindByKey(Pj.KeyInt()); Pk != nil {
+			return fmt.Errorf("party %s is in both the old and the new committee under the same key; "+
+				"a retained member must join the new committee under a new key, running one party per committee", Pk)
+		}
+	}
+	return nil
+}
+
 func (rgParams *ReSharingParameters) IsOldCommittee() bool {
 	partyID := rgParams.partyID
 	for _, Pj := range rgParams.parties.IDs() {
@@ -0,0 +1,186 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// parametersJSON is the serialised form of Parameters and ReSharingParameters. It holds the configuration that is
+// plain data; the context, callbacks, codec, ModExp backend, sender verifier, EdDSA and MtA hashes and random seed
+// are not serialised.
+type parametersJSON struct {
+	Curve                    CurveName
+	PartyID                  *PartyID
+	Parties                  []*PartyID
+	PartyCount               int
+	Threshold                int
+	SafePrimeGenTimeout      time.Duration
+	CompressKGCommitments    bool                `json:",omitempty"`
+	ConcurrentVerification   bool                `json:",omitempty"`
+	VerificationChunkSize    int                 `json:",omitempty"`
+	MaxGoroutines            int                 `json:",omitempty"`
+	SessionExpiry            int64               `json:",omitempty"` // Unix seconds
+	SessionNonce             []byte              `json:",omitempty"`
+	MaxDuration              time.Duration       `json:",omitempty"`
+	UnknownSenderPolicy      UnknownSenderPolicy `json:",omitempty"`
+	MaxRetries               *int                `json:",omitempty"` // absent in data saved before it was kept
+	VRFShareExport           bool                `json:",omitempty"`
+	UnsafeKGIgnoreH1H2Dupes  bool                `json:",omitempty"`
+	UnsafeDeterministicNonce bool                `json:",omitempty"`
+
+	// re-sharing only
+	NewParties          []*PartyID          `json:",omitempty"`
+	NewPartyCount       int                 `json:",omitempty"`
+	NewThreshold        int                 `json:",omitempty"`
+	RetainedPartyPolicy RetainedPartyPolicy `json:",omitempty"`
+}
+
+// MarshalJSON serialises the configuration of the session: its curve, party, committee, counts, threshold, timeouts,
+// session nonce, retry limit and options, so that the restored Parameters have the same SessionID. The context, observer, culprit handler, progress callback, codec, ModExp backend, sender verifier, EdDSA
+// and MtA hashes and random seed are not serialised and must be set again on the Parameters given by UnmarshalJSON. A
+// goroutine budget is kept as its size, so a limiter shared with other sessions is not. The curve must be one of those that
+// GetCurveByName knows.
+func (params *Parameters) MarshalJSON() ([]byte, error) {
+	aux, err := params.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON restores Parameters serialised by MarshalJSON. The party ID is the one of the committee with the same
+// key, so that it can be compared to the committee's IDs as those of Parameters made with NewParameters can.
+func (params *Parameters) UnmarshalJSON(payload []byte) error {
+	aux := new(parametersJSON)
+	if err := json.Unmarshal(payload, aux); err != nil {
+		return err
+	}
+	restored, err := aux.toParameters(aux.Parties)
+	if err != nil {
+		return err
+	}
+	*params = *restored
+	return nil
+}
+
+// MarshalJSON serialises the configuration of the re-sharing as Parameters.MarshalJSON does, with the new committee,
+// its party count and threshold and the retained party policy
+func (rgParams *ReSharingParameters) MarshalJSON() ([]byte, error) {
+	if rgParams.Parameters == nil {
+		return nil, errors.New("MarshalJSON: the re-sharing parameters are incomplete")
+	}
+	aux, err := rgParams.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	if rgParams.newParties != nil {
+		aux.NewParties = rgParams.newParties.IDs()
+	}
+	aux.NewPartyCount, aux.NewThreshold = rgParams.newPartyCount, rgParams.newThreshold
+	aux.RetainedPartyPolicy = rgParams.retainedPartyPolicy
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON restores ReSharingParameters serialised by MarshalJSON. The party ID is the one of the old or the new
+// committee with the same key.
+func (rgParams *ReSharingParameters) UnmarshalJSON(payload []byte) error {
+	aux := new(parametersJSON)
+	if err := json.Unmarshal(payload, aux); err != nil {
+		return err
+	}
+	restored, err := aux.toParameters(append(append([]*PartyID(nil), aux.Parties...), aux.NewParties...))
+	if err != nil {
+		return err
+	}
+	*rgParams = ReSharingParameters{
+		Parameters:          restored,
+		newParties:          NewPeerContext(aux.NewParties),
+		newPartyCount:       aux.NewPartyCount,
+		newThreshold:        aux.NewThreshold,
+		retainedPartyPolicy: aux.RetainedPartyPolicy,
+	}
+	return nil
+}
+
+func (params *Parameters) toJSON() (*parametersJSON, error) {
+	curve, ok := GetCurveName(params.EC())
+	if !ok {
+		return nil, errors.New("MarshalJSON: the curve of the parameters has no registered name")
+	}
+	aux := &parametersJSON{
+		Curve:                    curve,
+		PartyID:                  params.partyID,
+		PartyCount:               params.partyCount,
+		Threshold:                params.threshold,
+		SafePrimeGenTimeout:      params.safePrimeGenTimeout,
+		CompressKGCommitments:    params.compressKGCommitments,
+		ConcurrentVerification:   params.concurrentVerification,
+		VerificationChunkSize:    params.verificationChunkSize,
+		MaxGoroutines:            params.MaxGoroutines(),
+		MaxDuration:              params.maxDuration,
+		UnknownSenderPolicy:      params.unknownSenderPolicy,
+		VRFShareExport:           params.vrfShareExport,
+		UnsafeKGIgnoreH1H2Dupes:  params.unsafeKGIgnoreH1H2Dupes,
+		UnsafeDeterministicNonce: params.deterministicNonce,
+		SessionNonce:             params.sessionNonce,
+		MaxRetries:               &params.maxRetries,
+	}
+	if params.parties != nil {
+		aux.Parties = params.parties.IDs()
+	}
+	if !params.sessionExpiry.IsZero() {
+		aux.SessionExpiry = params.sessionExpiry.Unix()
+	}
+	return aux, nil
+}
+
+// toParameters restores the Parameters, taking the party ID from `members` by its key
+func (aux *parametersJSON) toParameters(members []*PartyID) (*Parameters, error) {
+	curve, ok := GetCurveByName(aux.Curve)
+	if !ok {
+		return nil, fmt.Errorf("UnmarshalJSON: unknown curve %q", aux.Curve)
+	}
+	if !aux.PartyID.ValidateBasic() {
+		return nil, errors.New("UnmarshalJSON: the party ID is missing")
+	}
+	partyID := SortedPartyIDs(members).FindByKey(aux.PartyID.KeyInt())
+	if partyID == nil {
+		return nil, fmt.Errorf("UnmarshalJSON: party %s is not a member of the committee", aux.PartyID)
+	}
+	params := &Parameters{
+		ec:                      curve,
+		partyID:                 partyID,
+		parties:                 NewPeerContext(aux.Parties),
+		partyCount:              aux.PartyCount,
+		threshold:               aux.Threshold,
+		safePrimeGenTimeout:     aux.SafePrimeGenTimeout,
+		compressKGCommitments:   aux.CompressKGCommitments,
+		concurrentVerification:  aux.ConcurrentVerification,
+		verificationChunkSize:   aux.VerificationChunkSize,
+		maxDuration:             aux.MaxDuration,
+		unknownSenderPolicy:     aux.UnknownSenderPolicy,
+		vrfShareExport:          aux.VRFShareExport,
+		unsafeKGIgnoreH1H2Dupes: aux.UnsafeKGIgnoreH1H2Dupes,
+		deterministicNonce:      aux.UnsafeDeterministicNonce,
+		sessionNonce:            aux.SessionNonce,
+		maxRetries:              defaultMaxRetries,
+	}
+	if aux.MaxRetries != nil {
+		params.maxRetries = *aux.MaxRetries
+	}
+	if 0 < aux.MaxGoroutines {
+		params.SetMaxGoroutines(aux.MaxGoroutines)
+	}
+	if aux.SessionExpiry != 0 {
+		params.sessionExpiry = time.Unix(aux.SessionExpiry, 0)
+	}
+	return params, nil
+}
@@ -0,0 +1,350 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestSessionID(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	msg := big.NewInt(42)
+
+	// every party builds its own Parameters from its own view of the same peer list
+	var want []byte
+	for _, pID := range pIDs {
+		params := NewParameters(NewPeerContext(copyAndSortPartyIDs(pIDs)), pID, len(pIDs), 2)
+		got := params.SessionID(msg)
+		assert.Len(t, got, 32)
+		if want == nil {
+			want = got
+		}
+		assert.Equal(t, want, got)
+	}
+
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2)
+	assert.NotEqual(t, want, params.SessionID(big.NewInt(43)), "a different message must give a different ID")
+	assert.NotEqual(t, want, params.SessionID(nil), "keygen must not share an ID with signing")
+
+	params = NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 3)
+	assert.NotEqual(t, want, params.SessionID(msg), "a different threshold must give a different ID")
+
+	params = NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2)
+	params.SetCurve(edwards.Edwards())
+	assert.NotEqual(t, want, params.SessionID(msg), "a different curve must give a different ID")
+
+	others := GenerateTestPartyIDs(5)
+	params = NewParameters(NewPeerContext(others), others[0], len(others), 2)
+	assert.NotEqual(t, want, params.SessionID(msg), "different parties must give a different ID")
+
+	params = NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2).With(WithMtAHash(sha256.New))
+	assert.NotEqual(t, want, params.SessionID(msg), "a different MtA hash must give a different ID")
+	params.SetMtAHash(sha512.New512_256)
+	assert.Equal(t, want, params.SessionID(msg), "setting the default MtA hash must keep the ID")
+
+	params = NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2).With(WithEdDSAHash(sha512.New512_256))
+	assert.NotEqual(t, want, params.SessionID(msg), "a different EdDSA hash must give a different ID")
+	params.SetEdDSAHash(sha512.New)
+	assert.Equal(t, want, params.SessionID(msg), "setting the default EdDSA hash must keep the ID")
+
+	params = NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2).With(WithSessionNonce([]byte("attempt 1")))
+	withNonce := params.SessionID(msg)
+	assert.NotEqual(t, want, withNonce, "a nonce must give a different ID")
+	params.SetSessionNonce([]byte("attempt 2"))
+	assert.NotEqual(t, withNonce, params.SessionID(msg), "a retry with a fresh nonce must give a different ID")
+	params.SetSessionNonce(nil)
+	assert.Equal(t, want, params.SessionID(msg), "clearing the nonce must keep the ID")
+}
+
+func TestReSharingSessionID(t *testing.T) {
+	oldPIDs, newPIDs := GenerateTestPartyIDs(4), GenerateTestPartyIDs(5)
+	oldCtx, newCtx := NewPeerContext(oldPIDs), NewPeerContext(newPIDs)
+
+	// a member of each committee derives the same ID
+	want := NewReSharingParameters(oldCtx, newCtx, oldPIDs[0], len(oldPIDs), 2, len(newPIDs), 3).ReSharingSessionID()
+	assert.Equal(t, want, NewReSharingParameters(oldCtx, newCtx, newPIDs[0], len(oldPIDs), 2, len(newPIDs), 3).ReSharingSessionID())
+
+	assert.NotEqual(t, want, NewReSharingParameters(oldCtx, newCtx, oldPIDs[0], len(oldPIDs), 2, len(newPIDs), 2).ReSharingSessionID(),
+		"a different new threshold must give a different ID")
+	others := GenerateTestPartyIDs(5)
+	assert.NotEqual(t, want, NewReSharingParameters(oldCtx, NewPeerContext(others), oldPIDs[0], len(oldPIDs), 2, len(others), 3).ReSharingSessionID(),
+		"a different new committee must give a different ID")
+	params := NewReSharingParameters(oldCtx, newCtx, oldPIDs[0], len(oldPIDs), 2, len(newPIDs), 3)
+	params.SetSessionNonce([]byte("retry"))
+	assert.NotEqual(t, want, params.ReSharingSessionID(), "a nonce must give a different ID")
+}
+
+func TestVerifyPeers(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(7)
+	self := pIDs[2]
+	for _, mode := range []struct {
+		concurrent bool
+		chunkSize  int
+	}{{false, 0}, {true, 0}, {true, 2}, {true, len(pIDs)}} {
+		concurrent, chunkSize := mode.concurrent, mode.chunkSize
+		t.Run(fmt.Sprintf("concurrent=%v,chunk=%d", concurrent, chunkSize), func(t *testing.T) {
+			params := NewParameters(NewPeerContext(pIDs), self, len(pIDs), 3)
+			params.SetConcurrentVerification(concurrent)
+			params.SetVerificationChunkSize(chunkSize)
+
+			var mtx sync.Mutex
+			visited := make(map[int]bool)
+			culprits, errs := params.VerifyPeers(func(j int, Pj *PartyID) error {
+				mtx.Lock()
+				visited[j] = true
+				mtx.Unlock()
+				assert.Equal(t, pIDs[j], Pj)
+				if j == 1 || j == 5 || j == 6 {
+					return errors.New(Pj.Id)
+				}
+				return nil
+			})
+			assert.Len(t, visited, len(pIDs)-1, "every peer must be checked")
+			assert.False(t, visited[self.Index], "a party must not check itself")
+			assert.Equal(t, []*PartyID{pIDs[1], pIDs[5], pIDs[6]}, culprits, "culprits must be in party order")
+			if assert.Len(t, errs, 3) {
+				for k, err := range errs {
+					assert.EqualError(t, err, culprits[k].Id, "each error must belong to its culprit")
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyPeersAbortsWhenQuorumLost(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(7)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 3)
+
+	assert.NoError(t, params.QuorumError(nil))
+	assert.NoError(t, params.QuorumError([]*PartyID{pIDs[1], pIDs[2], pIDs[3], pIDs[3]}), "3 culprits leave 4 honest parties")
+
+	// every peer fails; the 4th culprit leaves 3 honest parties where 4 are needed
+	checked := 0
+	culprits, errs := params.VerifyPeers(func(j int, Pj *PartyID) error {
+		checked++
+		return errors.New("bad proof")
+	})
+	assert.Equal(t, 4, checked, "no peer may be checked once the quorum is lost")
+	assert.Equal(t, []*PartyID{pIDs[1], pIDs[2], pIDs[3], pIDs[4]}, culprits)
+	assert.Len(t, errs, 4)
+	err := params.QuorumError(culprits)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, ErrQuorumLost))
+		assert.Contains(t, err.Error(), "leaving 3 honest parties where 4 are needed")
+	}
+
+	// concurrent checks report the same culprits, whichever order they finish in
+	params.SetConcurrentVerification(true)
+	params.SetVerificationChunkSize(1)
+	for i := 0; i < 10; i++ {
+		culprits, _ = params.VerifyPeers(func(j int, Pj *PartyID) error {
+			time.Sleep(time.Duration(len(pIDs)-j) * time.Millisecond)
+			return errors.New("bad proof")
+		})
+		assert.Equal(t, []*PartyID{pIDs[1], pIDs[2], pIDs[3], pIDs[4]}, culprits)
+		assert.Error(t, params.QuorumError(culprits))
+	}
+}
+
+func TestParametersWith(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 2)
+
+	clone := params.With(
+		WithThreshold(3),
+		WithSafePrimeGenTimeout(time.Minute),
+		WithConcurrentVerification(true),
+		WithCompressKGCommitments(true),
+		WithCurve(edwards.Edwards()),
+	)
+	assert.Equal(t, 3, clone.Threshold())
+	assert.Equal(t, time.Minute, clone.SafePrimeGenTimeout())
+	assert.True(t, clone.ConcurrentVerification())
+	assert.True(t, clone.CompressKGCommitments())
+	assert.Equal(t, edwards.Edwards(), clone.EC())
+	assert.Equal(t, params.PartyID(), clone.PartyID())
+	assert.Equal(t, params.PartyCount(), clone.PartyCount())
+	assert.Equal(t, params.Parties().IDs(), clone.Parties().IDs())
+
+	// the original is unaffected by the options or by later changes to the clone
+	assert.Equal(t, 2, params.Threshold())
+	assert.False(t, params.ConcurrentVerification())
+	assert.False(t, params.CompressKGCommitments())
+	assert.Equal(t, EC(), params.EC())
+	clone.SetCompressKGCommitments(false)
+	clone.Parties().SetIDs(pIDs[:3])
+	assert.True(t, params.With(WithCompressKGCommitments(true)).CompressKGCommitments())
+	assert.False(t, params.CompressKGCommitments())
+	assert.Len(t, params.Parties().IDs(), 5)
+
+	assert.Equal(t, params.SessionID(nil), params.With().SessionID(nil), "a copy without options is the same session")
+}
+
+func TestParametersWithContextDeadline(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(2)
+	p2pCtx := NewPeerContext(pIDs)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the earlier of the timeout and the deadline is taken
+	params := NewParametersWithContext(ctx, p2pCtx, pIDs[0], len(pIDs), 1)
+	assert.LessOrEqual(t, int64(params.SafePrimeGenTimeout()), int64(time.Minute))
+	assert.Greater(t, int64(params.SafePrimeGenTimeout()), int64(50*time.Second))
+	params = NewParametersWithContext(ctx, p2pCtx, pIDs[0], len(pIDs), 1, 10*time.Second)
+	assert.Equal(t, 10*time.Second, params.SafePrimeGenTimeout())
+	params = NewParametersWithContext(context.Background(), p2pCtx, pIDs[0], len(pIDs), 1, time.Hour)
+	assert.Equal(t, time.Hour, params.SafePrimeGenTimeout())
+
+	// a deadline that has passed leaves no time rather than a negative one
+	past, cancelPast := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelPast()
+	params = NewParametersWithContext(past, p2pCtx, pIDs[0], len(pIDs), 1)
+	assert.Equal(t, time.Duration(0), params.SafePrimeGenTimeout())
+}
+
+func TestParametersJSON(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(5)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[2], len(pIDs), 2, time.Minute).With(
+		WithCurve(edwards.Edwards()),
+		WithConcurrentVerification(true),
+		WithVerificationChunkSize(3),
+		WithSessionExpiry(time.Now().Add(time.Hour)),
+		WithMaxDuration(10*time.Minute),
+		WithUnknownSenderPolicy(UnknownSenderAbort),
+		WithMaxRetries(0),
+	)
+	params.SetMaxGoroutines(4)
+	params.SetSessionNonce([]byte("attempt 2"))
+	bz, err := json.Marshal(params)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	restored := new(Parameters)
+	if !assert.NoError(t, json.Unmarshal(bz, restored)) {
+		return
+	}
+	assert.Equal(t, params.SessionID(big.NewInt(42)), restored.SessionID(big.NewInt(42)), "it should be the same session")
+	assert.Equal(t, edwards.Edwards().Params(), restored.EC().Params())
+	assert.Equal(t, params.PartyCount(), restored.PartyCount())
+	assert.Equal(t, params.Threshold(), restored.Threshold())
+	assert.Equal(t, time.Minute, restored.SafePrimeGenTimeout())
+	assert.True(t, restored.ConcurrentVerification())
+	assert.Equal(t, 3, restored.VerificationChunkSize())
+	assert.Equal(t, 4, restored.MaxGoroutines())
+	assert.Equal(t, params.SessionExpiry().Unix(), restored.SessionExpiry().Unix())
+	assert.Equal(t, 10*time.Minute, restored.MaxDuration())
+	assert.Equal(t, UnknownSenderAbort, restored.UnknownSenderPolicy())
+	assert.Equal(t, []byte("attempt 2"), restored.SessionNonce())
+	assert.Equal(t, 0, restored.MaxRetries(), "disabled retries should stay disabled")
+	// Parameters saved before the retry limit was kept get the default
+	var raw map[string]json.RawMessage
+	if assert.NoError(t, json.Unmarshal(bz, &raw)) {
+		delete(raw, "MaxRetries")
+		legacy, err := json.Marshal(raw)
+		assert.NoError(t, err)
+		if assert.NoError(t, json.Unmarshal(legacy, restored)) {
+			assert.Equal(t, NewParameters(nil, nil, 1, 0).MaxRetries(), restored.MaxRetries())
+		}
+	}
+	if assert.Len(t, restored.Parties().IDs(), len(pIDs)) {
+		for j, pID := range restored.Parties().IDs() {
+			assert.Equal(t, pIDs[j].Index, pID.Index)
+			assert.Equal(t, pIDs[j].Key, pID.Key)
+			assert.Equal(t, pIDs[j].Moniker, pID.Moniker)
+		}
+	}
+	assert.True(t, restored.PartyID() == restored.Parties().IDs()[2], "the party ID should be the committee's")
+
+	// a curve that cannot be named cannot be restored
+	params.SetCurve(&elliptic.CurveParams{Name: "custom", P: big.NewInt(7), N: big.NewInt(5), Gx: big.NewInt(1), Gy: big.NewInt(1)})
+	_, err = json.Marshal(params)
+	assert.Error(t, err)
+}
+
+func TestReSharingParametersJSON(t *testing.T) {
+	oldPIDs := GenerateTestPartyIDs(3)
+	newPIDs := GenerateTestPartyIDs(4, len(oldPIDs))
+	params := NewReSharingParameters(NewPeerContext(oldPIDs), NewPeerContext(newPIDs), newPIDs[1], len(oldPIDs), 1, len(newPIDs), 2)
+	params.SetRetainedPartyPolicy(RetainedPartyRequireNewKey)
+	bz, err := json.Marshal(params)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	restored := new(ReSharingParameters)
+	if !assert.NoError(t, json.Unmarshal(bz, restored)) {
+		return
+	}
+	assert.Equal(t, len(oldPIDs), restored.OldPartyCount())
+	assert.Equal(t, 1, restored.Threshold())
+	assert.Equal(t, len(newPIDs), restored.NewPartyCount())
+	assert.Equal(t, 2, restored.NewThreshold())
+	assert.Equal(t, RetainedPartyRequireNewKey, restored.RetainedPartyPolicy())
+	assert.Equal(t, params.MaxRetries(), restored.MaxRetries(), "the default retry limit should be kept")
+	assert.Len(t, restored.OldParties().IDs(), len(oldPIDs))
+	assert.Len(t, restored.NewParties().IDs(), len(newPIDs))
+	assert.False(t, restored.IsOldCommittee())
+	assert.True(t, restored.IsNewCommittee())
+	assert.Equal(t, newPIDs[1].Index, restored.PartyID().Index)
+	assert.True(t, restored.IsCommitteeMember(restored.PartyID()))
+
+	// a party ID that is in neither committee is rejected
+	other := GenerateTestPartyIDs(1)[0]
+	bz, err = json.Marshal(NewReSharingParameters(NewPeerContext(oldPIDs), NewPeerContext(newPIDs), other, len(oldPIDs), 1, len(newPIDs), 2))
+	assert.NoError(t, err)
+	assert.Error(t, json.Unmarshal(bz, restored))
+}
+
+// copyAndSortPartyIDs copies the party IDs and sorts them afresh, as each party would on its own
+func copyAndSortPartyIDs(pIDs SortedPartyIDs) SortedPartyIDs {
+	unsorted := make(UnSortedPartyIDs, len(pIDs))
+	for i := len(pIDs) - 1; 0 <= i; i-- {
+		unsorted[len(pIDs)-1-i] = NewPartyID(pIDs[i].Id, pIDs[i].Moniker, new(big.Int).SetBytes(pIDs[i].Key))
+	}
+	return SortPartyIDs(unsorted)
+}
+
+// BenchmarkVerifyPeersChunkSize verifies 64 peers with a modular exponentiation each, as a proof check does, in chunks
+// of several sizes within a budget of one goroutine per core
+func BenchmarkVerifyPeersChunkSize(b *testing.B) {
+	pIDs := GenerateTestPartyIDs(65)
+	mod := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 1024), big.NewInt(105))
+	base, exp := new(big.Int).Rsh(mod, 3), new(big.Int).Rsh(mod, 7)
+	for _, chunkSize := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("chunk=%d", chunkSize), func(b *testing.B) {
+			params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), len(pIDs)/2).With(
+				WithConcurrentVerification(true),
+				WithVerificationChunkSize(chunkSize),
+			)
+			params.SetMaxGoroutines(runtime.NumCPU())
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				params.VerifyPeers(func(j int, Pj *PartyID) error {
+					new(big.Int).Exp(base, exp, mod)
+					return nil
+				})
+			}
+		})
+	}
+}
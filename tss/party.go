@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/ordinox/thorchain-tss-lib/common"
 )
@@ -33,17 +34,29 @@ type Party interface {
 	// Private lifecycle methods
 	setRound(Round) *Error
 	round() Round
+	roundStarted() time.Time
+	started() time.Time
 	advance()
+	retreat(Round)
+	retries() int
 	lock()
 	unlock()
 }
 
 type BaseParty struct {
-	mtx        sync.Mutex
-	rnd        Round
+	mtx      sync.Mutex
+	rnd      Round
+	rndStart time.Time
+	start    time.Time
+	// the number of rounds retried with RetryRound
+	retried    int
 	FirstRound Round
 }
 
+// ErrSessionTimeout is the cause of the error that a party returns once it has run longer than the maximum duration
+// set with Parameters.SetMaxDuration
+var ErrSessionTimeout = errors.New("the session exceeded its maximum duration")
+
 func (p *BaseParty) Running() bool {
 	return p.rnd != nil
 }
@@ -94,6 +107,8 @@ func (p *BaseParty) setRound(round Round) *Error {
 		return p.WrapError(errors.New("a round is already set on this party"))
 	}
 	p.rnd = round
+	p.rndStart = time.Now()
+	p.start = p.rndStart
 	return nil
 }
 
@@ -101,8 +116,27 @@ func (p *BaseParty) round() Round {
 	return p.rnd
 }
 
+func (p *BaseParty) roundStarted() time.Time {
+	return p.rndStart
+}
+
+func (p *BaseParty) started() time.Time {
+	return p.start
+}
+
 func (p *BaseParty) advance() {
 	p.rnd = p.rnd.NextRound()
+	p.rndStart = time.Now()
+}
+
+func (p *BaseParty) retreat(round Round) {
+	p.rnd = round
+	p.rndStart = time.Now()
+	p.retried++
+}
+
+func (p *BaseParty) retries() int {
+	return p.retried
 }
 
 func (p *BaseParty) lock() {
@@ -133,34 +167,51 @@ func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 	}
 	if len(prepare) == 1 {
 		if err := prepare[0](round); err != nil {
-			return err
+			return failed(p, err)
 		}
 	}
+	if err := cancelled(p); err != nil {
+		return failed(p, err)
+	}
 	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
 	defer func() {
 		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
 	}()
-	return p.round().Start()
+	return failed(p, p.round().Start())
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
-	// fast-fail on an invalid message; do not lock the mutex yet
-	if _, err := p.ValidateMessage(msg); err != nil {
-		return false, err
-	}
 	// lock the mutex. need this mtx unlock hook; L108 is recursive so cannot use defer
 	r := func(ok bool, err *Error) (bool, *Error) {
+		err = failed(p, err)
 		p.unlock()
 		return ok, err
 	}
 	p.lock() // data is written to P state below
+	if err := cancelled(p); err != nil {
+		return r(false, err)
+	}
+	if err := timedOut(p); err != nil {
+		return r(false, err)
+	}
+	// the sender is checked before the message is validated, so that a malformed message from outside the committee is
+	// treated as the UnknownSenderPolicy says rather than failing the party
+	if msg != nil {
+		if known, err := checkSender(p, msg); !known {
+			return r(false, err)
+		}
+	}
+	// fast-fail on an invalid message
+	if _, err := p.ValidateMessage(msg); err != nil {
+		return r(false, withFault(err, FaultInvalidMessage))
+	}
 	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
 	if p.round() != nil {
 		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
 	}
 	if ok, err := p.StoreMessage(msg); err != nil || !ok {
-		return r(false, err)
+		return r(false, withFault(err, FaultInvalidMessage))
 	}
 	if p.round() != nil {
 		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
@@ -168,7 +219,13 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 			return r(false, err)
 		}
 		if p.round().CanProceed() {
+			if o := observerOf(p); o != nil {
+				o.RoundFinished(task, p.round().RoundNumber(), time.Since(p.roundStarted()))
+			}
 			if p.advance(); p.round() != nil {
+				if err := cancelled(p); err != nil {
+					return r(false, err)
+				}
 				if err := p.round().Start(); err != nil {
 					return r(false, err)
 				}
@@ -185,3 +242,217 @@ func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
 	}
 	return r(true, nil)
 }
+
+// CheckDeadline returns an ErrSessionTimeout if the party has run longer than its maximum duration, naming the round
+// it is in and the parties it is waiting for, or an error wrapping that of the context of its parameters once that is
+// done. A party only checks its deadline when it receives a message, so call this from a timer to end a session in
+// which the messages have stopped.
+func CheckDeadline(p Party) *Error {
+	p.lock()
+	defer p.unlock()
+	if err := cancelled(p); err != nil {
+		return failed(p, err)
+	}
+	return failed(p, timedOut(p))
+}
+
+// cancelled returns an error wrapping the error of the context of the party's parameters once that is done
+func cancelled(p Party) *Error {
+	rnd := p.round()
+	if rnd == nil {
+		return nil
+	}
+	if err := rnd.Params().Context().Err(); err != nil {
+		return rnd.WrapError(fmt.Errorf("the session was abandoned in round %d: %w", rnd.RoundNumber(), err))
+	}
+	return nil
+}
+
+// timedOut returns an ErrSessionTimeout if the running party has run longer than its maximum duration
+func timedOut(p Party) *Error {
+	rnd := p.round()
+	if rnd == nil || rnd.Params().MaxDuration() <= 0 {
+		return nil
+	}
+	elapsed := time.Since(p.started())
+	if elapsed <= rnd.Params().MaxDuration() {
+		return nil
+	}
+	waitingFor := rnd.WaitingFor()
+	err := rnd.WrapError(fmt.Errorf("%w after %s in round %d, waiting for %v", ErrSessionTimeout, elapsed.Round(time.Millisecond), rnd.RoundNumber(), waitingFor), waitingFor...)
+	return withFault(err, FaultTimeout)
+}
+
+// checkSender reports whether the sender of msg is a member of the party's committee. A message from an outsider is
+// dropped, with an error if the policy of the party's parameters is UnknownSenderAbort.
+func checkSender(p Party, msg ParsedMessage) (bool, *Error) {
+	rnd := p.round()
+	if rnd == nil {
+		return true, nil
+	}
+	var committee interface{ IsCommitteeMember(*PartyID) bool } = rnd.Params()
+	if rs, ok := rnd.(interface{ ReSharingParams() *ReSharingParameters }); ok {
+		committee = rs.ReSharingParams()
+	}
+	if committee.IsCommitteeMember(msg.GetFrom()) {
+		return authenticSender(p, msg)
+	}
+	switch rnd.Params().UnknownSenderPolicy() {
+	case UnknownSenderDrop:
+	case UnknownSenderAbort:
+		err := p.WrapError(fmt.Errorf("received a message from %s, who is not a member of the committee: %s", msg.GetFrom(), msg), msg.GetFrom())
+		return false, withFault(err, FaultInvalidMessage)
+	default:
+		common.Logger.Warnf("party %s: dropped a message from %s, who is not a member of the committee: %s", p.PartyID(), msg.GetFrom(), msg)
+	}
+	return false, nil
+}
+
+// authenticSender rejects a message from a member that the SenderVerifier, if one was set, did not authenticate as sent
+// by that member. The error names no culprit: the claimed sender is the party impersonated, not the one at fault.
+func authenticSender(p Party, msg ParsedMessage) (bool, *Error) {
+	verifier := p.round().Params().SenderVerifier()
+	if verifier == nil {
+		return true, nil
+	}
+	if err := verifier(msg); err != nil {
+		err = fmt.Errorf("the message claiming to be from %s failed authentication: %w", msg.GetFrom(), err)
+		return false, withFault(p.WrapError(err), FaultImpersonation)
+	}
+	return true, nil
+}
+
+// failed reports a non-nil error to the party's observer and, unless RetryRound may still retry it, its culprits to
+// the culprit handler, if they were set, and returns it
+func failed(p Party, err *Error) *Error {
+	if err != nil {
+		if o := observerOf(p); o != nil {
+			o.Failed(err)
+		}
+		if rnd := p.round(); rnd != nil && canRetry(p, rnd, err) != nil {
+			reportCulprits(rnd.Params(), err)
+		}
+	}
+	return err
+}
+
+// reportCulprits reports the culprits of a final error to the culprit handler, if one was set and they were not
+// reported before
+func reportCulprits(params *Parameters, err *Error) {
+	if err.reported {
+		return
+	}
+	err.reported = true
+	if handler := params.CulpritHandler(); handler != nil {
+		for _, event := range CulpritEvents(err) {
+			handler(event)
+		}
+	}
+}
+
+// ErrNotRetryable is the cause of the error that RetryRound returns when the failure cannot be retried
+var ErrNotRetryable = errors.New("the round cannot be retried")
+
+// IsTransient reports whether `err` may not be the fault of its culprits, so that the round may be retried with new
+// messages from them. Only a failure with culprits whose messages a SenderVerifier vouched for as theirs is transient:
+// a retry then asks those parties to resend, which a member whose message was garbled, e.g. by its own transport, can
+// do. Without one, any peer can fail a round in the name of a member, again and again, so no failure is transient.
+// A timeout is never transient, as the session is over, and neither is a self-caused failure.
+func IsTransient(params *Parameters, err *Error) bool {
+	if err == nil || len(err.Culprits()) == 0 || err.SelfCaused() || err.FaultType() == FaultTimeout {
+		return false
+	}
+	return params.SenderVerifier() != nil
+}
+
+// canRetry returns nil if RetryRound can retry the failure `err` of the party in its current round `rnd`, or an error
+// wrapping ErrNotRetryable that says why not
+func canRetry(p Party, rnd Round, err *Error) error {
+	if err == nil || err.Round() != rnd.RoundNumber() {
+		return fmt.Errorf("%w: the error is not a failure of the current round", ErrNotRetryable)
+	}
+	if !IsTransient(rnd.Params(), err) {
+		return fmt.Errorf("%w: the failure is not transient: %v", ErrNotRetryable, err)
+	}
+	if maxRetries := rnd.Params().MaxRetries(); maxRetries <= p.retries() {
+		return fmt.Errorf("%w: the party has used up its %d retries", ErrNotRetryable, maxRetries)
+	}
+	if _, ok := rnd.(RetryableRound); !ok {
+		return fmt.Errorf("%w: round %d does not support retries", ErrNotRetryable, rnd.RoundNumber())
+	}
+	return nil
+}
+
+// RetryRound recovers a party from a transient failure, as reported by IsTransient, in its current round. The party
+// goes back to the previous round, dropping the messages of the culprits of `err` in it, and proceeds again once new
+// ones from them have been received; the state of the rounds before is kept, and no round is started again until
+// then. It returns an error wrapping ErrNotRetryable unless the current round implements RetryableRound and can be
+// retried, and the party has not used up the retries of Parameters.MaxRetries. The application must have the
+// culprits' messages sent again, e.g. by its transport resending them. The culprits of a failure that may be retried
+// are reported to the CulpritHandler only once RetryRound refuses it.
+func RetryRound(p Party, err *Error) *Error {
+	p.lock()
+	defer p.unlock()
+	rnd := p.round()
+	if rnd == nil {
+		return p.WrapError(fmt.Errorf("%w: the party is not running", ErrNotRetryable))
+	}
+	if cause := canRetry(p, rnd, err); cause != nil {
+		if err != nil {
+			// the failure is final; failed may have deferred its report
+			reportCulprits(rnd.Params(), err)
+		}
+		return p.WrapError(cause)
+	}
+	prev := rnd.(RetryableRound).Retry(err.Culprits())
+	if prev == nil {
+		reportCulprits(rnd.Params(), err)
+		return p.WrapError(fmt.Errorf("%w: round %d cannot be retried in its state", ErrNotRetryable, rnd.RoundNumber()))
+	}
+	p.retreat(prev)
+	common.Logger.Infof("party %s: retrying round %d, waiting for %v", p.PartyID(), prev.RoundNumber(), prev.WaitingFor())
+	return nil
+}
+
+// BaseSnapshot calls snapshot with the party's current round while holding the party's lock, so that the state it
+// reads is not changed by a concurrent Update. The round is nil if the party has not started or has finished.
+func BaseSnapshot(p Party, snapshot func(Round) error) *Error {
+	p.lock()
+	defer p.unlock()
+	if err := snapshot(p.round()); err != nil {
+		return p.WrapError(err)
+	}
+	return nil
+}
+
+// BaseResume puts a party that was restored from a snapshot into `round`, which must already hold the state and
+// messages of the snapshot and be marked as started. The round is not started again; it is updated with the messages
+// it holds and the party proceeds from there, as it would in Update.
+func BaseResume(p Party, task string, round Round) *Error {
+	p.lock()
+	defer p.unlock()
+	if err := p.setRound(round); err != nil {
+		return err
+	}
+	common.Logger.Infof("party %s: %s resuming in round %d", round.Params().PartyID(), task, round.RoundNumber())
+	for p.round() != nil {
+		if _, err := p.round().Update(); err != nil {
+			return failed(p, err)
+		}
+		if !p.round().CanProceed() {
+			return nil
+		}
+		if o := observerOf(p); o != nil {
+			o.RoundFinished(task, p.round().RoundNumber(), time.Since(p.roundStarted()))
+		}
+		if p.advance(); p.round() != nil {
+			if err := p.round().Start(); err != nil {
+				return failed(p, err)
+			}
+			common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, p.round().RoundNumber())
+		} else {
+			common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestUnknownSenderPolicy(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	// an outsider that claims the index of a member
+	outsider := NewPartyID("outsider", "outsider", big.NewInt(424242))
+	outsider.Index = pIDs[1].Index
+
+	start := func(policy UnknownSenderPolicy, handler CulpritHandler) Party {
+		params := NewParameters(p2pCtx, pIDs[0], len(pIDs), 1).With(WithUnknownSenderPolicy(policy))
+		params.SetCurve(edwards.Edwards())
+		params.SetCulpritHandler(handler)
+		P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+		if err := P.Start(); !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		return P
+	}
+	assert.Equal(t, UnknownSenderLogAndDrop, NewParameters(p2pCtx, pIDs[0], len(pIDs), 1).UnknownSenderPolicy(), "the default should tolerate noise")
+
+	// an outsider with an index beyond the committee
+	outOfRange := NewPartyID("out-of-range", "out-of-range", big.NewInt(434343))
+	outOfRange.Index = len(pIDs)
+
+	for _, policy := range []UnknownSenderPolicy{UnknownSenderLogAndDrop, UnknownSenderDrop} {
+		var reported []CulpritEvent
+		P := start(policy, func(event CulpritEvent) { reported = append(reported, event) })
+		for _, msg := range []ParsedMessage{
+			keygen.NewKGRound1Message(outsider, big.NewInt(1)),
+			keygen.NewKGRound1Message(outOfRange, big.NewInt(1)),
+			// malformed content, which fails ValidateBasic
+			keygen.NewKGRound1Message(outsider, big.NewInt(0)),
+		} {
+			ok, err := P.Update(msg)
+			assert.False(t, ok, "the message should be dropped")
+			assert.Nil(t, err, "the party should not abort")
+		}
+		assert.Empty(t, reported)
+		assert.Contains(t, P.WaitingFor(), pIDs[1], "the member whose index was claimed should still be waited for")
+	}
+
+	var reported []CulpritEvent
+	P := start(UnknownSenderAbort, func(event CulpritEvent) { reported = append(reported, event) })
+	ok, err := P.Update(keygen.NewKGRound1Message(outsider, big.NewInt(1)))
+	assert.False(t, ok)
+	if assert.NotNil(t, err, "the party should abort") {
+		assert.Equal(t, []*PartyID{outsider}, err.Culprits())
+		assert.Equal(t, FaultInvalidMessage, err.FaultType())
+	}
+	if assert.Len(t, reported, 1) {
+		assert.Equal(t, outsider, reported[0].Culprit)
+	}
+	for _, msg := range []ParsedMessage{
+		keygen.NewKGRound1Message(outOfRange, big.NewInt(1)),
+		keygen.NewKGRound1Message(outsider, big.NewInt(0)),
+	} {
+		Q := start(UnknownSenderAbort, nil)
+		_, err := Q.Update(msg)
+		if assert.NotNil(t, err, "the party should abort") {
+			assert.Equal(t, []*PartyID{msg.GetFrom()}, err.Culprits())
+			assert.Contains(t, err.Error(), "not a member of the committee")
+		}
+	}
+
+	// a member is accepted under every policy
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestInvalidMessageFailsUnderLock(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	params.SetCurve(edwards.Edwards())
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+	// an invalid message fails the party only once it is not busy, so that the failure does not race with its round
+	type result struct {
+		err      *Error
+		released bool
+	}
+	var released int32
+	sending, done := make(chan struct{}), make(chan result, 1)
+	err := BaseSnapshot(P, func(Round) error {
+		go func() {
+			close(sending)
+			_, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(0)))
+			done <- result{err, atomic.LoadInt32(&released) == 1}
+		}()
+		<-sending
+		runtime.Gosched()
+		atomic.StoreInt32(&released, 1)
+		return nil
+	})
+	assert.Nil(t, err)
+	res := <-done
+	assert.True(t, res.released, "the invalid message failed the party while it was locked")
+	if assert.NotNil(t, res.err) {
+		assert.Equal(t, FaultInvalidMessage, res.err.FaultType())
+		assert.Equal(t, []*PartyID{pIDs[1]}, res.err.Culprits())
+	}
+}
+
+func TestMaxDuration(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	var reported []CulpritEvent
+	params := NewParameters(p2pCtx, pIDs[0], len(pIDs), 1).With(WithMaxDuration(time.Hour))
+	params.SetCurve(edwards.Edwards())
+	params.SetCulpritHandler(func(event CulpritEvent) { reported = append(reported, event) })
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+
+	// within the deadline a slow peer is waited for
+	assert.Nil(t, CheckDeadline(P))
+	ok, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// the last peer is too slow, as the deadline is brought forward rather than waited for
+	params.SetMaxDuration(time.Nanosecond)
+	err = CheckDeadline(P)
+	if assert.NotNil(t, err, "the deadline should have passed") {
+		assert.True(t, errors.Is(err, ErrSessionTimeout))
+		assert.Equal(t, 1, err.Round())
+		assert.Equal(t, []*PartyID{pIDs[2]}, err.Culprits(), "the missing party should be named")
+		assert.Equal(t, FaultTimeout, err.FaultType())
+	}
+	if assert.Len(t, reported, 1) {
+		assert.Equal(t, pIDs[2], reported[0].Culprit)
+	}
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[2], big.NewInt(1)))
+	assert.False(t, ok)
+	if assert.NotNil(t, err, "a message after the deadline should fail the update") {
+		assert.True(t, errors.Is(err, ErrSessionTimeout))
+	}
+}
+
+func TestParametersWithContext(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	assert.Equal(t, context.Background(), NewParameters(p2pCtx, pIDs[0], len(pIDs), 1).Context())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params := NewParametersWithContext(ctx, p2pCtx, pIDs[0], len(pIDs), 1, time.Minute)
+	assert.Equal(t, time.Minute, params.SafePrimeGenTimeout(), "the timeout should still be taken")
+	params.SetCurve(edwards.Edwards())
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+	ok, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	// the coordinator abandons the session
+	cancel()
+	err = CheckDeadline(P)
+	if assert.NotNil(t, err) {
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, 1, err.Round())
+		assert.Empty(t, err.Culprits(), "no peer is to blame")
+	}
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[2], big.NewInt(1)))
+	assert.False(t, ok)
+	if assert.NotNil(t, err, "a message after the cancellation should fail the update") {
+		assert.True(t, errors.Is(err, context.Canceled))
+	}
+
+	// a party of a session abandoned before it started does not start
+	P = keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); assert.NotNil(t, err) {
+		assert.True(t, errors.Is(err, context.Canceled))
+	}
+}
+
+func TestSenderVerifierRejectsImpersonation(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	p2pCtx := NewPeerContext(pIDs)
+	// the transport authenticated each message as sent by the party in `authenticated`
+	errNotAuthenticated := errors.New("the channel is not the claimed sender's")
+	var authenticated *PartyID
+	verifier := func(msg ParsedMessage) error {
+		if msg.GetFrom().KeyInt().Cmp(authenticated.KeyInt()) != 0 {
+			return errNotAuthenticated
+		}
+		return nil
+	}
+	var reported []CulpritEvent
+	params := NewParameters(p2pCtx, pIDs[0], len(pIDs), 1).With(WithSenderVerifier(verifier))
+	params.SetCurve(edwards.Edwards())
+	params.SetCulpritHandler(func(event CulpritEvent) { reported = append(reported, event) })
+	P := keygen.NewLocalParty(params, make(chan Message, len(pIDs)), nil)
+	if err := P.Start(); !assert.Nil(t, err) {
+		return
+	}
+
+	// party 2 sends a message that claims the PartyID of party 1
+	authenticated = pIDs[2]
+	ok, err := P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(1)))
+	assert.False(t, ok)
+	if assert.NotNil(t, err, "the impersonation should be rejected") {
+		assert.True(t, errors.Is(err, errNotAuthenticated))
+		assert.Equal(t, FaultImpersonation, err.FaultType())
+		assert.Empty(t, err.Culprits(), "the impersonated party should not be blamed")
+	}
+	assert.Empty(t, reported)
+	assert.Contains(t, P.WaitingFor(), pIDs[1], "the impersonated party should still be waited for")
+
+	// the genuine message of party 1 is accepted
+	authenticated = pIDs[1]
+	ok, err = P.Update(keygen.NewKGRound1Message(pIDs[1], big.NewInt(1)))
+	assert.True(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestIsTransient(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(3)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+	cause := errors.New("a proof failed to verify")
+
+	// unless senders are authenticated, anyone could fail a round in the name of a member
+	assert.False(t, IsTransient(params, NewError(cause, "task", 2, pIDs[0], pIDs[1])), "a failure of an unauthenticated sender is not transient")
+
+	params.SetSenderVerifier(func(ParsedMessage) error { return nil })
+	assert.True(t, IsTransient(params, NewError(cause, "task", 2, pIDs[0], pIDs[1])))
+	assert.False(t, IsTransient(params, NewError(cause, "task", 2, pIDs[0])), "a failure with no culprits is not transient")
+	assert.False(t, IsTransient(params, NewError(cause, "task", 2, pIDs[0], pIDs[0])), "a self-caused failure is not transient")
+	timeout := NewError(NewFaultError(FaultTimeout, cause), "task", 2, pIDs[0], pIDs[1])
+	assert.False(t, IsTransient(params, timeout), "a timeout is not transient")
+}
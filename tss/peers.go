@@ -6,12 +6,34 @@
 
 package tss
 
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
 type (
 	PeerContext struct {
 		partyIDs SortedPartyIDs
 	}
+
+	// ValidatorOrdering orders the public keys of a validator set to give the order of the parties of a committee.
+	// It must be deterministic and depend only on the keys, not on their input order, so that every node derives the
+	// same committee; it returns a permutation of its input.
+	ValidatorOrdering func(pubKeys [][]byte) [][]byte
 )
 
+// OrderByKey orders a validator set by the public keys as big-endian integers, as SortPartyIDs orders parties
+func OrderByKey(pubKeys [][]byte) [][]byte {
+	ordered := append([][]byte(nil), pubKeys...)
+	sort.Slice(ordered, func(a, b int) bool {
+		return new(big.Int).SetBytes(ordered[a]).Cmp(new(big.Int).SetBytes(ordered[b])) < 0
+	})
+	return ordered
+}
+
 func NewPeerContext(parties SortedPartyIDs) *PeerContext {
 	return &PeerContext{partyIDs: parties}
 }
@@ -23,3 +45,69 @@ func (p2pCtx *PeerContext) IDs() SortedPartyIDs {
 func (p2pCtx *PeerContext) SetIDs(ids SortedPartyIDs) {
 	p2pCtx.partyIDs = ids
 }
+
+// isMemberOf reports whether Pj is in the context under the same key and index
+func isMemberOf(p2pCtx *PeerContext, Pj *PartyID) bool {
+	if p2pCtx == nil || Pj == nil {
+		return false
+	}
+	for _, pid := range p2pCtx.partyIDs {
+		if pid.Index == Pj.Index && pid.KeyInt().Cmp(Pj.KeyInt()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPeerContextFromValidators derives the parties of a committee from the public keys of a validator set, in the
+// order given by `ordering` (OrderByKey if nil). Each party's key is its public key as an integer and its id and
+// moniker are the hex encoding of the public key; a node finds its own PartyID with FindByKey.
+// Nodes given the same validator set in any order build identical contexts.
+func NewPeerContextFromValidators(pubKeys [][]byte, ordering ValidatorOrdering) (*PeerContext, error) {
+	if len(pubKeys) == 0 {
+		return nil, errors.New("the validator set is empty")
+	}
+	if ordering == nil {
+		ordering = OrderByKey
+	}
+	seen := make(map[string]bool, len(pubKeys))
+	for _, pk := range pubKeys {
+		key := new(big.Int).SetBytes(pk)
+		if key.Sign() == 0 {
+			return nil, errors.New("the validator set holds an empty or zero public key")
+		}
+		if seen[key.String()] {
+			return nil, fmt.Errorf("the validator set holds the public key %s more than once", hex.EncodeToString(pk))
+		}
+		seen[key.String()] = true
+	}
+	ordered := ordering(append([][]byte(nil), pubKeys...))
+	if !isPermutation(ordered, pubKeys) {
+		return nil, errors.New("the validator ordering did not return a permutation of the validator set")
+	}
+	ids := make(SortedPartyIDs, len(ordered))
+	for i, pk := range ordered {
+		id := hex.EncodeToString(pk)
+		ids[i] = NewPartyID(id, id, new(big.Int).SetBytes(pk))
+		ids[i].Index = i
+	}
+	return NewPeerContext(ids), nil
+}
+
+// isPermutation reports whether `a` holds the same byte strings as `b`, which holds no duplicates
+func isPermutation(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	left := make(map[string]bool, len(b))
+	for _, pk := range b {
+		left[string(pk)] = true
+	}
+	for _, pk := range a {
+		if !left[string(pk)] {
+			return false
+		}
+		delete(left, string(pk))
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+func TestNewPeerContextFromValidators(t *testing.T) {
+	pubKeys := make([][]byte, 7)
+	for i := range pubKeys {
+		sk, err := btcec.NewPrivateKey()
+		if !assert.NoError(t, err) {
+			return
+		}
+		pubKeys[i] = sk.PubKey().SerializeCompressed()
+	}
+	shuffled := append([][]byte(nil), pubKeys...)
+	for i, j := 0, len(shuffled)-1; i < j; i, j = i+1, j-1 {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	shuffled[0], shuffled[3] = shuffled[3], shuffled[0]
+
+	// an ordering by the hash of each key, as a chain might derive from its validator set
+	byHash := func(pks [][]byte) [][]byte {
+		sort.Slice(pks, func(a, b int) bool {
+			ha, hb := sha256.Sum256(pks[a]), sha256.Sum256(pks[b])
+			return bytes.Compare(ha[:], hb[:]) < 0
+		})
+		return pks
+	}
+	for name, ordering := range map[string]ValidatorOrdering{"default": nil, "by hash": byHash} {
+		t.Run(name, func(t *testing.T) {
+			node1, err := NewPeerContextFromValidators(pubKeys, ordering)
+			if !assert.NoError(t, err) {
+				return
+			}
+			node2, err := NewPeerContextFromValidators(shuffled, ordering)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, node1.IDs(), node2.IDs(), "both nodes must build the same context")
+			for i, pID := range node1.IDs() {
+				assert.Equal(t, i, pID.Index)
+			}
+		})
+	}
+	ctx, err := NewPeerContextFromValidators(shuffled, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, SortPartyIDs(ctx.IDs().ToUnSorted()), ctx.IDs(), "the default ordering is that of SortPartyIDs")
+	}
+
+	_, err = NewPeerContextFromValidators(append(pubKeys, pubKeys[2]), nil)
+	assert.Error(t, err, "a repeated validator must be rejected")
+	_, err = NewPeerContextFromValidators(pubKeys, func(pks [][]byte) [][]byte { return pks[1:] })
+	assert.Error(t, err, "an ordering that drops a validator must be rejected")
+}
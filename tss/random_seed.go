@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build tssdebug
+// +build tssdebug
+
+package tss
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+)
+
+const (
+	// RandomSeedLen is the length of a seed given to UNSAFE_SetRandomSeed
+	RandomSeedLen    = 32
+	randomSeedDomain = "tss-lib seeded randomness"
+)
+
+// RandReader returns the source of the randomness that an ECDSA signing party draws in the session with the given ID:
+// crypto/rand unless a seed was set with UNSAFE_SetRandomSeed
+func (params *Parameters) RandReader(sessionID []byte) io.Reader {
+	if params.randomSeed == nil {
+		return rand.Reader
+	}
+	var key []byte
+	if params.partyID != nil {
+		key = params.partyID.GetKey()
+	}
+	return &seededReader{key: common.SHA512_256([]byte(randomSeedDomain), params.randomSeed, key, sessionID)}
+}
+
+// UNSAFE_SetRandomSeed makes an ECDSA signing party draw the randomness of its round 1, its nonce share k_i, gamma_i,
+// the randomness of its commitment and its MtA encryption and range proofs, from a stream derived from the
+// RandomSeedLen-byte `seed`, its PartyID and the session ID, so that a session can be replayed to reproduce test
+// vectors or debug a failed signing. A nil seed restores crypto/rand. It is only built with the tssdebug build tag.
+//
+// ⚠️ UNSAFE: the seed determines k_i, from which anyone who knows it solves for the party's key share given a
+// signature, and a co-signer who makes two sessions with the same ID run differently learns the share as well. Only
+// use it in tests and debugging, never with keys that hold value.
+func (params *Parameters) UNSAFE_SetRandomSeed(seed []byte) {
+	if seed == nil {
+		params.randomSeed = nil
+		return
+	}
+	if len(seed) != RandomSeedLen {
+		panic(fmt.Errorf("UNSAFE_SetRandomSeed: expected a seed of %d bytes", RandomSeedLen))
+	}
+	common.Logger.Warn("UNSAFE_SetRandomSeed() has been called; do not sign with these keys in production.")
+	params.randomSeed = append([]byte(nil), seed...)
+}
+
+// seededReader is a deterministic stream of SHA-512(key || counter) blocks
+type seededReader struct {
+	key     []byte
+	counter uint64
+	buf     []byte
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(r.buf) == 0 {
+			block := sha512.New()
+			_, _ = block.Write(r.key)
+			_ = binary.Write(block, binary.BigEndian, r.counter)
+			r.buf = block.Sum(nil)
+			r.counter++
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return len(p), nil
+}
@@ -0,0 +1,21 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+//go:build !tssdebug
+// +build !tssdebug
+
+package tss
+
+import (
+	"crypto/rand"
+	"io"
+)
+
+// RandReader returns the source of the randomness that an ECDSA signing party draws in the session with the given ID:
+// always crypto/rand, as UNSAFE_SetRandomSeed is only built with the tssdebug build tag
+func (params *Parameters) RandReader(sessionID []byte) io.Reader {
+	return rand.Reader
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DiffCommittees matches the members of two committees by their Id, which a member keeps when it moves to the new
+// committee under a new key. It returns the members of the old committee that are retained in the new one, the
+// members of the old committee that are removed and the members of the new committee that are added.
+func DiffCommittees(oldIDs, newIDs SortedPartyIDs) (retained, removed, added SortedPartyIDs) {
+	inNew := make(map[string]bool, len(newIDs))
+	for _, Pj := range newIDs {
+		inNew[Pj.GetId()] = true
+	}
+	inOld := make(map[string]bool, len(oldIDs))
+	for _, Pj := range oldIDs {
+		inOld[Pj.GetId()] = true
+		if inNew[Pj.GetId()] {
+			retained = append(retained, Pj)
+		} else {
+			removed = append(removed, Pj)
+		}
+	}
+	for _, Pj := range newIDs {
+		if !inOld[Pj.GetId()] {
+			added = append(added, Pj)
+		}
+	}
+	return
+}
+
+// PlanReshare builds the ReSharingParameters of `partyID` for moving a key shared by the old committee under
+// `oldThreshold` to the new committee `newCtx` under `newThreshold`. `oldCtx` holds the members of the old committee
+// that are available to take part; it checks that at least oldThreshold+1 of them are and that the new committee can
+// meet its threshold. Only oldThreshold+1 of the available members take part, preferring those retained in the new
+// committee, as they run a party anyway; the choice depends only on the committees, so every node makes the same one.
+// The old committee of the plan holds copies of the chosen members, indexed within it.
+// A node that is in neither committee of the plan gets an error, as does a member that is in both under one key: the
+// plan requires a new key of a retained member (see RetainedPartyRequireNewKey).
+func PlanReshare(oldCtx, newCtx *PeerContext, oldThreshold, newThreshold int, partyID *PartyID) (*ReSharingParameters, error) {
+	if oldCtx == nil || newCtx == nil || partyID == nil {
+		return nil, errors.New("PlanReshare() received a nil argument")
+	}
+	oldIDs, newIDs := oldCtx.IDs(), newCtx.IDs()
+	if oldThreshold < 1 || newThreshold < 1 {
+		return nil, fmt.Errorf("the thresholds must be at least 1, got %d and %d", oldThreshold, newThreshold)
+	}
+	if len(oldIDs) < oldThreshold+1 {
+		return nil, fmt.Errorf("%d members of the old committee are available but %d are needed to re-share under threshold %d",
+			len(oldIDs), oldThreshold+1, oldThreshold)
+	}
+	if len(newIDs) < newThreshold+1 {
+		return nil, fmt.Errorf("the new committee of %d members cannot meet the threshold %d; it needs at least %d",
+			len(newIDs), newThreshold, newThreshold+1)
+	}
+	for _, ids := range []SortedPartyIDs{oldIDs, newIDs} {
+		seen := make(map[string]bool, len(ids))
+		for _, Pj := range ids {
+			if seen[Pj.GetId()] {
+				return nil, fmt.Errorf("the id %q is taken by two members of one committee", Pj.GetId())
+			}
+			seen[Pj.GetId()] = true
+		}
+	}
+
+	// the retained members take part first, then the others in the order of the old committee
+	retained, removed, _ := DiffCommittees(oldIDs, newIDs)
+	chosen := append(append(make(SortedPartyIDs, 0, len(oldIDs)), retained...), removed...)[:oldThreshold+1]
+	contributors := make(UnSortedPartyIDs, 0, len(chosen))
+	for _, Pj := range chosen {
+		contributors = append(contributors, NewPartyID(Pj.GetId(), Pj.GetMoniker(), Pj.KeyInt()))
+	}
+	sorted := SortPartyIDs(contributors)
+
+	self := sorted.FindByKey(partyID.KeyInt())
+	if self == nil {
+		if self = newIDs.FindByKey(partyID.KeyInt()); self == nil {
+			return nil, fmt.Errorf("party %s has no part in the re-sharing", partyID)
+		}
+	}
+	params := NewReSharingParameters(NewPeerContext(sorted), newCtx, self, len(sorted), oldThreshold, len(newIDs), newThreshold)
+	params.SetRetainedPartyPolicy(RetainedPartyRequireNewKey)
+	if err := params.CheckRetainedParties(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/common"
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// newCommittee builds a committee of the given members, each under a fresh key
+func newCommittee(ids ...string) *PeerContext {
+	pIDs := make(UnSortedPartyIDs, 0, len(ids))
+	for _, id := range ids {
+		pIDs = append(pIDs, NewPartyID(id, fmt.Sprintf("P[%s]", id), common.MustGetRandomInt(256)))
+	}
+	return NewPeerContext(SortPartyIDs(pIDs))
+}
+
+func idsOf(pIDs SortedPartyIDs) []string {
+	out := make([]string, 0, len(pIDs))
+	for _, Pj := range pIDs {
+		out = append(out, Pj.GetId())
+	}
+	return out
+}
+
+func TestPlanReshareAddParty(t *testing.T) {
+	oldCtx := newCommittee("1", "2", "3", "4")
+	newCtx := newCommittee("1", "2", "3", "4", "5")
+
+	retained, removed, added := DiffCommittees(oldCtx.IDs(), newCtx.IDs())
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4"}, idsOf(retained))
+	assert.Empty(t, removed)
+	assert.Equal(t, []string{"5"}, idsOf(added))
+
+	var olds int
+	for _, Pj := range oldCtx.IDs() {
+		params, err := PlanReshare(oldCtx, newCtx, 2, 2, Pj)
+		if err != nil {
+			assert.Contains(t, err.Error(), "has no part")
+			continue
+		}
+		olds++
+		assert.True(t, params.IsOldCommittee())
+		assert.False(t, params.IsNewCommittee())
+		assert.Len(t, params.OldParties().IDs(), 3, "only threshold+1 old members should take part")
+		assert.Equal(t, 2, params.Threshold())
+		assert.Equal(t, 5, params.NewPartyCount())
+		assert.Equal(t, 2, params.NewThreshold())
+		assert.Equal(t, params.PartyID(), params.OldParties().IDs()[params.PartyID().Index])
+	}
+	assert.Equal(t, 3, olds)
+
+	for _, Pj := range newCtx.IDs() {
+		params, err := PlanReshare(oldCtx, newCtx, 2, 2, Pj)
+		if assert.NoError(t, err) {
+			assert.False(t, params.IsOldCommittee())
+			assert.True(t, params.IsNewCommittee())
+		}
+	}
+}
+
+func TestPlanReshareRemoveParty(t *testing.T) {
+	oldCtx := newCommittee("1", "2", "3", "4", "5")
+	newCtx := newCommittee("1", "2", "4")
+
+	retained, removed, added := DiffCommittees(oldCtx.IDs(), newCtx.IDs())
+	assert.ElementsMatch(t, []string{"1", "2", "4"}, idsOf(retained))
+	assert.ElementsMatch(t, []string{"3", "5"}, idsOf(removed))
+	assert.Empty(t, added)
+
+	params, err := PlanReshare(oldCtx, newCtx, 2, 1, newCtx.IDs()[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the retained members take part rather than the removed ones, as they run a party anyway
+	assert.ElementsMatch(t, []string{"1", "2", "4"}, idsOf(params.OldParties().IDs()))
+	assert.Equal(t, 3, params.NewPartyCount())
+	assert.Equal(t, 1, params.NewThreshold())
+
+	// every node plans the same old committee
+	for _, Pj := range oldCtx.IDs() {
+		other, err := PlanReshare(oldCtx, newCtx, 2, 1, Pj)
+		if Pj.GetId() == "3" || Pj.GetId() == "5" {
+			assert.Error(t, err, "a removed member should not be needed")
+			continue
+		}
+		if assert.NoError(t, err) {
+			assert.Equal(t, params.OldParties().IDs().Keys(), other.OldParties().IDs().Keys())
+		}
+	}
+}
+
+func TestPlanReshareThresholdChange(t *testing.T) {
+	oldCtx := newCommittee("1", "2", "3", "4", "5")
+	newCtx := newCommittee("1", "2", "3", "4", "5")
+
+	params, err := PlanReshare(oldCtx, newCtx, 2, 3, oldCtx.IDs()[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, params.OldParties().IDs(), 3)
+	assert.Equal(t, 2, params.Threshold())
+	assert.Equal(t, 3, params.NewThreshold())
+	assert.Equal(t, 5, params.NewPartyCount())
+	assert.NoError(t, params.CheckRetainedParties())
+}
+
+func TestPlanReshareInfeasible(t *testing.T) {
+	oldCtx := newCommittee("1", "2", "3", "4", "5")
+	newCtx := newCommittee("1", "2", "3")
+
+	// too few old members are available
+	available := NewPeerContext(oldCtx.IDs()[:2])
+	_, err := PlanReshare(available, newCtx, 2, 1, available.IDs()[0])
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "are available")
+	}
+	// the new committee cannot meet its threshold
+	_, err = PlanReshare(oldCtx, newCtx, 2, 3, oldCtx.IDs()[0])
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "cannot meet the threshold")
+	}
+	_, err = PlanReshare(oldCtx, newCtx, 0, 1, oldCtx.IDs()[0])
+	assert.Error(t, err)
+	// a member may not take its place in both committees under one key
+	_, err = PlanReshare(oldCtx, oldCtx, 2, 2, oldCtx.IDs()[0])
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "under the same key")
+	}
+	// a node in neither committee
+	_, err = PlanReshare(oldCtx, newCtx, 2, 1, newCommittee("6").IDs()[0])
+	assert.Error(t, err)
+}
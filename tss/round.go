@@ -17,3 +17,13 @@ type Round interface {
 	WaitingFor() []*PartyID
 	WrapError(err error, culprits ...*PartyID) *Error
 }
+
+// RetryableRound is a round that can go back to the previous round when it fails to process the messages of some
+// parties, so that new messages from them are received. Only a round that checks those messages before it changes or
+// sends anything may implement it.
+type RetryableRound interface {
+	Round
+	// Retry drops the previous round's messages of `parties` and returns that round, waiting for new ones from them.
+	// It returns nil if this round cannot be retried in its state.
+	Retry(parties []*PartyID) Round
+}
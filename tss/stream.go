@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+type (
+	// ExpectedMessage is a message that a party of a protocol receives from each of the other parties in one of its
+	// rounds. Each protocol package lists its own with ExpectedMessages.
+	ExpectedMessage struct {
+		Round int
+		// Type is the type of the message as returned by Message.Type
+		Type      string
+		Broadcast bool
+	}
+)
+
+// ValidateMessageStream checks that a stream of messages received by this party, such as one stored to be replayed
+// into a new party, is complete and well-formed for a protocol whose messages are `expected`, e.g. the
+// ExpectedMessages of the keygen or signing package. The stream must hold exactly one message of each expected type
+// from each of the other parties, broadcast or, when the type is not broadcast, addressed to this party. All missing,
+// duplicate and unexpected messages are reported, including every message of a round that is missing entirely.
+func ValidateMessageStream(msgs []ParsedMessage, params *Parameters, expected []ExpectedMessage) error {
+	type slot struct {
+		msgType string
+		from    int
+	}
+	me, parties := params.PartyID(), params.Parties().IDs()
+	byType := make(map[string]ExpectedMessage, len(expected))
+	for _, exp := range expected {
+		byType[exp.Type] = exp
+	}
+	counts := make(map[slot]int, len(msgs))
+	var result *multierror.Error
+	for _, msg := range msgs {
+		if msg == nil || msg.GetFrom() == nil {
+			result = multierror.Append(result, errors.New("nil message or sender in the stream"))
+			continue
+		}
+		exp, known := byType[msg.Type()]
+		from := parties.FindByKey(msg.GetFrom().KeyInt())
+		if !known || from == nil || from.KeyInt().Cmp(me.KeyInt()) == 0 {
+			result = multierror.Append(result, fmt.Errorf("unexpected %s from %s", msg.Type(), msg.GetFrom()))
+			continue
+		}
+		if exp.Broadcast != msg.IsBroadcast() {
+			kind := map[bool]string{true: "broadcast", false: "point-to-point"}
+			result = multierror.Append(result, fmt.Errorf("round %d %s from %s is %s rather than %s",
+				exp.Round, exp.Type, from, kind[msg.IsBroadcast()], kind[exp.Broadcast]))
+			continue
+		}
+		// messages parsed from the wire do not carry their recipients, so they are only checked when known
+		if to := msg.GetTo(); !msg.IsBroadcast() && len(to) > 0 && (len(to) != 1 || to[0].KeyInt().Cmp(me.KeyInt()) != 0) {
+			result = multierror.Append(result, fmt.Errorf("round %d %s from %s is addressed to another party", exp.Round, exp.Type, from))
+			continue
+		}
+		counts[slot{exp.Type, from.Index}]++
+	}
+	for _, exp := range expected {
+		for _, pID := range parties {
+			if pID.KeyInt().Cmp(me.KeyInt()) == 0 {
+				continue
+			}
+			switch n := counts[slot{exp.Type, pID.Index}]; {
+			case n == 0:
+				result = multierror.Append(result, fmt.Errorf("missing round %d %s from %s", exp.Round, exp.Type, pID))
+			case 1 < n:
+				result = multierror.Append(result, fmt.Errorf("%d copies of round %d %s from %s", n, exp.Round, exp.Type, pID))
+			}
+		}
+	}
+	return result.ErrorOrNil()
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ordinox/thorchain-tss-lib/crypto"
+	"github.com/ordinox/thorchain-tss-lib/crypto/vss"
+	"github.com/ordinox/thorchain-tss-lib/crypto/zkp"
+	"github.com/ordinox/thorchain-tss-lib/eddsa/keygen"
+	. "github.com/ordinox/thorchain-tss-lib/tss"
+)
+
+// receivedStream builds the round 1 (broadcast) and round 2 (point-to-point, then broadcast) keygen messages received
+// by pIDs[0]
+func receivedStream(t *testing.T, pIDs SortedPartyIDs) []ParsedMessage {
+	ec := edwards.Edwards()
+	proof, err := zkp.NewDLogProof(ec, big.NewInt(3), crypto.ScalarBaseMult(ec, big.NewInt(3)))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	msgs := make([]ParsedMessage, 0, 3*len(pIDs))
+	for _, from := range pIDs[1:] {
+		msgs = append(msgs, keygen.NewKGRound1Message(from, big.NewInt(1)))
+	}
+	for _, from := range pIDs[1:] {
+		share := &vss.Share{Threshold: 1, ID: pIDs[0].KeyInt(), Share: big.NewInt(2)}
+		msgs = append(msgs, keygen.NewKGRound2Message1(pIDs[0], from, share))
+	}
+	for _, from := range pIDs[1:] {
+		msgs = append(msgs, keygen.NewKGRound2Message2(from, []*big.Int{big.NewInt(4)}, proof))
+	}
+	return msgs
+}
+
+func TestValidateMessageStream(t *testing.T) {
+	pIDs := GenerateTestPartyIDs(4)
+	params := NewParameters(NewPeerContext(pIDs), pIDs[0], len(pIDs), 1)
+
+	t.Run("complete", func(t *testing.T) {
+		assert.NoError(t, ValidateMessageStream(receivedStream(t, pIDs), params, keygen.ExpectedMessages()))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		msgs := receivedStream(t, pIDs)
+		msgs = append(msgs[:1], msgs[2:]...) // drop round 1 from pIDs[2]
+		err := ValidateMessageStream(msgs, params, keygen.ExpectedMessages())
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "missing round 1 KGRound1Message from "+pIDs[2].String())
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		msgs := receivedStream(t, pIDs)
+		msgs = append(msgs, msgs[len(msgs)-1]) // the broadcast of round 2 from pIDs[3] again
+		err := ValidateMessageStream(msgs, params, keygen.ExpectedMessages())
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "2 copies of round 2 KGRound2Message2 from "+pIDs[3].String())
+		}
+	})
+
+	t.Run("unexpected", func(t *testing.T) {
+		msgs := receivedStream(t, pIDs)
+		share := &vss.Share{Threshold: 1, ID: pIDs[2].KeyInt(), Share: big.NewInt(2)}
+		msgs = append(msgs,
+			keygen.NewKGRound1Message(pIDs[0], big.NewInt(1)),   // from this party
+			keygen.NewKGRound2Message1(pIDs[2], pIDs[1], share)) // to another party
+		err := ValidateMessageStream(msgs, params, keygen.ExpectedMessages())
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unexpected KGRound1Message from "+pIDs[0].String())
+			assert.Contains(t, err.Error(), "is addressed to another party")
+		}
+	})
+	t.Run("dropped round", func(t *testing.T) {
+		// every broadcast of round 2 is missing, which leaves no trace of the round in the stream
+		msgs := receivedStream(t, pIDs)
+		msgs = msgs[:2*(len(pIDs)-1)]
+		err := ValidateMessageStream(msgs, params, keygen.ExpectedMessages())
+		if assert.Error(t, err) {
+			for _, from := range pIDs[1:] {
+				assert.Contains(t, err.Error(), "missing round 2 KGRound2Message2 from "+from.String())
+			}
+		}
+	})
+}
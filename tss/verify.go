@@ -0,0 +1,106 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQuorumLost is wrapped by the error of a session in which too many parties were found to be culprits for it to
+// complete
+var ErrQuorumLost = errors.New("fewer than threshold+1 honest parties remain")
+
+// QuorumError returns an error wrapping ErrQuorumLost once the culprits leave fewer than threshold+1 honest parties in
+// the session, so that it cannot complete; it returns nil otherwise.
+func (params *Parameters) QuorumError(culprits []*PartyID) error {
+	distinct := make(map[*PartyID]struct{}, len(culprits))
+	for _, Pj := range culprits {
+		distinct[Pj] = struct{}{}
+	}
+	partyCount := len(params.Parties().IDs())
+	if honest := partyCount - len(distinct); honest <= params.Threshold() {
+		return fmt.Errorf("%w: %d of the %d parties are culprits, leaving %d honest parties where %d are needed",
+			ErrQuorumLost, len(distinct), partyCount, honest, params.Threshold()+1)
+	}
+	return nil
+}
+
+// VerifyPeers runs verify for each party of the session other than this one and returns the parties for which it
+// failed together with their errors, in the order of the parties. When concurrent verification is enabled the checks
+// run concurrently in chunks of VerificationChunkSize peers per goroutine, within the goroutine budget of the session;
+// either way all of them have completed when VerifyPeers returns.
+// Once the culprits leave fewer than threshold+1 honest parties the session can no longer complete, and QuorumError
+// then describes the abort. Only the culprits of the lowest indices, up to the first that loses the quorum, are
+// returned, and a check is skipped once that many peers of lower indices have failed, so that the result does not
+// depend on the order in which concurrent checks finish.
+func (params *Parameters) VerifyPeers(verify func(j int, Pj *PartyID) error) (culprits []*PartyID, errs []error) {
+	Ps := params.Parties().IDs()
+	results := make([]error, len(Ps))
+	// the number of failed checks after which the quorum is lost
+	maxFailures := len(Ps) - params.Threshold()
+	var mtx sync.Mutex
+	run := func(j int, Pj *PartyID) {
+		mtx.Lock()
+		below := 0
+		for k := 0; k < j; k++ {
+			if results[k] != nil {
+				below++
+			}
+		}
+		mtx.Unlock()
+		if maxFailures <= below {
+			return
+		}
+		if err := verify(j, Pj); err != nil {
+			mtx.Lock()
+			results[j] = err
+			mtx.Unlock()
+		}
+	}
+	if params.ConcurrentVerification() {
+		peers := make([]int, 0, len(Ps))
+		for j := range Ps {
+			if j != params.PartyID().Index {
+				peers = append(peers, j)
+			}
+		}
+		// each goroutine checks a chunk of the peers in turn
+		chunkSize := params.VerificationChunkSize()
+		wg := sync.WaitGroup{}
+		for start := 0; start < len(peers); start += chunkSize {
+			end := start + chunkSize
+			if len(peers) < end {
+				end = len(peers)
+			}
+			chunk := peers[start:end]
+			wg.Add(1)
+			params.Go(func() {
+				defer wg.Done()
+				for _, j := range chunk {
+					run(j, Ps[j])
+				}
+			})
+		}
+		wg.Wait()
+	} else {
+		for j, Pj := range Ps {
+			if j == params.PartyID().Index {
+				continue
+			}
+			run(j, Pj)
+		}
+	}
+	for j, err := range results {
+		if err != nil && len(culprits) < maxFailures {
+			culprits = append(culprits, Ps[j])
+			errs = append(errs, err)
+		}
+	}
+	return
+}
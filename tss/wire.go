@@ -11,19 +11,32 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
-	"google.golang.org/protobuf/proto"
 )
 
-// Used externally to update a LocalParty with a valid ParsedMessage
-func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+// Used externally to update a LocalParty with a valid ParsedMessage.
+// The bytes are decoded with the given codec, which must be the one they were encoded with, or with ProtoCodec.
+func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool, optionalCodec ...Codec) (ParsedMessage, error) {
+	var codec Codec
+	if 0 < len(optionalCodec) {
+		if 1 < len(optionalCodec) {
+			return nil, errors.New("ParseWireMessage: expected 0 or 1 item in `optionalCodec`")
+		}
+		codec = optionalCodec[0]
+	}
 	wire := new(MessageWrapper)
 	wire.Message = new(any.Any)
 	wire.From = from.MessageWrapper_PartyID
 	wire.IsBroadcast = isBroadcast
-	if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
+	if err := codecOrDefault(codec).Unmarshal(wireBytes, wire.Message); err != nil {
+		return nil, err
+	}
+	msg, err := parseWrappedMessage(wire, from)
+	if err != nil {
 		return nil, err
 	}
-	return parseWrappedMessage(wire, from)
+	// a stored message is encoded again as it was received, e.g. in a snapshot
+	msg.(*MessageImpl).codec = codec
+	return msg, nil
 }
 
 func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, error) {